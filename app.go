@@ -90,10 +90,11 @@ type LongyApp struct {
 	mm *module.Manager
 }
 
-// NewLongyApp is a constructor function for LongyApp
+// NewLongyApp is a constructor function for LongyApp. The game is instrumented with `metrics`
+// unless it is nil
 //nolint: dupl
 func NewLongyApp(
-	logger log.Logger, db dbm.DB, baseAppOptions ...func(*bam.BaseApp),
+	logger log.Logger, db dbm.DB, metrics *longy.Metrics, baseAppOptions ...func(*bam.BaseApp),
 ) *LongyApp {
 
 	// First define the top level codec that will be shared by the different modules
@@ -194,6 +195,9 @@ func NewLongyApp(
 		app.accountKeeper,
 		app.bankKeeper,
 	)
+	if metrics != nil {
+		app.longyKeeper.SetMetrics(metrics)
+	}
 
 	app.mm = module.NewManager(
 		genaccounts.NewAppModule(app.accountKeeper),
@@ -233,12 +237,16 @@ func NewLongyApp(
 	app.SetBeginBlocker(app.BeginBlocker)
 	app.SetEndBlocker(app.EndBlocker)
 
-	// The AnteHandler handles signature verification and transaction pre-processing
+	// The AnteHandler handles signature verification and transaction pre-processing. The longy
+	// ante handler restricts what attendee accounts can send and how often
 	app.SetAnteHandler(
-		auth.NewAnteHandler(
-			app.accountKeeper,
-			app.supplyKeeper,
-			auth.DefaultSigVerificationGasConsumer,
+		longy.NewAnteHandler(
+			app.longyKeeper,
+			auth.NewAnteHandler(
+				app.accountKeeper,
+				app.supplyKeeper,
+				auth.DefaultSigVerificationGasConsumer,
+			),
 		),
 	)
 
@@ -1,38 +1,88 @@
 package main
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws/session"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	longyApp "github.com/eco/longy"
+	"github.com/eco/longy/eventbrite"
 	ks "github.com/eco/longy/key-service"
+	"github.com/eco/longy/key-service/admin"
+	"github.com/eco/longy/key-service/campaign"
+	"github.com/eco/longy/key-service/cluster"
 	ksCfg "github.com/eco/longy/key-service/config"
 	eb "github.com/eco/longy/key-service/eventbrite"
+	"github.com/eco/longy/key-service/health"
 	"github.com/eco/longy/key-service/mail"
 	mk "github.com/eco/longy/key-service/masterkey"
+	"github.com/eco/longy/key-service/metrics"
 	dbm "github.com/eco/longy/key-service/models"
+	ksSession "github.com/eco/longy/key-service/session"
+	"github.com/eco/longy/key-service/signer"
+	"github.com/eco/longy/key-service/suppression"
+	"github.com/eco/longy/ticketing"
 	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/client/longyclient"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 )
 
+var log = logrus.WithField("module", "ks")
+
 func init() {
 	rootCmd.Flags().Int("port", 1337, "port to bind the rekey service")
+	rootCmd.Flags().Int("metrics-port", 0, "port serving the prometheus metrics on /metrics. disabled if 0")
 
 	rootCmd.Flags().String("longy-chain-id", "longychain", "chain-id of the running longy game")
 	rootCmd.Flags().String("longy-restservice", "http://localhost:1317", "scheme://host:port of the full node rest client")
+	rootCmd.Flags().Duration("longy-rest-timeout", longyclient.DefaultTimeout, "timeout of a request to the rest client")
+	rootCmd.Flags().Int("longy-rest-retries", longyclient.DefaultRetries, "number of times a failed query is retried")
 	rootCmd.Flags().String("longy-app-url", "http://localhost:5000", "scheme://host of the client web app")
+	rootCmd.Flags().String("address-namespace", "",
+		"event namespace the chain derives attendee addresses with. must match the genesis address_namespace")
 
-	// using "master" as the seed
-	rootCmd.Flags().String("longy-masterkey",
-		"fc613b4dfd6736a7bd268c8a0e74ed0d1c04a959f59dd74ef2874983fd443fca", "hex encoded master private key")
+	rootCmd.Flags().String("longy-masterkey", "", "hex encoded master private key")
+	rootCmd.Flags().String("keyring-dir", "",
+		"client home with the key-service key generated by `lyd init-service-keys`. replaces --longy-masterkey")
+	rootCmd.Flags().String("keyring-passphrase", "", "passphrase of the key-service key in --keyring-dir")
+	rootCmd.Flags().Bool("insecure-dev", false,
+		"allow the well-known development master key. used when no master key is set")
+	rootCmd.Flags().String("signer-socket", "",
+		"unix socket of the `ks signer` daemon holding the master key. replaces the master key flags")
 
 	rootCmd.Flags().String("eventbrite-auth", "", "eventbrite authorization token")
 	rootCmd.Flags().Int("eventbrite-event", 0, "id associated with the eventbrite event")
+	rootCmd.Flags().String("ticketing-csv", "", "csv file to read attendees from instead of eventbrite")
 
 	rootCmd.Flags().String("aws-content-bucket", "linkedup-user-content", "content bucket for user uploads")
 	rootCmd.Flags().Bool("email-mock", false, "print email URLs instead of emailing")
 	rootCmd.Flags().Bool("localstack", false, "use localstack instead of aws; implies --email-mock")
+
+	rootCmd.Flags().Int("campaign-rate", 10, "maximum number of campaign emails sent per second")
+	rootCmd.Flags().StringSlice("ses-topic-arns", nil,
+		"sns topics of the ses bounce and complaint notifications posted to /webhooks/ses. required unless insecure")
+	rootCmd.Flags().Bool("ses-insecure-notifications", false,
+		"accept unsigned notifications on /webhooks/ses. used to post notifications locally")
+
+	rootCmd.Flags().String("session-secret", "",
+		"hex encoded secret authenticating attendee sessions. random if empty, required with --cluster")
+
+	rootCmd.Flags().String("admin-keys", "", "json file with the named admin api keys and their scopes")
+
+	rootCmd.Flags().Bool("cluster", false,
+		"share the master key with other key services. key transactions are broadcast by an elected instance")
+	rootCmd.Flags().String("instance-id", "", "unique name of this key service. hostname and a random suffix if empty")
+	rootCmd.Flags().Duration("lease-ttl", 30*time.Second, "expiry of the broadcaster lease held by the elected instance")
 }
 
 var rootCmd = &cobra.Command{
@@ -45,6 +95,9 @@ var rootCmd = &cobra.Command{
 		viper.AutomaticEnv()
 
 		port := viper.GetInt("port")
+		if metricsPort := viper.GetInt("metrics-port"); metricsPort > 0 {
+			go serveMetrics(metricsPort)
+		}
 
 		authToken := viper.GetString("eventbrite-auth")
 		eventID := viper.GetInt("eventbrite-event")
@@ -57,16 +110,36 @@ var rootCmd = &cobra.Command{
 
 		longyChainID := viper.GetString("longy-chain-id")
 		longyAppURL := viper.GetString("longy-app-url")
-		longyRestURL := viper.GetString("longy-restservice")
-		ksCfg.SetLongyRestURL(longyRestURL)
+		chain := longyclient.New(viper.GetString("longy-restservice"),
+			longyclient.WithCodec(longyApp.MakeCodec()),
+			longyclient.WithTimeout(viper.GetDuration("longy-rest-timeout")),
+			longyclient.WithRetries(viper.GetInt("longy-rest-retries"), longyclient.DefaultBackoff))
+		ksCfg.SetAddressNamespace(viper.GetString("address-namespace"))
 
-		key, err := util.Secp256k1FromHex(viper.GetString("longy-masterkey"))
+		mSigner, err := masterSigner()
 		if err != nil {
 			return fmt.Errorf("masterkey: %s", err)
 		}
 
-		/** Eventbrite session **/
-		ebSession, err := eb.CreateSession(eventID, authToken)
+		/** Admin credentials **/
+		adminKeysPath := viper.GetString("admin-keys")
+		if len(adminKeysPath) == 0 {
+			return fmt.Errorf("admin keys: --admin-keys must be set")
+		}
+		adminKeys, err := admin.LoadKeys(adminKeysPath)
+		if err != nil {
+			return fmt.Errorf("admin keys: %s", err)
+		}
+
+		/** Ticketing session **/
+		var provider ticketing.Provider = eventbrite.NewProvider(eventID, authToken, ticketing.DefaultRoleMap())
+		if csvPath := viper.GetString("ticketing-csv"); len(csvPath) > 0 {
+			provider, err = ticketing.NewCSVProvider(csvPath, ticketing.DefaultRoleMap())
+			if err != nil {
+				return fmt.Errorf("ticketing csv: %s", err)
+			}
+		}
+		ebSession, err := eb.CreateSession(provider)
 		if err != nil {
 			return err
 		}
@@ -81,6 +154,7 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("mail client: %s", err)
 		}
+		mClient = mail.NewInstrumentedClient(mClient)
 
 		/** Backend DB **/
 		db, err := dbm.NewDatabaseContextWithCfg(awsCfg, localstack, contentBucket)
@@ -89,18 +163,138 @@ var rootCmd = &cobra.Command{
 		}
 
 		/** Master key session **/
-		mKey, err := mk.NewMasterKey(key, longyChainID)
-		if err != nil {
+		newMasterKey := func() (mk.Keyer, error) {
+			key, err := mk.NewMasterKey(mSigner, longyChainID, chain)
+			if err != nil {
+				return nil, err
+			}
+			return key, nil
+		}
+		instanceID := viper.GetString("instance-id")
+		if len(instanceID) == 0 {
+			hostname, _ := os.Hostname()
+			instanceID = fmt.Sprintf("%s-%s", hostname, cmn.RandStr(6))
+		}
+		var mKey mk.Keyer
+		if viper.GetBool("cluster") {
+			mKey = cluster.NewNode(&db, instanceID, viper.GetDuration("lease-ttl"), newMasterKey)
+		} else if mKey, err = newMasterKey(); err != nil {
 			return fmt.Errorf("master key: %s", err)
 		}
+		onboarding := cluster.NewOnboarding(&db, instanceID)
+
+		/** Email campaigns **/
+		campaigns := campaign.NewRunner(&db, ebSession, mClient, chain, viper.GetInt("campaign-rate"))
+		if viper.GetBool("cluster") {
+			// only the instance holding the campaign lease sends the emails
+			campaigns.Cluster(&db, instanceID, viper.GetDuration("lease-ttl"))
+		} else if err = campaigns.Resume(); err != nil {
+			return fmt.Errorf("campaigns: %s", err)
+		}
 
-		service := ks.NewService(ebSession, &mKey, &db, mClient)
+		/** Attendee sessions **/
+		sessionSecret, err := hex.DecodeString(viper.GetString("session-secret"))
+		if err != nil {
+			return fmt.Errorf("session secret: %s", err)
+		}
+		var challenges ksSession.Challenges
+		if viper.GetBool("cluster") {
+			// sessions and challenges must be accepted by every instance
+			if len(sessionSecret) == 0 {
+				return fmt.Errorf("--session-secret is required with --cluster")
+			}
+			challenges = &db
+		}
+		sessions := ksSession.NewIssuer(sessionSecret, longyChainID, chain, challenges)
+
+		/** Dependency status **/
+		checker := health.NewChecker(
+			health.ChainCheck(chain),
+			health.MasterAccountCheck(chain, sdk.AccAddress(mSigner.PubKey().Address())),
+			health.StorageCheck(&db),
+			health.MailCheck(mClient),
+			health.TicketingCheck(ebSession),
+		)
+
+		/** Bounce and complaint notifications **/
+		suppressionOpts := []suppression.Option{suppression.WithTopics(viper.GetStringSlice("ses-topic-arns")...)}
+		if viper.GetBool("ses-insecure-notifications") {
+			suppressionOpts = append(suppressionOpts, suppression.WithoutVerification())
+		}
+		suppressions, err := suppression.NewReceiver(&db, suppressionOpts...)
+		if err != nil {
+			return fmt.Errorf("ses notifications: %s", err)
+		}
+
+		service := ks.NewService(ebSession, mKey, onboarding, &db, mClient, campaigns, sessions, adminKeys, chain,
+			checker, suppressions)
 		service.StartHTTP(port)
 
 		return nil
 	},
 }
 
+// serveMetrics exposes the prometheus metrics on a port separate from the public api
+func serveMetrics(port int) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux); err != nil {
+		log.WithError(err).Error("metrics server")
+	}
+}
+
+// masterSigner returns the signer of the master key. The key is held by the signing daemon when
+// --signer-socket is set and loaded in process otherwise
+func masterSigner() (signer.Signer, error) {
+	socket := viper.GetString("signer-socket")
+	if len(socket) == 0 {
+		key, err := readMasterKey()
+		if err != nil {
+			return nil, err
+		}
+		return signer.NewLocal(key), nil
+	}
+
+	remote, err := signer.NewRemote(socket)
+	if err != nil {
+		return nil, fmt.Errorf("signing daemon: %s", err)
+	}
+	if longy.IsWellKnownServiceKey(remote.PubKey()) && !viper.GetBool("insecure-dev") {
+		return nil, fmt.Errorf("refusing the well-known development key without --insecure-dev")
+	}
+	return remote, nil
+}
+
+// readMasterKey loads the master key from the keyring or the hex flag. Keys derived from the
+// public service seeds are refused unless --insecure-dev is set
+func readMasterKey() (tmcrypto.PrivKey, error) {
+	insecureDev := viper.GetBool("insecure-dev")
+
+	var (
+		key tmcrypto.PrivKey
+		err error
+	)
+	switch {
+	case len(viper.GetString("keyring-dir")) > 0:
+		key, err = util.Secp256k1FromKeyring(viper.GetString("keyring-dir"), longy.KeyServiceKeyName,
+			viper.GetString("keyring-passphrase"))
+	case len(viper.GetString("longy-masterkey")) > 0:
+		key, err = util.Secp256k1FromHex(viper.GetString("longy-masterkey"))
+	case insecureDev:
+		key = secp256k1.GenPrivKeySecp256k1([]byte(longy.ServiceSeed))
+	default:
+		return nil, fmt.Errorf("--keyring-dir or --longy-masterkey must be set")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if longy.IsWellKnownServiceKey(key.PubKey()) && !insecureDev {
+		return nil, fmt.Errorf("refusing the well-known development key without --insecure-dev")
+	}
+	return key, nil
+}
+
 func main() {
 	err := rootCmd.Execute()
 	if err != nil {
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/eco/longy/key-service/signer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	signerCmd.Flags().String("socket", "/var/run/ks-signer.sock", "unix socket to serve sign requests on")
+	signerCmd.Flags().String("longy-chain-id", "longychain", "chain-id of the transactions that are signed")
+
+	signerCmd.Flags().String("longy-masterkey", "", "hex encoded master private key")
+	signerCmd.Flags().String("keyring-dir", "",
+		"client home with the key-service key generated by `lyd init-service-keys`. replaces --longy-masterkey")
+	signerCmd.Flags().String("keyring-passphrase", "", "passphrase of the key-service key in --keyring-dir")
+	signerCmd.Flags().Bool("insecure-dev", false,
+		"allow the well-known development master key. used when no master key is set")
+
+	rootCmd.AddCommand(signerCmd)
+}
+
+var signerCmd = &cobra.Command{
+	Use:          "signer",
+	Short:        "signing daemon holding the master key. only signs MsgKey transactions",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags()) //nolint
+		viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+		viper.AutomaticEnv()
+
+		key, err := readMasterKey()
+		if err != nil {
+			return fmt.Errorf("masterkey: %s", err)
+		}
+
+		daemon := signer.NewDaemon(key, viper.GetString("longy-chain-id"), []string{signer.MsgKeyType})
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-stop
+			daemon.Close() //nolint
+		}()
+
+		socket := viper.GetString("socket")
+		defer os.Remove(socket) //nolint
+		return daemon.ListenAndServe(socket)
+	},
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path"
 
@@ -14,6 +15,8 @@ import (
 	bankcmd "github.com/cosmos/cosmos-sdk/x/bank/client/cli"
 	app "github.com/eco/longy"
 	longycmd "github.com/eco/longy/x/longy/client/cli"
+	"github.com/eco/longy/x/longy/client/rest/broadcast"
+	"github.com/eco/longy/x/longy/client/rest/query"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/tendermint/go-amino"
@@ -50,7 +53,7 @@ func main() {
 		queryCmd(cdc),
 		txCmd(cdc),
 		client.LineBreak,
-		lcd.ServeCommand(cdc, registerRoutes),
+		restServerCmd(cdc),
 		client.LineBreak,
 		keys.Commands(),
 		client.LineBreak,
@@ -65,7 +68,21 @@ func main() {
 	}
 }
 
+func restServerCmd(cdc *amino.Codec) *cobra.Command {
+	cmd := lcd.ServeCommand(cdc, registerRoutes)
+	query.AddClaimFlags(cmd)
+	broadcast.AddFlags(cmd)
+	return cmd
+}
+
 func registerRoutes(rs *lcd.RestServer) {
+	if err := query.LoadClaimSigner(); err != nil {
+		panic(fmt.Sprintf("claim service key: %s", err))
+	}
+	if err := broadcast.LoadConfig(); err != nil {
+		panic(fmt.Sprintf("broadcast proxy: %s", err))
+	}
+
 	client.RegisterRoutes(rs.CliCtx, rs.Mux)
 	app.ModuleBasics.RegisterRESTRoutes(rs.CliCtx, rs.Mux)
 }
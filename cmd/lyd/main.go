@@ -7,8 +7,10 @@ import (
 	genaccscli "github.com/cosmos/cosmos-sdk/x/genaccounts/client/cli"
 	"github.com/cosmos/cosmos-sdk/x/staking"
 	app "github.com/eco/longy"
+	"github.com/eco/longy/x/longy"
 	"github.com/eco/longy/x/longy/client/cli/genesis"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/tendermint/tendermint/libs/cli"
 	"github.com/tendermint/tendermint/libs/log"
 	"io"
@@ -53,7 +55,10 @@ func main() {
 		genesis.AddGenesisAttendeesCmd(ctx, cdc),
 		// AddGenesisPrizesCmd allows users to add the list of prizes and their quantity for the event
 		genesis.AddGenesisPrizesCmd(ctx, cdc),
+		// MigrateAttendeeAddressesCmd moves the attendees to the addresses derived from the event namespace
+		genesis.MigrateAttendeeAddressesCmd(ctx, cdc),
 		// service commands
+		genesis.InitServiceKeysCmd(ctx, cdc, app.DefaultCLIHome),
 		genesis.AddSetGenesisKeyServiceCmd(ctx, cdc),
 		genesis.AddSetGenesisBonusServiceCmd(ctx, cdc),
 		genesis.AddSetGenesisClaimServiceCmd(ctx, cdc),
@@ -72,14 +77,28 @@ func main() {
 }
 
 func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application {
-	return app.NewLongyApp(logger, db)
+	return app.NewLongyApp(logger, db, moduleMetrics())
+}
+
+// moduleMetrics returns the game metrics when the tendermint prometheus instrumentation is enabled.
+// They are served on the instrumentation port along with the tendermint metrics
+func moduleMetrics() *longy.Metrics {
+	if !viper.GetBool("instrumentation.prometheus") {
+		return nil
+	}
+
+	namespace := viper.GetString("instrumentation.namespace")
+	if len(namespace) == 0 {
+		namespace = "tendermint"
+	}
+	return longy.PrometheusMetrics(namespace)
 }
 
 func exportAppStateAndTMValidators(
 	logger log.Logger, db dbm.DB, traceStore io.Writer, height int64, forZeroHeight bool, jailWhiteList []string,
 ) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 
-	longyApp := app.NewLongyApp(logger, db)
+	longyApp := app.NewLongyApp(logger, db, nil)
 	if height != -1 {
 		err := longyApp.LoadHeight(height)
 		if err != nil {
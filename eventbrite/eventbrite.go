@@ -8,29 +8,103 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/eco/longy/ticketing"
 )
 
 const (
 	// EventEnvKey -
-	EventEnvKey = "EVENTBRTIE_EVENT"
+	EventEnvKey = "EVENTBRITE_EVENT"
+	// LegacyEventEnvKey is the misspelled event variable read by earlier releases, still read when
+	// `EventEnvKey` is not set
+	LegacyEventEnvKey = "EVENTBRTIE_EVENT"
 	// AuthEnvKey -
 	AuthEnvKey = "EVENTBRITE_AUTH"
 
-	urlFormat  = "https://www.eventbriteapi.com/v3/events/%d/attendees/?page=%d"
-	authFormat = "Bearer %s"
+	urlFormat         = "https://www.eventbriteapi.com/v3/events/%d/attendees/?page=%d"
+	attendeeURLFormat = "https://www.eventbriteapi.com/v3/events/%d/attendees/%d/"
+	authFormat        = "Bearer %s"
 )
 
 var netClient = &http.Client{
 	Timeout: 5 * time.Second,
 }
 
-// AttendeeProfile -
-type AttendeeProfile struct {
-	ID int `json:"id"`
+// AttendeeProfile is the profile shared by all ticketing providers
+type AttendeeProfile = ticketing.AttendeeProfile
+
+// Provider is the eventbrite implementation of `ticketing.Provider`
+type Provider struct {
+	ticketing.RoleMap
+
+	eventID   int
+	authToken string
+}
+
+var _ ticketing.Provider = &Provider{}
+
+// NewProvider is the constructor for `Provider`
+func NewProvider(eventID int, authToken string, roles ticketing.RoleMap) *Provider {
+	return &Provider{
+		RoleMap:   roles,
+		eventID:   eventID,
+		authToken: authToken,
+	}
+}
+
+// NewProviderFromEnv constructs a `Provider` using the `EventEnvKey` and `AuthEnvKey` environment variables
+func NewProviderFromEnv(roles ticketing.RoleMap) (*Provider, error) {
+	eventStr := os.Getenv(EventEnvKey)
+	if len(eventStr) == 0 {
+		eventStr = os.Getenv(LegacyEventEnvKey)
+	}
+	authToken := os.Getenv(AuthEnvKey)
+	if len(eventStr) == 0 || len(authToken) == 0 {
+		err := fmt.Errorf("%s and %s environment variables must be set to communicate with eventbrite",
+			EventEnvKey, AuthEnvKey)
+		return nil, err
+	}
+
+	eventID, err := strconv.Atoi(eventStr)
+	if err != nil {
+		err = fmt.Errorf("event id must be a positive number in decimal format: %s", err)
+		return nil, err
+	}
+
+	return NewProvider(eventID, authToken, roles), nil
+}
+
+// Attendees lists every attendee of the event
+func (p *Provider) Attendees() ([]AttendeeProfile, error) {
+	return GetAttendees(p.eventID, p.authToken)
+}
+
+// Attendee retrieves the attendee with `id` directly from eventbrite
+func (p *Provider) Attendee(id int) (*AttendeeProfile, bool, error) {
+	resp, err := get(fmt.Sprintf(attendeeURLFormat, p.eventID, id), p.authToken)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close() //nolint
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	} else if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("bad response. status code=%d", resp.StatusCode)
+		return nil, false, err
+	}
+
+	var body json.RawMessage
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		err = fmt.Errorf("reading request body: %s", err)
+		return nil, false, err
+	}
+
+	profile, err := getProfile(body)
+	if err != nil {
+		return nil, false, err
+	}
 
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
+	return profile, true, nil
 }
 
 // GetAttendees -
@@ -64,21 +138,12 @@ func GetAttendees(eventID int, authToken string) ([]AttendeeProfile, error) {
 
 // GetAttendeesFromEnv -
 func GetAttendeesFromEnv() ([]AttendeeProfile, error) {
-	eventStr := os.Getenv(EventEnvKey)
-	authToken := os.Getenv(AuthEnvKey)
-	if len(eventStr) == 0 || len(authToken) == 0 {
-		err := fmt.Errorf("%s and %s environment variables must be set to communicate with eventbrite",
-			EventEnvKey, AuthEnvKey)
-		return nil, err
-	}
-
-	eventID, err := strconv.Atoi(eventStr)
+	provider, err := NewProviderFromEnv(ticketing.DefaultRoleMap())
 	if err != nil {
-		err = fmt.Errorf("event id must be a positive number in decimal format: %s", err)
 		return nil, err
 	}
 
-	return GetAttendees(eventID, authToken)
+	return provider.Attendees()
 }
 
 func fetchPage(eventID int, authToken string, page int) (attendees []AttendeeProfile, hasMore bool, err error) {
@@ -91,22 +156,8 @@ func fetchPage(eventID int, authToken string, page int) (attendees []AttendeePro
 		Attendees []json.RawMessage `json:"attendees"`
 	}
 
-	auth := fmt.Sprintf(authFormat, authToken)
-	url, err := url.Parse(fmt.Sprintf(urlFormat, eventID, page))
-	if err != nil {
-		err = fmt.Errorf("parsing url: %s", err)
-		return nil, false, err
-	}
-	req := &http.Request{
-		URL:    url,
-		Method: "GET",
-		Header: map[string][]string{
-			"Authorization": {auth},
-		},
-	}
-	resp, err := netClient.Do(req)
+	resp, err := get(fmt.Sprintf(urlFormat, eventID, page), authToken)
 	if err != nil {
-		err = fmt.Errorf("eventbrite request delivery: %s", err)
 		return nil, false, err
 	}
 	defer resp.Body.Close() //nolint
@@ -130,7 +181,7 @@ func fetchPage(eventID int, authToken string, page int) (attendees []AttendeePro
 	for i := 0; i < numAttendees; i++ {
 		attendee, err2 := getProfile(data.Attendees[i])
 		if err2 != nil {
-			return nil, false, err
+			return nil, false, err2
 		}
 
 		attendees[i] = *attendee
@@ -142,6 +193,26 @@ func fetchPage(eventID int, authToken string, page int) (attendees []AttendeePro
 	return attendees, hasMore, err
 }
 
+func get(rawURL string, authToken string) (*http.Response, error) {
+	url, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing url: %s", err)
+	}
+	req := &http.Request{
+		URL:    url,
+		Method: "GET",
+		Header: map[string][]string{
+			"Authorization": {fmt.Sprintf(authFormat, authToken)},
+		},
+	}
+	resp, err := netClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("eventbrite request delivery: %s", err)
+	}
+
+	return resp, nil
+}
+
 func getProfile(body json.RawMessage) (*AttendeeProfile, error) {
 	/** Parse the entire attendee body **/
 	var jsonResp map[string]json.RawMessage
@@ -179,5 +250,12 @@ func getProfile(body json.RawMessage) (*AttendeeProfile, error) {
 	}
 	profile.ID = attendeeID
 
+	// the ticket class lives outside of the profile
+	if ticketClass, ok := jsonResp["ticket_class_name"]; ok {
+		if err := json.Unmarshal(ticketClass, &profile.TicketClass); err != nil {
+			return nil, fmt.Errorf("unable to parse ticket class: %s", err)
+		}
+	}
+
 	return &profile, nil
 }
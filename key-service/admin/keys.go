@@ -0,0 +1,139 @@
+package admin
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Scope is a group of admin actions a key is allowed to perform
+type Scope string
+
+const (
+	// ScopeEmailOverride allows reading and overriding the email address of an attendee
+	ScopeEmailOverride Scope = "email-override"
+	// ScopeBulkSend allows sending emails to many attendees at once
+	ScopeBulkSend Scope = "bulk-send"
+	// ScopeRecoveryAssist allows sending recovery emails on behalf of an attendee
+	ScopeRecoveryAssist Scope = "recovery-assist"
+	// ScopeBlacklist allows reading and managing the email blacklist
+	ScopeBlacklist Scope = "blacklist"
+	// ScopeAudit allows reading the audit log
+	ScopeAudit Scope = "audit"
+)
+
+// MinSecretLength is the minimum length of an admin key secret
+const MinSecretLength = 32
+
+var knownScopes = map[Scope]bool{
+	ScopeEmailOverride:  true,
+	ScopeBulkSend:       true,
+	ScopeRecoveryAssist: true,
+	ScopeBlacklist:      true,
+	ScopeAudit:          true,
+}
+
+// Key is a named admin credential. The name is recorded in the audit log for every
+// action performed with the key
+type Key struct {
+	Name   string  `json:"name"`
+	Secret string  `json:"secret"`
+	Scopes []Scope `json:"scopes"`
+}
+
+// HasScope indicates if the key is allowed to perform actions in `scope`
+func (k *Key) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys is the set of admin credentials the key service accepts
+type Keys struct {
+	keys   []Key
+	hashes [][sha256.Size]byte
+}
+
+// NewKeys validates `keys` and builds the credential set. At least one key is required,
+// names must be unique, secrets must be at least `MinSecretLength` characters and every
+// scope must be known
+func NewKeys(keys []Key) (Keys, error) {
+	if len(keys) == 0 {
+		return Keys{}, fmt.Errorf("at least one admin key must be configured")
+	}
+
+	names := make(map[string]bool, len(keys))
+	set := Keys{
+		keys:   make([]Key, len(keys)),
+		hashes: make([][sha256.Size]byte, len(keys)),
+	}
+	for i, k := range keys {
+		k.Name = strings.TrimSpace(k.Name)
+		switch {
+		case len(k.Name) == 0:
+			return Keys{}, fmt.Errorf("admin key %d has no name", i)
+		case names[k.Name]:
+			return Keys{}, fmt.Errorf("duplicate admin key name %s", k.Name)
+		case len(k.Secret) < MinSecretLength:
+			return Keys{}, fmt.Errorf("admin key %s secret must be at least %d characters", k.Name, MinSecretLength)
+		case len(k.Scopes) == 0:
+			return Keys{}, fmt.Errorf("admin key %s has no scopes", k.Name)
+		}
+		for _, s := range k.Scopes {
+			if !knownScopes[s] {
+				return Keys{}, fmt.Errorf("admin key %s has unknown scope %s", k.Name, s)
+			}
+		}
+
+		names[k.Name] = true
+		set.keys[i] = k
+		set.hashes[i] = sha256.Sum256([]byte(k.Secret))
+	}
+
+	return set, nil
+}
+
+// LoadKeys reads a json array of `Key` from the file at `path`
+func LoadKeys(path string) (Keys, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Keys{}, fmt.Errorf("reading admin keys: %s", err)
+	}
+
+	var keys []Key
+	if err := json.Unmarshal(bz, &keys); err != nil {
+		return Keys{}, fmt.Errorf("decoding admin keys: %s", err)
+	}
+
+	return NewKeys(keys)
+}
+
+// Authenticate returns the key with `secret`. Secrets are hashed to a fixed length and
+// every key is compared so that the time taken does not depend on which key, if any, matched
+func (ks Keys) Authenticate(secret string) (*Key, bool) {
+	hash := sha256.Sum256([]byte(secret))
+
+	match := -1
+	for i := range ks.hashes {
+		if subtle.ConstantTimeCompare(hash[:], ks.hashes[i][:]) == 1 {
+			match = i
+		}
+	}
+
+	if match < 0 || len(secret) == 0 {
+		return nil, false
+	}
+	key := ks.keys[match]
+	return &key, true
+}
+
+// Len is the number of configured keys
+func (ks Keys) Len() int {
+	return len(ks.keys)
+}
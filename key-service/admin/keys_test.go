@@ -0,0 +1,63 @@
+package admin_test
+
+import (
+	"github.com/eco/longy/key-service/admin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"strings"
+)
+
+var _ = Describe("Admin Keys", func() {
+	var (
+		secretA = strings.Repeat("a", admin.MinSecretLength)
+		secretB = strings.Repeat("b", admin.MinSecretLength)
+	)
+
+	It("should refuse an empty key set", func() {
+		_, err := admin.NewKeys(nil)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("should refuse malformed keys", func() {
+		scopes := []admin.Scope{admin.ScopeBulkSend}
+		invalid := [][]admin.Key{
+			{{Name: "", Secret: secretA, Scopes: scopes}},
+			{{Name: "ops", Secret: "short", Scopes: scopes}},
+			{{Name: "ops", Secret: secretA}},
+			{{Name: "ops", Secret: secretA, Scopes: []admin.Scope{"root"}}},
+			{{Name: "ops", Secret: secretA, Scopes: scopes}, {Name: "ops", Secret: secretB, Scopes: scopes}},
+		}
+		for _, keys := range invalid {
+			_, err := admin.NewKeys(keys)
+			Expect(err).ToNot(BeNil())
+		}
+	})
+
+	Context("when keys are configured", func() {
+		var keys admin.Keys
+		BeforeEach(func() {
+			var err error
+			keys, err = admin.NewKeys([]admin.Key{
+				{Name: "support", Secret: secretA, Scopes: []admin.Scope{admin.ScopeEmailOverride}},
+				{Name: "ops", Secret: secretB, Scopes: []admin.Scope{admin.ScopeBulkSend, admin.ScopeAudit}},
+			})
+			Expect(err).To(BeNil())
+			Expect(keys.Len()).To(Equal(2))
+		})
+
+		It("should authenticate the key with the matching secret", func() {
+			key, ok := keys.Authenticate(secretB)
+			Expect(ok).To(BeTrue())
+			Expect(key.Name).To(Equal("ops"))
+			Expect(key.HasScope(admin.ScopeAudit)).To(BeTrue())
+			Expect(key.HasScope(admin.ScopeEmailOverride)).To(BeFalse())
+		})
+
+		It("should reject unknown and empty secrets", func() {
+			_, ok := keys.Authenticate(secretA + "a")
+			Expect(ok).To(BeFalse())
+			_, ok = keys.Authenticate("")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
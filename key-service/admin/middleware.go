@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eco/longy/key-service/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AuthHeader is the header carrying the admin key secret
+	AuthHeader = "Authorization"
+
+	bearerPrefix = "Bearer "
+)
+
+var log = logrus.WithField("module", "admin")
+
+type contextKey int
+
+const entryContextKey contextKey = 0
+
+// AuditLog is the append-only store admin actions are recorded to
+type AuditLog interface {
+	AppendAuditEntry(entry *models.AuditEntry) bool
+}
+
+// Authorizer guards admin routes with the configured keys and records every
+// authenticated request to the audit log
+type Authorizer struct {
+	keys  Keys
+	audit AuditLog
+}
+
+// NewAuthorizer is the constructor for `Authorizer`
+func NewAuthorizer(keys Keys, audit AuditLog) *Authorizer {
+	return &Authorizer{
+		keys:  keys,
+		audit: audit,
+	}
+}
+
+// Require only lets requests through that carry a key with `scope`. The request is
+// recorded under `action` once the handler completes
+func (a *Authorizer) Require(scope Scope, action string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		secret := strings.TrimPrefix(r.Header.Get(AuthHeader), bearerPrefix)
+		key, ok := a.keys.Authenticate(secret)
+		if !ok {
+			log.WithField("remote", r.RemoteAddr).WithField("action", action).Warn("rejected admin credentials")
+			http.Error(w, "invalid admin credentials", http.StatusUnauthorized)
+			return
+		} else if !key.HasScope(scope) {
+			a.record(&models.AuditEntry{
+				Key:    key.Name,
+				Scope:  string(scope),
+				Action: action,
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Status: http.StatusForbidden,
+				Remote: r.RemoteAddr,
+			})
+			http.Error(w, "admin key lacks the "+string(scope)+" scope", http.StatusForbidden)
+			return
+		}
+
+		entry := &models.AuditEntry{
+			Time:   time.Now().UTC(),
+			Key:    key.Name,
+			Scope:  string(scope),
+			Action: action,
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Remote: r.RemoteAddr,
+		}
+		sw := &statusWriter{ResponseWriter: w}
+		next(sw, r.WithContext(context.WithValue(r.Context(), entryContextKey, entry)))
+
+		entry.Status = sw.status
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+		a.record(entry)
+	}
+}
+
+// SetAuditTarget describes what the admin action in `r` operated on, i.e the attendee id
+func SetAuditTarget(r *http.Request, target string) {
+	if entry, ok := r.Context().Value(entryContextKey).(*models.AuditEntry); ok {
+		entry.Target = target
+	}
+}
+
+// KeyName returns the name of the admin key that authorized `r`
+func KeyName(r *http.Request) string {
+	if entry, ok := r.Context().Value(entryContextKey).(*models.AuditEntry); ok {
+		return entry.Key
+	}
+	return ""
+}
+
+func (a *Authorizer) record(entry *models.AuditEntry) {
+	if ok := a.audit.AppendAuditEntry(entry); !ok {
+		log.WithField("key", entry.Key).WithField("action", entry.Action).Error("admin action not audited")
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
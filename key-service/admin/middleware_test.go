@@ -0,0 +1,88 @@
+package admin_test
+
+import (
+	"github.com/eco/longy/key-service/admin"
+	"github.com/eco/longy/key-service/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+type memoryAuditLog struct {
+	entries []models.AuditEntry
+}
+
+func (l *memoryAuditLog) AppendAuditEntry(entry *models.AuditEntry) bool {
+	l.entries = append(l.entries, *entry)
+	return true
+}
+
+var _ = Describe("Admin Authorizer", func() {
+	var (
+		secret = strings.Repeat("s", admin.MinSecretLength)
+		audit  *memoryAuditLog
+		auth   *admin.Authorizer
+		called bool
+	)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		admin.SetAuditTarget(r, "1234")
+		w.WriteHeader(http.StatusCreated)
+	}
+
+	serve := func(header string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/emails", nil)
+		if len(header) > 0 {
+			r.Header.Set(admin.AuthHeader, header)
+		}
+		w := httptest.NewRecorder()
+		auth.Require(admin.ScopeEmailOverride, "email.set", handler)(w, r)
+		return w
+	}
+
+	BeforeEach(func() {
+		called = false
+		audit = &memoryAuditLog{}
+		keys, err := admin.NewKeys([]admin.Key{
+			{Name: "support", Secret: secret, Scopes: []admin.Scope{admin.ScopeEmailOverride}},
+			{Name: "ops", Secret: strings.Repeat("o", admin.MinSecretLength),
+				Scopes: []admin.Scope{admin.ScopeBulkSend}},
+		})
+		Expect(err).To(BeNil())
+		auth = admin.NewAuthorizer(keys, audit)
+	})
+
+	It("should reject requests without credentials", func() {
+		w := serve("")
+		Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		Expect(called).To(BeFalse())
+		Expect(audit.entries).To(BeEmpty())
+	})
+
+	It("should reject and audit keys without the scope", func() {
+		w := serve(strings.Repeat("o", admin.MinSecretLength))
+		Expect(w.Code).To(Equal(http.StatusForbidden))
+		Expect(called).To(BeFalse())
+		Expect(audit.entries).To(HaveLen(1))
+		Expect(audit.entries[0].Key).To(Equal("ops"))
+		Expect(audit.entries[0].Status).To(Equal(http.StatusForbidden))
+	})
+
+	It("should serve and audit keys with the scope", func() {
+		w := serve("Bearer " + secret)
+		Expect(w.Code).To(Equal(http.StatusCreated))
+		Expect(called).To(BeTrue())
+		Expect(audit.entries).To(HaveLen(1))
+
+		entry := audit.entries[0]
+		Expect(entry.Key).To(Equal("support"))
+		Expect(entry.Action).To(Equal("email.set"))
+		Expect(entry.Scope).To(Equal(string(admin.ScopeEmailOverride)))
+		Expect(entry.Target).To(Equal("1234"))
+		Expect(entry.Status).To(Equal(http.StatusCreated))
+		Expect(entry.Path).To(Equal("/emails"))
+	})
+})
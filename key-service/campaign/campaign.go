@@ -0,0 +1,510 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eco/longy/eventbrite"
+	ebSession "github.com/eco/longy/key-service/eventbrite"
+	"github.com/eco/longy/key-service/mail"
+	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/x/longy/client/longyclient"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("module", "campaign")
+
+// campaignLease is the lease electing the instance sending the campaigns of a cluster
+const campaignLease = "campaigns"
+
+var (
+	// ErrInvalidTarget denotes a campaign target that is not supported
+	ErrInvalidTarget = errors.New("target must be one of onboarded, claimed or ids")
+
+	// ErrNoAttendees denotes a campaign without any attendee to email
+	ErrNoAttendees = errors.New("campaign has no attendees")
+
+	// ErrNotFound denotes a campaign that does not exist
+	ErrNotFound = errors.New("campaign not found")
+
+	// ErrNotRunning denotes a campaign that already completed or was cancelled
+	ErrNotRunning = errors.New("campaign is not running")
+
+	// ErrStorage denotes a failure of the backing store
+	ErrStorage = errors.New("campaign storage failed")
+)
+
+// Store is the persistence used by campaigns
+type Store interface {
+	GetAttendeeInfo(id int) ([]byte, error)
+	GetEmail(id int) string
+	GetBlacklistEntry(email string) bool
+	StoreVerificationToken(id int, token string) bool
+
+	StoreCampaign(campaign *models.Campaign) bool
+	GetCampaign(id string) (*models.Campaign, error)
+	GetCampaigns() ([]models.Campaign, error)
+	StoreCampaignResult(result *models.CampaignResult) bool
+	GetCampaignResults(id string) ([]models.CampaignResult, error)
+}
+
+// Lease elects a single instance among the key services sharing the store
+type Lease interface {
+	AcquireLease(name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(name, holder string) error
+}
+
+// Status is a campaign along with the tally of its results
+type Status struct {
+	models.Campaign
+
+	Total   int `json:"total"`
+	Pending int `json:"pending"`
+	Sent    int `json:"sent"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// attendeeInfo is the part of the stored attendee information needed to email them
+type attendeeInfo struct {
+	Profile *eventbrite.AttendeeProfile `json:"attendee"`
+}
+
+// Runner sends the export email of every attendee targeted by a campaign, one email
+// every `interval`. Progress is stored per attendee so that campaigns interrupted by a
+// restart continue where they stopped when `Resume` is called
+type Runner struct {
+	store    Store
+	interval time.Duration
+
+	attendees func() []int
+	claimed   func(id int) (bool, error)
+	send      func(email string, id int, token string) error
+
+	// set by Cluster, campaigns then only run on the instance holding the campaign lease
+	lease      Lease
+	instanceID string
+	ttl        time.Duration
+	done       chan struct{}
+	elected    sync.WaitGroup
+
+	mtx     sync.Mutex
+	leader  bool
+	running map[string]chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewRunner is the constructor for `Runner`. At most `rate` emails are sent per second
+func NewRunner(db *models.DatabaseContext, eb *ebSession.Session, mc mail.Client, chain *longyclient.Client,
+	rate int) *Runner {
+	attendees := func() []int {
+		profiles := eb.GetAttendees()
+		ids := make([]int, 0, len(profiles))
+		for id := range profiles {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	send := func(email string, id int, token string) error {
+		return mc.SendExportEmail(db, email, id, token)
+	}
+
+	claimed := func(id int) (bool, error) {
+		return chain.IsAttendeeClaimed(strconv.Itoa(id))
+	}
+
+	return newRunner(db, rate, attendees, claimed, send)
+}
+
+func newRunner(store Store, rate int, attendees func() []int, claimed func(int) (bool, error),
+	send func(string, int, string) error) *Runner {
+	if rate <= 0 {
+		rate = 1
+	}
+
+	return &Runner{
+		store:    store,
+		interval: time.Second / time.Duration(rate),
+
+		attendees: attendees,
+		claimed:   claimed,
+		send:      send,
+
+		leader:  true,
+		running: make(map[string]chan struct{}),
+	}
+}
+
+// Cluster runs the campaigns of the key services sharing `lease` on the single instance holding
+// the campaign lease, renewed every third of `ttl`. The elected instance resumes the running
+// campaigns, including the ones created on other instances, and stops them once it loses the
+// lease. It replaces `Resume`
+func (r *Runner) Cluster(lease Lease, instanceID string, ttl time.Duration) {
+	r.mtx.Lock()
+	r.lease = lease
+	r.instanceID = instanceID
+	r.ttl = ttl
+	r.done = make(chan struct{})
+	r.leader = false
+	r.mtx.Unlock()
+
+	r.elected.Add(1)
+	go r.elect()
+}
+
+func (r *Runner) elect() {
+	defer r.elected.Done()
+
+	r.mtx.Lock()
+	done := r.done
+	r.mtx.Unlock()
+
+	ticker := time.NewTicker(r.ttl / 3)
+	defer ticker.Stop()
+	for {
+		held, err := r.lease.AcquireLease(campaignLease, r.instanceID, r.ttl)
+		if err != nil {
+			log.WithError(err).Warn("campaign lease unavailable")
+			held = false
+		}
+
+		r.mtx.Lock()
+		wasLeader := r.leader
+		r.leader = held
+		r.mtx.Unlock()
+
+		if held {
+			if !wasLeader {
+				log.WithField("instance", r.instanceID).Info("elected to send the campaigns")
+			}
+			// picks up the campaigns created on the other instances
+			if err := r.Resume(); err != nil {
+				log.WithError(err).Warn("failed to resume the campaigns")
+			}
+		} else if wasLeader {
+			log.WithField("instance", r.instanceID).Info("lost the campaign lease")
+			r.stopAll()
+		}
+
+		select {
+		case <-done:
+			if held {
+				if err := r.lease.ReleaseLease(campaignLease, r.instanceID); err != nil {
+					log.WithError(err).Warn("failed to release the campaign lease")
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Create stores a new campaign and starts sending. For the `ids` target `ids` are the
+// attendees emailed, otherwise every attendee from the ticketing provider is a candidate
+func (r *Runner) Create(target string, ids []int, createdBy string) (*models.Campaign, error) {
+	switch target {
+	case models.CampaignTargetOnboarded, models.CampaignTargetClaimed:
+		ids = r.attendees()
+	case models.CampaignTargetIDs:
+	default:
+		return nil, ErrInvalidTarget
+	}
+
+	ids = dedupe(ids)
+	if len(ids) == 0 {
+		return nil, ErrNoAttendees
+	}
+
+	now := time.Now().UTC()
+	c := &models.Campaign{
+		ID:          fmt.Sprintf("%d", now.UnixNano()),
+		Target:      target,
+		AttendeeIDs: ids,
+		Status:      models.CampaignRunning,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+	}
+	if ok := r.store.StoreCampaign(c); !ok {
+		return nil, ErrStorage
+	}
+
+	// in a cluster the campaign is started by the elected instance
+	if r.isLeader() {
+		r.start(c, nil)
+	}
+	return c, nil
+}
+
+// Resume restarts every campaign that was still running when the service stopped, unless it
+// already runs on this instance. Attendees that already have a result are not emailed again
+func (r *Runner) Resume() error {
+	campaigns, err := r.store.GetCampaigns()
+	if err != nil {
+		return err
+	}
+
+	for i := range campaigns {
+		c := campaigns[i]
+		if c.Status != models.CampaignRunning || r.isRunning(c.ID) {
+			continue
+		}
+
+		results, err := r.store.GetCampaignResults(c.ID)
+		if err != nil {
+			return err
+		}
+		done := make(map[int]bool, len(results))
+		for _, res := range results {
+			done[res.AttendeeID] = true
+		}
+
+		log.WithField("campaign", c.ID).WithField("done", len(done)).Info("resuming campaign")
+		r.start(&c, done)
+	}
+
+	return nil
+}
+
+// Cancel stops the campaign with `id`. Attendees not yet emailed are left without a result. A
+// campaign running on another instance stops before its next email, once it reads the status
+func (r *Runner) Cancel(id string) error {
+	c, err := r.store.GetCampaign(id)
+	if err != nil {
+		return ErrStorage
+	} else if c == nil {
+		return ErrNotFound
+	} else if c.Status != models.CampaignRunning {
+		return ErrNotRunning
+	}
+
+	r.mtx.Lock()
+	if stop, ok := r.running[id]; ok {
+		close(stop)
+		delete(r.running, id)
+	}
+	r.mtx.Unlock()
+
+	c.Status = models.CampaignCancelled
+	if ok := r.store.StoreCampaign(c); !ok {
+		return ErrStorage
+	}
+	return nil
+}
+
+// Status returns the campaign with `id` and the tally of its results
+func (r *Runner) Status(id string) (*Status, error) {
+	c, err := r.store.GetCampaign(id)
+	if err != nil {
+		return nil, ErrStorage
+	} else if c == nil {
+		return nil, ErrNotFound
+	}
+
+	results, err := r.store.GetCampaignResults(id)
+	if err != nil {
+		return nil, ErrStorage
+	}
+
+	status := &Status{Campaign: *c, Total: len(c.AttendeeIDs)}
+	for _, res := range results {
+		switch res.Result {
+		case models.ResultSent:
+			status.Sent++
+		case models.ResultSkipped:
+			status.Skipped++
+		case models.ResultFailed:
+			status.Failed++
+		}
+	}
+	status.Pending = status.Total - len(results)
+
+	return status, nil
+}
+
+// Close stops every running campaign and waits for them to return. Campaigns are left
+// running in the store so they are resumed on the next start
+func (r *Runner) Close() {
+	r.mtx.Lock()
+	done := r.done
+	r.done = nil
+	r.mtx.Unlock()
+
+	if done != nil {
+		close(done)
+		r.elected.Wait()
+	}
+
+	r.stopAll()
+	r.wg.Wait()
+}
+
+func (r *Runner) stopAll() {
+	r.mtx.Lock()
+	for id, stop := range r.running {
+		close(stop)
+		delete(r.running, id)
+	}
+	r.mtx.Unlock()
+}
+
+func (r *Runner) isLeader() bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.leader
+}
+
+func (r *Runner) isRunning(id string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	_, ok := r.running[id]
+	return ok
+}
+
+// isCancelled reads the stored status of the campaign, which another instance may have cancelled
+func (r *Runner) isCancelled(id string) bool {
+	c, err := r.store.GetCampaign(id)
+	return err == nil && c != nil && c.Status != models.CampaignRunning
+}
+
+func (r *Runner) start(c *models.Campaign, done map[int]bool) {
+	stop := make(chan struct{})
+	r.mtx.Lock()
+	r.running[c.ID] = stop
+	r.mtx.Unlock()
+
+	r.wg.Add(1)
+	go r.run(c, done, stop)
+}
+
+func (r *Runner) run(c *models.Campaign, done map[int]bool, stop chan struct{}) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for _, id := range c.AttendeeIDs {
+		if done[id] {
+			continue
+		}
+
+		select {
+		case <-stop:
+			log.WithField("campaign", c.ID).Info("campaign stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if r.isCancelled(c.ID) {
+			log.WithField("campaign", c.ID).Info("campaign cancelled")
+			r.forget(c.ID, stop)
+			return
+		}
+
+		result := r.process(c, id)
+		if ok := r.store.StoreCampaignResult(result); !ok {
+			// the attendee is emailed again if the campaign is resumed
+			log.WithField("campaign", c.ID).WithField("id", id).Error("campaign result not recorded")
+		}
+	}
+
+	// the campaign was stopped or cancelled while the last attendee was processed
+	if !r.forget(c.ID, stop) || r.isCancelled(c.ID) {
+		return
+	}
+
+	c.Status = models.CampaignCompleted
+	if ok := r.store.StoreCampaign(c); !ok {
+		log.WithField("campaign", c.ID).Error("campaign completion not recorded")
+		return
+	}
+	log.WithField("campaign", c.ID).Info("campaign completed")
+}
+
+// forget removes the campaign from the running campaigns if `stop` is still its channel, returning
+// false if it was already stopped
+func (r *Runner) forget(id string, stop chan struct{}) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.running[id] != stop {
+		return false
+	}
+	delete(r.running, id)
+	return true
+}
+
+// process emails the attendee with `id` a fresh export token if the attendee is targeted
+// by `c`
+func (r *Runner) process(c *models.Campaign, id int) *models.CampaignResult {
+	result := func(res, reason string) *models.CampaignResult {
+		return &models.CampaignResult{
+			CampaignID: c.ID,
+			AttendeeID: id,
+			Result:     res,
+			Reason:     reason,
+			Time:       time.Now().UTC(),
+		}
+	}
+
+	infoBz, err := r.store.GetAttendeeInfo(id)
+	if err != nil {
+		return result(models.ResultFailed, "key-service down")
+	} else if len(infoBz) == 0 {
+		return result(models.ResultSkipped, "not onboarded")
+	}
+	var info attendeeInfo
+	if err = json.Unmarshal(infoBz, &info); err != nil || info.Profile == nil {
+		return result(models.ResultFailed, "corrupt attendee information")
+	}
+
+	if c.Target == models.CampaignTargetClaimed {
+		claimed, err := r.claimed(id)
+		if err != nil {
+			return result(models.ResultFailed, "longy chain unreachable")
+		} else if !claimed {
+			return result(models.ResultSkipped, "not claimed")
+		}
+	}
+
+	//check if the email has been changed manually for the attendee
+	email := info.Profile.Email
+	if storedEmail := r.store.GetEmail(id); storedEmail != "" {
+		email = storedEmail
+	}
+	if len(email) == 0 {
+		return result(models.ResultFailed, "no email address")
+	} else if r.store.GetBlacklistEntry(email) {
+		return result(models.ResultSkipped, "blacklisted")
+	}
+
+	token := models.NewVerificationToken()
+	if ok := r.store.StoreVerificationToken(id, token); !ok {
+		return result(models.ResultFailed, "key-service down")
+	}
+
+	if err = r.send(email, id, token); err != nil {
+		return result(models.ResultFailed, err.Error())
+	}
+
+	return result(models.ResultSent, "")
+}
+
+// dedupe returns the unique positive ids in ascending order
+func dedupe(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id < 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+
+	sort.Ints(unique)
+	return unique
+}
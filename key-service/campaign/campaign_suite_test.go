@@ -0,0 +1,12 @@
+package campaign
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestCampaign(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Campaign Suite")
+}
@@ -0,0 +1,326 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/eco/longy/eventbrite"
+	"github.com/eco/longy/key-service/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// memoryStore keeps campaigns and attendee information in memory
+type memoryStore struct {
+	sync.Mutex
+	info      map[int][]byte
+	emails    map[int]string
+	blacklist map[string]bool
+	tokens    map[int]string
+	campaigns map[string]models.Campaign
+	results   map[string]map[int]models.CampaignResult
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		info:      make(map[int][]byte),
+		emails:    make(map[int]string),
+		blacklist: make(map[string]bool),
+		tokens:    make(map[int]string),
+		campaigns: make(map[string]models.Campaign),
+		results:   make(map[string]map[int]models.CampaignResult),
+	}
+}
+
+func (s *memoryStore) onboard(id int, email string) {
+	bz, err := json.Marshal(attendeeInfo{Profile: &eventbrite.AttendeeProfile{ID: id, Email: email}})
+	Expect(err).To(BeNil())
+	s.Lock()
+	s.info[id] = bz
+	s.Unlock()
+}
+
+func (s *memoryStore) GetAttendeeInfo(id int) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.info[id], nil
+}
+
+func (s *memoryStore) GetEmail(id int) string {
+	s.Lock()
+	defer s.Unlock()
+	return s.emails[id]
+}
+
+func (s *memoryStore) GetBlacklistEntry(email string) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.blacklist[email]
+}
+
+func (s *memoryStore) StoreVerificationToken(id int, token string) bool {
+	s.Lock()
+	defer s.Unlock()
+	s.tokens[id] = token
+	return true
+}
+
+func (s *memoryStore) StoreCampaign(c *models.Campaign) bool {
+	s.Lock()
+	defer s.Unlock()
+	s.campaigns[c.ID] = *c
+	return true
+}
+
+func (s *memoryStore) GetCampaign(id string) (*models.Campaign, error) {
+	s.Lock()
+	defer s.Unlock()
+	c, ok := s.campaigns[id]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (s *memoryStore) GetCampaigns() ([]models.Campaign, error) {
+	s.Lock()
+	defer s.Unlock()
+	var campaigns []models.Campaign
+	for _, c := range s.campaigns {
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, nil
+}
+
+func (s *memoryStore) StoreCampaignResult(r *models.CampaignResult) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.results[r.CampaignID] == nil {
+		s.results[r.CampaignID] = make(map[int]models.CampaignResult)
+	}
+	s.results[r.CampaignID][r.AttendeeID] = *r
+	return true
+}
+
+func (s *memoryStore) GetCampaignResults(id string) ([]models.CampaignResult, error) {
+	s.Lock()
+	defer s.Unlock()
+	var results []models.CampaignResult
+	for _, r := range s.results[id] {
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func (s *memoryStore) result(campaignID string, id int) (models.CampaignResult, bool) {
+	s.Lock()
+	defer s.Unlock()
+	r, ok := s.results[campaignID][id]
+	return r, ok
+}
+
+// mailbox records the export emails sent
+type mailbox struct {
+	sync.Mutex
+	sent map[int]string
+	fail map[int]bool
+}
+
+func (m *mailbox) send(email string, id int, token string) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.fail[id] {
+		return errors.New("mail down")
+	}
+	m.sent[id] = email
+	return nil
+}
+
+func (m *mailbox) count() int {
+	m.Lock()
+	defer m.Unlock()
+	return len(m.sent)
+}
+
+// memoryLease is a lease without expiry
+type memoryLease struct {
+	sync.Mutex
+	holder string
+}
+
+func (l *memoryLease) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	l.Lock()
+	defer l.Unlock()
+	if len(l.holder) == 0 {
+		l.holder = holder
+	}
+	return l.holder == holder, nil
+}
+
+func (l *memoryLease) ReleaseLease(name, holder string) error {
+	l.Lock()
+	defer l.Unlock()
+	if l.holder == holder {
+		l.holder = ""
+	}
+	return nil
+}
+
+var _ = Describe("Campaign Runner", func() {
+	var (
+		store   *memoryStore
+		mails   *mailbox
+		claimed map[int]bool
+		runner  *Runner
+	)
+
+	BeforeEach(func() {
+		store = newMemoryStore()
+		mails = &mailbox{sent: make(map[int]string), fail: make(map[int]bool)}
+		claimed = map[int]bool{2: true}
+
+		store.onboard(1, "one@example.com")
+		store.onboard(2, "two@example.com")
+		store.onboard(3, "three@example.com")
+
+		attendees := func() []int { return []int{3, 1, 2, 4} }
+		isClaimed := func(id int) (bool, error) { return claimed[id], nil }
+		runner = newRunner(store, 1000, attendees, isClaimed, mails.send)
+	})
+
+	AfterEach(func() {
+		runner.Close()
+	})
+
+	waitForStatus := func(id, status string) {
+		Eventually(func() string {
+			c, _ := store.GetCampaign(id)
+			return c.Status
+		}, time.Second).Should(Equal(status))
+	}
+
+	It("emails every onboarded attendee", func() {
+		c, err := runner.Create(models.CampaignTargetOnboarded, nil, "ops")
+		Expect(err).To(BeNil())
+		Expect(c.AttendeeIDs).To(Equal([]int{1, 2, 3, 4}))
+		Expect(c.CreatedBy).To(Equal("ops"))
+		waitForStatus(c.ID, models.CampaignCompleted)
+
+		Expect(mails.sent).To(HaveLen(3))
+		Expect(store.tokens).To(HaveLen(3))
+		r, _ := store.result(c.ID, 4)
+		Expect(r.Result).To(Equal(models.ResultSkipped))
+
+		status, err := runner.Status(c.ID)
+		Expect(err).To(BeNil())
+		Expect(status.Total).To(Equal(4))
+		Expect(status.Sent).To(Equal(3))
+		Expect(status.Skipped).To(Equal(1))
+		Expect(status.Pending).To(Equal(0))
+	})
+
+	It("only emails claimed attendees", func() {
+		c, err := runner.Create(models.CampaignTargetClaimed, nil, "ops")
+		Expect(err).To(BeNil())
+		waitForStatus(c.ID, models.CampaignCompleted)
+
+		Expect(mails.sent).To(Equal(map[int]string{2: "two@example.com"}))
+		r, _ := store.result(c.ID, 1)
+		Expect(r.Reason).To(Equal("not claimed"))
+	})
+
+	It("emails specific attendees using the override and skipping blacklisted addresses", func() {
+		store.emails[1] = "override@example.com"
+		store.blacklist["three@example.com"] = true
+
+		c, err := runner.Create(models.CampaignTargetIDs, []int{3, 1, 1}, "ops")
+		Expect(err).To(BeNil())
+		Expect(c.AttendeeIDs).To(Equal([]int{1, 3}))
+		waitForStatus(c.ID, models.CampaignCompleted)
+
+		Expect(mails.sent).To(Equal(map[int]string{1: "override@example.com"}))
+		r, _ := store.result(c.ID, 3)
+		Expect(r.Reason).To(Equal("blacklisted"))
+	})
+
+	It("records failed sends", func() {
+		mails.fail[2] = true
+
+		c, err := runner.Create(models.CampaignTargetIDs, []int{2}, "ops")
+		Expect(err).To(BeNil())
+		waitForStatus(c.ID, models.CampaignCompleted)
+
+		r, _ := store.result(c.ID, 2)
+		Expect(r.Result).To(Equal(models.ResultFailed))
+		Expect(r.Reason).To(Equal("mail down"))
+	})
+
+	It("rejects invalid campaigns", func() {
+		_, err := runner.Create("everyone", nil, "ops")
+		Expect(err).To(Equal(ErrInvalidTarget))
+
+		_, err = runner.Create(models.CampaignTargetIDs, nil, "ops")
+		Expect(err).To(Equal(ErrNoAttendees))
+	})
+
+	It("resumes running campaigns without emailing attendees twice", func() {
+		c := &models.Campaign{
+			ID:          "1",
+			Target:      models.CampaignTargetOnboarded,
+			AttendeeIDs: []int{1, 2, 3},
+			Status:      models.CampaignRunning,
+		}
+		store.StoreCampaign(c)
+		store.StoreCampaignResult(&models.CampaignResult{CampaignID: "1", AttendeeID: 1, Result: models.ResultSent})
+
+		Expect(runner.Resume()).To(BeNil())
+		waitForStatus(c.ID, models.CampaignCompleted)
+
+		Expect(mails.sent).To(HaveLen(2))
+		Expect(mails.sent).ToNot(HaveKey(1))
+	})
+
+	It("stops a cancelled campaign", func() {
+		runner.interval = time.Hour
+
+		c, err := runner.Create(models.CampaignTargetOnboarded, nil, "ops")
+		Expect(err).To(BeNil())
+		Expect(runner.Cancel(c.ID)).To(BeNil())
+		waitForStatus(c.ID, models.CampaignCancelled)
+		Expect(mails.count()).To(Equal(0))
+
+		Expect(runner.Cancel(c.ID)).To(Equal(ErrNotRunning))
+		Expect(runner.Cancel("404")).To(Equal(ErrNotFound))
+	})
+
+	It("stops a campaign cancelled on another instance", func() {
+		runner.interval = 50 * time.Millisecond
+
+		c, err := runner.Create(models.CampaignTargetOnboarded, nil, "ops")
+		Expect(err).To(BeNil())
+		c.Status = models.CampaignCancelled
+		store.StoreCampaign(c)
+
+		Eventually(func() bool { return runner.isRunning(c.ID) }, time.Second).Should(BeFalse())
+		Expect(mails.count()).To(Equal(0))
+		waitForStatus(c.ID, models.CampaignCancelled)
+	})
+
+	It("only sends campaigns from the instance holding the lease", func() {
+		lease := &memoryLease{holder: "other"}
+		runner.Cluster(lease, "self", 30*time.Millisecond)
+
+		c, err := runner.Create(models.CampaignTargetOnboarded, nil, "ops")
+		Expect(err).To(BeNil())
+		Consistently(mails.count, 100*time.Millisecond).Should(Equal(0))
+
+		Expect(lease.ReleaseLease(campaignLease, "other")).To(BeNil())
+		waitForStatus(c.ID, models.CampaignCompleted)
+		Expect(mails.sent).To(HaveLen(3))
+
+		runner.Close()
+		Expect(lease.holder).To(BeEmpty())
+	})
+})
@@ -0,0 +1,311 @@
+package cluster
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/key-service/masterkey"
+	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/util"
+	"github.com/sirupsen/logrus"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+)
+
+var log = logrus.WithField("module", "cluster")
+
+// ErrTimeout denotes a key request the broadcaster did not complete in time
+var ErrTimeout = errors.New("key request timed out")
+
+const (
+	// broadcasterLease is the lease electing the instance broadcasting the key transactions
+	broadcasterLease = "broadcaster"
+
+	// pollInterval is how often the queue is checked for new and completed key requests
+	pollInterval = 500 * time.Millisecond
+
+	// keyTimeout is how long a key request waits for the broadcaster
+	keyTimeout = 2 * time.Minute
+)
+
+// Store is the persistence shared by the key service instances
+type Store interface {
+	AcquireLease(name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(name, holder string) error
+
+	EnqueueKeyRequest(req *models.KeyRequest) (bool, error)
+	GetKeyRequest(address string) (*models.KeyRequest, error)
+	GetQueuedKeyRequests() ([]models.KeyRequest, error)
+	StoreKeyRequest(req *models.KeyRequest) bool
+
+	CreateOnboardRequest(req *models.OnboardRequest) (bool, error)
+	GetOnboardRequest(id int) (*models.OnboardRequest, error)
+	LockOnboardRequest(id int, owner, fingerprint string, until time.Time) (bool, error)
+	CompleteOnboardRequest(id int, owner, response string) (bool, error)
+	UnlockOnboardRequest(id int, owner string) error
+}
+
+// Node is a key service instance sharing the master key with the other instances. Key
+// requests are queued in the store and broadcast by the single instance holding the
+// broadcaster lease, so that the master account sequence is only used by one process
+type Node struct {
+	store    Store
+	id       string
+	ttl      time.Duration
+	poll     time.Duration
+	timeout  time.Duration
+	newKeyer func() (masterkey.Keyer, error)
+
+	// only accessed by the election routine
+	deadline time.Time
+
+	mtx      sync.Mutex
+	keyer    masterkey.Keyer
+	inFlight map[string]bool
+
+	done chan struct{}
+	run  sync.WaitGroup
+	keys sync.WaitGroup
+}
+
+// NewNode is the constructor for `Node`. The instance is identified by `instanceID` in the
+// lease, which is renewed every third of `ttl`. `newKeyer` creates the master key once this
+// instance is elected broadcaster
+func NewNode(store Store, instanceID string, ttl time.Duration, newKeyer func() (masterkey.Keyer, error)) *Node {
+	return newNode(store, instanceID, ttl, pollInterval, keyTimeout, newKeyer)
+}
+
+func newNode(store Store, instanceID string, ttl, poll, timeout time.Duration,
+	newKeyer func() (masterkey.Keyer, error)) *Node {
+	n := &Node{
+		store:    store,
+		id:       instanceID,
+		ttl:      ttl,
+		poll:     poll,
+		timeout:  timeout,
+		newKeyer: newKeyer,
+
+		inFlight: make(map[string]bool),
+		done:     make(chan struct{}),
+	}
+
+	n.run.Add(1)
+	go n.elect()
+
+	log.WithField("instance", instanceID).Info("joined the key service cluster")
+	return n
+}
+
+// SendKeyTransaction queues the MsgKey for the broadcaster and blocks until it was committed.
+// A request already queued for `attendeeAddr` is waited on instead of queueing another one
+func (n *Node) SendKeyTransaction(
+	attendeeAddr sdk.AccAddress,
+	newPublicKey tmcrypto.PubKey,
+	commitment util.Commitment,
+) error {
+	req := &models.KeyRequest{
+		Address:    attendeeAddr.String(),
+		PubKey:     hex.EncodeToString(newPublicKey.Bytes()),
+		Commitment: hex.EncodeToString(commitment),
+	}
+	if _, err := n.store.EnqueueKeyRequest(req); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(n.poll)
+	defer ticker.Stop()
+	timeout := time.NewTimer(n.timeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			return ErrTimeout
+		case <-n.done:
+			return masterkey.ErrClosed
+		}
+
+		req, err := n.store.GetKeyRequest(attendeeAddr.String())
+		if err != nil || req == nil {
+			continue
+		}
+
+		switch req.Status {
+		case models.KeyRequestKeyed:
+			return nil
+		case models.KeyRequestAlreadyKeyed:
+			return masterkey.ErrAlreadyKeyed
+		case models.KeyRequestFailed:
+			return masterkey.ErrTxFailed
+		}
+	}
+}
+
+// Close stops waiting on and broadcasting key requests. The broadcaster lease is released
+// so that another instance takes over right away
+func (n *Node) Close() {
+	close(n.done)
+	n.run.Wait()
+	n.stepDown()
+	n.keys.Wait()
+
+	if err := n.store.ReleaseLease(broadcasterLease, n.id); err != nil {
+		log.WithError(err).Warn("failed to release the broadcaster lease")
+	}
+}
+
+// Leading indicates if this instance is the broadcaster
+func (n *Node) Leading() bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.keyer != nil
+}
+
+// elect renews the lease and broadcasts the queued key requests while it is held
+func (n *Node) elect() {
+	defer n.run.Done()
+
+	renew := time.NewTicker(n.ttl / 3)
+	defer renew.Stop()
+	poll := time.NewTicker(n.poll)
+	defer poll.Stop()
+
+	n.campaign()
+	for {
+		select {
+		case <-renew.C:
+			n.campaign()
+		case <-poll.C:
+			if !n.Leading() {
+				continue
+			}
+			if time.Now().After(n.deadline) {
+				log.Warn("broadcaster lease could not be renewed. stepping down")
+				n.stepDown()
+				continue
+			}
+			n.process()
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// campaign acquires or renews the broadcaster lease. A lease that cannot be renewed because
+// of the store is held until shortly before it expires
+func (n *Node) campaign() {
+	held, err := n.store.AcquireLease(broadcasterLease, n.id, n.ttl)
+	switch {
+	case err != nil:
+		// stepping down is left to the deadline
+	case !held:
+		if n.Leading() {
+			log.Warn("broadcaster lease taken by another instance. stepping down")
+			n.stepDown()
+		}
+	default:
+		n.deadline = time.Now().Add(n.ttl - n.ttl/3)
+		if !n.Leading() {
+			n.lead()
+		}
+	}
+}
+
+func (n *Node) lead() {
+	keyer, err := n.newKeyer()
+	if err != nil {
+		log.WithError(err).Error("failed to create the master key. giving up the broadcaster lease")
+		if err = n.store.ReleaseLease(broadcasterLease, n.id); err != nil {
+			log.WithError(err).Warn("failed to release the broadcaster lease")
+		}
+		return
+	}
+
+	n.mtx.Lock()
+	n.keyer = keyer
+	n.mtx.Unlock()
+	log.WithField("instance", n.id).Info("elected broadcaster")
+}
+
+// stepDown closes the master key. Requests being broadcast stay queued for the next broadcaster
+func (n *Node) stepDown() {
+	n.mtx.Lock()
+	keyer := n.keyer
+	n.keyer = nil
+	n.mtx.Unlock()
+
+	if keyer != nil {
+		keyer.Close()
+	}
+}
+
+// process hands every queued request that is not being broadcast yet to the master key
+func (n *Node) process() {
+	reqs, err := n.store.GetQueuedKeyRequests()
+	if err != nil {
+		return
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	for i := range reqs {
+		if n.keyer == nil || n.inFlight[reqs[i].Address] {
+			continue
+		}
+
+		n.inFlight[reqs[i].Address] = true
+		n.keys.Add(1)
+		go n.key(n.keyer, reqs[i])
+	}
+}
+
+//nolint:gocritic
+func (n *Node) key(keyer masterkey.Keyer, req models.KeyRequest) {
+	defer n.keys.Done()
+	defer func() {
+		n.mtx.Lock()
+		delete(n.inFlight, req.Address)
+		n.mtx.Unlock()
+	}()
+
+	err := sendKeyRequest(keyer, &req)
+	switch err {
+	case nil:
+		req.Status = models.KeyRequestKeyed
+	case masterkey.ErrAlreadyKeyed:
+		req.Status = models.KeyRequestAlreadyKeyed
+	case masterkey.ErrClosed:
+		// stepped down while broadcasting, the next broadcaster picks the request up
+		return
+	default:
+		req.Status = models.KeyRequestFailed
+		req.Error = err.Error()
+	}
+
+	n.store.StoreKeyRequest(&req)
+}
+
+func sendKeyRequest(keyer masterkey.Keyer, req *models.KeyRequest) error {
+	addr, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return fmt.Errorf("address: %s", err)
+	}
+	pubKeyBz, err := hex.DecodeString(req.PubKey)
+	if err != nil {
+		return fmt.Errorf("public key: %s", err)
+	}
+	pubKey, err := cryptoAmino.PubKeyFromBytes(pubKeyBz)
+	if err != nil {
+		return fmt.Errorf("public key: %s", err)
+	}
+	commitment, err := hex.DecodeString(req.Commitment)
+	if err != nil {
+		return fmt.Errorf("commitment: %s", err)
+	}
+
+	return keyer.SendKeyTransaction(addr, pubKey, commitment)
+}
@@ -0,0 +1,315 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/key-service/masterkey"
+	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// memoryStore keeps the lease, the key queue and the onboard requests in memory
+type memoryStore struct {
+	sync.Mutex
+	leases   map[string]models.Lease
+	queue    map[string]models.KeyRequest
+	onboards map[int]models.OnboardRequest
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		leases:   make(map[string]models.Lease),
+		queue:    make(map[string]models.KeyRequest),
+		onboards: make(map[int]models.OnboardRequest),
+	}
+}
+
+func (s *memoryStore) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	if l, ok := s.leases[name]; ok && l.Holder != holder && l.Expires >= now.Unix() {
+		return false, nil
+	}
+	s.leases[name] = models.Lease{Name: name, Holder: holder, Expires: now.Add(ttl).Unix()}
+	return true, nil
+}
+
+func (s *memoryStore) ReleaseLease(name, holder string) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.leases[name].Holder == holder {
+		delete(s.leases, name)
+	}
+	return nil
+}
+
+func (s *memoryStore) holder(name string) string {
+	s.Lock()
+	defer s.Unlock()
+	return s.leases[name].Holder
+}
+
+func (s *memoryStore) EnqueueKeyRequest(req *models.KeyRequest) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if q, ok := s.queue[req.Address]; ok && q.Status == models.KeyRequestQueued {
+		return false, nil
+	}
+	req.Status = models.KeyRequestQueued
+	s.queue[req.Address] = *req
+	return true, nil
+}
+
+func (s *memoryStore) GetKeyRequest(address string) (*models.KeyRequest, error) {
+	s.Lock()
+	defer s.Unlock()
+	req, ok := s.queue[address]
+	if !ok {
+		return nil, nil
+	}
+	return &req, nil
+}
+
+func (s *memoryStore) GetQueuedKeyRequests() ([]models.KeyRequest, error) {
+	s.Lock()
+	defer s.Unlock()
+	var reqs []models.KeyRequest
+	for _, req := range s.queue {
+		if req.Status == models.KeyRequestQueued {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs, nil
+}
+
+func (s *memoryStore) StoreKeyRequest(req *models.KeyRequest) bool {
+	s.Lock()
+	defer s.Unlock()
+	s.queue[req.Address] = *req
+	return true
+}
+
+func (s *memoryStore) CreateOnboardRequest(req *models.OnboardRequest) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.onboards[req.AttendeeID]; ok {
+		return false, nil
+	}
+	s.onboards[req.AttendeeID] = *req
+	return true, nil
+}
+
+func (s *memoryStore) GetOnboardRequest(id int) (*models.OnboardRequest, error) {
+	s.Lock()
+	defer s.Unlock()
+	req, ok := s.onboards[id]
+	if !ok {
+		return nil, nil
+	}
+	return &req, nil
+}
+
+func (s *memoryStore) LockOnboardRequest(id int, owner, fingerprint string, until time.Time) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	req := s.onboards[id]
+	if req.Emailed || req.LockedUntil >= time.Now().Unix() {
+		return false, nil
+	}
+	req.Owner, req.Fingerprint, req.LockedUntil = owner, fingerprint, until.Unix()
+	s.onboards[id] = req
+	return true, nil
+}
+
+func (s *memoryStore) CompleteOnboardRequest(id int, owner, response string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	req := s.onboards[id]
+	if req.Owner != owner {
+		return false, nil
+	}
+	req.Emailed, req.Response, req.LockedUntil = true, response, 0
+	s.onboards[id] = req
+	return true, nil
+}
+
+func (s *memoryStore) UnlockOnboardRequest(id int, owner string) error {
+	s.Lock()
+	defer s.Unlock()
+	req := s.onboards[id]
+	if req.Owner == owner {
+		req.LockedUntil = 0
+		s.onboards[id] = req
+	}
+	return nil
+}
+
+// fakeKeyer records the keyed attendees of the instance it was created by
+type fakeKeyer struct {
+	sync.Mutex
+	keyed  map[string]int
+	err    error
+	closed bool
+}
+
+func (k *fakeKeyer) SendKeyTransaction(addr sdk.AccAddress, _ tmcrypto.PubKey, _ util.Commitment) error {
+	k.Lock()
+	defer k.Unlock()
+	if k.closed {
+		return masterkey.ErrClosed
+	}
+	k.keyed[addr.String()]++
+	if k.keyed[addr.String()] > 1 {
+		return masterkey.ErrAlreadyKeyed
+	}
+	return k.err
+}
+
+func (k *fakeKeyer) Close() {
+	k.Lock()
+	defer k.Unlock()
+	k.closed = true
+}
+
+func (k *fakeKeyer) fail(err error) {
+	k.Lock()
+	defer k.Unlock()
+	k.err = err
+}
+
+func (k *fakeKeyer) isClosed() bool {
+	k.Lock()
+	defer k.Unlock()
+	return k.closed
+}
+
+func (k *fakeKeyer) count(addr sdk.AccAddress) int {
+	k.Lock()
+	defer k.Unlock()
+	return k.keyed[addr.String()]
+}
+
+const ttl = 300 * time.Millisecond
+
+var _ = Describe("Cluster", func() {
+	var (
+		store  *memoryStore
+		keyers map[string]*fakeKeyer
+		mtx    sync.Mutex
+		nodes  []*Node
+	)
+
+	newTestNode := func(id string) *Node {
+		n := newNode(store, id, ttl, 10*time.Millisecond, 2*time.Second, func() (masterkey.Keyer, error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			keyers[id] = &fakeKeyer{keyed: make(map[string]int)}
+			return keyers[id], nil
+		})
+		nodes = append(nodes, n)
+		return n
+	}
+	keyerOf := func(id string) *fakeKeyer {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return keyers[id]
+	}
+
+	newAddress := func() sdk.AccAddress {
+		return sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	}
+	pubKey := secp256k1.GenPrivKey().PubKey()
+	_, commitment := util.CreateCommitment()
+
+	BeforeEach(func() {
+		store = newMemoryStore()
+		keyers = make(map[string]*fakeKeyer)
+		nodes = nil
+	})
+
+	AfterEach(func() {
+		for _, n := range nodes {
+			select {
+			case <-n.done:
+			default:
+				n.Close()
+			}
+		}
+	})
+
+	It("elects a single broadcaster", func() {
+		a, b := newTestNode("a"), newTestNode("b")
+		Eventually(func() bool { return a.Leading() || b.Leading() }).Should(BeTrue())
+		Consistently(func() bool { return a.Leading() && b.Leading() }, ttl).Should(BeFalse())
+		Expect(store.holder(broadcasterLease)).ToNot(BeEmpty())
+	})
+
+	It("keys the requests of every instance through the broadcaster", func() {
+		a, b := newTestNode("a"), newTestNode("b")
+		Eventually(func() bool { return a.Leading() || b.Leading() }).Should(BeTrue())
+		leader, follower := "a", b
+		if b.Leading() {
+			leader, follower = "b", a
+		}
+
+		addr := newAddress()
+		Expect(follower.SendKeyTransaction(addr, pubKey, commitment)).To(BeNil())
+		Expect(keyerOf(leader).count(addr)).To(Equal(1))
+
+		// a retried request is reported as already keyed instead of keying twice
+		Expect(follower.SendKeyTransaction(addr, pubKey, commitment)).To(Equal(masterkey.ErrAlreadyKeyed))
+	})
+
+	It("reports failed key transactions", func() {
+		a := newTestNode("a")
+		Eventually(a.Leading).Should(BeTrue())
+		keyerOf("a").fail(masterkey.ErrTxFailed)
+
+		Expect(a.SendKeyTransaction(newAddress(), pubKey, commitment)).To(Equal(masterkey.ErrTxFailed))
+	})
+
+	It("times out without a broadcaster", func() {
+		Expect(store.AcquireLease(broadcasterLease, "gone", time.Minute)).To(BeTrue())
+		n := newNode(store, "a", ttl, 10*time.Millisecond, 100*time.Millisecond,
+			func() (masterkey.Keyer, error) { return nil, nil })
+		nodes = append(nodes, n)
+
+		Expect(n.SendKeyTransaction(newAddress(), pubKey, commitment)).To(Equal(ErrTimeout))
+		Expect(n.Leading()).To(BeFalse())
+	})
+
+	It("hands the broadcaster role over when the broadcaster closes", func() {
+		a := newTestNode("a")
+		Eventually(a.Leading).Should(BeTrue())
+		b := newTestNode("b")
+		Consistently(b.Leading, ttl/2).Should(BeFalse())
+
+		a.Close()
+		Expect(keyerOf("a").isClosed()).To(BeTrue())
+		Eventually(b.Leading, 2*ttl).Should(BeTrue())
+
+		addr := newAddress()
+		Expect(b.SendKeyTransaction(addr, pubKey, commitment)).To(BeNil())
+		Expect(keyerOf("b").count(addr)).To(Equal(1))
+	})
+
+	It("steps down when the lease is taken", func() {
+		a := newTestNode("a")
+		Eventually(a.Leading).Should(BeTrue())
+
+		store.Lock()
+		store.leases[broadcasterLease] = models.Lease{Name: broadcasterLease, Holder: "b",
+			Expires: time.Now().Add(time.Minute).Unix()}
+		store.Unlock()
+
+		Eventually(a.Leading, ttl).Should(BeFalse())
+		Expect(keyerOf("a").isClosed()).To(BeTrue())
+	})
+})
@@ -0,0 +1,123 @@
+package cluster
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/eco/longy/key-service/models"
+)
+
+var (
+	// ErrOnboarded denotes an attendee that was onboarded with other keys
+	ErrOnboarded = errors.New("attendee onboarded with other keys")
+
+	// ErrInProgress denotes an attendee whose onboarding is being processed by another request
+	ErrInProgress = errors.New("attendee onboarding in progress")
+
+	// ErrStorage denotes a failure of the backing store
+	ErrStorage = errors.New("onboarding storage failed")
+)
+
+// lockTTL is how long a request holds the onboarding of an attendee before a retry may take over
+const lockTTL = 3 * time.Minute
+
+// Claim is the exclusive right of a request to onboard an attendee
+type Claim struct {
+	id    int
+	owner string
+}
+
+// Onboarding makes /key idempotent on the attendee id across every key service instance. A retried
+// request is answered with the response of the request that onboarded the attendee, so that the
+// attendee is never keyed or emailed twice
+type Onboarding struct {
+	store      Store
+	instanceID string
+	lockTTL    time.Duration
+}
+
+// NewOnboarding is the constructor for `Onboarding`. Claims are owned by `instanceID`
+func NewOnboarding(store Store, instanceID string) *Onboarding {
+	return &Onboarding{store: store, instanceID: instanceID, lockTTL: lockTTL}
+}
+
+// Claim hands the onboarding of the attendee with `id` to the caller. `fingerprint` identifies the
+// keys being onboarded. If the attendee was already onboarded with the same keys, no claim and the
+// response of that request are returned. `ErrOnboarded` is returned if other keys were onboarded
+// and `ErrInProgress` if another request holds the claim
+func (o *Onboarding) Claim(id int, fingerprint string) (*Claim, string, error) {
+	claim := &Claim{id: id, owner: o.instanceID + "/" + randomID()}
+	until := time.Now().Add(o.lockTTL)
+
+	req, err := o.store.GetOnboardRequest(id)
+	if err != nil {
+		return nil, "", ErrStorage
+	}
+	if req == nil {
+		var created bool
+		created, err = o.store.CreateOnboardRequest(&models.OnboardRequest{
+			AttendeeID:  id,
+			Fingerprint: fingerprint,
+			Owner:       claim.owner,
+			LockedUntil: until.Unix(),
+			CreatedAt:   time.Now().UTC(),
+		})
+		if err != nil {
+			return nil, "", ErrStorage
+		} else if created {
+			return claim, "", nil
+		}
+	} else {
+		var locked bool
+		locked, err = o.store.LockOnboardRequest(id, claim.owner, fingerprint, until)
+		if err != nil {
+			return nil, "", ErrStorage
+		} else if locked {
+			return claim, "", nil
+		}
+	}
+
+	// onboarded or claimed by a concurrent request
+	req, err = o.store.GetOnboardRequest(id)
+	switch {
+	case err != nil || req == nil:
+		return nil, "", ErrStorage
+	case !req.Emailed:
+		return nil, "", ErrInProgress
+	case req.Fingerprint != fingerprint:
+		return nil, "", ErrOnboarded
+	default:
+		return nil, req.Response, nil
+	}
+}
+
+// Complete records the attendee of `claim` as onboarded. Retries are answered with `response`
+func (o *Onboarding) Complete(claim *Claim, response string) error {
+	completed, err := o.store.CompleteOnboardRequest(claim.id, claim.owner, response)
+	if err != nil {
+		return ErrStorage
+	} else if !completed {
+		// the claim expired and was taken over by a retry
+		log.WithField("id", claim.id).Warn("onboarding completed after the claim expired")
+	}
+
+	return nil
+}
+
+// Release gives up `claim` without onboarding the attendee so that a retry proceeds immediately
+func (o *Onboarding) Release(claim *Claim) {
+	if err := o.store.UnlockOnboardRequest(claim.id, claim.owner); err != nil {
+		log.WithError(err).WithField("id", claim.id).Warn("failed to release the onboarding claim")
+	}
+}
+
+// randomID distinguishes the claims of concurrent requests handled by the same instance
+func randomID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
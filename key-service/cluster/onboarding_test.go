@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Onboarding", func() {
+	var (
+		store *memoryStore
+		ob    *Onboarding
+	)
+
+	BeforeEach(func() {
+		store = newMemoryStore()
+		ob = NewOnboarding(store, "a")
+	})
+
+	It("claims an attendee once", func() {
+		claim, _, err := ob.Claim(1, "keys")
+		Expect(err).To(BeNil())
+		Expect(claim).ToNot(BeNil())
+
+		_, _, err = NewOnboarding(store, "b").Claim(1, "keys")
+		Expect(err).To(Equal(ErrInProgress))
+		_, _, err = ob.Claim(1, "keys")
+		Expect(err).To(Equal(ErrInProgress))
+
+		other, _, err := ob.Claim(2, "keys")
+		Expect(err).To(BeNil())
+		Expect(other).ToNot(BeNil())
+	})
+
+	It("answers retries with the response of the onboarding", func() {
+		claim, _, err := ob.Claim(1, "keys")
+		Expect(err).To(BeNil())
+		Expect(ob.Complete(claim, "a***e@example.com")).To(BeNil())
+
+		retry, response, err := NewOnboarding(store, "b").Claim(1, "keys")
+		Expect(err).To(BeNil())
+		Expect(retry).To(BeNil())
+		Expect(response).To(Equal("a***e@example.com"))
+	})
+
+	It("refuses other keys once onboarded", func() {
+		claim, _, err := ob.Claim(1, "keys")
+		Expect(err).To(BeNil())
+		Expect(ob.Complete(claim, "a***e@example.com")).To(BeNil())
+
+		_, _, err = ob.Claim(1, "other keys")
+		Expect(err).To(Equal(ErrOnboarded))
+	})
+
+	It("lets a retry proceed once a claim is released", func() {
+		claim, _, err := ob.Claim(1, "keys")
+		Expect(err).To(BeNil())
+		ob.Release(claim)
+
+		retry, _, err := ob.Claim(1, "other keys")
+		Expect(err).To(BeNil())
+		Expect(retry).ToNot(BeNil())
+		Expect(store.onboards[1].Fingerprint).To(Equal("other keys"))
+	})
+
+	It("lets a retry take over an expired claim", func() {
+		ob.lockTTL = -time.Second
+		claim, _, err := ob.Claim(1, "keys")
+		Expect(err).To(BeNil())
+
+		ob.lockTTL = time.Minute
+		retry, _, err := ob.Claim(1, "keys")
+		Expect(err).To(BeNil())
+		Expect(retry).ToNot(BeNil())
+
+		// the expired claim can no longer complete the onboarding
+		Expect(ob.Complete(claim, "first")).To(BeNil())
+		Expect(store.onboards[1].Emailed).To(BeFalse())
+		Expect(ob.Complete(retry, "second")).To(BeNil())
+		Expect(store.onboards[1].Response).To(Equal("second"))
+	})
+})
@@ -1,17 +1,22 @@
 package config
 
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+)
+
 type cfg struct {
-	longyRestURL string
+	addressNamespace string
 }
 
 var globalCfg = cfg{}
 
-// SetLongyRestURL -
-func SetLongyRestURL(url string) {
-	globalCfg.longyRestURL = url
+// SetAddressNamespace sets the event namespace the chain derives attendee addresses with
+func SetAddressNamespace(namespace string) {
+	globalCfg.addressNamespace = namespace
 }
 
-// LongyRestURL -
-func LongyRestURL() string {
-	return globalCfg.longyRestURL
+// AttendeeAddress returns the address of the attendee with `id` on the longy chain
+func AttendeeAddress(id string) sdk.AccAddress {
+	return util.AttendeeAddress(globalCfg.addressNamespace, id)
 }
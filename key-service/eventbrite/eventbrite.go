@@ -2,6 +2,8 @@ package eventbrite
 
 import (
 	"github.com/eco/longy/eventbrite"
+	"github.com/eco/longy/key-service/metrics"
+	"github.com/eco/longy/ticketing"
 	"github.com/sirupsen/logrus"
 	"sync"
 	"time"
@@ -11,40 +13,47 @@ var (
 	log = logrus.WithField("module", "eventbrite-session")
 )
 
-// Session to hook into the eventbrite API
+// PollInterval is how often the attendee list is retrieved from the provider
+const PollInterval = 5 * time.Minute
+
+// Session to hook into the ticketing provider of the event
 type Session struct {
 	sync.Mutex
-	ticker *time.Ticker
+	ticker   *time.Ticker
+	provider ticketing.Provider
 
-	numAttendees int
-	attendees    map[int]eventbrite.AttendeeProfile
+	attendees map[int]eventbrite.AttendeeProfile
+	lastPoll  time.Time
 }
 
-// CreateSession to interact iwht the EventBrite Event APIs. The constructed
-// session has a default timeout of 10 seconds
-func CreateSession(eventID int, authToken string) (*Session, error) {
-	log.WithField("event", eventID).
-		Info("eventbrite session created")
+// CreateSession to interact with the attendees of `provider`. The attendee list is
+// polled in 5 minute intervals
+func CreateSession(provider ticketing.Provider) (*Session, error) {
+	log.Info("ticketing session created")
 
-	attendees, err := eventbrite.GetAttendees(eventID, authToken)
+	attendees, err := provider.Attendees()
 	if err != nil {
 		return nil, err
 	}
 
-	log.Infof("retrieved %d attendees from eventbrite", len(attendees))
+	log.Infof("retrieved %d attendees from the ticketing provider", len(attendees))
+	metrics.TicketingPolls.WithLabelValues("ok").Inc()
+	metrics.TicketingAttendees.Set(float64(len(attendees)))
+	metrics.TicketingLastPoll.SetToCurrentTime()
 
-	ticker := time.NewTicker(5 * time.Minute)
-	log.Info("eventbrite polling setup for 5 minute intervals")
+	ticker := time.NewTicker(PollInterval)
+	log.Info("ticketing polling setup for 5 minute intervals")
 
 	session := &Session{
-		Mutex:  sync.Mutex{},
-		ticker: ticker,
+		Mutex:    sync.Mutex{},
+		ticker:   ticker,
+		provider: provider,
 
-		numAttendees: len(attendees),
-		attendees:    make(map[int]eventbrite.AttendeeProfile),
+		attendees: make(map[int]eventbrite.AttendeeProfile),
+		lastPoll:  time.Now(),
 	}
 
-	go session.poll(ticker, eventID, authToken)
+	go session.poll(ticker)
 
 	session.Lock()
 	for i := 0; i < len(attendees); i++ {
@@ -58,45 +67,100 @@ func CreateSession(eventID int, authToken string) (*Session, error) {
 
 // Close will release resources
 func (s *Session) Close() {
-	log.Info("ending polling with eventbrite")
+	log.Info("ending polling with the ticketing provider")
 	s.ticker.Stop()
 }
 
+// LastPoll returns the time the attendee list was last retrieved from the provider
+func (s *Session) LastPoll() time.Time {
+	s.Lock()
+	defer s.Unlock()
+	return s.lastPoll
+}
+
 // AttendeeProfile -
 func (s *Session) AttendeeProfile(id int) (*eventbrite.AttendeeProfile, bool) {
+	s.Lock()
 	profile, ok := s.attendees[id]
+	s.Unlock()
+
 	return &profile, ok
 }
 
-//GetAttendees returns all the attendees from eventbrite
+//GetAttendees returns a copy of all the attendees from the ticketing provider
 func (s *Session) GetAttendees() map[int]eventbrite.AttendeeProfile {
-	return s.attendees
+	s.Lock()
+	defer s.Unlock()
+
+	attendees := make(map[int]eventbrite.AttendeeProfile, len(s.attendees))
+	for id, profile := range s.attendees {
+		attendees[id] = profile
+	}
+	return attendees
 }
 
-func (s *Session) poll(ticker *time.Ticker, eventID int, authToken string) {
+// Refresh re-fetches the attendee with `id` from the provider and updates the cached
+// attendee list without waiting for the next poll. false is returned if the provider
+// does not know about this attendee
+func (s *Session) Refresh(id int) (*eventbrite.AttendeeProfile, bool, error) {
+	profile, found, err := s.provider.Attendee(id)
+	if err != nil || !found {
+		return nil, found, err
+	}
+
+	s.Lock()
+	s.attendees[id] = *profile
+	s.Unlock()
+
+	log.WithField("id", id).Info("refreshed cached attendee")
+	return profile, true, nil
+}
+
+func (s *Session) poll(ticker *time.Ticker) {
 	for range ticker.C {
-		attendees, err := eventbrite.GetAttendees(eventID, authToken)
+		attendees, err := s.provider.Attendees()
+		metrics.TicketingPolls.WithLabelValues(metrics.Result(err)).Inc()
 		if err != nil {
-			log.WithError(err).Warn("error polling eventbrite")
+			log.WithError(err).Warn("error polling the ticketing provider")
 			continue
 		}
+		metrics.TicketingAttendees.Set(float64(len(attendees)))
+		metrics.TicketingLastPoll.SetToCurrentTime()
 
-		if len(attendees) != s.numAttendees {
-			// there are updates
-			newMap := make(map[int]eventbrite.AttendeeProfile)
-			for i := 0; i < len(attendees); i++ {
-				id := attendees[i].ID
-				newMap[id] = attendees[i]
-			}
-
-			s.Lock()
-			s.attendees = newMap
-			s.numAttendees = len(attendees)
-			s.Unlock()
-
+		if s.update(attendees) {
 			log.Info("updated cached attendee list")
 		}
+	}
+}
+
+// update replaces the cached attendee list with the polled `attendees` when the attendee ids differ,
+// returning true if it was replaced
+func (s *Session) update(attendees []eventbrite.AttendeeProfile) bool {
+	newMap := make(map[int]eventbrite.AttendeeProfile, len(attendees))
+	for i := 0; i < len(attendees); i++ {
+		newMap[attendees[i].ID] = attendees[i]
+	}
 
-		// no updates, continue
+	s.Lock()
+	defer s.Unlock()
+	s.lastPoll = time.Now()
+
+	if sameIDs(s.attendees, newMap) {
+		// no updates
+		return false
+	}
+	s.attendees = newMap
+	return true
+}
+
+func sameIDs(a, b map[int]eventbrite.AttendeeProfile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,12 @@
+package eventbrite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestEventbrite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Eventbrite Session Suite")
+}
@@ -0,0 +1,82 @@
+package eventbrite
+
+import (
+	"sync"
+
+	"github.com/eco/longy/eventbrite"
+	"github.com/eco/longy/ticketing"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeProvider returns its attendees and refreshes any attendee id
+type fakeProvider struct {
+	ticketing.RoleMap
+	attendees []eventbrite.AttendeeProfile
+}
+
+func (p *fakeProvider) Attendees() ([]eventbrite.AttendeeProfile, error) {
+	return p.attendees, nil
+}
+
+func (p *fakeProvider) Attendee(id int) (*eventbrite.AttendeeProfile, bool, error) {
+	return &eventbrite.AttendeeProfile{ID: id}, true, nil
+}
+
+var _ = Describe("Session", func() {
+	var session *Session
+
+	profiles := func(ids ...int) []eventbrite.AttendeeProfile {
+		attendees := make([]eventbrite.AttendeeProfile, len(ids))
+		for i, id := range ids {
+			attendees[i] = eventbrite.AttendeeProfile{ID: id}
+		}
+		return attendees
+	}
+
+	BeforeEach(func() {
+		var err error
+		session, err = CreateSession(&fakeProvider{RoleMap: ticketing.DefaultRoleMap(), attendees: profiles(1, 2)})
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		session.Close()
+	})
+
+	It("should not update when the polled attendees are the same", func() {
+		Expect(session.update(profiles(2, 1))).To(BeFalse())
+	})
+
+	It("should update when an attendee is replaced by another", func() {
+		Expect(session.update(profiles(1, 3))).To(BeTrue())
+		_, ok := session.AttendeeProfile(2)
+		Expect(ok).To(BeFalse())
+		_, ok = session.AttendeeProfile(3)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should update when a refreshed attendee is not polled", func() {
+		_, _, err := session.Refresh(3)
+		Expect(err).To(BeNil())
+		Expect(session.update(profiles(1, 2, 4))).To(BeTrue())
+		_, ok := session.AttendeeProfile(4)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should refresh attendees while polling", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func(id int) {
+				defer wg.Done()
+				_, _, _ = session.Refresh(id)
+			}(i)
+			go func() {
+				defer wg.Done()
+				session.update(profiles(1, 2))
+			}()
+		}
+		wg.Wait()
+	})
+})
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/badoux/checkmail"
+	"github.com/eco/longy/key-service/admin"
+	"github.com/eco/longy/key-service/mail"
+	"github.com/eco/longy/key-service/masterkey"
+	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/x/longy/client/longyclient"
+	"github.com/gorilla/mux"
+)
+
+const (
+	emailKey = "email"
+
+	defaultAuditLimit = 100
+)
+
+func registerAdmin(r *mux.Router, auth *admin.Authorizer, db *models.DatabaseContext,
+	mk masterkey.Keyer, mc mail.Client, chain *longyclient.Client) {
+	r.HandleFunc("/admin/audit", auth.Require(admin.ScopeAudit, "audit.get",
+		getAuditLog(db))).Methods(http.MethodGet, http.MethodOptions)
+
+	r.HandleFunc(fmt.Sprintf("/emails/{%s:[0-9]+}/recover", idKey), auth.Require(admin.ScopeRecoveryAssist,
+		"recovery.assist", assistRecovery(db, mk, mc, chain))).Methods(http.MethodPost, http.MethodOptions)
+
+	r.HandleFunc("/emails/blacklist", auth.Require(admin.ScopeBlacklist, "blacklist.list",
+		listBlacklistEntries(db))).Methods(http.MethodGet)
+	r.HandleFunc("/emails/blacklist", auth.Require(admin.ScopeBlacklist, "blacklist.set",
+		setBlacklistEntry(db))).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc(fmt.Sprintf("/emails/blacklist/{%s}", emailKey), auth.Require(admin.ScopeBlacklist,
+		"blacklist.get", getBlacklistEntry(db))).Methods(http.MethodGet, http.MethodOptions)
+	r.HandleFunc(fmt.Sprintf("/emails/blacklist/{%s}", emailKey), auth.Require(admin.ScopeBlacklist,
+		"blacklist.delete", deleteBlacklistEntry(db))).Methods(http.MethodDelete)
+}
+
+// getAuditLog lists the audit log, newest first. The `key`, `action`, `since` (RFC3339)
+// and `limit` query parameters narrow down the result
+func getAuditLog(db *models.DatabaseContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := models.AuditFilter{
+			Key:    query.Get("key"),
+			Action: query.Get("action"),
+			Limit:  defaultAuditLimit,
+		}
+
+		if since := query.Get("since"); len(since) > 0 {
+			t, err := time.Parse(time.RFC3339, since)
+			if err != nil {
+				http.Error(w, "since must be an RFC3339 timestamp", http.StatusBadRequest)
+				return
+			}
+			filter.Since = t
+		}
+		if limit := query.Get("limit"); len(limit) > 0 {
+			l, err := strconv.Atoi(limit)
+			if err != nil || l <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			filter.Limit = l
+		}
+
+		entries, err := db.GetAuditEntries(filter)
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+
+		bz, err := json.Marshal(entries)
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bz)
+	}
+}
+
+// assistRecovery sends the recovery email of an onboarded attendee on their behalf. The
+// email goes to the attendee's address on file, never to the admin
+func assistRecovery(db *models.DatabaseContext, mk masterkey.Keyer, mc mail.Client,
+	chain *longyclient.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars[idKey])
+		if err != nil || id < 0 {
+			http.Error(w, "id expected to be a positive integer", http.StatusBadRequest)
+			return
+		}
+		admin.SetAuditTarget(r, vars[idKey])
+
+		infoBz, err := db.GetAttendeeInfo(id)
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		} else if len(infoBz) == 0 {
+			http.Error(w, "attendee not found", http.StatusNotFound)
+			return
+		}
+		var attendeeInfo AttendeeInfo
+		if err = json.Unmarshal(infoBz, &attendeeInfo); err != nil {
+			http.Error(w, "corrupt attendee information", http.StatusInternalServerError)
+			return
+		}
+
+		keyed, err := chain.IsAttendeeKeyed(strconv.Itoa(id))
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+
+		keyAndEmail(mk, db, mc, id, &attendeeInfo, false, keyed, false)(w, r)
+	}
+}
+
+// listBlacklistEntries lists the email blacklist, newest first. The `reason` query parameter
+// narrows down the result
+func listBlacklistEntries(db *models.DatabaseContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, err := db.GetSuppressions()
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+
+		reason := r.URL.Query().Get("reason")
+		filtered := []models.Suppression{}
+		for _, e := range entries {
+			if len(reason) == 0 || e.Reason == reason {
+				filtered = append(filtered, e)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, filtered)
+	}
+}
+
+// setBlacklistEntry blacklists or clears an address. The reason defaults to `manual` and
+// the admin key is recorded as the source
+func setBlacklistEntry(db *models.DatabaseContext) http.HandlerFunc {
+	type reqBody struct {
+		Email       string `json:"email"`
+		Blacklisted bool   `json:"blacklisted"`
+		Reason      string `json:"reason"`
+		Detail      string `json:"detail"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body reqBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("request body invalid: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		email := strings.TrimSpace(body.Email)
+		if err := checkmail.ValidateFormat(email); err != nil {
+			http.Error(w, "email address is empty or invalid", http.StatusBadRequest)
+			return
+		}
+		admin.SetAuditTarget(r, email)
+
+		reason := body.Reason
+		if len(reason) == 0 {
+			reason = models.SuppressionManual
+		}
+		entry := &models.Suppression{
+			Email:       email,
+			Blacklisted: body.Blacklisted,
+			Reason:      reason,
+			Detail:      body.Detail,
+			Source:      "admin:" + admin.KeyName(r),
+		}
+		if ok := db.StoreSuppression(entry); !ok {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// getBlacklistEntry returns the blacklist entry of an address. Addresses without an entry
+// are reported as not blacklisted
+func getBlacklistEntry(db *models.DatabaseContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := mux.Vars(r)[emailKey]
+		admin.SetAuditTarget(r, email)
+
+		entry, err := db.GetSuppression(email)
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		} else if entry == nil {
+			entry = &models.Suppression{Email: email}
+		}
+
+		writeJSON(w, http.StatusOK, entry)
+	}
+}
+
+// deleteBlacklistEntry removes the blacklist entry of an address so that it is emailed again
+func deleteBlacklistEntry(db *models.DatabaseContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := mux.Vars(r)[emailKey]
+		admin.SetAuditTarget(r, email)
+
+		if ok := db.DeleteSuppression(email); !ok {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/eco/longy/key-service/admin"
+	"github.com/eco/longy/key-service/campaign"
+	"github.com/eco/longy/key-service/models"
+	"github.com/gorilla/mux"
+)
+
+const (
+	campaignIDKey = "campaign_id"
+)
+
+func registerCampaign(r *mux.Router, auth *admin.Authorizer, db *models.DatabaseContext, runner *campaign.Runner) {
+	s := r.PathPrefix("/emails/campaigns").Subrouter()
+	s.HandleFunc("", auth.Require(admin.ScopeBulkSend, "campaign.create",
+		createCampaign(runner))).Methods(http.MethodPost, http.MethodOptions)
+	s.HandleFunc("", auth.Require(admin.ScopeBulkSend, "campaign.list",
+		getCampaigns(db))).Methods(http.MethodGet, http.MethodOptions)
+	s.HandleFunc(fmt.Sprintf("/{%s:[0-9]+}", campaignIDKey), auth.Require(admin.ScopeBulkSend, "campaign.get",
+		getCampaignStatus(runner))).Methods(http.MethodGet, http.MethodOptions)
+	s.HandleFunc(fmt.Sprintf("/{%s:[0-9]+}/results", campaignIDKey), auth.Require(admin.ScopeBulkSend,
+		"campaign.results", getCampaignResults(db))).Methods(http.MethodGet, http.MethodOptions)
+	s.HandleFunc(fmt.Sprintf("/{%s:[0-9]+}/cancel", campaignIDKey), auth.Require(admin.ScopeBulkSend,
+		"campaign.cancel", cancelCampaign(runner))).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// createCampaign starts emailing export links to the targeted attendees. The request
+// returns once the campaign is stored, progress is available through the status route
+func createCampaign(runner *campaign.Runner) http.HandlerFunc {
+	type reqBody struct {
+		Target      string `json:"target"`
+		AttendeeIDs []int  `json:"attendee_ids"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body reqBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("request body invalid: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		c, err := runner.Create(body.Target, body.AttendeeIDs, admin.KeyName(r))
+		switch err {
+		case nil:
+		case campaign.ErrInvalidTarget, campaign.ErrNoAttendees:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+		admin.SetAuditTarget(r, c.ID)
+
+		writeJSON(w, http.StatusCreated, c)
+	}
+}
+
+func getCampaigns(db *models.DatabaseContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		campaigns, err := db.GetCampaigns()
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, campaigns)
+	}
+}
+
+func getCampaignStatus(runner *campaign.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)[campaignIDKey]
+		admin.SetAuditTarget(r, id)
+
+		status, err := runner.Status(id)
+		switch err {
+		case nil:
+			writeJSON(w, http.StatusOK, status)
+		case campaign.ErrNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func getCampaignResults(db *models.DatabaseContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)[campaignIDKey]
+		admin.SetAuditTarget(r, id)
+
+		c, err := db.GetCampaign(id)
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		} else if c == nil {
+			http.Error(w, campaign.ErrNotFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		results, err := db.GetCampaignResults(id)
+		if err != nil {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+		if results == nil {
+			results = []models.CampaignResult{}
+		}
+
+		writeJSON(w, http.StatusOK, results)
+	}
+}
+
+func cancelCampaign(runner *campaign.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)[campaignIDKey]
+		admin.SetAuditTarget(r, id)
+
+		switch err := runner.Cancel(id); err {
+		case nil:
+			w.WriteHeader(http.StatusOK)
+		case campaign.ErrNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case campaign.ErrNotRunning:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(bz)
+}
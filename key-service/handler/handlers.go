@@ -1,11 +1,18 @@
 package handler
 
 import (
+	"github.com/eco/longy/key-service/admin"
+	"github.com/eco/longy/key-service/campaign"
+	"github.com/eco/longy/key-service/cluster"
 	"github.com/eco/longy/key-service/eventbrite"
+	"github.com/eco/longy/key-service/health"
 	"github.com/eco/longy/key-service/mail"
 	"github.com/eco/longy/key-service/masterkey"
 	"github.com/eco/longy/key-service/middleware"
 	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/key-service/session"
+	"github.com/eco/longy/key-service/suppression"
+	"github.com/eco/longy/x/longy/client/longyclient"
 	"github.com/eco/longy/x/longy/client/rest"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -14,23 +21,41 @@ import (
 
 var log = logrus.WithField("module", "handler")
 
-// Router returns the root http Handler
+// Router returns the root http Handler. Admin routes are guarded by `keys` and audited to `db`.
+// Attendee sessions are issued and verified by `issuer`. Attendees are keyed by `mk` and their
+// onboarding is made idempotent by `ob`. The chain is queried through `chain` and the dependencies
+// are reported by `checker`. Bounce and complaint notifications are handled by `suppressions`
 func Router(
 	eb *eventbrite.Session,
-	mk *masterkey.MasterKey,
+	mk masterkey.Keyer,
+	ob *cluster.Onboarding,
 	db *models.DatabaseContext,
-	mc mail.Client) http.Handler {
+	mc mail.Client,
+	runner *campaign.Runner,
+	issuer *session.Issuer,
+	keys admin.Keys,
+	chain *longyclient.Client,
+	checker *health.Checker,
+	suppressions *suppression.Receiver) http.Handler {
+
+	auth := admin.NewAuthorizer(keys, db)
 
 	r := mux.NewRouter()
 	// IMPORTANT: you must specify an OPTIONS method matcher for the middleware to set CORS headers
 	r.Use(mux.CORSMethodMiddleware(r))
 	r.Use(rest.CorsMiddleware)
+	r.Use(middleware.Metrics)
 
 	registerPing(r)
-	registerKey(r, eb, mk, db, mc)
-	registerEmailManual(r, db, eb, mc)
-	registerInfo(r, db, mc)
+	registerHealth(r, checker)
+	registerKey(r, eb, mk, ob, db, mc, chain)
+	registerEmailManual(r, auth, db, eb)
+	registerCampaign(r, auth, db, runner)
+	registerAdmin(r, auth, db, mk, mc, chain)
+	registerSession(r, issuer)
+	registerInfo(r, db, mc, issuer)
 	registerIDToAddress(r)
+	registerWebhook(r, eb, suppressions)
 
 	return middleware.LogHTTP(r)
 }
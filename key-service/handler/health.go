@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/eco/longy/key-service/health"
+	"github.com/gorilla/mux"
+)
+
+func registerHealth(r *mux.Router, checker *health.Checker) {
+	r.HandleFunc("/healthz", healthz(checker)).Methods(http.MethodGet, http.MethodOptions)
+	r.HandleFunc("/readyz", readyz(checker)).Methods(http.MethodGet, http.MethodOptions)
+}
+
+// healthz reports the status of every dependency. The key service is alive as long as it
+// answers, so the response is always OK
+func healthz(checker *health.Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, checker.Report())
+	}
+}
+
+// readyz reports the status of every dependency and fails if the key service cannot serve
+// requests without one of them
+func readyz(checker *health.Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Report()
+		if !report.Ready() {
+			writeJSON(w, http.StatusServiceUnavailable, report)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	}
+}
@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"github.com/eco/longy/util"
+	ksCfg "github.com/eco/longy/key-service/config"
 	"github.com/gorilla/mux"
 	"net/http"
 )
@@ -15,7 +15,7 @@ func registerIDToAddress(r *mux.Router) {
 func idToAddress() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := mux.Vars(r)["id"]
-		addr := util.IDToAddress(id).String()
+		addr := ksCfg.AttendeeAddress(id).String()
 
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(addr))
@@ -5,16 +5,19 @@ import (
 	"fmt"
 	"github.com/eco/longy/key-service/mail"
 	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/key-service/session"
 	"github.com/gorilla/mux"
 	"net/http"
 )
 
-func registerInfo(r *mux.Router, db *models.DatabaseContext, mc mail.Client) {
-	r.HandleFunc("/sendEmail", sendEmailToAttendee(db, mc)).Methods(http.MethodPost, http.MethodOptions)
+func registerInfo(r *mux.Router, db *models.DatabaseContext, mc mail.Client, issuer *session.Issuer) {
+	r.HandleFunc("/sendEmail", sendEmailToAttendee(db, mc, issuer)).Methods(http.MethodPost, http.MethodOptions)
 }
 
+// sendEmailToAttendee emails shared info to the attendee. The attendee authenticates with
+// a badge session or, without one, with the verification token sent to their email
 //nolint:gocyclo
-func sendEmailToAttendee(db *models.DatabaseContext, mc mail.Client) func(
+func sendEmailToAttendee(db *models.DatabaseContext, mc mail.Client, issuer *session.Issuer) func(
 	http.ResponseWriter, *http.Request) {
 	type sendBody struct {
 		ID    int    `json:"id"`
@@ -47,20 +50,27 @@ func sendEmailToAttendee(db *models.DatabaseContext, mc mail.Client) func(
 			return
 		}
 
-		expectedToken, err := db.GetVerificationToken(info.Profile.ID)
-		if err != nil {
-			http.Error(w, "could not retrieve expected token for account", http.StatusServiceUnavailable)
-			return
-		}
+		if id, ok := sessionAttendee(r, issuer); ok {
+			if id != info.Profile.ID {
+				http.Error(w, "session belongs to another attendee", http.StatusForbidden)
+				return
+			}
+		} else {
+			expectedToken, err := db.GetVerificationToken(info.Profile.ID)
+			if err != nil {
+				http.Error(w, "could not retrieve expected token for account", http.StatusServiceUnavailable)
+				return
+			}
 
-		if len(expectedToken) == 0 {
-			http.Error(w, "attendee has no auth token stored", http.StatusUnauthorized)
-			return
-		}
+			if len(expectedToken) == 0 {
+				http.Error(w, "attendee has no auth token stored", http.StatusUnauthorized)
+				return
+			}
 
-		if expectedToken != sb.Token {
-			http.Error(w, "incorrect auth token", http.StatusUnauthorized)
-			return
+			if expectedToken != sb.Token {
+				http.Error(w, "incorrect auth token", http.StatusUnauthorized)
+				return
+			}
 		}
 
 		//check if the email has been changed manually for the attendee
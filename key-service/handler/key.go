@@ -1,21 +1,23 @@
 package handler
 
 import (
-	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/eco/longy/eventbrite"
+	"github.com/eco/longy/key-service/cluster"
+	ksCfg "github.com/eco/longy/key-service/config"
 	ebSession "github.com/eco/longy/key-service/eventbrite"
-	longyClnt "github.com/eco/longy/key-service/longyclient"
 	"github.com/eco/longy/key-service/mail"
 	"github.com/eco/longy/key-service/masterkey"
 	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/x/longy/client/longyclient"
 	"github.com/eco/longy/util"
 	"github.com/gorilla/mux"
 )
@@ -40,13 +42,15 @@ type AttendeeInfo struct {
 func registerKey(
 	r *mux.Router,
 	eb *ebSession.Session,
-	mk *masterkey.MasterKey,
+	mk masterkey.Keyer,
+	ob *cluster.Onboarding,
 	db *models.DatabaseContext,
-	mc mail.Client) {
+	mc mail.Client,
+	chain *longyclient.Client) {
 
 	// POST
-	r.HandleFunc("/key", key(eb, mk, db, mc)).Methods(http.MethodPost, http.MethodOptions)
-	r.HandleFunc("/recover", keyRecover(db, mk, mc)).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc("/key", key(eb, mk, ob, db, mc)).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc("/recover", keyRecover(db, mk, mc, chain)).Methods(http.MethodPost, http.MethodOptions)
 
 	// GET
 	r.HandleFunc("/recover/{id}/{token}", keyRetrieval(db)).Methods(http.MethodGet, http.MethodOptions)
@@ -54,10 +58,12 @@ func registerKey(
 
 // All core logic is implemented here. If there are plans to expand this service,
 // logic (email retrieval, etc) can be lifted into http middleware to allow for better
-// composability
+// composability. Requests are idempotent on the attendee id, a retry is answered with the
+// response of the request that onboarded the attendee
 //nolint: gocyclo, gocritic
 func key(eb *ebSession.Session,
-	mk *masterkey.MasterKey,
+	mk masterkey.Keyer,
+	ob *cluster.Onboarding,
 	db *models.DatabaseContext,
 	mc mail.Client) http.HandlerFunc {
 	type reqBody struct {
@@ -92,67 +98,130 @@ func key(eb *ebSession.Session,
 			return
 		}
 
-		/** Check if this attendee already has info registered **/
-		infoBz, err := db.GetAttendeeInfo(body.AttendeeID)
+		/** Sanity check on the private key **/
+		_, err := util.Secp256k1FromHex(body.CosmosPrivateKey)
 		if err != nil {
-			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			http.Error(w, fmt.Sprintf("bad cosmos private key: %s", err), http.StatusBadRequest)
 			return
-		} else if len(infoBz) != 0 {
+		}
+
+		/** Claim the onboarding of this attendee so that retries never key or email twice **/
+		fingerprint := keyFingerprint(body.CosmosPrivateKey, body.RSAPrivateKey, body.RSAPublicKey,
+			body.UseVerification)
+		claim, response, err := ob.Claim(body.AttendeeID, fingerprint)
+		switch {
+		case err == cluster.ErrOnboarded:
 			http.Error(w, "attendee info onboarded. use /recover instead", http.StatusConflict)
 			return
+		case err == cluster.ErrInProgress:
+			http.Error(w, "attendee onboarding in progress. try again", http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		case claim == nil:
+			// retry of a request that onboarded the attendee
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(response)) //nolint
+			return
 		}
+		completed := false
+		defer func() {
+			if !completed {
+				ob.Release(claim)
+			}
+		}()
 
-		/** Sanity check on the private key **/
-		_, err = util.Secp256k1FromHex(body.CosmosPrivateKey)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("bad cosmos private key: %s", err), http.StatusBadRequest)
+		/** Check if this attendee already has info registered **/
+		info, status, msg := onboardingInfo(db, body.AttendeeID, profile,
+			body.CosmosPrivateKey, body.RSAPrivateKey, body.RSAPublicKey)
+		if info == nil {
+			http.Error(w, msg, status)
 			return
 		}
 
-		/** Store the attendee information **/
-
-		imageUploadURL, err := db.GetImageUploadURL(body.AttendeeID)
-		if err != nil {
-			http.Error(w, "failed to sign image upload URL", http.StatusServiceUnavailable)
+		// the account is keyed only if a previous attempt failed after keying it, which is
+		// reported as already keyed
+		keyed := false
+		maskedEmail, status, msg := sendKeyAndEmail(mk, db, mc,
+			body.AttendeeID, info, true, keyed, body.UseVerification)
+		if status != http.StatusOK {
+			http.Error(w, msg, status)
 			return
 		}
 
-		// generate the unique <secret / commitment> pair for this attendee
-		secret, commitment := util.CreateCommitment()
-		info := &AttendeeInfo{
-			Address: util.IDToAddress(fmt.Sprintf("%d", body.AttendeeID)),
-			Profile: profile,
+		completed = true
+		if err = ob.Complete(claim, maskedEmail); err != nil {
+			log.WithError(err).WithField("id", body.AttendeeID).Error("failed to record the onboarding")
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(maskedEmail)) //nolint
+	}
+}
+
+// onboardingInfo returns the attendee information to onboard the attendee with. Information stored
+// by a failed attempt with the same keys is reused, so that the attendee keeps the commitment it
+// may already be keyed with. Otherwise the http status and message of the failure are returned
+//nolint:gocritic
+func onboardingInfo(
+	db *models.DatabaseContext,
+	id int,
+	profile *eventbrite.AttendeeProfile,
+	cosmosPrivateKey, rsaPrivateKey, rsaPublicKey string,
+) (*AttendeeInfo, int, string) {
+	infoBz, err := db.GetAttendeeInfo(id)
+	if err != nil {
+		return nil, http.StatusServiceUnavailable, "key-service down"
+	} else if len(infoBz) != 0 {
+		var info AttendeeInfo
+		if err = json.Unmarshal(infoBz, &info); err != nil {
+			return nil, http.StatusInternalServerError, "corrupt attendee information"
+		} else if info.CosmosPrivateKey != cosmosPrivateKey || info.RSAPrivateKey != rsaPrivateKey ||
+			info.RSAPublicKey != rsaPublicKey {
+			return nil, http.StatusConflict, "attendee info onboarded. use /recover instead"
+		}
+		return &info, http.StatusOK, ""
+	}
+
+	/** Store the attendee information **/
 
-			CosmosPrivateKey: body.CosmosPrivateKey,
-			RSAPrivateKey:    body.RSAPrivateKey,
-			RSAPublicKey:     body.RSAPublicKey,
+	imageUploadURL, err := db.GetImageUploadURL(id)
+	if err != nil {
+		return nil, http.StatusServiceUnavailable, "failed to sign image upload URL"
+	}
 
-			CommitmentSecret: secret,
-			Commitment:       commitment,
+	// generate the unique <secret / commitment> pair for this attendee
+	secret, commitment := util.CreateCommitment()
+	info := &AttendeeInfo{
+		Address: ksCfg.AttendeeAddress(fmt.Sprintf("%d", id)),
+		Profile: profile,
 
-			ImageUploadURL: imageUploadURL,
-		}
-		bz, err := json.Marshal(info)
-		if err != nil {
-			log.WithError(err).WithField("data", info).Error("marshaling attendee info")
-			http.Error(w, "key storage service down", http.StatusInternalServerError)
-			return
-		}
-		if ok := db.StoreAttendeeInfo(body.AttendeeID, bz); !ok {
-			http.Error(w, "key storage service down", http.StatusServiceUnavailable)
-			return
-		}
+		CosmosPrivateKey: cosmosPrivateKey,
+		RSAPrivateKey:    rsaPrivateKey,
+		RSAPublicKey:     rsaPublicKey,
 
-		keyed := false // attendee information stored for the first time. Account could not have been keyed prior
-		keyAndEmail(mk, db, mc,
-			body.AttendeeID, info, true, keyed, body.UseVerification)(w, r)
+		CommitmentSecret: secret,
+		Commitment:       commitment,
+
+		ImageUploadURL: imageUploadURL,
+	}
+	bz, err := json.Marshal(info)
+	if err != nil {
+		log.WithError(err).WithField("data", info).Error("marshaling attendee info")
+		return nil, http.StatusInternalServerError, "key storage service down"
+	}
+	if ok := db.StoreAttendeeInfo(id, bz); !ok {
+		return nil, http.StatusServiceUnavailable, "key storage service down"
 	}
+
+	return info, http.StatusOK, ""
 }
 
 func keyRecover(
 	db *models.DatabaseContext,
-	mk *masterkey.MasterKey,
-	mc mail.Client) http.HandlerFunc {
+	mk masterkey.Keyer,
+	mc mail.Client,
+	chain *longyclient.Client) http.HandlerFunc {
 	type reqBody struct {
 		AttendeeID      int  `json:"attendee_id"`
 		UseVerification bool `json:"use_verification"`
@@ -185,7 +254,7 @@ func keyRecover(
 			return
 		}
 
-		keyed, err := longyClnt.IsAttendeeKeyed(body.AttendeeID)
+		keyed, err := chain.IsAttendeeKeyed(strconv.Itoa(body.AttendeeID))
 		if err != nil {
 			http.Error(w, "key-service down", http.StatusServiceUnavailable)
 			return
@@ -196,10 +265,10 @@ func keyRecover(
 	}
 }
 
-// helper used by both `key` and `keyRecover`
-//nolint:gocyclo
+// helper used by both `keyRecover` and admin assisted recovery
+//nolint:gocritic
 func keyAndEmail(
-	mk *masterkey.MasterKey,
+	mk masterkey.Keyer,
 	db *models.DatabaseContext,
 	mc mail.Client,
 
@@ -208,60 +277,76 @@ func keyAndEmail(
 	onboarding, keyed, useVerification bool,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		idStr := fmt.Sprintf("%d", id)
-
-		// key the attendee if required
-		if !keyed {
-			privKey, err := util.Secp256k1FromHex(info.CosmosPrivateKey)
-			if err != nil {
-				http.Error(w, "corrupt attendee private key", http.StatusInternalServerError)
-				return
-			}
-			err = mk.SendKeyTransaction(util.IDToAddress(idStr), privKey.PubKey(), info.Commitment)
-			if err != nil && err != masterkey.ErrAlreadyKeyed {
-				http.Error(w, "internal error. try again", http.StatusInternalServerError)
-				return
-			}
+		maskedEmail, status, msg := sendKeyAndEmail(mk, db, mc, id, info, onboarding, keyed, useVerification)
+		if status != http.StatusOK {
+			http.Error(w, msg, status)
+			return
 		}
 
-		// unique token to retrieve stored info. Store this token if we are `useVerification` or `!onboarding`.
-		// !onboarding indicates this was instantiated via recovery
-		token := generateVerificationToken()
-		if useVerification || !onboarding {
-			if ok := db.StoreVerificationToken(id, token); !ok {
-				http.Error(w, "key-service down", http.StatusServiceUnavailable)
-				return
-			}
-		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(maskedEmail)) //nolint
+	}
+}
+
+// sendKeyAndEmail keys the attendee if required and emails them. The masked email is returned on
+// success, the http status and message of the failure otherwise
+//nolint:gocyclo,gocritic
+func sendKeyAndEmail(
+	mk masterkey.Keyer,
+	db *models.DatabaseContext,
+	mc mail.Client,
+
+	id int,
+	info *AttendeeInfo,
+	onboarding, keyed, useVerification bool,
+) (string, int, string) {
+	idStr := fmt.Sprintf("%d", id)
 
-		// send the email
-		var err error
-		//check if the email has been changed manually for the attendee
-		storedEmail := db.GetEmail(info.Profile.ID)
-		if storedEmail != "" {
-			info.Profile.Email = storedEmail
+	// key the attendee if required
+	if !keyed {
+		privKey, err := util.Secp256k1FromHex(info.CosmosPrivateKey)
+		if err != nil {
+			return "", http.StatusInternalServerError, "corrupt attendee private key"
 		}
-		if useVerification {
-			err = mc.SendVerificationEmail(db, info.Profile.Email, token)
-		} else {
-			if onboarding {
-				// onboarding email
-				err = mc.SendOnboardingEmail(db, info.Address, info.Profile, info.CommitmentSecret, info.ImageUploadURL)
-			} else {
-				// recovery email
-				err = mc.SendRecoveryEmail(db, info.Profile, idStr, token)
-			}
+		err = mk.SendKeyTransaction(ksCfg.AttendeeAddress(idStr), privKey.PubKey(), info.Commitment)
+		if err != nil && err != masterkey.ErrAlreadyKeyed {
+			return "", http.StatusInternalServerError, "internal error. try again"
 		}
+	}
 
-		if err != nil {
-			http.Error(w, "email error. try again", http.StatusServiceUnavailable)
-			return
+	// unique token to retrieve stored info. Store this token if we are `useVerification` or `!onboarding`.
+	// !onboarding indicates this was instantiated via recovery
+	token := models.NewVerificationToken()
+	if useVerification || !onboarding {
+		if ok := db.StoreVerificationToken(id, token); !ok {
+			return "", http.StatusServiceUnavailable, "key-service down"
 		}
+	}
 
-		maskedEmail := maskEmail(info.Profile.Email)
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(maskedEmail)) //nolint
+	// send the email
+	var err error
+	//check if the email has been changed manually for the attendee
+	storedEmail := db.GetEmail(info.Profile.ID)
+	if storedEmail != "" {
+		info.Profile.Email = storedEmail
+	}
+	if useVerification {
+		err = mc.SendVerificationEmail(db, info.Profile.Email, token)
+	} else {
+		if onboarding {
+			// onboarding email
+			err = mc.SendOnboardingEmail(db, info.Address, info.Profile, info.CommitmentSecret, info.ImageUploadURL)
+		} else {
+			// recovery email
+			err = mc.SendRecoveryEmail(db, info.Profile, idStr, token)
+		}
+	}
+
+	if err != nil {
+		return "", http.StatusServiceUnavailable, "email error. try again"
 	}
+
+	return maskEmail(info.Profile.Email), http.StatusOK, ""
 }
 
 // retrieve attendee information with the given verification token
@@ -304,20 +389,14 @@ func keyRetrieval(db *models.DatabaseContext) http.HandlerFunc {
 
 /** Helpers **/
 
-// used to generate a verification code 6 digits in length
-var table = [10]byte{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}
-
-func generateVerificationToken() string {
-	b := make([]byte, 6)
-	n, err := io.ReadAtLeast(rand.Reader, b, 6)
-	if n != 6 {
-		panic(err)
-	}
-
-	for i := 0; i < len(b); i++ {
-		b[i] = table[int(b[i])%len(table)]
+// keyFingerprint identifies the keys of a /key request without storing them again
+func keyFingerprint(cosmosPrivateKey, rsaPrivateKey, rsaPublicKey string, useVerification bool) string {
+	h := sha256.New()
+	for _, s := range []string{cosmosPrivateKey, rsaPrivateKey, rsaPublicKey, fmt.Sprintf("%t", useVerification)} {
+		h.Write([]byte(s)) //nolint
+		h.Write([]byte{0}) //nolint
 	}
-	return string(b)
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 func maskEmail(email string) string {
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/eco/longy/key-service/session"
+	"github.com/gorilla/mux"
+)
+
+const (
+	sessionHeader = "Authorization"
+	bearerPrefix  = "Bearer "
+)
+
+func registerSession(r *mux.Router, issuer *session.Issuer) {
+	r.HandleFunc("/session/challenge", sessionChallenge(issuer)).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc("/session", signIn(issuer)).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// sessionChallenge returns the sign doc an attendee signs with their badge key to sign in
+func sessionChallenge(issuer *session.Issuer) http.HandlerFunc {
+	type reqBody struct {
+		AttendeeID int `json:"attendee_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body reqBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("request body invalid: %s", err), http.StatusBadRequest)
+			return
+		} else if body.AttendeeID < 0 {
+			http.Error(w, "attendee id must be a positive integer", http.StatusBadRequest)
+			return
+		}
+
+		doc, err := issuer.Challenge(body.AttendeeID)
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, doc)
+	}
+}
+
+// signIn exchanges a signed challenge for a session token
+func signIn(issuer *session.Issuer) http.HandlerFunc {
+	type reqBody struct {
+		AttendeeID int    `json:"attendee_id"`
+		Nonce      string `json:"nonce"`
+		Sig        string `json:"sig"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body reqBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("request body invalid: %s", err), http.StatusBadRequest)
+			return
+		} else if body.AttendeeID < 0 {
+			http.Error(w, "attendee id must be a positive integer", http.StatusBadRequest)
+			return
+		}
+
+		s, err := issuer.SignIn(body.AttendeeID, body.Nonce, body.Sig)
+		switch err {
+		case nil:
+			writeJSON(w, http.StatusCreated, s)
+		case session.ErrUnknownChallenge, session.ErrChallengeExpired,
+			session.ErrNotKeyed, session.ErrInvalidSignature:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			log.WithError(err).Info("failed sign in")
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+		}
+	}
+}
+
+// sessionAttendee returns the attendee id of the session token in `r`. false is returned if
+// the request does not carry a valid session
+func sessionAttendee(r *http.Request, issuer *session.Issuer) (int, bool) {
+	header := r.Header.Get(sessionHeader)
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return 0, false
+	}
+
+	id, err := issuer.Verify(strings.TrimPrefix(header, bearerPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	ebSession "github.com/eco/longy/key-service/eventbrite"
+	"github.com/eco/longy/key-service/suppression"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// eventbriteAPIHost is the only host eventbrite webhooks may reference
+	eventbriteAPIHost = "www.eventbriteapi.com"
+
+	attendeeCreatedAction = "attendee.created"
+	attendeeUpdatedAction = "attendee.updated"
+	webhookTestAction     = "test"
+)
+
+// matches the attendee id in an eventbrite api url, i.e /v3/events/123/attendees/456/
+var attendeeAPIPath = regexp.MustCompile(`^/v3/events/\d+/attendees/(\d+)/?$`)
+
+func registerWebhook(r *mux.Router, eb *ebSession.Session, suppressions *suppression.Receiver) {
+	r.HandleFunc("/webhooks/eventbrite", eventbriteWebhook(eb)).Methods(http.MethodPost, http.MethodOptions)
+	r.Handle("/webhooks/ses", suppressions).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// eventbriteWebhook applies attendee pushes from eventbrite immediately instead of waiting
+// for the next poll. Webhook payloads are not signed, so the payload is only used to
+// learn which attendee changed. The attendee itself is always re-fetched through the
+// session's provider, which is bound to our event and credentials
+func eventbriteWebhook(eb *ebSession.Session) http.HandlerFunc {
+	type webhookConfig struct {
+		Action string `json:"action"`
+	}
+	type reqBody struct {
+		APIURL string        `json:"api_url"`
+		Config webhookConfig `json:"config"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body reqBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("request body: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		switch body.Config.Action {
+		case attendeeCreatedAction, attendeeUpdatedAction:
+		case webhookTestAction:
+			w.WriteHeader(http.StatusOK)
+			return
+		default:
+			// acknowledge so eventbrite does not retry actions we do not care about
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		id, err := attendeeIDFromAPIURL(body.APIURL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_, found, err := eb.Refresh(id)
+		if err != nil {
+			log.WithError(err).WithField("id", id).Warn("webhook attendee refresh")
+			http.Error(w, "ticketing provider unavailable", http.StatusServiceUnavailable)
+			return
+		} else if !found {
+			http.Error(w, "attendee not found for this event", http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func attendeeIDFromAPIURL(apiURL string) (int, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return -1, fmt.Errorf("malformed api_url: %s", err)
+	} else if u.Host != eventbriteAPIHost {
+		return -1, fmt.Errorf("api_url must reference %s", eventbriteAPIHost)
+	}
+
+	match := attendeeAPIPath.FindStringSubmatch(u.Path)
+	if match == nil {
+		return -1, fmt.Errorf("api_url must reference an attendee")
+	}
+
+	id, err := strconv.Atoi(match[1])
+	if err != nil {
+		return -1, fmt.Errorf("attendee id must be a positive integer")
+	}
+
+	return id, nil
+}
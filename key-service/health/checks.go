@@ -0,0 +1,143 @@
+package health
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ebSession "github.com/eco/longy/key-service/eventbrite"
+	"github.com/eco/longy/key-service/mail"
+	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/x/longy/client/longyclient"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("module", "health")
+
+// maxBlockAge is the age of the latest block after which the chain is considered halted
+const maxBlockAge = 2 * time.Minute
+
+// chainStatus is the sync status of the full node behind the rest server
+type chainStatus struct {
+	Syncing     bool      `json:"syncing"`
+	Height      int64     `json:"height"`
+	BlockTime   time.Time `json:"block_time"`
+	BlockAgeSec int64     `json:"block_age_seconds"`
+}
+
+// accountStatus is the state of the master account on chain
+type accountStatus struct {
+	Address       sdk.AccAddress `json:"address"`
+	AccountNumber uint64         `json:"account_number"`
+	Sequence      uint64         `json:"sequence"`
+	Coins         sdk.Coins      `json:"coins"`
+}
+
+// ticketingStatus is the state of the attendee list polled from the ticketing provider
+type ticketingStatus struct {
+	LastPoll    time.Time `json:"last_poll"`
+	LastPollAge int64     `json:"last_poll_age_seconds"`
+	Attendees   int       `json:"attendees"`
+}
+
+// ChainCheck verifies that the rest server is reachable and its full node is in sync with the chain
+func ChainCheck(chain *longyclient.Client) Check {
+	return Check{
+		Name:     "chain",
+		Critical: true,
+		Run: func() (interface{}, error) {
+			syncing, err := chain.IsSyncing()
+			if err != nil {
+				return nil, err
+			}
+			height, blockTime, err := chain.LatestBlock()
+			if err != nil {
+				return nil, err
+			}
+
+			status := chainStatus{
+				Syncing:     syncing,
+				Height:      height,
+				BlockTime:   blockTime,
+				BlockAgeSec: int64(time.Since(blockTime).Seconds()),
+			}
+			switch {
+			case syncing:
+				return status, fmt.Errorf("full node is catching up")
+			case time.Since(blockTime) > maxBlockAge:
+				return status, fmt.Errorf("no block since %s", blockTime.Format(time.RFC3339))
+			}
+			return status, nil
+		},
+	}
+}
+
+// MasterAccountCheck reports the sequence and balance of the master account at `address`
+func MasterAccountCheck(chain *longyclient.Client, address sdk.AccAddress) Check {
+	return Check{
+		Name:     "master_account",
+		Critical: true,
+		Run: func() (interface{}, error) {
+			acc, err := chain.Account(address)
+			if err != nil {
+				return nil, err
+			}
+
+			status := accountStatus{
+				Address:       address,
+				AccountNumber: acc.GetAccountNumber(),
+				Sequence:      acc.GetSequence(),
+				Coins:         acc.GetCoins(),
+			}
+			if acc.GetAddress().Empty() {
+				return status, fmt.Errorf("master account does not exist on chain")
+			}
+			return status, nil
+		},
+	}
+}
+
+// StorageCheck verifies that the storage backend can be reached
+func StorageCheck(db *models.DatabaseContext) Check {
+	return Check{
+		Name:     "storage",
+		Critical: true,
+		Run: func() (interface{}, error) {
+			return nil, db.Ping()
+		},
+	}
+}
+
+// MailCheck reports the sending quota of the mail provider
+func MailCheck(mc mail.Client) Check {
+	return Check{
+		Name: "mail",
+		Run: func() (interface{}, error) {
+			status, err := mc.Status()
+			if status == nil {
+				return nil, err
+			}
+			return status, err
+		},
+	}
+}
+
+// TicketingCheck verifies that the attendee list was polled recently. Attendees registered
+// since the last successful poll cannot onboard until they are refreshed
+func TicketingCheck(eb *ebSession.Session) Check {
+	return Check{
+		Name: "ticketing",
+		Run: func() (interface{}, error) {
+			lastPoll := eb.LastPoll()
+			status := ticketingStatus{
+				LastPoll:    lastPoll,
+				LastPollAge: int64(time.Since(lastPoll).Seconds()),
+				Attendees:   len(eb.GetAttendees()),
+			}
+			if time.Since(lastPoll) > 3*ebSession.PollInterval {
+				return status, fmt.Errorf("attendee list not polled since %s", lastPoll.Format(time.RFC3339))
+			}
+			return status, nil
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package health
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusOK denotes that every dependency is available
+	StatusOK = "ok"
+	// StatusDegraded denotes an unavailable dependency the key service can serve without
+	StatusDegraded = "degraded"
+	// StatusDown denotes an unavailable dependency the key service cannot serve without
+	StatusDown = "down"
+
+	// StatusFailing denotes a dependency whose check failed
+	StatusFailing = "failing"
+
+	// checkTimeout is how long a single check may take before it is reported as failing
+	checkTimeout = 5 * time.Second
+
+	// reportTTL is how long a report is reused, so that frequent probes do not load the dependencies
+	reportTTL = 5 * time.Second
+)
+
+// Check reports the status of a dependency. The details are included in the report and an
+// error marks the dependency as failing. The key service is down if a `Critical` check fails
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func() (interface{}, error)
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Status   string      `json:"status"`
+	Critical bool        `json:"critical"`
+	Error    string      `json:"error,omitempty"`
+	Details  interface{} `json:"details,omitempty"`
+	Latency  string      `json:"latency"`
+}
+
+// Report is the status of the key service and every dependency
+type Report struct {
+	Status    string            `json:"status"`
+	CheckedAt time.Time         `json:"checked_at"`
+	Checks    map[string]Result `json:"checks"`
+}
+
+// Ready indicates if the key service can serve requests
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Checker runs the dependency checks of the key service
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+	ttl     time.Duration
+
+	mtx  sync.Mutex
+	last *Report
+}
+
+// NewChecker is the constructor for `Checker`
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: checkTimeout, ttl: reportTTL}
+}
+
+// Report runs every check concurrently. A report younger than a few seconds is returned as is
+func (c *Checker) Report() Report {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.last != nil && time.Since(c.last.CheckedAt) < c.ttl {
+		return *c.last
+	}
+
+	report := Report{
+		Status:    StatusOK,
+		CheckedAt: time.Now().UTC(),
+		Checks:    make(map[string]Result, len(c.checks)),
+	}
+
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i := range c.checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.run(c.checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, check := range c.checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusFailing {
+			continue
+		}
+
+		if check.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+
+	c.last = &report
+	return report
+}
+
+func (c *Checker) run(check Check) Result {
+	type outcome struct {
+		details interface{}
+		err     error
+	}
+
+	start := time.Now()
+	done := make(chan outcome, 1)
+	go func() {
+		details, err := check.Run()
+		done <- outcome{details, err}
+	}()
+
+	var o outcome
+	select {
+	case o = <-done:
+	case <-time.After(c.timeout):
+		o.err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	result := Result{
+		Status:   StatusOK,
+		Critical: check.Critical,
+		Details:  o.details,
+		Latency:  time.Since(start).Round(time.Millisecond).String(),
+	}
+	if o.err != nil {
+		result.Status = StatusFailing
+		result.Error = o.err.Error()
+		log.WithError(o.err).WithField("check", check.Name).Warn("health check failing")
+	}
+
+	return result
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checker", func() {
+	passing := func(name string, critical bool) Check {
+		return Check{Name: name, Critical: critical, Run: func() (interface{}, error) {
+			return map[string]int{"height": 10}, nil
+		}}
+	}
+	failing := func(name string, critical bool) Check {
+		return Check{Name: name, Critical: critical, Run: func() (interface{}, error) {
+			return nil, errors.New("unreachable")
+		}}
+	}
+
+	It("is ok when every check passes", func() {
+		report := NewChecker(passing("chain", true), passing("mail", false)).Report()
+		Expect(report.Status).To(Equal(StatusOK))
+		Expect(report.Ready()).To(BeTrue())
+		Expect(report.Checks).To(HaveLen(2))
+		Expect(report.Checks["chain"].Status).To(Equal(StatusOK))
+		Expect(report.Checks["chain"].Details).To(Equal(map[string]int{"height": 10}))
+	})
+
+	It("is degraded but ready when a non-critical check fails", func() {
+		report := NewChecker(passing("chain", true), failing("mail", false)).Report()
+		Expect(report.Status).To(Equal(StatusDegraded))
+		Expect(report.Ready()).To(BeTrue())
+		Expect(report.Checks["mail"].Status).To(Equal(StatusFailing))
+		Expect(report.Checks["mail"].Error).To(Equal("unreachable"))
+	})
+
+	It("is down when a critical check fails", func() {
+		report := NewChecker(failing("chain", true), failing("mail", false)).Report()
+		Expect(report.Status).To(Equal(StatusDown))
+		Expect(report.Ready()).To(BeFalse())
+	})
+
+	It("fails checks that time out", func() {
+		c := NewChecker(Check{Name: "storage", Critical: true, Run: func() (interface{}, error) {
+			time.Sleep(time.Second)
+			return nil, nil
+		}})
+		c.timeout = 10 * time.Millisecond
+
+		report := c.Report()
+		Expect(report.Status).To(Equal(StatusDown))
+		Expect(report.Checks["storage"].Error).To(ContainSubstring("timed out"))
+	})
+
+	It("reuses recent reports", func() {
+		runs := 0
+		c := NewChecker(Check{Name: "chain", Run: func() (interface{}, error) {
+			runs++
+			return nil, nil
+		}})
+
+		c.Report()
+		c.Report()
+		Expect(runs).To(Equal(1))
+
+		c.ttl = 0
+		c.Report()
+		Expect(runs).To(Equal(2))
+	})
+})
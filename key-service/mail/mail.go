@@ -39,6 +39,16 @@ type Client interface {
 	SendExportEmail(db *models.DatabaseContext, attendeeEmail string, id int, token string) error
 
 	SendAttendeeSharedInfoEmail(ctx *models.DatabaseContext, attendeeEmail string, sharedInfo string) error
+
+	// Status reports the sending quota of the mail provider
+	Status() (*Status, error)
+}
+
+// Status is the sending quota of the mail provider over the last 24 hours
+type Status struct {
+	Max24HourSend   float64 `json:"max_24_hour_send"`
+	MaxSendRate     float64 `json:"max_send_rate"`
+	SentLast24Hours float64 `json:"sent_last_24_hours"`
 }
 
 type sesClient struct {
@@ -204,6 +214,24 @@ func (c sesClient) sendEmailWithURL(
 	return err
 }
 
+// Status retrieves the SES sending quota. An exhausted quota is reported as an error
+func (c sesClient) Status() (*Status, error) {
+	quota, err := c.ses.GetSendQuota(&ses.GetSendQuotaInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		Max24HourSend:   aws.Float64Value(quota.Max24HourSend),
+		MaxSendRate:     aws.Float64Value(quota.MaxSendRate),
+		SentLast24Hours: aws.Float64Value(quota.SentLast24Hours),
+	}
+	if status.Max24HourSend > 0 && status.SentLast24Hours >= status.Max24HourSend {
+		return status, fmt.Errorf("24 hour sending quota exhausted")
+	}
+	return status, nil
+}
+
 // SendOnboardingEmail will construct and send the email corresponding to onboarding the user
 func (c mockClient) SendOnboardingEmail(
 	db *models.DatabaseContext,
@@ -259,6 +287,11 @@ func (c mockClient) SendAttendeeSharedInfoEmail(
 	return nil
 }
 
+// Status of the mock client, which never runs out of quota
+func (c mockClient) Status() (*Status, error) {
+	return &Status{}, nil
+}
+
 /** Helpers **/
 
 func makeRecoveryURI(clientURL string, id string, token string) (string, error) {
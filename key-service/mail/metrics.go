@@ -0,0 +1,46 @@
+package mail
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	eb "github.com/eco/longy/eventbrite"
+	"github.com/eco/longy/key-service/metrics"
+	"github.com/eco/longy/key-service/models"
+)
+
+// instrumentedClient counts the emails sent through the wrapped client by kind and result
+type instrumentedClient struct {
+	Client
+}
+
+// NewInstrumentedClient wraps `c` so that every email sent is recorded in the key service metrics
+func NewInstrumentedClient(c Client) Client {
+	return instrumentedClient{Client: c}
+}
+
+func record(kind string, err error) error {
+	metrics.EmailsSent.WithLabelValues(kind, metrics.Result(err)).Inc()
+	return err
+}
+
+func (c instrumentedClient) SendOnboardingEmail(db *models.DatabaseContext, attendeeAddr sdk.AccAddress,
+	profile *eb.AttendeeProfile, secret string, imageUploadURL string) error {
+	return record("onboarding", c.Client.SendOnboardingEmail(db, attendeeAddr, profile, secret, imageUploadURL))
+}
+
+func (c instrumentedClient) SendRecoveryEmail(db *models.DatabaseContext, profile *eb.AttendeeProfile,
+	id string, token string) error {
+	return record("recovery", c.Client.SendRecoveryEmail(db, profile, id, token))
+}
+
+func (c instrumentedClient) SendVerificationEmail(db *models.DatabaseContext, dest string, token string) error {
+	return record("verification", c.Client.SendVerificationEmail(db, dest, token))
+}
+
+func (c instrumentedClient) SendExportEmail(db *models.DatabaseContext, dstEmail string, id int, token string) error {
+	return record("export", c.Client.SendExportEmail(db, dstEmail, id, token))
+}
+
+func (c instrumentedClient) SendAttendeeSharedInfoEmail(db *models.DatabaseContext, attendeeEmail string,
+	sharedInfo string) error {
+	return record("shared_info", c.Client.SendAttendeeSharedInfoEmail(db, attendeeEmail, sharedInfo))
+}
@@ -3,15 +3,15 @@ package masterkey
 import (
 	"errors"
 	"fmt"
-	"sync"
+	"time"
 
-	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	longyApp "github.com/eco/longy"
-	longyClnt "github.com/eco/longy/key-service/longyclient"
+	"github.com/eco/longy/key-service/metrics"
+	"github.com/eco/longy/key-service/signer"
 	"github.com/eco/longy/util"
 	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/client/longyclient"
+	"github.com/eco/longy/x/longy/client/txsender"
 	"github.com/sirupsen/logrus"
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 )
@@ -21,101 +21,259 @@ var log = logrus.WithField("module", "masterkey")
 var (
 	// ErrAlreadyKeyed denotes that this address has already been key'd
 	ErrAlreadyKeyed = errors.New("account already key'ed")
+
+	// ErrTxFailed denotes that the key transaction was rejected by the chain
+	ErrTxFailed = errors.New("failed tx")
+
+	// ErrBroadcast denotes that the key transaction could not be committed after retrying
+	ErrBroadcast = errors.New("key transaction could not be broadcast")
+
+	// ErrClosed denotes that the master key is no longer sending transactions
+	ErrClosed = errors.New("master key closed")
 )
 
-// MasterKey encapslates the master key for the longy game
-type MasterKey struct {
-	privKey tmcrypto.PrivKey
-	pubKey  tmcrypto.PubKey
-	address sdk.AccAddress
+const (
+	// maxBatchSize is the maximum number of MsgKey included in a single transaction
+	maxBatchSize = 50
+
+	// gasPerMsg is the gas allotted for every MsgKey in a transaction
+	gasPerMsg = 50000
+
+	// maxRetries is the number of times a batch is re-broadcast after a sequence mismatch
+	// or a network failure before the pending requests are failed
+	maxRetries = 3
 
-	chainID string
+	// queueSize is the number of requests that can wait for the next batch
+	queueSize = 1024
+)
 
-	accNum      uint64
-	sequenceNum uint64
-	seqLock     *sync.Mutex
+// Keyer keys attendee accounts on chain. `MasterKey` broadcasts the key transactions itself
+type Keyer interface {
+	SendKeyTransaction(attendeeAddr sdk.AccAddress, newPublicKey tmcrypto.PubKey, commitment util.Commitment) error
+	Close()
+}
 
-	cdc *codec.Codec
+// keyRequest is a MsgKey waiting to be included in the next batch
+type keyRequest struct {
+	msg    longy.MsgKey
+	result chan error
 }
 
-// NewMasterKey is the constructor for `Key`. A new secp256k1 is generated if empty.
-// The `chainID` is used when generating RekeyTransactions to prevent cross-chain replay attacks
-func NewMasterKey(privateKey tmcrypto.PrivKey, chainID string) (MasterKey, error) {
+// MasterKey encapslates the master key for the longy game. Key requests are queued and
+// broadcast together, one transaction per block
+type MasterKey struct {
+	address sdk.AccAddress
 
-	// retrieve details about the master account from the rest endpoint
-	sdkAddr := sdk.AccAddress(privateKey.PubKey().Address())
-	masterAccount, err := longyClnt.GetAccount(sdkAddr)
-	if err != nil {
-		return MasterKey{}, fmt.Errorf("masterkey account retrieval: %s", err)
-	}
+	// only used by the batching routine
+	sender *txsender.Sender
+
+	requests chan *keyRequest
+	done     chan struct{}
+}
 
-	k := MasterKey{
-		privKey: privateKey,
-		pubKey:  privateKey.PubKey(),
-		address: sdkAddr,
+// NewMasterKey is the constructor for `Key`. Transactions are signed by `s`, which may hold the
+// key in process or in a signing daemon. The `chainID` is used when generating RekeyTransactions
+// to prevent cross-chain replay attacks. The master account is retrieved and transactions are broadcast through `chain`
+func NewMasterKey(s signer.Signer, chainID string, chain *longyclient.Client) (*MasterKey, error) {
+	return newMasterKey(s, chainID, chain)
+}
 
-		chainID: chainID,
+func newMasterKey(s signer.Signer, chainID string, chain txsender.Chain) (*MasterKey, error) {
+	k := &MasterKey{
+		address: sdk.AccAddress(s.PubKey().Address()),
 
-		accNum:      masterAccount.GetAccountNumber(),
-		sequenceNum: masterAccount.GetSequence(),
-		seqLock:     &sync.Mutex{},
+		sender: txsender.New(s, chainID, chain, txsender.WithGas(gasPerMsg)),
 
-		cdc: longyApp.MakeCodec(),
+		requests: make(chan *keyRequest, queueSize),
+		done:     make(chan struct{}),
 	}
 
-	log.Infof("constructed master key. Chain-Id=%s, AccountNum=%d, SequenceNum=%d", k.chainID, k.accNum, k.sequenceNum)
+	// retrieve details about the master account from the rest endpoint
+	if err := k.sender.Sync(); err != nil {
+		return nil, fmt.Errorf("masterkey %s", err)
+	}
+
+	go k.batch()
+
+	accNum, sequence := k.sender.Account()
+	log.Infof("constructed master key. Chain-Id=%s, AccountNum=%d, SequenceNum=%d", chainID, accNum, sequence)
 	return k, nil
 }
 
-// SendKeyTransaction generates a `RekeyMsg`, authorized by the master key. The transaction bytes
-// generated are created using the cosmos-sdk/x/auth module's StdSignDoc.
+// SendKeyTransaction generates a `RekeyMsg`, authorized by the master key, and blocks until the
+// transaction including it has been committed. The transaction bytes generated are created using
+// the cosmos-sdk/x/auth module's StdSignDoc.
 func (mk *MasterKey) SendKeyTransaction(
 	attendeeAddr sdk.AccAddress,
 	newPublicKey tmcrypto.PubKey,
 	commitment util.Commitment,
 ) error {
+	req := &keyRequest{
+		msg:    longy.NewMsgKey(attendeeAddr, mk.address, newPublicKey, commitment),
+		result: make(chan error, 1),
+	}
+
+	// fail early on messages that would fail the whole batch
+	if err := req.msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	select {
+	case mk.requests <- req:
+	case <-mk.done:
+		return ErrClosed
+	}
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-mk.done:
+		return ErrClosed
+	}
+}
 
-	/** Block until we submit the transaction **/
-	mk.seqLock.Lock()
-
-	// create and broadcast the transaction
-	keyMsg := longy.NewMsgKey(attendeeAddr, mk.address, newPublicKey, commitment)
-	tx := mk.createKeyTx(keyMsg)
-	res, err := longyClnt.BroadcastAuthTx(tx, "block")
-	if err != nil { // nolint
-		log.WithError(err).Info("failed transaction submission")
-	} else {
-		if res.Code != 0 {
-			if res.Code == uint32(longy.CodeAttendeeKeyed) {
-				err = ErrAlreadyKeyed
-			} else {
-				log.WithField("raw_log", res.RawLog).Info("failed tx response")
-				err = fmt.Errorf("failed tx")
+// Close stops the batching routine. Waiting requests are failed with `ErrClosed`
+func (mk *MasterKey) Close() {
+	close(mk.done)
+}
+
+// batch collects every waiting request into a transaction. While a transaction is being
+// committed new requests queue up for the next one, resulting in one transaction per block
+func (mk *MasterKey) batch() {
+	for {
+		var pending []*keyRequest
+		select {
+		case req := <-mk.requests:
+			pending = append(pending, req)
+		case <-mk.done:
+			return
+		}
+
+	drain:
+		for len(pending) < maxBatchSize {
+			select {
+			case req := <-mk.requests:
+				pending = append(pending, req)
+			default:
+				break drain
 			}
 		}
 
-		mk.sequenceNum++
+		mk.send(pending)
 	}
+}
+
+// send broadcasts `pending` and reports the result of each request. Requests whose message
+// fails are reported and the remaining requests are re-broadcast
+//nolint:gocyclo
+func (mk *MasterKey) send(pending []*keyRequest) {
+	retries := 0
+	for len(pending) > 0 {
+		if retries > maxRetries {
+			log.WithField("requests", len(pending)).Error("giving up on key transaction")
+			metrics.BroadcastFailures.WithLabelValues("retries_exhausted").Inc()
+			resolve(pending, ErrBroadcast)
+			return
+		}
 
-	mk.seqLock.Unlock()
+		msgs := make([]sdk.Msg, len(pending))
+		for i := range pending {
+			msgs[i] = pending[i].msg
+		}
+
+		start := time.Now()
+		_, err := mk.sender.Send(msgs...)
+
+		if signErr, ok := err.(*txsender.SignError); ok {
+			if signErr.Err == signer.ErrRejected {
+				// the signing daemon refuses the same transaction on every attempt
+				log.Error("key transaction rejected by the signing daemon")
+				metrics.BroadcastFailures.WithLabelValues("signer_rejected").Inc()
+				resolve(pending, ErrTxFailed)
+				return
+			}
+			log.WithError(signErr.Err).Warn("failed to sign key transaction")
+			metrics.BroadcastFailures.WithLabelValues("signer_error").Inc()
+			retries++
+			continue
+		}
+
+		outcome := broadcastOutcome(err)
+		metrics.BroadcastDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+		if outcome != "ok" {
+			metrics.BroadcastFailures.WithLabelValues(outcome).Inc()
+		}
 
-	return err
+		txErr, failed := err.(*txsender.TxError)
+		switch {
+		case err == nil:
+			resolve(pending, nil)
+			return
+
+		case !failed:
+			// the tx may or may not have been committed. Re-broadcasting is safe since
+			// attendees keyed by the first attempt are reported as already keyed
+			log.WithError(err).Info("failed transaction submission")
+			retries++
+
+		case txErr.SequenceMismatch():
+			log.Warn("master key sequence mismatch. resyncing")
+			retries++
+
+		case txErr.MsgIndex < 0:
+			log.WithField("raw_log", txErr.Log).Info("failed tx response")
+			resolve(pending, ErrTxFailed)
+			return
+
+		default:
+			keyErr := keyError(txErr)
+			pending[txErr.MsgIndex].result <- keyErr
+			metrics.KeyRequests.WithLabelValues(keyResult(keyErr)).Inc()
+			pending = append(pending[:txErr.MsgIndex], pending[txErr.MsgIndex+1:]...)
+		}
+	}
 }
 
-//nolint
-func (mk *MasterKey) createKeyTx(keyMsg longy.MsgKey) *auth.StdTx {
-	msgs := []sdk.Msg{keyMsg}
+func resolve(pending []*keyRequest, err error) {
+	for _, req := range pending {
+		req.result <- err
+	}
+	metrics.KeyRequests.WithLabelValues(keyResult(err)).Add(float64(len(pending)))
+}
+
+// broadcastOutcome labels the result of a key transaction broadcast
+func broadcastOutcome(err error) string {
+	txErr, failed := err.(*txsender.TxError)
+	switch {
+	case err == nil:
+		return "ok"
+	case !failed:
+		return "network_error"
+	case txErr.SequenceMismatch():
+		return "sequence_mismatch"
+	default:
+		return "msg_failed"
+	}
+}
 
-	nilFee := auth.NewStdFee(50000, sdk.NewCoins(sdk.NewInt64Coin("longy", 0)))
-	signBytes := auth.StdSignBytes(mk.chainID, mk.accNum, mk.sequenceNum, nilFee, msgs, "")
+// keyResult labels the result of a key request
+func keyResult(err error) string {
+	switch err {
+	case nil:
+		return "keyed"
+	case ErrAlreadyKeyed:
+		return "already_keyed"
+	default:
+		return "failed"
+	}
+}
 
-	// sign the message with the master private key
-	sig, err := mk.privKey.Sign(signBytes)
-	if err != nil {
-		panic(err)
+// keyError is the error reported to the request whose message failed the transaction
+func keyError(txErr *txsender.TxError) error {
+	if txErr.Codespace == longy.ModuleName && txErr.Code == longy.CodeAttendeeKeyed {
+		return ErrAlreadyKeyed
 	}
-	stdSig := auth.StdSignature{PubKey: mk.pubKey, Signature: sig}
-	tx := auth.NewStdTx(msgs, nilFee, []auth.StdSignature{stdSig}, "")
 
-	return &tx
+	log.WithField("raw_log", txErr.Log).Info("failed key message")
+	return ErrTxFailed
 }
@@ -0,0 +1,12 @@
+package masterkey
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestMasterKey(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Master Key Suite")
+}
@@ -0,0 +1,196 @@
+package masterkey
+
+import (
+	"errors"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/eco/longy/key-service/signer"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// fakeChain answers broadcasts with the scripted responses in order and succeeds once they run out
+type fakeChain struct {
+	sync.Mutex
+	sequence  uint64
+	responses []*sdk.TxResponse
+	txs       []*auth.StdTx
+	gate      chan struct{}
+	waiting   int
+}
+
+func (c *fakeChain) Account(addr sdk.AccAddress) (auth.Account, error) {
+	c.Lock()
+	defer c.Unlock()
+	acc := auth.NewBaseAccountWithAddress(addr)
+	_ = acc.SetAccountNumber(7)
+	_ = acc.SetSequence(c.sequence)
+	return &acc, nil
+}
+
+func (c *fakeChain) BroadcastTx(tx *auth.StdTx, mode string) (*sdk.TxResponse, error) {
+	if c.gate != nil {
+		c.Lock()
+		c.waiting++
+		c.Unlock()
+		<-c.gate
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.txs = append(c.txs, tx)
+	if len(c.responses) == 0 {
+		c.sequence++
+		return &sdk.TxResponse{}, nil
+	}
+
+	res := c.responses[0]
+	c.responses = c.responses[1:]
+	return res, nil
+}
+
+func (c *fakeChain) Tx(hash string) (*sdk.TxResponse, error) {
+	return nil, errors.New("not found")
+}
+
+func (c *fakeChain) waitingBroadcasts() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.waiting
+}
+
+func (c *fakeChain) broadcasts() []*auth.StdTx {
+	c.Lock()
+	defer c.Unlock()
+	return c.txs
+}
+
+var _ = Describe("Master Key Sender", func() {
+	var (
+		chain *fakeChain
+		mk    *MasterKey
+	)
+
+	send := func(id string) chan error {
+		result := make(chan error, 1)
+		k := mk
+		go func() {
+			pub := secp256k1.GenPrivKeySecp256k1([]byte(id)).PubKey()
+			result <- k.SendKeyTransaction(util.IDToAddress(id), pub, util.Commitment{})
+		}()
+		return result
+	}
+
+	start := func() {
+		var err error
+		mk, err = newMasterKey(signer.NewLocal(secp256k1.GenPrivKeySecp256k1([]byte("master"))), "longychain", chain)
+		Expect(err).To(BeNil())
+	}
+
+	BeforeEach(func() {
+		chain = &fakeChain{sequence: 3}
+	})
+
+	AfterEach(func() {
+		mk.Close()
+	})
+
+	It("should sync the account from the chain", func() {
+		start()
+		accNum, sequence := mk.sender.Account()
+		Expect(accNum).To(Equal(uint64(7)))
+		Expect(sequence).To(Equal(uint64(3)))
+	})
+
+	It("should batch requests that queue up while a transaction is committed", func() {
+		chain.gate = make(chan struct{})
+		start()
+
+		first := send("1")
+		Eventually(chain.waitingBroadcasts).Should(Equal(1))
+
+		var rest []chan error
+		for _, id := range []string{"2", "3", "4"} {
+			rest = append(rest, send(id))
+		}
+		Eventually(func() int { return len(mk.requests) }).Should(Equal(3))
+
+		close(chain.gate)
+		Eventually(first).Should(Receive(BeNil()))
+		for _, r := range rest {
+			Eventually(r).Should(Receive(BeNil()))
+		}
+
+		txs := chain.broadcasts()
+		Expect(txs).To(HaveLen(2))
+		Expect(txs[0].Msgs).To(HaveLen(1))
+		Expect(txs[1].Msgs).To(HaveLen(3))
+		Expect(txs[1].Fee.Gas).To(Equal(uint64(3 * gasPerMsg)))
+	})
+
+	It("should resync and retry on a sequence mismatch", func() {
+		start()
+		chain.Lock()
+		chain.sequence = 10
+		chain.responses = []*sdk.TxResponse{
+			{Code: uint32(sdk.CodeUnauthorized), Codespace: string(sdk.CodespaceRoot)},
+		}
+		chain.Unlock()
+
+		Eventually(send("1")).Should(Receive(BeNil()))
+		Expect(chain.broadcasts()).To(HaveLen(2))
+		_, sequence := mk.sender.Account()
+		Expect(sequence).To(Equal(uint64(11)))
+	})
+
+	It("should report a failed message and re-broadcast the rest", func() {
+		chain.gate = make(chan struct{})
+		start()
+
+		first := send("1")
+		Eventually(chain.waitingBroadcasts).Should(Equal(1))
+		second := send("2")
+		Eventually(func() int { return len(mk.requests) }).Should(Equal(1))
+		third := send("3")
+		Eventually(func() int { return len(mk.requests) }).Should(Equal(2))
+
+		chain.Lock()
+		chain.responses = []*sdk.TxResponse{
+			{}, // first batch
+			{
+				Code:      uint32(longy.CodeAttendeeKeyed),
+				Codespace: longy.ModuleName,
+				Logs: sdk.ABCIMessageLogs{
+					{MsgIndex: 0, Success: false},
+				},
+			},
+		}
+		chain.Unlock()
+		close(chain.gate)
+
+		Eventually(first).Should(Receive(BeNil()))
+		Eventually(second).Should(Receive(Equal(ErrAlreadyKeyed)))
+		Eventually(third).Should(Receive(BeNil()))
+
+		txs := chain.broadcasts()
+		Expect(txs).To(HaveLen(3))
+		Expect(txs[2].Msgs).To(HaveLen(1))
+	})
+
+	It("should fail the requests once retries run out", func() {
+		start()
+		chain.Lock()
+		for i := 0; i <= maxRetries; i++ {
+			chain.responses = append(chain.responses,
+				&sdk.TxResponse{Code: uint32(sdk.CodeUnauthorized), Codespace: string(sdk.CodespaceRoot)})
+		}
+		chain.Unlock()
+
+		Eventually(send("1")).Should(Receive(Equal(ErrBroadcast)))
+	})
+})
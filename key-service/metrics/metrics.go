@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric of the key service
+const namespace = "ks"
+
+var (
+	// HTTPRequests counts the handled requests by route, method and status code
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Handled requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPDuration observes the latency of the handled requests by route and method
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the handled requests by route and method.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"route", "method"})
+
+	// BroadcastDuration observes the latency of the key transaction broadcasts by outcome
+	BroadcastDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "masterkey",
+		Name:      "broadcast_duration_seconds",
+		Help:      "Latency of the key transaction broadcasts by outcome.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	}, []string{"outcome"})
+
+	// BroadcastFailures counts the failed key transaction broadcasts by reason
+	BroadcastFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "masterkey",
+		Name:      "broadcast_failures_total",
+		Help:      "Failed key transaction broadcasts by reason.",
+	}, []string{"reason"})
+
+	// KeyRequests counts the key requests resolved by the master key by result
+	KeyRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "masterkey",
+		Name:      "key_requests_total",
+		Help:      "Key requests resolved by the master key by result.",
+	}, []string{"result"})
+
+	// TicketingPolls counts the polls of the ticketing provider by result
+	TicketingPolls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ticketing",
+		Name:      "polls_total",
+		Help:      "Polls of the ticketing provider by result.",
+	}, []string{"result"})
+
+	// TicketingAttendees is the number of attendees retrieved from the ticketing provider
+	TicketingAttendees = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ticketing",
+		Name:      "attendees",
+		Help:      "Attendees retrieved from the ticketing provider.",
+	})
+
+	// TicketingLastPoll is the unix time of the last successful poll of the ticketing provider
+	TicketingLastPoll = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ticketing",
+		Name:      "last_poll_timestamp_seconds",
+		Help:      "Unix time of the last successful poll of the ticketing provider.",
+	})
+
+	// EmailsSent counts the emails handed to the mail provider by kind and result
+	EmailsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mail",
+		Name:      "emails_total",
+		Help:      "Emails handed to the mail provider by kind and result.",
+	}, []string{"kind", "result"})
+
+	// Suppressions counts the addresses suppressed from bounce and complaint notifications by reason
+	Suppressions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mail",
+		Name:      "suppressions_total",
+		Help:      "Addresses suppressed from bounce and complaint notifications by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		HTTPRequests,
+		HTTPDuration,
+		BroadcastDuration,
+		BroadcastFailures,
+		KeyRequests,
+		TicketingPolls,
+		TicketingAttendees,
+		TicketingLastPoll,
+		EmailsSent,
+		Suppressions,
+	)
+}
+
+// Result labels the outcome of an operation that failed with `err`
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// Handler serves the metrics in the prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
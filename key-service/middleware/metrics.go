@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eco/longy/key-service/metrics"
+	"github.com/gorilla/mux"
+)
+
+// Metrics records the status and latency of the requests by route. It is used as a router
+// middleware so that requests are labeled with the route template instead of the raw path
+func Metrics(underlying http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+
+		sw := statusWriter{ResponseWriter: w}
+		start := time.Now()
+		underlying.ServeHTTP(&sw, r)
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+
+		metrics.HTTPDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		metrics.HTTPRequests.WithLabelValues(route, r.Method, strconv.Itoa(sw.status)).Inc()
+	})
+}
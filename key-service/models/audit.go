@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// AuditEntry is a record of an action performed with an admin key
+type AuditEntry struct {
+	ID     int64     `json:"id" dynamodbav:"ID"`
+	Time   time.Time `json:"time"`
+	Key    string    `json:"key"`
+	Scope  string    `json:"scope"`
+	Action string    `json:"action"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Target string    `json:"target,omitempty"`
+	Status int       `json:"status"`
+	Remote string    `json:"remote"`
+}
+
+// AuditFilter narrows down the audit entries returned by `GetAuditEntries`. Zero values
+// match every entry
+type AuditFilter struct {
+	Key    string
+	Action string
+	Since  time.Time
+	Limit  int
+}
+
+func (f *AuditFilter) matches(e *AuditEntry) bool {
+	return (len(f.Key) == 0 || e.Key == f.Key) &&
+		(len(f.Action) == 0 || e.Action == f.Action) &&
+		!e.Time.Before(f.Since)
+}
+
+// AppendAuditEntry appends `entry` to the audit log. Entries are never overwritten, the
+// ID is the entry's time in nanoseconds and is bumped until it is unique
+func (db DatabaseContext) AppendAuditEntry(entry *AuditEntry) bool {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	entry.ID = entry.Time.UnixNano()
+
+	for attempt := 0; attempt < 5; attempt++ {
+		item, err := dynamodbattribute.MarshalMap(entry)
+		if err != nil {
+			panic(err)
+		}
+
+		_, err = db.db.PutItem(&dynamodb.PutItemInput{
+			TableName:           aws.String(auditTableName),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(ID)"),
+		})
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			entry.ID++
+			continue
+		} else if err != nil {
+			log.WithError(err).WithField("entry", entry).Error("failed audit storage")
+			return false
+		}
+
+		return true
+	}
+
+	log.WithField("entry", entry).Error("failed to find a unique audit id")
+	return false
+}
+
+// GetAuditEntries returns the audit entries matching `filter`, newest first
+func (db DatabaseContext) GetAuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	var (
+		entries []AuditEntry
+		err     error
+	)
+	input := &dynamodb.ScanInput{TableName: aws.String(auditTableName)}
+	err = db.db.ScanPages(input, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range page.Items {
+			var e AuditEntry
+			if uerr := dynamodbattribute.UnmarshalMap(item, &e); uerr != nil {
+				panic(fmt.Sprintf("Failed to unmarshal AuditEntry: %s", uerr))
+			}
+			if filter.matches(&e) {
+				entries = append(entries, e)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		log.WithError(err).Info("failed audit retrieval")
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID > entries[j].ID })
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+const (
+	// CampaignTargetOnboarded targets every attendee that onboarded with the key service
+	CampaignTargetOnboarded = "onboarded"
+	// CampaignTargetClaimed targets onboarded attendees that claimed their account on chain
+	CampaignTargetClaimed = "claimed"
+	// CampaignTargetIDs targets the onboarded attendees in a list of ids
+	CampaignTargetIDs = "ids"
+
+	// CampaignRunning is the status of a campaign that is still sending
+	CampaignRunning = "running"
+	// CampaignCompleted is the status of a campaign that went through every attendee
+	CampaignCompleted = "completed"
+	// CampaignCancelled is the status of a campaign that was stopped by an admin
+	CampaignCancelled = "cancelled"
+
+	// ResultSent is the result for an attendee that was emailed
+	ResultSent = "sent"
+	// ResultSkipped is the result for an attendee that is not targeted by the campaign
+	ResultSkipped = "skipped"
+	// ResultFailed is the result for an attendee that could not be emailed
+	ResultFailed = "failed"
+)
+
+// Campaign is a bulk export email job. AttendeeIDs are the candidates captured when the
+// campaign was created, they are filtered by `Target` as they are processed
+type Campaign struct {
+	ID          string    `json:"id" dynamodbav:"ID"`
+	Target      string    `json:"target"`
+	AttendeeIDs []int     `json:"attendee_ids"`
+	Status      string    `json:"status"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CampaignResult is the outcome of a campaign for a single attendee
+type CampaignResult struct {
+	CampaignID string    `json:"campaign_id" dynamodbav:"CampaignID"`
+	AttendeeID int       `json:"attendee_id" dynamodbav:"AttendeeID"`
+	Result     string    `json:"result"`
+	Reason     string    `json:"reason,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// StoreCampaign creates or updates `campaign`
+func (db DatabaseContext) StoreCampaign(campaign *Campaign) bool {
+	campaign.UpdatedAt = time.Now().UTC()
+	item, err := dynamodbattribute.MarshalMap(campaign)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(campaignTableName),
+		Item:      item,
+	})
+	if err != nil {
+		log.WithError(err).WithField("campaign", campaign.ID).Error("failed campaign storage")
+		return false
+	}
+
+	return true
+}
+
+// GetCampaign retrieves the campaign with `id`. nil is returned if it does not exist
+func (db DatabaseContext) GetCampaign(id string) (*Campaign, error) {
+	result, err := db.db.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(campaignTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {
+				S: aws.String(id),
+			},
+		},
+	})
+	if err != nil {
+		log.WithError(err).WithField("campaign", id).Info("failed campaign retrieval")
+		return nil, err
+	} else if result == nil || len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var c Campaign
+	if err = dynamodbattribute.UnmarshalMap(result.Item, &c); err != nil {
+		panic(fmt.Sprintf("Failed to unmarshal Campaign: %s", err))
+	}
+
+	return &c, nil
+}
+
+// GetCampaigns retrieves every campaign, newest first
+func (db DatabaseContext) GetCampaigns() ([]Campaign, error) {
+	var campaigns []Campaign
+	input := &dynamodb.ScanInput{TableName: aws.String(campaignTableName)}
+	err := db.db.ScanPages(input, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range page.Items {
+			var c Campaign
+			if uerr := dynamodbattribute.UnmarshalMap(item, &c); uerr != nil {
+				panic(fmt.Sprintf("Failed to unmarshal Campaign: %s", uerr))
+			}
+			campaigns = append(campaigns, c)
+		}
+		return true
+	})
+	if err != nil {
+		log.WithError(err).Info("failed campaigns retrieval")
+		return nil, err
+	}
+
+	sort.Slice(campaigns, func(i, j int) bool { return campaigns[i].CreatedAt.After(campaigns[j].CreatedAt) })
+	return campaigns, nil
+}
+
+// StoreCampaignResult records the outcome of a campaign for an attendee
+func (db DatabaseContext) StoreCampaignResult(result *CampaignResult) bool {
+	item, err := dynamodbattribute.MarshalMap(result)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(campaignResultTableName),
+		Item:      item,
+	})
+	if err != nil {
+		log.WithError(err).WithField("campaign", result.CampaignID).Error("failed campaign result storage")
+		return false
+	}
+
+	return true
+}
+
+// GetCampaignResults retrieves the recorded results of the campaign with `id`, ordered by attendee id
+func (db DatabaseContext) GetCampaignResults(id string) ([]CampaignResult, error) {
+	var results []CampaignResult
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(campaignResultTableName),
+		KeyConditionExpression: aws.String("CampaignID = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":id": {S: aws.String(id)},
+		},
+	}
+	err := db.db.QueryPages(input, func(page *dynamodb.QueryOutput, last bool) bool {
+		for _, item := range page.Items {
+			var r CampaignResult
+			if uerr := dynamodbattribute.UnmarshalMap(item, &r); uerr != nil {
+				panic(fmt.Sprintf("Failed to unmarshal CampaignResult: %s", uerr))
+			}
+			results = append(results, r)
+		}
+		return true
+	})
+	if err != nil {
+		log.WithError(err).WithField("campaign", id).Info("failed campaign results retrieval")
+		return nil, err
+	}
+
+	return results, nil
+}
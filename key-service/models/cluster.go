@@ -0,0 +1,356 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+const (
+	// KeyRequestQueued is the status of a key request waiting for the broadcaster
+	KeyRequestQueued = "queued"
+	// KeyRequestKeyed is the status of a key request whose attendee was keyed
+	KeyRequestKeyed = "keyed"
+	// KeyRequestAlreadyKeyed is the status of a key request whose attendee was keyed before
+	KeyRequestAlreadyKeyed = "already_keyed"
+	// KeyRequestFailed is the status of a key request that could not be committed
+	KeyRequestFailed = "failed"
+)
+
+// Lease elects a single holder among the key service instances until it expires
+type Lease struct {
+	Name    string
+	Holder  string
+	Expires int64
+}
+
+// KeyRequest is a MsgKey queued for the instance holding the broadcaster lease. Requests are
+// keyed by the bech32 attendee address, the public key and commitment are hex encoded
+type KeyRequest struct {
+	Address    string    `json:"address" dynamodbav:"Address"`
+	PubKey     string    `json:"pub_key"`
+	Commitment string    `json:"commitment"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	QueuedAt   time.Time `json:"queued_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OnboardRequest records the /key request of an attendee so that retries of the request are
+// answered without keying or emailing the attendee again. Owner is the request processing it
+// until LockedUntil
+type OnboardRequest struct {
+	AttendeeID  int
+	Fingerprint string
+	Owner       string
+	LockedUntil int64
+	Emailed     bool
+	Response    string
+	CreatedAt   time.Time
+}
+
+// AcquireLease takes or renews the lease `name` for `holder` for `ttl`. false is returned if
+// another holder has an unexpired lease
+func (db DatabaseContext) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	item, err := dynamodbattribute.MarshalMap(&Lease{Name: name, Holder: holder, Expires: now.Add(ttl).Unix()})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(leaseTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(#n) OR Expires < :now OR Holder = :holder"),
+		ExpressionAttributeNames: map[string]*string{
+			"#n": aws.String("Name"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now":    {N: aws.String(fmt.Sprintf("%d", now.Unix()))},
+			":holder": {S: aws.String(holder)},
+		},
+	})
+	if isConditionFailed(err) {
+		return false, nil
+	} else if err != nil {
+		log.WithError(err).WithField("lease", name).Info("failed lease acquisition")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseLease gives up the lease `name` if it is held by `holder`
+func (db DatabaseContext) ReleaseLease(name, holder string) error {
+	_, err := db.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(leaseTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Name": {S: aws.String(name)},
+		},
+		ConditionExpression: aws.String("Holder = :holder"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":holder": {S: aws.String(holder)},
+		},
+	})
+	if err != nil && !isConditionFailed(err) {
+		log.WithError(err).WithField("lease", name).Info("failed lease release")
+		return err
+	}
+
+	return nil
+}
+
+// EnqueueKeyRequest queues `req` for the broadcaster. false is returned if a request for the
+// attendee is already queued, the caller waits on that request instead
+func (db DatabaseContext) EnqueueKeyRequest(req *KeyRequest) (bool, error) {
+	req.Status = KeyRequestQueued
+	req.QueuedAt = time.Now().UTC()
+	req.UpdatedAt = req.QueuedAt
+	item, err := dynamodbattribute.MarshalMap(req)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(keyQueueTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(Address) OR #s <> :queued"),
+		ExpressionAttributeNames: map[string]*string{
+			"#s": aws.String("Status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":queued": {S: aws.String(KeyRequestQueued)},
+		},
+	})
+	if isConditionFailed(err) {
+		return false, nil
+	} else if err != nil {
+		log.WithError(err).WithField("address", req.Address).Info("failed key request enqueue")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetKeyRequest retrieves the latest key request of the attendee with `address`. nil is
+// returned if the attendee was never queued
+func (db DatabaseContext) GetKeyRequest(address string) (*KeyRequest, error) {
+	result, err := db.db.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(keyQueueTableName),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Address": {S: aws.String(address)},
+		},
+	})
+	if err != nil {
+		log.WithError(err).WithField("address", address).Info("failed key request retrieval")
+		return nil, err
+	} else if result == nil || len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var req KeyRequest
+	if err = dynamodbattribute.UnmarshalMap(result.Item, &req); err != nil {
+		panic(fmt.Sprintf("Failed to unmarshal KeyRequest: %s", err))
+	}
+
+	return &req, nil
+}
+
+// GetQueuedKeyRequests retrieves every key request waiting for the broadcaster
+func (db DatabaseContext) GetQueuedKeyRequests() ([]KeyRequest, error) {
+	var reqs []KeyRequest
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(keyQueueTableName),
+		ConsistentRead:   aws.Bool(true),
+		FilterExpression: aws.String("#s = :queued"),
+		ExpressionAttributeNames: map[string]*string{
+			"#s": aws.String("Status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":queued": {S: aws.String(KeyRequestQueued)},
+		},
+	}
+	err := db.db.ScanPages(input, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range page.Items {
+			var req KeyRequest
+			if uerr := dynamodbattribute.UnmarshalMap(item, &req); uerr != nil {
+				panic(fmt.Sprintf("Failed to unmarshal KeyRequest: %s", uerr))
+			}
+			reqs = append(reqs, req)
+		}
+		return true
+	})
+	if err != nil {
+		log.WithError(err).Info("failed queued key requests retrieval")
+		return nil, err
+	}
+
+	return reqs, nil
+}
+
+// StoreKeyRequest records the outcome of `req`
+func (db DatabaseContext) StoreKeyRequest(req *KeyRequest) bool {
+	req.UpdatedAt = time.Now().UTC()
+	item, err := dynamodbattribute.MarshalMap(req)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(keyQueueTableName),
+		Item:      item,
+	})
+	if err != nil {
+		log.WithError(err).WithField("address", req.Address).Error("failed key request storage")
+		return false
+	}
+
+	return true
+}
+
+// CreateOnboardRequest stores `req` if the attendee has no onboard request yet. false is
+// returned if one exists
+func (db DatabaseContext) CreateOnboardRequest(req *OnboardRequest) (bool, error) {
+	item, err := dynamodbattribute.MarshalMap(req)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(onboardTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(AttendeeID)"),
+	})
+	if isConditionFailed(err) {
+		return false, nil
+	} else if err != nil {
+		log.WithError(err).WithField("id", req.AttendeeID).Info("failed onboard request storage")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetOnboardRequest retrieves the onboard request of the attendee with `id`. nil is returned
+// if it does not exist
+func (db DatabaseContext) GetOnboardRequest(id int) (*OnboardRequest, error) {
+	result, err := db.db.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(onboardTableName),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]*dynamodb.AttributeValue{
+			"AttendeeID": {N: aws.String(idToString(id))},
+		},
+	})
+	if err != nil {
+		log.WithError(err).WithField("id", id).Info("failed onboard request retrieval")
+		return nil, err
+	} else if result == nil || len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var req OnboardRequest
+	if err = dynamodbattribute.UnmarshalMap(result.Item, &req); err != nil {
+		panic(fmt.Sprintf("Failed to unmarshal OnboardRequest: %s", err))
+	}
+
+	return &req, nil
+}
+
+// LockOnboardRequest hands the onboard request of the attendee with `id` to `owner` until
+// `until` for the keys with `fingerprint`. false is returned if the attendee was emailed or
+// another owner holds the lock
+func (db DatabaseContext) LockOnboardRequest(id int, owner, fingerprint string, until time.Time) (bool, error) {
+	_, err := db.db.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(onboardTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"AttendeeID": {N: aws.String(idToString(id))},
+		},
+		UpdateExpression:    aws.String("SET #o = :owner, Fingerprint = :fingerprint, LockedUntil = :until"),
+		ConditionExpression: aws.String("Emailed = :false AND LockedUntil < :now"),
+		ExpressionAttributeNames: map[string]*string{
+			"#o": aws.String("Owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner":       {S: aws.String(owner)},
+			":fingerprint": {S: aws.String(fingerprint)},
+			":until":       {N: aws.String(fmt.Sprintf("%d", until.Unix()))},
+			":now":         {N: aws.String(fmt.Sprintf("%d", time.Now().Unix()))},
+			":false":       {BOOL: aws.Bool(false)},
+		},
+	})
+	if isConditionFailed(err) {
+		return false, nil
+	} else if err != nil {
+		log.WithError(err).WithField("id", id).Info("failed onboard request lock")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CompleteOnboardRequest marks the attendee with `id` as emailed and stores the `response`
+// returned to retries. false is returned if `owner` no longer holds the lock
+func (db DatabaseContext) CompleteOnboardRequest(id int, owner, response string) (bool, error) {
+	_, err := db.db.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(onboardTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"AttendeeID": {N: aws.String(idToString(id))},
+		},
+		UpdateExpression:    aws.String("SET Emailed = :true, #r = :response, LockedUntil = :zero"),
+		ConditionExpression: aws.String("#o = :owner"),
+		ExpressionAttributeNames: map[string]*string{
+			"#o": aws.String("Owner"),
+			"#r": aws.String("Response"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner":    {S: aws.String(owner)},
+			":response": {S: aws.String(response)},
+			":true":     {BOOL: aws.Bool(true)},
+			":zero":     {N: aws.String("0")},
+		},
+	})
+	if isConditionFailed(err) {
+		return false, nil
+	} else if err != nil {
+		log.WithError(err).WithField("id", id).Error("failed onboard request completion")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UnlockOnboardRequest releases the lock `owner` holds on the onboard request of the attendee
+// with `id` so that a retry can proceed immediately
+func (db DatabaseContext) UnlockOnboardRequest(id int, owner string) error {
+	_, err := db.db.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(onboardTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"AttendeeID": {N: aws.String(idToString(id))},
+		},
+		UpdateExpression:    aws.String("SET LockedUntil = :zero"),
+		ConditionExpression: aws.String("#o = :owner"),
+		ExpressionAttributeNames: map[string]*string{
+			"#o": aws.String("Owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner": {S: aws.String(owner)},
+			":zero":  {N: aws.String("0")},
+		},
+	})
+	if err != nil && !isConditionFailed(err) {
+		log.WithError(err).WithField("id", id).Info("failed onboard request unlock")
+		return err
+	}
+
+	return nil
+}
+
+func isConditionFailed(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
@@ -17,6 +17,16 @@ const (
 	authTableName      = "linkedup-keyservice-auth"
 	emailTableName     = "linkedup-email"
 	blacklistTableName = "linkedup-blacklist"
+	auditTableName     = "linkedup-audit"
+
+	campaignTableName       = "linkedup-campaign"
+	campaignResultTableName = "linkedup-campaign-result"
+
+	leaseTableName    = "linkedup-lease"
+	keyQueueTableName = "linkedup-key-queue"
+	onboardTableName  = "linkedup-onboard"
+
+	sessionChallengeTableName = "linkedup-session-challenge"
 )
 
 var (
@@ -41,6 +51,14 @@ func NewDatabaseContext(localstack bool, contentBucket string) (DatabaseContext,
 	)
 }
 
+// Ping verifies that the attendee information table can be reached
+func (db DatabaseContext) Ping() error {
+	_, err := db.db.DescribeTable(&dynamodb.DescribeTableInput{
+		TableName: aws.String(infoTableName),
+	})
+	return err
+}
+
 // NewDatabaseContextWithCfg constructs a new DatabaseContext, using the given AWS
 // session handle.
 func NewDatabaseContextWithCfg(cfg client.ConfigProvider, localstack bool,
@@ -69,11 +87,9 @@ func NewDatabaseContextWithCfg(cfg client.ConfigProvider, localstack bool,
 
 	// try create the tables if they haven't already been instantiated
 	if err := createTables(context.db); err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			if aerr.Code() != dynamodb.ErrCodeResourceInUseException {
-				log.Info("dynamo table already created")
-				return context, nil
-			}
+		if _, ok := err.(awserr.Error); ok {
+			log.WithError(err).Warn("creating dynamo tables")
+			return context, nil
 		} else {
 			return context, err
 		}
@@ -102,43 +118,89 @@ func createTables(db *dynamodb.DynamoDB) error {
 		return err
 	}
 
-	_, err = db.CreateTable(&dynamodb.CreateTableInput{
-		BillingMode: aws.String("PAY_PER_REQUEST"),
-		TableName:   aws.String(blacklistTableName),
-		AttributeDefinitions: []*dynamodb.AttributeDefinition{
-			{
-				AttributeName: aws.String("Email"),
-				AttributeType: aws.String("S"),
-			},
-		},
-		KeySchema: []*dynamodb.KeySchemaElement{
-			{
-				AttributeName: aws.String("Email"),
-				KeyType:       aws.String("HASH"),
-			},
-		},
-	})
-	
-	return err
+	/** create table to store the admin audit log **/
+	err = createTable(db, auditTableName)
+	if err != nil {
+		return err
+	}
+
+	/** create tables to store bulk email campaigns and their progress **/
+	err = createTableWithKeys(db, campaignTableName, "ID", "S", "", "")
+	if err != nil {
+		return err
+	}
+	err = createTableWithKeys(db, campaignResultTableName, "CampaignID", "S", "AttendeeID", "N")
+	if err != nil {
+		return err
+	}
+
+	/** create tables coordinating the key service instances **/
+	err = createTableWithKeys(db, leaseTableName, "Name", "S", "", "")
+	if err != nil {
+		return err
+	}
+	err = createTableWithKeys(db, keyQueueTableName, "Address", "S", "", "")
+	if err != nil {
+		return err
+	}
+	err = createTableWithKeys(db, onboardTableName, "AttendeeID", "N", "", "")
+	if err != nil {
+		return err
+	}
+
+	/** create table to store the attendee sign in challenges **/
+	err = createTableWithKeys(db, sessionChallengeTableName, "Nonce", "S", "", "")
+	if err != nil {
+		return err
+	}
+
+	return createTableWithKeys(db, blacklistTableName, "Email", "S", "", "")
 }
 
-func createTable(db *dynamodb.DynamoDB, tableName string) error {
-	_, err := db.CreateTable(&dynamodb.CreateTableInput{
+// createTableWithKeys creates a table keyed by `hashKey` and, if set, `rangeKey`
+func createTableWithKeys(db *dynamodb.DynamoDB, tableName string,
+	hashKey, hashType, rangeKey, rangeType string) error {
+	input := &dynamodb.CreateTableInput{
 		BillingMode: aws.String("PAY_PER_REQUEST"),
 		TableName:   aws.String(tableName),
 		AttributeDefinitions: []*dynamodb.AttributeDefinition{
 			{
-				AttributeName: aws.String("ID"),
-				AttributeType: aws.String("N"),
+				AttributeName: aws.String(hashKey),
+				AttributeType: aws.String(hashType),
 			},
 		},
 		KeySchema: []*dynamodb.KeySchemaElement{
 			{
-				AttributeName: aws.String("ID"),
+				AttributeName: aws.String(hashKey),
 				KeyType:       aws.String("HASH"),
 			},
 		},
-	})
+	}
+	if len(rangeKey) > 0 {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, &dynamodb.AttributeDefinition{
+			AttributeName: aws.String(rangeKey),
+			AttributeType: aws.String(rangeType),
+		})
+		input.KeySchema = append(input.KeySchema, &dynamodb.KeySchemaElement{
+			AttributeName: aws.String(rangeKey),
+			KeyType:       aws.String("RANGE"),
+		})
+	}
+
+	_, err := db.CreateTable(input)
+	return ignoreTableExists(err)
+}
+
+func createTable(db *dynamodb.DynamoDB, tableName string) error {
+	return createTableWithKeys(db, tableName, "ID", "N", "", "")
+}
+
+// tables are created on every start. Tables that already exist are skipped so that
+// tables added in later releases are still created on existing deployments
+func ignoreTableExists(err error) error {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeResourceInUseException {
+		return nil
+	}
 	return err
 }
 
@@ -209,12 +271,3 @@ func (db DatabaseContext) GetImageUploadURL(id int) (string, error) {
 
 	return result, nil
 }
-
-// GetBlacklistEntry checks if a particular email is blacklisted
-func (db DatabaseContext) GetBlacklistEntry(email string) bool {
-	entry := getBlacklistEntry(&db, email)
-	if entry != nil {
-		return entry.Blacklisted
-	}
-	return false
-}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// SessionChallenge is a sign in challenge issued to an attendee. It is consumed once signed, the
+// table expires the unsigned challenges through its time to live on Expires
+type SessionChallenge struct {
+	Nonce      string
+	AttendeeID int
+	Address    string
+	Expires    int64
+}
+
+// StoreSessionChallenge stores `c` until it is signed
+func (db DatabaseContext) StoreSessionChallenge(c *SessionChallenge) bool {
+	item, err := dynamodbattribute.MarshalMap(c)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(sessionChallengeTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(Nonce)"),
+	})
+	if err != nil {
+		log.WithError(err).WithField("id", c.AttendeeID).Error("failed session challenge storage")
+		return false
+	}
+
+	return true
+}
+
+// TakeSessionChallenge deletes and returns the challenge with `nonce` if it was issued to the
+// attendee with `id`, so that it is only signed once across the key services. nil is returned
+// if the challenge does not exist, was already taken or was issued to another attendee
+func (db DatabaseContext) TakeSessionChallenge(nonce string, id int) (*SessionChallenge, error) {
+	result, err := db.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(sessionChallengeTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Nonce": {S: aws.String(nonce)},
+		},
+		ConditionExpression: aws.String("AttendeeID = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":id": {N: aws.String(idToString(id))},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	})
+	if isConditionFailed(err) {
+		return nil, nil
+	} else if err != nil {
+		log.WithError(err).WithField("id", id).Info("failed session challenge retrieval")
+		return nil, err
+	} else if result == nil || len(result.Attributes) == 0 {
+		return nil, nil
+	}
+
+	var c SessionChallenge
+	if err = dynamodbattribute.UnmarshalMap(result.Attributes, &c); err != nil {
+		panic(fmt.Sprintf("Failed to unmarshal SessionChallenge: %s", err))
+	}
+
+	return &c, nil
+}
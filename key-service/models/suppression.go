@@ -0,0 +1,155 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+const (
+	// SuppressionManual is the reason of an address suppressed by an admin
+	SuppressionManual = "manual"
+	// SuppressionBounce is the reason of an address that permanently bounced
+	SuppressionBounce = "bounce"
+	// SuppressionComplaint is the reason of an address whose recipient marked our email as spam
+	SuppressionComplaint = "complaint"
+)
+
+// Suppression is an entry of the email blacklist. Emails are not sent to addresses that are
+// `Blacklisted`. Entries stored before reasons were recorded only carry the first two fields
+type Suppression struct {
+	Email       string    `json:"email" dynamodbav:"Email"`
+	Blacklisted bool      `json:"blacklisted"`
+	Reason      string    `json:"reason,omitempty"`
+	Detail      string    `json:"detail,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// NormalizeEmail is the form email addresses are suppressed under
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// StoreSuppression creates or replaces the entry of `s.Email`
+func (db DatabaseContext) StoreSuppression(s *Suppression) bool {
+	s.Email = NormalizeEmail(s.Email)
+	if s.Time.IsZero() {
+		s.Time = time.Now().UTC()
+	}
+
+	item, err := dynamodbattribute.MarshalMap(s)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = db.db.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(blacklistTableName),
+		Item:      item,
+	})
+	if err != nil {
+		log.WithError(err).WithField("email", s.Email).Error("failed blacklist storage")
+		return false
+	}
+
+	return true
+}
+
+// GetSuppression returns the entry of `email`, nil if there is none
+func (db DatabaseContext) GetSuppression(email string) (*Suppression, error) {
+	s, err := getSuppression(&db, NormalizeEmail(email))
+	if s == nil && err == nil && email != NormalizeEmail(email) {
+		// entries used to be stored as entered
+		s, err = getSuppression(&db, email)
+	}
+	return s, err
+}
+
+// GetSuppressions returns every entry of the email blacklist, newest first
+func (db DatabaseContext) GetSuppressions() ([]Suppression, error) {
+	var entries []Suppression
+	input := &dynamodb.ScanInput{TableName: aws.String(blacklistTableName)}
+	err := db.db.ScanPages(input, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range page.Items {
+			var s Suppression
+			if uerr := dynamodbattribute.UnmarshalMap(item, &s); uerr != nil {
+				panic(fmt.Sprintf("Failed to unmarshal Suppression: %s", uerr))
+			}
+			entries = append(entries, s)
+		}
+		return true
+	})
+	if err != nil {
+		log.WithError(err).Info("failed blacklist retrieval")
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	return entries, nil
+}
+
+// DeleteSuppression removes the entry of `email` so that it can be emailed again
+func (db DatabaseContext) DeleteSuppression(email string) bool {
+	keys := []string{NormalizeEmail(email)}
+	if email != keys[0] {
+		keys = append(keys, email)
+	}
+
+	for _, key := range keys {
+		_, err := db.db.DeleteItem(&dynamodb.DeleteItemInput{
+			TableName: aws.String(blacklistTableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"Email": {S: aws.String(key)},
+			},
+		})
+		if err != nil {
+			log.WithError(err).WithField("email", key).Error("failed blacklist deletion")
+			return false
+		}
+	}
+
+	return true
+}
+
+// StoreBlacklistEntry sets whether emails to `email` are blacklisted
+func (db DatabaseContext) StoreBlacklistEntry(email string, blacklisted bool) bool {
+	return db.StoreSuppression(&Suppression{
+		Email:       email,
+		Blacklisted: blacklisted,
+		Reason:      SuppressionManual,
+	})
+}
+
+// GetBlacklistEntry checks if a particular email is blacklisted
+func (db DatabaseContext) GetBlacklistEntry(email string) bool {
+	s, err := db.GetSuppression(email)
+	if err != nil || s == nil {
+		return false
+	}
+	return s.Blacklisted
+}
+
+func getSuppression(db *DatabaseContext, email string) (*Suppression, error) {
+	result, err := db.db.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(blacklistTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Email": {S: aws.String(email)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	} else if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var s Suppression
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// used to generate a verification code 6 digits in length
+var table = [10]byte{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}
+
+// NewVerificationToken generates a random 6 digit token for `StoreVerificationToken`
+func NewVerificationToken() string {
+	b := make([]byte, 6)
+	n, err := io.ReadAtLeast(rand.Reader, b, 6)
+	if n != 6 {
+		panic(err)
+	}
+
+	for i := 0; i < len(b); i++ {
+		b[i] = table[int(b[i])%len(table)]
+	}
+	return string(b)
+}
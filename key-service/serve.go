@@ -3,11 +3,18 @@ package rekeyservice
 import (
 	"context"
 	"fmt"
+	"github.com/eco/longy/key-service/admin"
+	"github.com/eco/longy/key-service/campaign"
+	"github.com/eco/longy/key-service/cluster"
 	"github.com/eco/longy/key-service/eventbrite"
 	"github.com/eco/longy/key-service/handler"
+	"github.com/eco/longy/key-service/health"
 	"github.com/eco/longy/key-service/mail"
 	"github.com/eco/longy/key-service/masterkey"
 	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/key-service/session"
+	"github.com/eco/longy/key-service/suppression"
+	"github.com/eco/longy/x/longy/client/longyclient"
 	"github.com/sirupsen/logrus"
 	"net/http"
 	"os"
@@ -21,22 +28,46 @@ var log = logrus.WithField("module", "rekeyservice")
 // Service composes the required modules needed to manage the lifecycle
 type Service struct {
 	ebSession  *eventbrite.Session
-	masterKey  *masterkey.MasterKey
+	masterKey  masterkey.Keyer
+	onboarding *cluster.Onboarding
 	db         *models.DatabaseContext
 	mailClient mail.Client
+	campaigns  *campaign.Runner
+	sessions   *session.Issuer
+	adminKeys  admin.Keys
+	chain      *longyclient.Client
+	health     *health.Checker
+
+	suppressions *suppression.Receiver
 }
 
-// NewService is the creator the the rekey-service
+// NewService is the creator the the rekey-service. `key` is the master key or, when several
+// instances share it, the cluster node queueing key requests for the broadcaster
 func NewService(
 	ebSession *eventbrite.Session,
-	key *masterkey.MasterKey,
+	key masterkey.Keyer,
+	onboarding *cluster.Onboarding,
 	db *models.DatabaseContext,
-	mc mail.Client) Service {
+	mc mail.Client,
+	campaigns *campaign.Runner,
+	sessions *session.Issuer,
+	adminKeys admin.Keys,
+	chain *longyclient.Client,
+	checker *health.Checker,
+	suppressions *suppression.Receiver) Service {
 	return Service{
 		ebSession:  ebSession,
 		masterKey:  key,
+		onboarding: onboarding,
 		db:         db,
 		mailClient: mc,
+		campaigns:  campaigns,
+		sessions:   sessions,
+		adminKeys:  adminKeys,
+		chain:      chain,
+		health:     checker,
+
+		suppressions: suppressions,
 	}
 }
 
@@ -44,7 +75,9 @@ func NewService(
 func (srv *Service) StartHTTP(port int) {
 	s := &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
-		Handler: handler.Router(srv.ebSession, srv.masterKey, srv.db, srv.mailClient),
+		Handler: handler.Router(srv.ebSession, srv.masterKey, srv.onboarding, srv.db, srv.mailClient, srv.campaigns,
+			srv.sessions, srv.adminKeys, srv.chain, srv.health,
+			srv.suppressions),
 	}
 
 	// will block
@@ -56,7 +89,9 @@ func (srv *Service) StartHTTP(port int) {
 
 // Close will release the resources used by the server
 func (srv *Service) Close() {
+	srv.campaigns.Close()
 	srv.ebSession.Close()
+	srv.masterKey.Close()
 	log.Info("done")
 }
 
@@ -0,0 +1,284 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	ksCfg "github.com/eco/longy/key-service/config"
+	"github.com/eco/longy/key-service/models"
+	"github.com/eco/longy/x/longy/client/longyclient"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("module", "session")
+
+const (
+	// ChallengeTTL is how long an attendee has to sign a challenge
+	ChallengeTTL = 2 * time.Minute
+
+	// TTL is how long a session token is valid for
+	TTL = 12 * time.Hour
+
+	// SignDocType distinguishes the session sign doc from other documents signed by attendees
+	SignDocType = "linkedup-session"
+
+	nonceLength = 16
+)
+
+var (
+	// ErrUnknownChallenge denotes a nonce that was never issued, already used or issued to another attendee
+	ErrUnknownChallenge = errors.New("unknown or already used challenge")
+
+	// ErrChallengeExpired denotes a nonce that was not signed in time
+	ErrChallengeExpired = errors.New("challenge expired")
+
+	// ErrNotKeyed denotes an attendee without a public key on chain
+	ErrNotKeyed = errors.New("attendee has no public key on chain")
+
+	// ErrInvalidSignature denotes a signature that does not match the on chain public key
+	ErrInvalidSignature = errors.New("signature does not match the attendee public key")
+
+	// ErrInvalidSession denotes a malformed, forged or expired session token
+	ErrInvalidSession = errors.New("invalid or expired session")
+
+	// ErrStorage denotes a challenge that could not be stored
+	ErrStorage = errors.New("challenge storage failed")
+)
+
+// Challenges keeps the issued challenges until they are signed. Key services sharing their
+// challenges accept a challenge issued by any of them
+type Challenges interface {
+	StoreSessionChallenge(c *models.SessionChallenge) bool
+	TakeSessionChallenge(nonce string, id int) (*models.SessionChallenge, error)
+}
+
+// SignDoc is the document an attendee signs with their badge key to start a session
+type SignDoc struct {
+	Type       string `json:"type"`
+	ChainID    string `json:"chain_id"`
+	AttendeeID int    `json:"attendee_id"`
+	Address    string `json:"address"`
+	Nonce      string `json:"nonce"`
+	Expires    int64  `json:"expires"`
+}
+
+// Bytes returns the sorted json encoding of the sign doc that is signed by the attendee
+func (d SignDoc) Bytes() []byte {
+	bz, err := json.Marshal(d)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+// Session is a token proving control of an attendee's badge key
+type Session struct {
+	Token      string `json:"token"`
+	AttendeeID int    `json:"attendee_id"`
+	Expires    int64  `json:"expires"`
+}
+
+type claims struct {
+	AttendeeID int   `json:"id"`
+	Expires    int64 `json:"exp"`
+}
+
+// Issuer hands out sign in challenges and exchanges signed challenges for session tokens.
+// Tokens are authenticated with an HMAC over `secret`, so any key service sharing the
+// secret accepts them
+type Issuer struct {
+	secret     []byte
+	chainID    string
+	challenges Challenges
+
+	getAccount func(addr sdk.AccAddress) (auth.Account, error)
+}
+
+// NewIssuer is the constructor for `Issuer`. A random secret is used if `secret` is empty,
+// invalidating sessions when the service restarts. Attendee accounts are retrieved through `chain`.
+// Challenges are kept in memory if `challenges` is nil
+func NewIssuer(secret []byte, chainID string, chain *longyclient.Client, challenges Challenges) *Issuer {
+	return newIssuer(secret, chainID, chain.Account, challenges)
+}
+
+func newIssuer(secret []byte, chainID string, getAccount func(sdk.AccAddress) (auth.Account, error),
+	challenges Challenges) *Issuer {
+	if len(secret) == 0 {
+		log.Warn("no session secret configured. sessions will not survive a restart")
+		secret = make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			panic(err)
+		}
+	}
+
+	if challenges == nil {
+		challenges = &memoryChallenges{challenges: make(map[string]models.SessionChallenge)}
+	}
+
+	return &Issuer{
+		secret:     secret,
+		chainID:    chainID,
+		challenges: challenges,
+		getAccount: getAccount,
+	}
+}
+
+// Challenge returns the sign doc the attendee with `id` must sign to start a session
+func (i *Issuer) Challenge(id int) (SignDoc, error) {
+	b := make([]byte, nonceLength)
+	if _, err := rand.Read(b); err != nil {
+		return SignDoc{}, err
+	}
+
+	doc := SignDoc{
+		Type:       SignDocType,
+		ChainID:    i.chainID,
+		AttendeeID: id,
+		Address:    ksCfg.AttendeeAddress(fmt.Sprintf("%d", id)).String(),
+		Nonce:      hex.EncodeToString(b),
+		Expires:    time.Now().Add(ChallengeTTL).Unix(),
+	}
+
+	c := &models.SessionChallenge{
+		Nonce:      doc.Nonce,
+		AttendeeID: doc.AttendeeID,
+		Address:    doc.Address,
+		Expires:    doc.Expires,
+	}
+	if ok := i.challenges.StoreSessionChallenge(c); !ok {
+		return SignDoc{}, ErrStorage
+	}
+
+	return doc, nil
+}
+
+// SignIn verifies `sig`, the hex encoded signature of the challenge with `nonce`, against the
+// attendee's public key on chain. The challenge is consumed and a session is returned
+func (i *Issuer) SignIn(id int, nonce, sig string) (*Session, error) {
+	c, err := i.challenges.TakeSessionChallenge(nonce, id)
+	if err != nil {
+		return nil, err
+	} else if c == nil {
+		return nil, ErrUnknownChallenge
+	} else if c.Expires <= time.Now().Unix() {
+		return nil, ErrChallengeExpired
+	}
+
+	doc := SignDoc{
+		Type:       SignDocType,
+		ChainID:    i.chainID,
+		AttendeeID: c.AttendeeID,
+		Address:    c.Address,
+		Nonce:      c.Nonce,
+		Expires:    c.Expires,
+	}
+
+	addr, err := sdk.AccAddressFromBech32(doc.Address)
+	if err != nil {
+		panic(err)
+	}
+	acc, err := i.getAccount(addr)
+	if err != nil {
+		return nil, err
+	} else if acc == nil || acc.GetPubKey() == nil {
+		return nil, ErrNotKeyed
+	}
+
+	sigBytes, err := hex.DecodeString(sig)
+	if err != nil || !acc.GetPubKey().VerifyBytes(doc.Bytes(), sigBytes) {
+		return nil, ErrInvalidSignature
+	}
+
+	return i.issue(id, time.Now().Add(TTL).Unix()), nil
+}
+
+// Verify returns the attendee id the session `token` was issued to
+func (i *Issuer) Verify(token string) (int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return 0, ErrInvalidSession
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return 0, ErrInvalidSession
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(mac, i.mac(payload)) {
+		return 0, ErrInvalidSession
+	}
+
+	var c claims
+	if err = json.Unmarshal(payload, &c); err != nil || c.Expires <= time.Now().Unix() {
+		return 0, ErrInvalidSession
+	}
+
+	return c.AttendeeID, nil
+}
+
+func (i *Issuer) issue(id int, expires int64) *Session {
+	payload, err := json.Marshal(claims{AttendeeID: id, Expires: expires})
+	if err != nil {
+		panic(err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(i.mac(payload))
+	return &Session{
+		Token:      token,
+		AttendeeID: id,
+		Expires:    expires,
+	}
+}
+
+func (i *Issuer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, i.secret)
+	_, _ = h.Write(payload)
+	return h.Sum(nil)
+}
+
+// memoryChallenges keeps the challenges of a single key service
+type memoryChallenges struct {
+	mtx        sync.Mutex
+	challenges map[string]models.SessionChallenge
+}
+
+func (m *memoryChallenges) StoreSessionChallenge(c *models.SessionChallenge) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.prune()
+	m.challenges[c.Nonce] = *c
+	return true
+}
+
+func (m *memoryChallenges) TakeSessionChallenge(nonce string, id int) (*models.SessionChallenge, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	c, ok := m.challenges[nonce]
+	if !ok || c.AttendeeID != id {
+		return nil, nil
+	}
+	delete(m.challenges, nonce)
+	return &c, nil
+}
+
+// prune drops the expired challenges. The lock must be held
+func (m *memoryChallenges) prune() {
+	now := time.Now().Unix()
+	for nonce, c := range m.challenges {
+		if c.Expires <= now {
+			delete(m.challenges, nonce)
+		}
+	}
+}
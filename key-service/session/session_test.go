@@ -0,0 +1,149 @@
+package session
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/eco/longy/key-service/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Session Issuer", func() {
+	const id = 1234
+
+	var (
+		key      tmcrypto.PrivKey
+		keyed    bool
+		chainErr error
+		issuer   *Issuer
+	)
+
+	getAccount := func(addr sdk.AccAddress) (auth.Account, error) {
+		if chainErr != nil {
+			return nil, chainErr
+		}
+		acc := auth.NewBaseAccountWithAddress(addr)
+		if keyed {
+			_ = acc.SetPubKey(key.PubKey())
+		}
+		return &acc, nil
+	}
+
+	sign := func(doc SignDoc) string {
+		sig, err := key.Sign(doc.Bytes())
+		Expect(err).To(BeNil())
+		return hex.EncodeToString(sig)
+	}
+
+	BeforeEach(func() {
+		key = secp256k1.GenPrivKey()
+		keyed = true
+		chainErr = nil
+		issuer = newIssuer([]byte("secret"), "longychain", getAccount, nil)
+	})
+
+	It("issues a session for a signed challenge", func() {
+		doc, err := issuer.Challenge(id)
+		Expect(err).To(BeNil())
+		Expect(doc.Type).To(Equal(SignDocType))
+		Expect(doc.ChainID).To(Equal("longychain"))
+		Expect(doc.AttendeeID).To(Equal(id))
+
+		s, err := issuer.SignIn(id, doc.Nonce, sign(doc))
+		Expect(err).To(BeNil())
+		Expect(s.AttendeeID).To(Equal(id))
+		Expect(s.Expires).To(BeNumerically(">", time.Now().Add(TTL-time.Minute).Unix()))
+
+		verified, err := issuer.Verify(s.Token)
+		Expect(err).To(BeNil())
+		Expect(verified).To(Equal(id))
+	})
+
+	It("consumes the challenge", func() {
+		doc, _ := issuer.Challenge(id)
+		sig := sign(doc)
+		_, err := issuer.SignIn(id, doc.Nonce, sig)
+		Expect(err).To(BeNil())
+
+		_, err = issuer.SignIn(id, doc.Nonce, sig)
+		Expect(err).To(Equal(ErrUnknownChallenge))
+	})
+
+	It("rejects a challenge issued to another attendee", func() {
+		doc, _ := issuer.Challenge(id + 1)
+		_, err := issuer.SignIn(id, doc.Nonce, sign(doc))
+		Expect(err).To(Equal(ErrUnknownChallenge))
+
+		// the challenge is still usable by its attendee
+		_, err = issuer.SignIn(id+1, doc.Nonce, sign(doc))
+		Expect(err).To(BeNil())
+	})
+
+	It("accepts a challenge issued by another key service sharing the challenges", func() {
+		shared := &memoryChallenges{challenges: make(map[string]models.SessionChallenge)}
+		issuer = newIssuer([]byte("secret"), "longychain", getAccount, shared)
+		other := newIssuer([]byte("secret"), "longychain", getAccount, shared)
+
+		doc, err := issuer.Challenge(id)
+		Expect(err).To(BeNil())
+		s, err := other.SignIn(id, doc.Nonce, sign(doc))
+		Expect(err).To(BeNil())
+
+		verified, err := issuer.Verify(s.Token)
+		Expect(err).To(BeNil())
+		Expect(verified).To(Equal(id))
+
+		_, err = issuer.SignIn(id, doc.Nonce, sign(doc))
+		Expect(err).To(Equal(ErrUnknownChallenge))
+	})
+
+	It("rejects a signature from another key", func() {
+		doc, _ := issuer.Challenge(id)
+		sig := sign(doc)
+		key = secp256k1.GenPrivKey()
+
+		_, err := issuer.SignIn(id, doc.Nonce, sig)
+		Expect(err).To(Equal(ErrInvalidSignature))
+	})
+
+	It("rejects attendees that are not keyed", func() {
+		keyed = false
+		doc, _ := issuer.Challenge(id)
+		_, err := issuer.SignIn(id, doc.Nonce, sign(doc))
+		Expect(err).To(Equal(ErrNotKeyed))
+	})
+
+	It("surfaces chain failures", func() {
+		chainErr = errors.New("down")
+		doc, _ := issuer.Challenge(id)
+		_, err := issuer.SignIn(id, doc.Nonce, sign(doc))
+		Expect(err).To(Equal(chainErr))
+	})
+
+	It("rejects forged and expired sessions", func() {
+		s := issuer.issue(id, time.Now().Add(time.Hour).Unix())
+
+		other := newIssuer([]byte("other secret"), "longychain", getAccount, nil)
+		_, err := other.Verify(s.Token)
+		Expect(err).To(Equal(ErrInvalidSession))
+
+		forged := other.issue(id+1, time.Now().Add(time.Hour).Unix())
+		parts := strings.Split(s.Token, ".")
+		_, err = issuer.Verify(strings.Split(forged.Token, ".")[0] + "." + parts[1])
+		Expect(err).To(Equal(ErrInvalidSession))
+
+		expired := issuer.issue(id, time.Now().Add(-time.Second).Unix())
+		_, err = issuer.Verify(expired.Token)
+		Expect(err).To(Equal(ErrInvalidSession))
+
+		_, err = issuer.Verify("garbage")
+		Expect(err).To(Equal(ErrInvalidSession))
+	})
+})
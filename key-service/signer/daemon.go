@@ -0,0 +1,176 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/eco/longy/x/longy"
+	"github.com/sirupsen/logrus"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+var log = logrus.WithField("module", "signer")
+
+// MsgKeyType is the amino name of `longy.MsgKey`, the only message the key service signs
+const MsgKeyType = longy.RouterKey + "/MsgKey"
+
+// signDoc is the auth.StdSignDoc as it is signed. Every field is listed so that sign bytes
+// of any other document are refused
+type signDoc struct {
+	AccountNumber string            `json:"account_number"`
+	ChainID       string            `json:"chain_id"`
+	Fee           json.RawMessage   `json:"fee"`
+	Memo          string            `json:"memo"`
+	Msgs          []json.RawMessage `json:"msgs"`
+	Sequence      string            `json:"sequence"`
+}
+
+// aminoMsg is the envelope amino wraps around every registered message
+type aminoMsg struct {
+	Type string `json:"type"`
+}
+
+// Daemon holds the private key in a separate process and signs transactions of a single
+// chain whose messages are all in the allowlist
+type Daemon struct {
+	privKey tmcrypto.PrivKey
+	chainID string
+	allowed map[string]bool
+
+	server *http.Server
+}
+
+// NewDaemon is the constructor for `Daemon`. Only transactions for `chainID` with messages
+// of the `allowed` amino types are signed
+func NewDaemon(privKey tmcrypto.PrivKey, chainID string, allowed []string) *Daemon {
+	d := &Daemon{
+		privKey: privKey,
+		chainID: chainID,
+		allowed: make(map[string]bool, len(allowed)),
+	}
+	for _, t := range allowed {
+		d.allowed[t] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(pubKeyPath, d.pubKey)
+	mux.HandleFunc(signPath, d.sign)
+	d.server = &http.Server{Handler: mux}
+
+	return d
+}
+
+// Handler returns the http handler serving the sign requests
+func (d *Daemon) Handler() http.Handler {
+	return d.server.Handler
+}
+
+// ListenAndServe serves sign requests on the unix socket at `socketPath`. The socket is only
+// accessible by the user running the daemon
+func (d *Daemon) ListenAndServe(socketPath string) error {
+	// a socket left behind by a previous run prevents binding
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(socketPath, 0600); err != nil {
+		_ = l.Close()
+		return err
+	}
+
+	log.WithField("socket", socketPath).Info("signing daemon listening")
+	err = d.server.Serve(l)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Close stops serving sign requests
+func (d *Daemon) Close() error {
+	return d.server.Close()
+}
+
+func (d *Daemon) pubKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, pubKeyResponse{PubKey: hex.EncodeToString(d.privKey.PubKey().Bytes())})
+}
+
+func (d *Daemon) sign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body signRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("request body invalid: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := d.allow(body.SignBytes); err != nil {
+		log.WithError(err).Warn("refused to sign")
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	sig, err := d.privKey.Sign(body.SignBytes)
+	if err != nil {
+		http.Error(w, "signing failed", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, signResponse{Signature: sig})
+}
+
+// allow returns an error if `signBytes` is not a transaction for the daemon's chain with
+// only allowlisted messages
+func (d *Daemon) allow(signBytes []byte) error {
+	var doc signDoc
+	dec := json.NewDecoder(bytes.NewReader(signBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("not a transaction sign doc: %s", err)
+	}
+
+	if doc.ChainID != d.chainID {
+		return fmt.Errorf("unexpected chain id %s", doc.ChainID)
+	} else if len(doc.Msgs) == 0 {
+		return fmt.Errorf("transaction has no messages")
+	}
+
+	for _, raw := range doc.Msgs {
+		var msg aminoMsg
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return fmt.Errorf("malformed message: %s", err)
+		} else if !d.allowed[msg.Type] {
+			return fmt.Errorf("message type %q is not allowed", msg.Type)
+		}
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bz)
+}
@@ -0,0 +1,150 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+)
+
+const (
+	signPath   = "/sign"
+	pubKeyPath = "/pubkey"
+
+	// remoteHost is a placeholder, requests are always dialed to the socket
+	remoteHost = "http://signer"
+)
+
+// ErrRejected denotes sign bytes the signing daemon refused to sign
+var ErrRejected = errors.New("signing daemon rejected the sign bytes")
+
+// Signer signs transactions on behalf of an account without exposing its private key
+type Signer interface {
+	PubKey() tmcrypto.PubKey
+	Sign(signBytes []byte) ([]byte, error)
+}
+
+// signRequest is the body of a sign request to the signing daemon
+type signRequest struct {
+	SignBytes []byte `json:"sign_bytes"`
+}
+
+// signResponse is the body of a successful sign request
+type signResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// pubKeyResponse is the hex encoded amino public key of the signing daemon
+type pubKeyResponse struct {
+	PubKey string `json:"pub_key"`
+}
+
+// Local holds the private key in process
+type Local struct {
+	privKey tmcrypto.PrivKey
+}
+
+// NewLocal is the constructor for `Local`
+func NewLocal(privKey tmcrypto.PrivKey) *Local {
+	return &Local{privKey: privKey}
+}
+
+// PubKey returns the public key of the signer
+func (l *Local) PubKey() tmcrypto.PubKey {
+	return l.privKey.PubKey()
+}
+
+// Sign signs `signBytes` with the private key
+func (l *Local) Sign(signBytes []byte) ([]byte, error) {
+	return l.privKey.Sign(signBytes)
+}
+
+// Remote signs through a signing daemon listening on a unix socket. The private key
+// never enters this process
+type Remote struct {
+	client *http.Client
+	pubKey tmcrypto.PubKey
+}
+
+// NewRemote is the constructor for `Remote`. The public key is retrieved from the signing
+// daemon listening on `socketPath`
+func NewRemote(socketPath string) (*Remote, error) {
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	resp, err := client.Get(remoteHost + pubKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint
+
+	var body pubKeyResponse
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("unexpected public key response: %s", err)
+	}
+	bz, err := hex.DecodeString(body.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected public key response: %s", err)
+	}
+	pubKey, err := cryptoAmino.PubKeyFromBytes(bz)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected public key response: %s", err)
+	}
+
+	return &Remote{client: client, pubKey: pubKey}, nil
+}
+
+// PubKey returns the public key of the signing daemon
+func (r *Remote) PubKey() tmcrypto.PubKey {
+	return r.pubKey
+}
+
+// Sign asks the signing daemon to sign `signBytes`. `ErrRejected` is returned if the
+// daemon refuses them
+func (r *Remote) Sign(signBytes []byte) ([]byte, error) {
+	reqBz, err := json.Marshal(signRequest{SignBytes: signBytes})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Post(remoteHost+signPath, "application/json", bytes.NewReader(reqBz))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden:
+		return nil, ErrRejected
+	default:
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("signing daemon error %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var body signResponse
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("unexpected sign response: %s", err)
+	}
+	if !r.pubKey.VerifyBytes(signBytes, body.Signature) {
+		return nil, fmt.Errorf("signing daemon returned an invalid signature")
+	}
+
+	return body.Signature, nil
+}
@@ -0,0 +1,12 @@
+package signer
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestSigner(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Signer Suite")
+}
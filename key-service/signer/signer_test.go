@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Master Key Signer", func() {
+	const chainID = "longychain"
+	var (
+		privKey = secp256k1.GenPrivKeySecp256k1([]byte("master"))
+		master  = sdk.AccAddress(privKey.PubKey().Address())
+		fee     = auth.NewStdFee(50000, sdk.NewCoins(sdk.NewInt64Coin("longy", 0)))
+	)
+
+	keyMsg := func() sdk.Msg {
+		pub := secp256k1.GenPrivKeySecp256k1([]byte("1")).PubKey()
+		return longy.NewMsgKey(util.IDToAddress("1"), master, pub, util.Commitment{})
+	}
+
+	It("should sign in process", func() {
+		local := NewLocal(privKey)
+		signBytes := auth.StdSignBytes(chainID, 1, 2, fee, []sdk.Msg{keyMsg()}, "")
+
+		sig, err := local.Sign(signBytes)
+		Expect(err).To(BeNil())
+		Expect(local.PubKey().VerifyBytes(signBytes, sig)).To(BeTrue())
+	})
+
+	Context("signing daemon", func() {
+		var (
+			dir    string
+			daemon *Daemon
+			remote *Remote
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "signer")
+			Expect(err).To(BeNil())
+			socket := filepath.Join(dir, "signer.sock")
+
+			daemon = NewDaemon(privKey, chainID, []string{MsgKeyType})
+			go daemon.ListenAndServe(socket) //nolint
+
+			Eventually(func() error {
+				remote, err = NewRemote(socket)
+				return err
+			}).Should(BeNil())
+		})
+
+		AfterEach(func() {
+			Expect(daemon.Close()).To(BeNil())
+			Expect(os.RemoveAll(dir)).To(BeNil())
+		})
+
+		It("should only be accessible by the daemon user", func() {
+			info, err := os.Stat(filepath.Join(dir, "signer.sock"))
+			Expect(err).To(BeNil())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("should expose the public key of the daemon", func() {
+			Expect(remote.PubKey()).To(Equal(privKey.PubKey()))
+		})
+
+		It("should sign key transactions", func() {
+			signBytes := auth.StdSignBytes(chainID, 1, 2, fee, []sdk.Msg{keyMsg(), keyMsg()}, "")
+
+			sig, err := remote.Sign(signBytes)
+			Expect(err).To(BeNil())
+			Expect(privKey.PubKey().VerifyBytes(signBytes, sig)).To(BeTrue())
+		})
+
+		It("should refuse transactions of another chain", func() {
+			signBytes := auth.StdSignBytes("otherchain", 1, 2, fee, []sdk.Msg{keyMsg()}, "")
+
+			_, err := remote.Sign(signBytes)
+			Expect(err).To(Equal(ErrRejected))
+		})
+
+		It("should refuse messages outside the allowlist", func() {
+			bonus := longy.NewMsgBonus("2", master)
+			signBytes := auth.StdSignBytes(chainID, 1, 2, fee, []sdk.Msg{keyMsg(), bonus}, "")
+
+			_, err := remote.Sign(signBytes)
+			Expect(err).To(Equal(ErrRejected))
+		})
+
+		It("should refuse anything but a transaction sign doc", func() {
+			_, err := remote.Sign([]byte("arbitrary bytes"))
+			Expect(err).To(Equal(ErrRejected))
+
+			_, err = remote.Sign([]byte(`{"chain_id":"longychain","msgs":[],"type":"session"}`))
+			Expect(err).To(Equal(ErrRejected))
+
+			_, err = remote.Sign(auth.StdSignBytes(chainID, 1, 2, fee, nil, ""))
+			Expect(err).To(Equal(ErrRejected))
+		})
+	})
+})
@@ -0,0 +1,250 @@
+package suppression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/eco/longy/key-service/metrics"
+	"github.com/eco/longy/key-service/models"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("module", "suppression")
+
+const (
+	// maxBodySize bounds the notifications read. SNS messages are at most 256KB
+	maxBodySize = 1 << 20
+
+	// maxDetailLength bounds the diagnostic recorded with a suppression
+	maxDetailLength = 512
+
+	bounceNotification    = "Bounce"
+	complaintNotification = "Complaint"
+	permanentBounce       = "Permanent"
+)
+
+// ErrNoTopics denotes verified notifications without accepted topics. Any topic signed by SNS,
+// including one owned by another AWS account, could then suppress addresses
+var ErrNoTopics = errors.New("at least one topic arn is required to verify notifications")
+
+// Store records suppressed addresses
+type Store interface {
+	StoreSuppression(s *models.Suppression) bool
+}
+
+// sesNotification is a bounce or complaint notification published by SES. Identity notifications
+// set `NotificationType` and configuration set events set `EventType`
+type sesNotification struct {
+	NotificationType string        `json:"notificationType"`
+	EventType        string        `json:"eventType"`
+	Bounce           *sesBounce    `json:"bounce"`
+	Complaint        *sesComplaint `json:"complaint"`
+}
+
+type sesBounce struct {
+	BounceType        string         `json:"bounceType"`
+	BounceSubType     string         `json:"bounceSubType"`
+	BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+	FeedbackID        string         `json:"feedbackId"`
+}
+
+type sesComplaint struct {
+	ComplainedRecipients  []sesRecipient `json:"complainedRecipients"`
+	ComplaintFeedbackType string         `json:"complaintFeedbackType"`
+	FeedbackID            string         `json:"feedbackId"`
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+// Receiver is the http endpoint of an SNS subscription to SES bounce and complaint notifications.
+// Permanently bounced and complaining addresses are suppressed, transient bounces are ignored
+type Receiver struct {
+	store    Store
+	verifier *Verifier
+	topics   map[string]bool
+	confirm  func(subscribeURL string) error
+}
+
+// Option configures a `Receiver`
+type Option func(*Receiver)
+
+// WithTopics only accepts messages published to the topics in `arns`. A topic is required
+// unless the messages are not verified
+func WithTopics(arns ...string) Option {
+	return func(rc *Receiver) {
+		for _, arn := range arns {
+			if arn = strings.TrimSpace(arn); len(arn) > 0 {
+				rc.topics[arn] = true
+			}
+		}
+	}
+}
+
+// WithoutVerification accepts unsigned messages so that notifications can be posted locally.
+// Subscriptions are then confirmed by hand with the logged url
+func WithoutVerification() Option {
+	return func(rc *Receiver) {
+		rc.verifier = nil
+	}
+}
+
+// NewReceiver is the constructor for `Receiver`. Suppressions are recorded in `store`.
+// ErrNoTopics is returned if the messages are verified without `WithTopics`
+func NewReceiver(store Store, opts ...Option) (*Receiver, error) {
+	rc := &Receiver{
+		store:    store,
+		verifier: NewVerifier(),
+		topics:   make(map[string]bool),
+	}
+	rc.confirm = rc.visit
+	for _, opt := range opts {
+		opt(rc)
+	}
+	if rc.verifier != nil && len(rc.topics) == 0 {
+		return nil, ErrNoTopics
+	}
+
+	return rc, nil
+}
+
+// ServeHTTP handles a message posted by SNS. SNS retries deliveries that are not acknowledged
+// with a 2xx status, which is relied upon when the suppressions cannot be stored
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var msg Message
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&msg); err != nil {
+		http.Error(w, fmt.Sprintf("request body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	logger := log.WithField("topic", msg.TopicArn).WithField("message_id", msg.MessageID)
+	if len(rc.topics) > 0 && !rc.topics[msg.TopicArn] {
+		logger.Warn("message from an unexpected topic")
+		http.Error(w, "topic not accepted", http.StatusForbidden)
+		return
+	}
+	if rc.verifier != nil {
+		if err := rc.verifier.Verify(&msg); err != nil {
+			logger.WithError(err).Warn("message signature")
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	switch msg.Type {
+	case TypeSubscriptionConfirmation:
+		if rc.verifier == nil {
+			logger.WithField("subscribe_url", msg.SubscribeURL).Warn("unverified subscription, confirm it by visiting the url")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if err := rc.confirm(msg.SubscribeURL); err != nil {
+			logger.WithError(err).Error("subscription confirmation")
+			http.Error(w, "subscription not confirmed", http.StatusBadGateway)
+			return
+		}
+		logger.Info("subscription confirmed")
+		w.WriteHeader(http.StatusOK)
+	case TypeUnsubscribeConfirmation:
+		logger.Warn("subscription removed")
+		w.WriteHeader(http.StatusOK)
+	case TypeNotification:
+		var n sesNotification
+		if err := json.Unmarshal([]byte(msg.Message), &n); err != nil {
+			http.Error(w, fmt.Sprintf("notification: %s", err), http.StatusBadRequest)
+			return
+		}
+		if !rc.apply(&n) {
+			http.Error(w, "key-service down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, fmt.Sprintf("unknown message type %q", msg.Type), http.StatusBadRequest)
+	}
+}
+
+// apply suppresses the addresses of `n`. It returns false if any of them could not be stored
+func (rc *Receiver) apply(n *sesNotification) bool {
+	kind := n.NotificationType
+	if len(kind) == 0 {
+		kind = n.EventType
+	}
+
+	var suppressions []models.Suppression
+	switch {
+	case kind == bounceNotification && n.Bounce != nil:
+		if n.Bounce.BounceType != permanentBounce {
+			log.WithField("type", n.Bounce.BounceType).WithField("recipients", len(n.Bounce.BouncedRecipients)).
+				Info("ignoring a non-permanent bounce")
+			return true
+		}
+		for _, recipient := range n.Bounce.BouncedRecipients {
+			detail := fmt.Sprintf("%s/%s", n.Bounce.BounceType, n.Bounce.BounceSubType)
+			if len(recipient.DiagnosticCode) > 0 {
+				detail = fmt.Sprintf("%s: %s", detail, recipient.DiagnosticCode)
+			}
+			suppressions = append(suppressions, models.Suppression{
+				Email:  recipient.EmailAddress,
+				Reason: models.SuppressionBounce,
+				Detail: detail,
+				Source: "ses:" + n.Bounce.FeedbackID,
+			})
+		}
+	case kind == complaintNotification && n.Complaint != nil:
+		for _, recipient := range n.Complaint.ComplainedRecipients {
+			suppressions = append(suppressions, models.Suppression{
+				Email:  recipient.EmailAddress,
+				Reason: models.SuppressionComplaint,
+				Detail: n.Complaint.ComplaintFeedbackType,
+				Source: "ses:" + n.Complaint.FeedbackID,
+			})
+		}
+	default:
+		// deliveries and other events
+		return true
+	}
+
+	ok := true
+	for i := range suppressions {
+		s := &suppressions[i]
+		if len(strings.TrimSpace(s.Email)) == 0 {
+			continue
+		}
+		s.Blacklisted = true
+		if len(s.Detail) > maxDetailLength {
+			s.Detail = s.Detail[:maxDetailLength]
+		}
+
+		if !rc.store.StoreSuppression(s) {
+			ok = false
+			continue
+		}
+		metrics.Suppressions.WithLabelValues(s.Reason).Inc()
+		log.WithField("email", s.Email).WithField("reason", s.Reason).Info("address suppressed")
+	}
+
+	return ok
+}
+
+// visit confirms a subscription by retrieving its subscription url
+func (rc *Receiver) visit(subscribeURL string) error {
+	if !rc.verifier.isSNSURL(subscribeURL) {
+		return fmt.Errorf("subscription url not served by sns: %s", subscribeURL)
+	}
+
+	resp, err := rc.verifier.client.Get(subscribeURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package suppression
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/eco/longy/key-service/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const testTopic = "arn:aws:sns:us-west-1:123456789012:ses-notifications"
+
+// memoryStore keeps the suppressions in memory
+type memoryStore struct {
+	sync.Mutex
+	entries map[string]models.Suppression
+	fail    bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: make(map[string]models.Suppression)}
+}
+
+func (s *memoryStore) StoreSuppression(e *models.Suppression) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.fail {
+		return false
+	}
+	e.Email = models.NormalizeEmail(e.Email)
+	s.entries[e.Email] = *e
+	return true
+}
+
+func (s *memoryStore) get(email string) (models.Suppression, bool) {
+	s.Lock()
+	defer s.Unlock()
+	e, ok := s.entries[email]
+	return e, ok
+}
+
+func notification(ses string) *Message {
+	return &Message{
+		Type:      TypeNotification,
+		MessageID: "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+		TopicArn:  testTopic,
+		Message:   ses,
+		Timestamp: "2019-06-12T20:58:09.000Z",
+	}
+}
+
+func post(rc *Receiver, msg *Message) *httptest.ResponseRecorder {
+	bz, err := json.Marshal(msg)
+	Expect(err).To(BeNil())
+
+	// sns posts its messages as plain text
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/ses", bytes.NewReader(bz))
+	req.Header.Set("Content-Type", "text/plain; charset=UTF-8")
+	w := httptest.NewRecorder()
+	rc.ServeHTTP(w, req)
+	return w
+}
+
+const permanentBounceNotification = `{
+	"notificationType": "Bounce",
+	"bounce": {
+		"bounceType": "Permanent",
+		"bounceSubType": "General",
+		"bouncedRecipients": [
+			{"emailAddress": "Gone@Example.com", "diagnosticCode": "smtp; 550 5.1.1 user unknown"},
+			{"emailAddress": "left@example.com"}
+		],
+		"feedbackId": "bounce-feedback"
+	},
+	"mail": {"messageId": "mail-id"}
+}`
+
+var _ = Describe("Receiver", func() {
+	var store *memoryStore
+
+	BeforeEach(func() {
+		store = newMemoryStore()
+	})
+
+	It("requires a topic to verify notifications", func() {
+		_, err := NewReceiver(store)
+		Expect(err).To(Equal(ErrNoTopics))
+
+		_, err = NewReceiver(store, WithTopics(" "))
+		Expect(err).To(Equal(ErrNoTopics))
+	})
+
+	Context("without verification", func() {
+		var rc *Receiver
+
+		BeforeEach(func() {
+			var err error
+			rc, err = NewReceiver(store, WithoutVerification())
+			Expect(err).To(BeNil())
+		})
+
+		It("suppresses permanently bounced recipients", func() {
+			w := post(rc, notification(permanentBounceNotification))
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			e, ok := store.get("gone@example.com")
+			Expect(ok).To(BeTrue())
+			Expect(e.Blacklisted).To(BeTrue())
+			Expect(e.Reason).To(Equal(models.SuppressionBounce))
+			Expect(e.Detail).To(Equal("Permanent/General: smtp; 550 5.1.1 user unknown"))
+			Expect(e.Source).To(Equal("ses:bounce-feedback"))
+
+			e, ok = store.get("left@example.com")
+			Expect(ok).To(BeTrue())
+			Expect(e.Detail).To(Equal("Permanent/General"))
+		})
+
+		It("ignores transient bounces", func() {
+			w := post(rc, notification(`{"notificationType": "Bounce", "bounce": {"bounceType": "Transient",
+				"bounceSubType": "MailboxFull", "bouncedRecipients": [{"emailAddress": "full@example.com"}]}}`))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(store.entries).To(BeEmpty())
+		})
+
+		It("suppresses complaining recipients of configuration set events", func() {
+			w := post(rc, notification(`{"eventType": "Complaint", "complaint": {"complaintFeedbackType": "abuse",
+				"complainedRecipients": [{"emailAddress": "angry@example.com"}], "feedbackId": "complaint-feedback"}}`))
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			e, ok := store.get("angry@example.com")
+			Expect(ok).To(BeTrue())
+			Expect(e.Blacklisted).To(BeTrue())
+			Expect(e.Reason).To(Equal(models.SuppressionComplaint))
+			Expect(e.Detail).To(Equal("abuse"))
+		})
+
+		It("acknowledges deliveries", func() {
+			w := post(rc, notification(`{"notificationType": "Delivery", "delivery": {}}`))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(store.entries).To(BeEmpty())
+		})
+
+		It("asks for a retry when the suppressions cannot be stored", func() {
+			store.fail = true
+			w := post(rc, notification(permanentBounceNotification))
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+
+		It("rejects malformed notifications", func() {
+			w := post(rc, notification("not json"))
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+			w = post(rc, &Message{Type: "Unknown"})
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("does not confirm subscriptions", func() {
+			confirmed := false
+			rc.confirm = func(string) error {
+				confirmed = true
+				return nil
+			}
+
+			msg := &Message{Type: TypeSubscriptionConfirmation, TopicArn: testTopic,
+				SubscribeURL: "https://sns.us-west-1.amazonaws.com/?Action=ConfirmSubscription"}
+			w := post(rc, msg)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(confirmed).To(BeFalse())
+		})
+	})
+
+	It("only accepts the configured topics", func() {
+		rc, err := NewReceiver(store, WithoutVerification(), WithTopics(testTopic))
+		Expect(err).To(BeNil())
+
+		msg := notification(permanentBounceNotification)
+		msg.TopicArn = "arn:aws:sns:us-west-1:123456789012:other"
+		w := post(rc, msg)
+		Expect(w.Code).To(Equal(http.StatusForbidden))
+		Expect(store.entries).To(BeEmpty())
+
+		w = post(rc, notification(permanentBounceNotification))
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(store.entries).To(HaveLen(2))
+	})
+
+	Context("with verification", func() {
+		var (
+			rc      *Receiver
+			key     *rsa.PrivateKey
+			server  *httptest.Server
+			certURL string
+		)
+
+		sign := func(msg *Message, version string) *Message {
+			msg.SignatureVersion = version
+			msg.SigningCertURL = certURL
+
+			var (
+				sig []byte
+				err error
+			)
+			if version == "1" {
+				digest := sha1.Sum([]byte(msg.stringToSign())) //nolint:gosec
+				sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+			} else {
+				digest := sha256.Sum256([]byte(msg.stringToSign()))
+				sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+			}
+			Expect(err).To(BeNil())
+			msg.Signature = base64.StdEncoding.EncodeToString(sig)
+			return msg
+		}
+
+		BeforeEach(func() {
+			var err error
+			key, err = rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).To(BeNil())
+
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+				NotBefore:    time.Now().Add(-time.Hour),
+				NotAfter:     time.Now().Add(time.Hour),
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+			Expect(err).To(BeNil())
+			certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(certPEM)
+			}))
+			certURL = server.URL + "/SimpleNotificationService-test.pem"
+
+			rc, err = NewReceiver(store, WithTopics(testTopic))
+			Expect(err).To(BeNil())
+			rc.verifier.client = server.Client()
+			rc.verifier.certHost = regexp.MustCompile(`^127\.0\.0\.1$`)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("accepts notifications signed by sns", func() {
+			w := post(rc, sign(notification(permanentBounceNotification), "1"))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(store.entries).To(HaveLen(2))
+
+			msg := notification(`{"notificationType": "Complaint", "complaint": {` +
+				`"complainedRecipients": [{"emailAddress": "angry@example.com"}]}}`)
+			msg.Subject = "Amazon SES Email Event Notification"
+			w = post(rc, sign(msg, "2"))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(store.entries).To(HaveLen(3))
+		})
+
+		It("rejects tampered and unsigned notifications", func() {
+			msg := sign(notification(permanentBounceNotification), "2")
+			msg.Message = `{"notificationType": "Complaint", "complaint": {` +
+				`"complainedRecipients": [{"emailAddress": "victim@example.com"}]}}`
+			w := post(rc, msg)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+
+			w = post(rc, notification(permanentBounceNotification))
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(store.entries).To(BeEmpty())
+		})
+
+		It("only fetches signing certificates from sns", func() {
+			rc.verifier.certHost = snsHost
+			w := post(rc, sign(notification(permanentBounceNotification), "2"))
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(store.entries).To(BeEmpty())
+		})
+
+		It("confirms signed subscriptions", func() {
+			var confirmedURL string
+			rc.confirm = func(subscribeURL string) error {
+				confirmedURL = subscribeURL
+				return nil
+			}
+
+			msg := &Message{
+				Type:         TypeSubscriptionConfirmation,
+				MessageID:    "165545c9-2a5c-472c-8df2-7ff2be2b3b1b",
+				Token:        "2336412f37",
+				TopicArn:     testTopic,
+				Message:      "You have chosen to subscribe to the topic",
+				SubscribeURL: "https://sns.us-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=2336412f37",
+				Timestamp:    "2019-06-12T20:50:09.000Z",
+			}
+			w := post(rc, sign(msg, "1"))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(confirmedURL).To(Equal(msg.SubscribeURL))
+		})
+	})
+})
@@ -0,0 +1,161 @@
+package suppression
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Types of the messages delivered by SNS
+const (
+	TypeNotification             = "Notification"
+	TypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	TypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// snsHost matches the hosts SNS serves its signing certificates and subscription urls from
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// Message is the envelope of every message SNS posts to an http subscription
+type Message struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token,omitempty"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject,omitempty"`
+	Message          string `json:"Message"`
+	SubscribeURL     string `json:"SubscribeURL,omitempty"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// stringToSign builds the canonical form SNS signs. Notifications sign their subject, if any,
+// and confirmations sign their subscription token and url
+func (m *Message) stringToSign() string {
+	var fields []string
+	add := func(name, value string) {
+		fields = append(fields, name, value)
+	}
+
+	add("Message", m.Message)
+	add("MessageId", m.MessageID)
+	if m.Type == TypeNotification {
+		if len(m.Subject) > 0 {
+			add("Subject", m.Subject)
+		}
+	} else {
+		add("SubscribeURL", m.SubscribeURL)
+	}
+	add("Timestamp", m.Timestamp)
+	if m.Type != TypeNotification {
+		add("Token", m.Token)
+	}
+	add("TopicArn", m.TopicArn)
+	add("Type", m.Type)
+
+	return strings.Join(fields, "\n") + "\n"
+}
+
+// Verifier checks that messages were signed by SNS. Signing certificates are only fetched
+// from SNS hosts over https and are kept for the lifetime of the verifier
+type Verifier struct {
+	client   *http.Client
+	certHost *regexp.Regexp
+
+	mtx   sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewVerifier is the constructor for `Verifier`
+func NewVerifier() *Verifier {
+	return &Verifier{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		certHost: snsHost,
+		certs:    make(map[string]*x509.Certificate),
+	}
+}
+
+// Verify returns an error if the signature of `m` is not valid
+func (v *Verifier) Verify(m *Message) error {
+	var alg x509.SignatureAlgorithm
+	switch m.SignatureVersion {
+	case "1":
+		alg = x509.SHA1WithRSA
+	case "2":
+		alg = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("unsupported signature version %q", m.SignatureVersion)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %s", err)
+	}
+
+	cert, err := v.certificate(m.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	if err := cert.CheckSignature(alg, []byte(m.stringToSign()), sig); err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	return nil
+}
+
+// isSNSURL indicates if `rawURL` is an https url of an SNS host
+func (v *Verifier) isSNSURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Scheme == "https" && v.certHost.MatchString(u.Hostname())
+}
+
+func (v *Verifier) certificate(certURL string) (*x509.Certificate, error) {
+	if !v.isSNSURL(certURL) || !strings.HasSuffix(certURL, ".pem") {
+		return nil, fmt.Errorf("signing certificate not served by sns: %s", certURL)
+	}
+
+	v.mtx.Lock()
+	cert, ok := v.certs[certURL]
+	v.mtx.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	// fetched without the lock so that a slow host does not hold up the other messages
+	resp, err := v.client.Get(certURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching signing certificate: %s", err)
+	}
+	defer resp.Body.Close() //nolint
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching signing certificate: status %d", resp.StatusCode)
+	}
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetching signing certificate: %s", err)
+	}
+	block, _ := pem.Decode(bz)
+	if block == nil {
+		return nil, fmt.Errorf("signing certificate is not pem encoded")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("signing certificate: %s", err)
+	}
+
+	v.mtx.Lock()
+	v.certs[certURL] = cert
+	v.mtx.Unlock()
+	return cert, nil
+}
@@ -0,0 +1,12 @@
+package suppression
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestSuppression(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Suppression Suite")
+}
@@ -27,7 +27,7 @@ $LYD add-genesis-account $($LYCLI keys show alice -a) 1000longy,100000000stake
 $LYD add-genesis-account $($LYCLI keys show bob -a) 1000longy,100000000stake
 $LYD add-genesis-account $($LYCLI keys show redeemer -a) 1000longy,100000000stake
 
-# Set the default master and bonus key
+# Set the well-known development service keys. Use `lyd init-service-keys` for a deployed chain
 $LYD set-genesis-key-service
 $LYD set-genesis-bonus-service
 $LYD set-genesis-claim-service
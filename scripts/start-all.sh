@@ -9,7 +9,7 @@ fi
 
 # rest
 sleep 8
-bin/lycli rest-server --chain-id longychain --trust-node --laddr "tcp://0.0.0.0:1317" &
+bin/lycli rest-server --chain-id longychain --trust-node --laddr "tcp://0.0.0.0:1317" --insecure-dev &
 
 # S3 bucket
 # This will fail badly if you're not running localstack!
@@ -17,7 +17,15 @@ sleep 8
 aws --endpoint-url=http://localstack:4572 s3api create-bucket --acl public-read --bucket linkedup-user-content
 aws --endpoint-url=http://localstack:4572 s3 sync scripts/prize-imagery s3://linkedup-user-content/prizes
 
+# admin keys. a fresh local key with every scope is generated unless ADMIN_KEYS points to a key file
+if [ -z "$ADMIN_KEYS" ]; then
+  ADMIN_KEYS=/tmp/admin-keys.json
+  ADMIN_SECRET=$(head -c 32 /dev/urandom | od -An -tx1 | tr -d ' \n')
+  echo "[{\"name\": \"local\", \"secret\": \"$ADMIN_SECRET\", \"scopes\": [\"email-override\", \"bulk-send\", \"recovery-assist\", \"blacklist\", \"audit\"]}]" > $ADMIN_KEYS
+  echo "local admin secret: $ADMIN_SECRET"
+fi
+
 # key service
 sleep 8
-bin/ks --localstack
+bin/ks --localstack --admin-keys $ADMIN_KEYS --insecure-dev --ses-insecure-notifications
 
@@ -1,3 +1,3 @@
 #!/usr/bin/env bash
 
-lycli rest-server --chain-id testchain --trust-node
+lycli rest-server --chain-id testchain --trust-node --insecure-dev
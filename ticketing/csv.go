@@ -0,0 +1,136 @@
+package ticketing
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	csvIDColumn          = "id"
+	csvFirstNameColumn   = "first_name"
+	csvLastNameColumn    = "last_name"
+	csvNameColumn        = "name"
+	csvEmailColumn       = "email"
+	csvCompanyColumn     = "company"
+	csvJobTitleColumn    = "job_title"
+	csvTicketClassColumn = "ticket_class"
+)
+
+// CSVProvider reads attendees from a csv export. The first row of the file is the
+// header and must contain the `id` and `email` columns. The optional columns are
+// `first_name`, `last_name`, `name`, `company`, `job_title` and `ticket_class`.
+//
+// The file is re-read on every call so that edits are picked up without a restart
+type CSVProvider struct {
+	RoleMap
+	path string
+}
+
+var _ Provider = &CSVProvider{}
+
+// NewCSVProvider is the constructor for `CSVProvider`. The file at `path` is parsed once
+// to fail early on a malformed export
+func NewCSVProvider(path string, roles RoleMap) (*CSVProvider, error) {
+	p := &CSVProvider{
+		RoleMap: roles,
+		path:    path,
+	}
+
+	if _, err := p.Attendees(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Attendees lists every attendee in the csv file
+func (p *CSVProvider) Attendees() ([]AttendeeProfile, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("opening attendee csv: %s", err)
+	}
+	defer f.Close() //nolint
+
+	return ParseCSV(f)
+}
+
+// Attendee looks up the attendee with `id` in the csv file
+func (p *CSVProvider) Attendee(id int) (*AttendeeProfile, bool, error) {
+	attendees, err := p.Attendees()
+	if err != nil {
+		return nil, false, err
+	}
+
+	for i := range attendees {
+		if attendees[i].ID == id {
+			return &attendees[i], true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// ParseCSV decodes the attendees in `r`. See `CSVProvider` for the expected format
+func ParseCSV(r io.Reader) ([]AttendeeProfile, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %s", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{csvIDColumn, csvEmailColumn} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header missing the %s column", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var attendees []AttendeeProfile
+	seen := make(map[int]bool)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading csv line %d: %s", line, err)
+		}
+
+		id, err := strconv.Atoi(field(record, csvIDColumn))
+		if err != nil || id < 0 {
+			return nil, fmt.Errorf("csv line %d: id must be a positive integer", line)
+		} else if seen[id] {
+			return nil, fmt.Errorf("csv line %d: duplicate id %d", line, id)
+		}
+		seen[id] = true
+
+		attendees = append(attendees, AttendeeProfile{
+			ID:          id,
+			FirstName:   field(record, csvFirstNameColumn),
+			LastName:    field(record, csvLastNameColumn),
+			Name:        field(record, csvNameColumn),
+			Email:       field(record, csvEmailColumn),
+			Company:     field(record, csvCompanyColumn),
+			JobTitle:    field(record, csvJobTitleColumn),
+			TicketClass: field(record, csvTicketClassColumn),
+		})
+	}
+
+	return attendees, nil
+}
@@ -0,0 +1,41 @@
+package ticketing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := "ID,First_Name,Last_Name,Email,Ticket_Class\n" +
+		"1,Satoshi,Nakamoto,satoshi@example.com,Sponsors\n" +
+		"2, Hal,Finney,hal@example.com,General Admission\n"
+
+	attendees, err := ParseCSV(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, attendees, 2)
+
+	require.Equal(t, 1, attendees[0].ID)
+	require.Equal(t, "Satoshi Nakamoto", attendees[0].FullName())
+	require.Equal(t, "satoshi@example.com", attendees[0].Email)
+	require.Equal(t, RoleSponsor, DefaultRoleMap().Role(attendees[0].TicketClass))
+
+	require.Equal(t, "Hal", attendees[1].FirstName)
+	require.Equal(t, RoleAttendee, DefaultRoleMap().Role(attendees[1].TicketClass))
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"id,name\n1,foo\n",
+		"email,name\nfoo@example.com,foo\n",
+		"id,email\nabc,foo@example.com\n",
+		"id,email\n1,foo@example.com\n1,bar@example.com\n",
+	}
+
+	for _, data := range cases {
+		_, err := ParseCSV(strings.NewReader(data))
+		require.Errorf(t, err, "case: %q", data)
+	}
+}
@@ -0,0 +1,48 @@
+package ticketing
+
+import "strings"
+
+const (
+	// RoleAttendee is the role of a regular ticket holder
+	RoleAttendee = "attendee"
+	// RoleSponsor is the role of event sponsors
+	RoleSponsor = "sponsor"
+	// RoleSpeaker is the role of event speakers
+	RoleSpeaker = "speaker"
+	// RoleStaff is the role of event staff and volunteers
+	RoleStaff = "staff"
+	// RoleVIP is the role of invited guests
+	RoleVIP = "vip"
+)
+
+const (
+	//TicketSponsorNameLowerCase is the sponsors ticket type
+	TicketSponsorNameLowerCase = "sponsors"
+	//TicketSpeakerCescNameLowerCase is the cesc speakers ticket type
+	TicketSpeakerCescNameLowerCase = "cesc speakers"
+	//TicketSpeakerEpicenterNameLowerCase is the epicenter speakers ticket type
+	TicketSpeakerEpicenterNameLowerCase = "epicenter speakers"
+)
+
+// RoleMap maps lower case ticket class names to a role. Ticket classes that are
+// not present map to `RoleAttendee`
+type RoleMap map[string]string
+
+// DefaultRoleMap returns the ticket classes of SF Blockchain Week that are not regular attendees
+func DefaultRoleMap() RoleMap {
+	return RoleMap{
+		TicketSponsorNameLowerCase:          RoleSponsor,
+		TicketSpeakerCescNameLowerCase:      RoleSpeaker,
+		TicketSpeakerEpicenterNameLowerCase: RoleSpeaker,
+	}
+}
+
+// Role returns the role associated with `ticketClass`
+func (m RoleMap) Role(ticketClass string) string {
+	role, ok := m[strings.ToLower(strings.TrimSpace(ticketClass))]
+	if !ok {
+		return RoleAttendee
+	}
+
+	return role
+}
@@ -0,0 +1,44 @@
+package ticketing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Provider is the source of truth for the attendees of the event. Both the genesis
+// generation and the key service read attendees through this interface
+type Provider interface {
+	// Attendees lists every ticket holder of the event
+	Attendees() ([]AttendeeProfile, error)
+
+	// Attendee looks up a single ticket holder by `id`. false is returned if the
+	// provider does not know about this attendee
+	Attendee(id int) (*AttendeeProfile, bool, error)
+
+	// Role maps the ticket class of an attendee to the role they play in the game
+	Role(ticketClass string) string
+}
+
+// AttendeeProfile -
+type AttendeeProfile struct {
+	ID int `json:"id"`
+
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+
+	Name        string `json:"name,omitempty"`
+	Company     string `json:"company,omitempty"`
+	JobTitle    string `json:"job_title,omitempty"`
+	TicketClass string `json:"ticket_class_name,omitempty"`
+}
+
+// FullName returns the display name of the attendee. The first and last name are
+// joined if the provider did not supply a full name
+func (p *AttendeeProfile) FullName() string {
+	if len(p.Name) > 0 {
+		return p.Name
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s", p.FirstName, p.LastName))
+}
@@ -3,12 +3,30 @@ package util
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	tmcrypto "github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/tmhash"
 )
 
-// IDToAddress will deterministically calculate an `sdk.AccAddress` using the given `id`
+// attendeeAddressDomain separates attendee addresses from any other hash of the namespace and id
+const attendeeAddressDomain = "linkedup/attendee"
+
+// IDToAddress will deterministically calculate an `sdk.AccAddress` using the given `id` as the
+// secp256k1 private key seed. Anyone knowing `id` can derive the private key of the address, it
+// is only kept for chains started without an address namespace and for migrating them
 func IDToAddress(id string) sdk.AccAddress {
 	privKey := tmcrypto.GenPrivKeySecp256k1([]byte(id))
 	address := privKey.PubKey().Address()
 
 	return sdk.AccAddress(address)
 }
+
+// AttendeeAddress will deterministically calculate the address of the attendee with `id` for the
+// event `namespace`. The address is a truncated hash of the namespace and id, so no private key
+// corresponds to it. The legacy `IDToAddress` derivation is used when `namespace` is empty
+func AttendeeAddress(namespace, id string) sdk.AccAddress {
+	if len(namespace) == 0 {
+		return IDToAddress(id)
+	}
+
+	preimage := attendeeAddressDomain + "/" + namespace + "/" + id
+	return sdk.AccAddress(tmhash.SumTruncated([]byte(preimage)))
+}
@@ -13,3 +13,17 @@ func TestIDToAddress(t *testing.T) {
 
 	require.Equal(t, address, address2)
 }
+
+func TestAttendeeAddress(t *testing.T) {
+	address := AttendeeAddress("sfbw", "1234")
+	require.Equal(t, address, AttendeeAddress("sfbw", "1234"))
+	require.Len(t, address, 20)
+
+	// the address differs from the seed derivation and across namespaces
+	require.NotEqual(t, IDToAddress("1234"), address)
+	require.NotEqual(t, AttendeeAddress("ethdenver", "1234"), address)
+	require.NotEqual(t, AttendeeAddress("sfbw", "12345"), address)
+
+	// chains without a namespace keep the seed derivation
+	require.Equal(t, IDToAddress("1234"), AttendeeAddress("", "1234"))
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+// Secp256k1FromKeyring decrypts the private key stored under `name` in the keyring of the
+// client home `dir`
+func Secp256k1FromKeyring(dir, name, passphrase string) (tmcrypto.PrivKey, error) {
+	kb, err := keys.NewKeyBaseFromDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening keyring: %s", err)
+	}
+	defer kb.CloseDB()
+
+	privKey, err := kb.ExportPrivateKeyObject(name, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %s", name, err)
+	}
+
+	return privKey, nil
+}
@@ -0,0 +1,31 @@
+package longy
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// EndBlocker snapshots, seeds and expires the raffles and updates the gauges of the game with the state at
+// the end of the block
+//nolint:gocritic
+func EndBlocker(ctx sdk.Context, k Keeper) {
+	k.UpdateRaffles(ctx)
+
+	metrics := k.Metrics(ctx)
+
+	prizes, err := k.GetPrizes(ctx)
+	if err == nil {
+		for _, p := range prizes {
+			metrics.PrizesRemaining.With("tier", strconv.Itoa(int(p.Tier))).Set(float64(p.Quantity))
+		}
+	}
+
+	if bonus := k.GetBonus(ctx); bonus != nil {
+		metrics.BonusActive.Set(1)
+		metrics.BonusMultiplier.Set(bonus.GetMultiplier())
+	} else {
+		metrics.BonusActive.Set(0)
+		metrics.BonusMultiplier.Set(1)
+	}
+}
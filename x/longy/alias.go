@@ -14,10 +14,76 @@ const (
 	// RouterKey is the key for routing messages to our handler
 	RouterKey = types.RouterKey
 
+	// ServiceSeed is the public seed of the development key service account
+	ServiceSeed = types.ServiceSeed
+	// BonusServiceSeed is the public seed of the development bonus service account
+	BonusServiceSeed = types.BonusServiceSeed
+	// ClaimServiceSeed is the public seed of the development claim service account
+	ClaimServiceSeed = types.ClaimServiceSeed
+
+	// KeyServiceKeyName is the keyring name of the generated key service account
+	KeyServiceKeyName = types.KeyServiceKeyName
+	// BonusServiceKeyName is the keyring name of the generated bonus service account
+	BonusServiceKeyName = types.BonusServiceKeyName
+	// ClaimServiceKeyName is the keyring name of the generated claim service account
+	ClaimServiceKeyName = types.ClaimServiceKeyName
+
+	// DefaultRaffleRevealWindow is the time the bonus service has after the deadline of a raffle to reveal it
+	DefaultRaffleRevealWindow = types.DefaultRaffleRevealWindow
+
 	/** ErrCodes **/
 
 	// CodeAttendeeKeyed is the alias for AttendeeKeyed
 	CodeAttendeeKeyed = types.AttendeeKeyed
+	// CodeAttendeeNotFound is the alias for AttendeeNotFound
+	CodeAttendeeNotFound = types.AttendeeNotFound
+	// CodeMsgNotAllowed is the alias for MsgNotAllowed
+	CodeMsgNotAllowed = types.MsgNotAllowed
+	// CodeInvalidTx is the alias for InvalidTx
+	CodeInvalidTx = types.InvalidTx
+	// CodeTxTooLarge is the alias for TxTooLarge
+	CodeTxTooLarge = types.TxTooLarge
+	// CodeRateLimited is the alias for RateLimited
+	CodeRateLimited = types.RateLimited
+	// CodeInvalidAttestation is the alias for InvalidAttestation
+	CodeInvalidAttestation = types.InvalidAttestation
+	// CodeAttestationOutOfWindow is the alias for AttestationOutOfWindow
+	CodeAttestationOutOfWindow = types.AttestationOutOfWindow
+	// CodeAttestationReplayed is the alias for AttestationReplayed
+	CodeAttestationReplayed = types.AttestationReplayed
+	// CodeInvalidRaffle is the alias for InvalidRaffle
+	CodeInvalidRaffle = types.InvalidRaffle
+	// CodeRaffleNotFound is the alias for RaffleNotFound
+	CodeRaffleNotFound = types.RaffleNotFound
+	// CodeRaffleNotDrawable is the alias for RaffleNotDrawable
+	CodeRaffleNotDrawable = types.RaffleNotDrawable
+	// CodeInvalidBoothChallenge is the alias for InvalidBoothChallenge
+	CodeInvalidBoothChallenge = types.InvalidBoothChallenge
+	// CodeBoothChallengeNotFound is the alias for BoothChallengeNotFound
+	CodeBoothChallengeNotFound = types.BoothChallengeNotFound
+	// CodeBoothChallengeExpired is the alias for BoothChallengeExpired
+	CodeBoothChallengeExpired = types.BoothChallengeExpired
+	// CodeBoothChallengeAnswered is the alias for BoothChallengeAnswered
+	CodeBoothChallengeAnswered = types.BoothChallengeAnswered
+	// CodeInvalidCheckpoint is the alias for InvalidCheckpoint
+	CodeInvalidCheckpoint = types.InvalidCheckpoint
+	// CodeCheckpointNotFound is the alias for CheckpointNotFound
+	CodeCheckpointNotFound = types.CheckpointNotFound
+	// CodeCheckpointInactive is the alias for CheckpointInactive
+	CodeCheckpointInactive = types.CheckpointInactive
+	// CodeCheckpointFull is the alias for CheckpointFull
+	CodeCheckpointFull = types.CheckpointFull
+	// CodeCheckpointScanned is the alias for CheckpointScanned
+	CodeCheckpointScanned = types.CheckpointScanned
+	// CodeInvalidAchievement is the alias for InvalidAchievement
+	CodeInvalidAchievement = types.InvalidAchievement
+
+	// CodeInvalidTeamRules is the alias for InvalidTeamRules
+	CodeInvalidTeamRules = types.InvalidTeamRules
+	// CodeBoothChallengeOpen is the alias for BoothChallengeOpen
+	CodeBoothChallengeOpen = types.BoothChallengeOpen
+	// CodeBoothAnswerNotCommitted is the alias for BoothAnswerNotCommitted
+	CodeBoothAnswerNotCommitted = types.BoothAnswerNotCommitted
 )
 
 var (
@@ -30,6 +96,12 @@ var (
 	// NewKeeper is the new keeper function alias for longy
 	NewKeeper = keeper.NewKeeper
 
+	// PrometheusMetrics is the function alias creating the module metrics served by tendermint
+	PrometheusMetrics = keeper.PrometheusMetrics
+
+	// NopMetrics is the function alias creating module metrics that are discarded
+	NopMetrics = keeper.NopMetrics
+
 	// NewAttendee is the function alias for creating a new attendee
 	NewAttendee = types.NewAttendee
 
@@ -42,20 +114,125 @@ var (
 	// NewMsgClearBonus is the function alias for the MsgBonus type
 	NewMsgClearBonus = types.NewMsgClearBonus
 
+	// NewScanAttestation is the function alias for creating an unsigned ScanAttestation
+	NewScanAttestation = types.NewScanAttestation
+
+	// NewMsgScanAttestations is the function alias for the MsgScanAttestations type
+	NewMsgScanAttestations = types.NewMsgScanAttestations
+
+	// NewMsgCreateRaffle is the function alias for the MsgCreateRaffle type
+	NewMsgCreateRaffle = types.NewMsgCreateRaffle
+
+	// NewMsgRevealRaffle is the function alias for the MsgRevealRaffle type
+	NewMsgRevealRaffle = types.NewMsgRevealRaffle
+
+	// NewMsgCreateChallenge is the function alias for the MsgCreateChallenge type
+	NewMsgCreateChallenge = types.NewMsgCreateChallenge
+
+	// NewMsgCommitAnswer is the function alias for the MsgCommitAnswer type
+	NewMsgCommitAnswer = types.NewMsgCommitAnswer
+
+	// NewMsgAnswerChallenge is the function alias for the MsgAnswerChallenge type
+	NewMsgAnswerChallenge = types.NewMsgAnswerChallenge
+
+	// NewBoothChallengeCommitment is the function alias to commit to the answer of a booth challenge
+	NewBoothChallengeCommitment = types.NewBoothChallengeCommitment
+
+	// NewBoothAnswerCommitment is the function alias for an attendee to commit to their answer
+	NewBoothAnswerCommitment = types.NewBoothAnswerCommitment
+
+	// NewMsgCreateCheckpoint is the function alias for the MsgCreateCheckpoint type
+	NewMsgCreateCheckpoint = types.NewMsgCreateCheckpoint
+
+	// CheckpointSignBytes is the function alias for the document signed with the key of a checkpoint
+	CheckpointSignBytes = types.CheckpointSignBytes
+
+	// NewMsgScanCheckpoint is the function alias for the MsgScanCheckpoint type
+	NewMsgScanCheckpoint = types.NewMsgScanCheckpoint
+
 	// NewQuerier is the function alias for creating a new querier
 	NewQuerier = querier.NewQuerier
+
+	// DefaultTxQuota is the function alias for the quota of chains that do not set one
+	DefaultTxQuota = types.DefaultTxQuota
+
+	// LeaderTierCategory is the function alias for the leader board category of the fastest attendees to a tier
+	LeaderTierCategory = types.LeaderTierCategory
+
+	// DefaultTeamRules is the function alias for the team rules of chains that do not set them
+	DefaultTeamRules = types.DefaultTeamRules
+
+	// IsWellKnownServiceKey is the function alias checking for service keys derived from the public seeds
+	IsWellKnownServiceKey = types.IsWellKnownServiceKey
 )
 
 type (
 	// Keeper is the keeper alias for longy
 	Keeper = keeper.Keeper
 
+	// Metrics is the type alias for the module metrics
+	Metrics = keeper.Metrics
+
 	// Attendee is the type alias for Attendee
 	Attendee = types.Attendee
 
 	// MsgKey is the type alias for MsgKey
 	MsgKey = types.MsgKey
 
+	// ScanAttestation is the type alias for ScanAttestation
+	ScanAttestation = types.ScanAttestation
+
+	// MsgScanAttestations is the type alias for MsgScanAttestations
+	MsgScanAttestations = types.MsgScanAttestations
+
+	// MsgCreateRaffle is the type alias for MsgCreateRaffle
+	MsgCreateRaffle = types.MsgCreateRaffle
+
+	// MsgRevealRaffle is the type alias for MsgRevealRaffle
+	MsgRevealRaffle = types.MsgRevealRaffle
+
+	// Raffle is the type alias for Raffle
+	Raffle = types.Raffle
+
+	// RaffleProof is the type alias for RaffleProof
+	RaffleProof = types.RaffleProof
+
+	// MsgCreateChallenge is the type alias for MsgCreateChallenge
+	MsgCreateChallenge = types.MsgCreateChallenge
+
+	// MsgCommitAnswer is the type alias for MsgCommitAnswer
+	MsgCommitAnswer = types.MsgCommitAnswer
+
+	// MsgAnswerChallenge is the type alias for MsgAnswerChallenge
+	MsgAnswerChallenge = types.MsgAnswerChallenge
+
+	// BoothChallenge is the type alias for BoothChallenge
+	BoothChallenge = types.BoothChallenge
+
+	// BoothAnswer is the type alias for BoothAnswer
+	BoothAnswer = types.BoothAnswer
+
+	// MsgCreateCheckpoint is the type alias for MsgCreateCheckpoint
+	MsgCreateCheckpoint = types.MsgCreateCheckpoint
+
+	// MsgScanCheckpoint is the type alias for MsgScanCheckpoint
+	MsgScanCheckpoint = types.MsgScanCheckpoint
+
+	// Checkpoint is the type alias for Checkpoint
+	Checkpoint = types.Checkpoint
+
+	// CheckpointScan is the type alias for CheckpointScan
+	CheckpointScan = types.CheckpointScan
+
+	// Achievement is the type alias for Achievement
+	Achievement = types.Achievement
+
+	// AchievementProgress is the type alias for AchievementProgress
+	AchievementProgress = types.AchievementProgress
+
+	// Badge is the type alias for Badge
+	Badge = types.Badge
+
 	// GenesisAttendees is the array of attendees for the genesis file
 	GenesisAttendees = types.GenesisAttendees
 
@@ -65,6 +242,63 @@ type (
 	// GenesisPrizes is the array of prizes for the event
 	GenesisPrizes = types.GenesisPrizes
 
+	// GenesisRoles is the array of role rules for the event
+	GenesisRoles = types.GenesisRoles
+
+	// GenesisAttestedScans is the array of attested scans for the genesis file
+	GenesisAttestedScans = types.GenesisAttestedScans
+
+	// GenesisRaffles is the array of raffles for the genesis file
+	GenesisRaffles = types.GenesisRaffles
+
+	// GenesisBoothChallenges is the array of booth challenges for the genesis file
+	GenesisBoothChallenges = types.GenesisBoothChallenges
+
+	// GenesisBoothAnswers is the array of unrevealed booth answers for the genesis file
+	GenesisBoothAnswers = types.GenesisBoothAnswers
+
+	// GenesisCheckpoints is the array of venue checkpoints for the genesis file
+	GenesisCheckpoints = types.GenesisCheckpoints
+
+	// GenesisCheckpointScans is the array of checkpoint attendance for the genesis file
+	GenesisCheckpointScans = types.GenesisCheckpointScans
+
+	// GenesisAchievements is the array of achievements for the genesis file
+	GenesisAchievements = types.GenesisAchievements
+
+	// RoleRule is the type alias for RoleRule
+	RoleRule = types.RoleRule
+
+	// Scan is the type alias for Scan
+	Scan = types.Scan
+
+	// Prize is the type alias for Prize
+	Prize = types.Prize
+
+	// Win is the type alias for Win
+	Win = types.Win
+
+	// Bonus is the type alias for Bonus
+	Bonus = types.Bonus
+
+	// LeaderBoard is the type alias for LeaderBoard
+	LeaderBoard = types.LeaderBoard
+
+	// CategoryLeaderBoard is the type alias for CategoryLeaderBoard
+	CategoryLeaderBoard = types.CategoryLeaderBoard
+
+	// RankedAttendee is the type alias for RankedAttendee
+	RankedAttendee = types.RankedAttendee
+
+	// TeamLeaderBoard is the type alias for TeamLeaderBoard
+	TeamLeaderBoard = types.TeamLeaderBoard
+
+	// TeamRules is the type alias for TeamRules
+	TeamRules = types.TeamRules
+
 	// GenesisService is the genesis type for the service account
 	GenesisService = types.GenesisService
+
+	// TxQuota is the type alias for TxQuota
+	TxQuota = types.TxQuota
 )
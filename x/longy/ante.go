@@ -0,0 +1,100 @@
+package longy
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+// NewAnteHandler wraps `next`, the auth ante handler verifying signatures and fees, with the
+// rules of the game. Transactions are free, so they are checked before being executed:
+//
+// - attendee accounts can only send longy messages and longy messages can only be sent by
+//   attendee and service accounts
+// - scan, attested scan and info data cannot be larger than the genesis `max_data_bytes`
+// - every attendee signing a transaction uses one transaction of their quota, and the sender of
+//   attestations one per attestation, as each is handled like a scan. The quota is only consumed
+//   once the signatures are verified by `next`
+//nolint:gocritic
+func NewAnteHandler(k Keeper, next sdk.AnteHandler) sdk.AnteHandler {
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, sdk.Result, bool) {
+		attendees, charges, err := checkMsgs(ctx, k, tx.GetMsgs())
+		if err != nil {
+			return ctx, err.Result(), true
+		}
+
+		newCtx, res, abort := next(ctx, tx, simulate)
+		if abort {
+			return newCtx, res, abort
+		}
+
+		for _, addr := range attendees {
+			if err := k.ConsumeTxQuota(newCtx, addr, charges[addr.String()]); err != nil {
+				return newCtx, err.Result(), true
+			}
+		}
+
+		return newCtx, res, false
+	}
+}
+
+// checkMsgs checks the messages of a transaction and returns its attendee signers along with the
+// transactions charged to each of them. The attestations are charged to the sender rather than
+// their scanners, whose signatures are only verified by the handler
+//nolint:gocritic
+func checkMsgs(ctx sdk.Context, k Keeper, msgs []sdk.Msg) ([]sdk.AccAddress, map[string]uint, sdk.Error) {
+	quota := k.GetTxQuota(ctx)
+
+	var attendees []sdk.AccAddress
+	charges, attestations := make(map[string]uint), make(map[string]uint)
+	for _, msg := range msgs {
+		for _, signer := range msg.GetSigners() {
+			_, isAttendee := k.GetAttendee(ctx, signer)
+			switch {
+			case msg.Route() != RouterKey && isAttendee:
+				return nil, nil, types.ErrMsgNotAllowed("attendee account %s can only send %s messages, got %s",
+					signer, RouterKey, msg.Type())
+			case msg.Route() == RouterKey && !isAttendee && !isServiceAccount(ctx, k, signer):
+				return nil, nil, types.ErrMsgNotAllowed("%s messages can only be sent by attendee and service "+
+					"accounts, got %s from %s", RouterKey, msg.Type(), signer)
+			}
+
+			if isAttendee && charges[signer.String()] == 0 {
+				charges[signer.String()] = 1
+				attendees = append(attendees, signer)
+			}
+		}
+
+		var data [][]byte
+		switch msg := msg.(type) {
+		case types.MsgScanQr:
+			data = append(data, msg.Data)
+		case types.MsgInfo:
+			data = append(data, msg.Data)
+		case types.MsgScanAttestations:
+			for _, attestation := range msg.Attestations {
+				data = append(data, attestation.Data)
+			}
+			attestations[msg.Sender.String()] += uint(len(msg.Attestations))
+		}
+		for _, d := range data {
+			if quota.MaxDataBytes > 0 && len(d) > quota.MaxDataBytes {
+				return nil, nil, types.ErrDataSizeOverLimit("%s data size is over the limit of %d bytes",
+					msg.Type(), quota.MaxDataBytes)
+			}
+		}
+	}
+
+	//a transaction of attestations counts as one transaction per attestation
+	for _, addr := range attendees {
+		if n := attestations[addr.String()]; n > charges[addr.String()] {
+			charges[addr.String()] = n
+		}
+	}
+
+	return attendees, charges, nil
+}
+
+//nolint:gocritic
+func isServiceAccount(ctx sdk.Context, k Keeper, addr sdk.AccAddress) bool {
+	return k.IsServiceAccount(ctx, addr) || k.IsBonusServiceAccount(ctx, addr) || k.IsClaimServiceAccount(ctx, addr)
+}
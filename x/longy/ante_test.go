@@ -0,0 +1,156 @@
+package longy_test
+
+import (
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ante Handler Tests", func() {
+	var anteHandler sdk.AnteHandler
+	var nextCalls int
+	var nextAbort bool
+	var attendee, other types.Attendee
+	var masterAddr = util.IDToAddress("master")
+
+	newTx := func(msgs ...sdk.Msg) sdk.Tx {
+		return auth.NewStdTx(msgs, auth.NewStdFee(50000, sdk.NewCoins()), nil, "")
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		attendee = utils.AddAttendeeToKeeper(ctx, &keeper, "1", true, false)
+		other = utils.AddAttendeeToKeeper(ctx, &keeper, "2", true, false)
+		utils.SetServiceAccount(ctx, keeper, masterAddr)
+
+		nextCalls, nextAbort = 0, false
+		anteHandler = longy.NewAnteHandler(keeper, func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context,
+			sdk.Result, bool) {
+			nextCalls++
+			if nextAbort {
+				return ctx, sdk.ErrUnauthorized("signature verification failed").Result(), true
+			}
+			return ctx, sdk.Result{}, false
+		})
+	})
+
+	It("should admit longy messages from attendees", func() {
+		_, res, abort := anteHandler(ctx, newTx(types.NewMsgQrScan(attendee.Address, "2", nil)), false)
+		Expect(abort).To(BeFalse())
+		Expect(res.IsOK()).To(BeTrue())
+		Expect(nextCalls).To(Equal(1))
+	})
+
+	It("should admit longy messages from service accounts", func() {
+		msg := types.NewMsgKey(attendee.Address, masterAddr, nil, util.Commitment{})
+		_, _, abort := anteHandler(ctx, newTx(msg), false)
+		Expect(abort).To(BeFalse())
+	})
+
+	It("should reject other messages from attendees", func() {
+		msg := bank.MsgSend{FromAddress: attendee.Address, ToAddress: other.Address,
+			Amount: sdk.NewCoins(sdk.NewInt64Coin("longy", 10))}
+		_, res, abort := anteHandler(ctx, newTx(msg), false)
+		Expect(abort).To(BeTrue())
+		Expect(res.Codespace).To(Equal(types.LongyCodeSpace))
+		Expect(res.Code).To(Equal(types.MsgNotAllowed))
+		Expect(nextCalls).To(Equal(0))
+	})
+
+	It("should reject longy messages from unknown accounts", func() {
+		msg := types.NewMsgQrScan(util.IDToAddress("stranger"), "1", nil)
+		_, res, abort := anteHandler(ctx, newTx(msg), false)
+		Expect(abort).To(BeTrue())
+		Expect(res.Code).To(Equal(types.MsgNotAllowed))
+	})
+
+	It("should reject oversized scan and info data", func() {
+		data := []byte(strings.Repeat("a", types.MaxDataSize+1))
+		_, res, abort := anteHandler(ctx, newTx(types.NewMsgQrScan(attendee.Address, "2", data)), false)
+		Expect(abort).To(BeTrue())
+		Expect(res.Code).To(Equal(types.DataSizeOverLimit))
+
+		_, res, abort = anteHandler(ctx, newTx(types.NewMsgInfo(attendee.Address, other.Address, data)), false)
+		Expect(abort).To(BeTrue())
+		Expect(res.Code).To(Equal(types.DataSizeOverLimit))
+
+		attestation := types.NewScanAttestation(attendee.Address, "2", data, time.Now(), "1")
+		msg := types.NewMsgScanAttestations(attendee.Address, []types.ScanAttestation{attestation})
+		_, res, abort = anteHandler(ctx, newTx(msg), false)
+		Expect(abort).To(BeTrue())
+		Expect(res.Code).To(Equal(types.DataSizeOverLimit))
+		Expect(nextCalls).To(Equal(0))
+	})
+
+	Context("with a quota", func() {
+		BeforeEach(func() {
+			keeper.SetTxQuota(ctx, types.TxQuota{MaxTxs: 2, WindowSeconds: 60, MaxDataBytes: types.MaxDataSize})
+		})
+
+		It("should reject the transactions over the quota until the window ends", func() {
+			tx := newTx(types.NewMsgQrScan(attendee.Address, "2", nil))
+			for i := 0; i < 2; i++ {
+				_, _, abort := anteHandler(ctx, tx, false)
+				Expect(abort).To(BeFalse())
+			}
+
+			_, res, abort := anteHandler(ctx, tx, false)
+			Expect(abort).To(BeTrue())
+			Expect(res.Code).To(Equal(types.TxQuotaExceeded))
+
+			// other attendees have their own quota
+			_, _, abort = anteHandler(ctx, newTx(types.NewMsgQrScan(other.Address, "1", nil)), false)
+			Expect(abort).To(BeFalse())
+
+			later := ctx.WithBlockTime(ctx.BlockTime().Add(time.Minute))
+			_, _, abort = anteHandler(later, tx, false)
+			Expect(abort).To(BeFalse())
+			Expect(keeper.GetTxUsage(later, attendee.Address).Count).To(Equal(uint(1)))
+		})
+
+		It("should count every attestation against the quota of the sender", func() {
+			attestations := []types.ScanAttestation{
+				types.NewScanAttestation(other.Address, "1", nil, time.Now(), "1"),
+				types.NewScanAttestation(other.Address, "3", nil, time.Now(), "2"),
+			}
+			_, _, abort := anteHandler(ctx, newTx(types.NewMsgScanAttestations(attendee.Address, attestations)), false)
+			Expect(abort).To(BeFalse())
+			Expect(keeper.GetTxUsage(ctx, attendee.Address).Count).To(Equal(uint(2)))
+			Expect(keeper.GetTxUsage(ctx, other.Address).Count).To(BeZero())
+
+			_, res, abort := anteHandler(ctx, newTx(types.NewMsgQrScan(attendee.Address, "2", nil)), false)
+			Expect(abort).To(BeTrue())
+			Expect(res.Code).To(Equal(types.TxQuotaExceeded))
+
+			// more attestations than the quota never fit
+			attestations = append(attestations, types.NewScanAttestation(other.Address, "4", nil, time.Now(), "3"))
+			_, res, abort = anteHandler(ctx, newTx(types.NewMsgScanAttestations(other.Address, attestations)), false)
+			Expect(abort).To(BeTrue())
+			Expect(res.Code).To(Equal(types.TxQuotaExceeded))
+		})
+
+		It("should not consume the quota of transactions failing verification", func() {
+			nextAbort = true
+			_, _, abort := anteHandler(ctx, newTx(types.NewMsgQrScan(attendee.Address, "2", nil)), false)
+			Expect(abort).To(BeTrue())
+			Expect(keeper.GetTxUsage(ctx, attendee.Address).Count).To(BeZero())
+		})
+
+		It("should not limit service accounts", func() {
+			msg := types.NewMsgKey(attendee.Address, masterAddr, nil, util.Commitment{})
+			for i := 0; i < 3; i++ {
+				_, _, abort := anteHandler(ctx, newTx(msg), false)
+				Expect(abort).To(BeFalse())
+			}
+		})
+	})
+})
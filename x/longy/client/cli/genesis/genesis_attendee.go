@@ -6,33 +6,67 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/server"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/ticketing"
 	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/internal/types"
 	"github.com/eco/longy/x/longy/utils"
 	"github.com/spf13/cobra"
 )
 
+const (
+	flagAttendeeCSV = "csv"
+	flagRoleConfig  = "roles"
+
+	flagAddressNamespace = "address-namespace"
+)
+
 // AddGenesisAttendeesCmd returns add-genesis-attendees cobra Command. Allows users to add the list of attendees
-// to the chain by their eventbrite id
+// to the chain by their eventbrite id, or by the ids in a csv export when --csv is set. Ticket classes are mapped
+// to roles and their point rules by the --roles config file, or the SF Blockchain Week defaults if not set.
+// Attendee addresses are derived from --address-namespace, or the namespace already in the genesis file
 func AddGenesisAttendeesCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add-genesis-attendees",
 		Short: "Add genesis attendees to genesis.json",
-		RunE: func(_ *cobra.Command, args []string) error {
-			return addGenesisAttendees(ctx, cdc)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			csvPath, err := cmd.Flags().GetString(flagAttendeeCSV)
+			if err != nil {
+				return err
+			}
+			rolesPath, err := cmd.Flags().GetString(flagRoleConfig)
+			if err != nil {
+				return err
+			}
+			namespace, err := cmd.Flags().GetString(flagAddressNamespace)
+			if err != nil {
+				return err
+			}
+
+			roles := utils.DefaultRoleConfig()
+			if len(rolesPath) > 0 {
+				if roles, err = utils.LoadRoleConfig(rolesPath); err != nil {
+					return err
+				}
+			}
+			return addGenesisAttendees(ctx, cdc, csvPath, roles, namespace)
 		},
 	}
 
+	cmd.Flags().String(flagAttendeeCSV, "", "csv file to read the attendees from instead of eventbrite")
+	cmd.Flags().String(flagRoleConfig, "", "json file mapping ticket classes to roles and their point rules")
+	cmd.Flags().String(flagAddressNamespace, "", "event namespace the attendee addresses are derived with")
 	return cmd
 }
 
 // AddGenesisAttendees adds the attendees and the service account to the genesis file under the longy key
-func addGenesisAttendees(ctx *server.Context, cdc *codec.Codec) error {
+func addGenesisAttendees(ctx *server.Context, cdc *codec.Codec, csvPath string, roles utils.RoleConfig,
+	namespace string) error {
 	appState, genDoc, genFile, err := getGenesisState(ctx, cdc)
 	if err != nil {
 		return err
 	}
 
-	genesisState, err := buildAttendeeGenesisState(appState, cdc)
+	genesisState, err := buildAttendeeGenesisState(appState, cdc, csvPath, roles, namespace)
 	if err != nil {
 		return err
 	}
@@ -40,7 +74,8 @@ func addGenesisAttendees(ctx *server.Context, cdc *codec.Codec) error {
 }
 
 // BuildGenesisState builds the genesis state for the longy module
-func buildAttendeeGenesisState(appState map[string]json.RawMessage, cdc *codec.Codec) (longy.GenesisState, sdk.Error) {
+func buildAttendeeGenesisState(appState map[string]json.RawMessage, cdc *codec.Codec,
+	csvPath string, roles utils.RoleConfig, namespace string) (longy.GenesisState, sdk.Error) {
 	var (
 		genesisState longy.GenesisState
 		err          sdk.Error
@@ -48,9 +83,21 @@ func buildAttendeeGenesisState(appState map[string]json.RawMessage, cdc *codec.C
 
 	// add genesis attendees to the app state
 	cdc.MustUnmarshalJSON(appState[longy.ModuleName], &genesisState)
+	genesisState.Roles = roles.Rules
+	if len(namespace) > 0 {
+		genesisState.AddressNamespace = namespace
+	}
 
-	//get the attendees from eventbrite
-	genesisState.Attendees, err = utils.GetAttendees()
+	//get the attendees from the csv export or eventbrite
+	if len(csvPath) > 0 {
+		provider, e := ticketing.NewCSVProvider(csvPath, roles.TicketClasses)
+		if e != nil {
+			return genesisState, types.ErrDefault(e.Error())
+		}
+		genesisState.Attendees, err = utils.GetProviderAttendees(provider, genesisState.AddressNamespace)
+	} else {
+		genesisState.Attendees, err = utils.GetAttendees(roles.TicketClasses, genesisState.AddressNamespace)
+	}
 	fmt.Printf("adding attendees to genesis : %d\n", len(genesisState.Attendees))
 
 	if err != nil {
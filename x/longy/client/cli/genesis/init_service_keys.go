@@ -0,0 +1,77 @@
+package genesis
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/input"
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/codec"
+	crkeys "github.com/cosmos/cosmos-sdk/crypto/keys"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+const (
+	flagClientHome = "home-client"
+)
+
+// serviceKeys maps the service seeds to the keyring names of their generated keys
+var serviceKeys = []struct {
+	seed string
+	name string
+}{
+	{types.ServiceSeed, types.KeyServiceKeyName},
+	{types.BonusServiceSeed, types.BonusServiceKeyName},
+	{types.ClaimServiceSeed, types.ClaimServiceKeyName},
+}
+
+// InitServiceKeysCmd generates fresh key, bonus and claim service keys into the client keyring and
+// sets their public keys in the genesis file. The private keys never leave the keyring
+func InitServiceKeysCmd(ctx *server.Context, cdc *codec.Codec, defaultClientHome string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init-service-keys",
+		Short: "generate the service account keys into the keyring and set them in the genesis file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			kb, err := keys.NewKeyBaseFromDir(viper.GetString(flagClientHome))
+			if err != nil {
+				return err
+			}
+			defer kb.CloseDB()
+
+			for _, s := range serviceKeys {
+				if _, err = kb.Get(s.name); err == nil {
+					return fmt.Errorf("key %s already exists in the keyring", s.name)
+				}
+			}
+
+			buf := bufio.NewReader(cmd.InOrStdin())
+			passphrase, err := input.GetCheckPassword(
+				"Enter a passphrase to encrypt the service keys:", "Repeat the passphrase:", buf)
+			if err != nil {
+				return err
+			}
+
+			pubKeys := make(map[string]crypto.PubKey, len(serviceKeys))
+			for _, s := range serviceKeys {
+				info, mnemonic, err := kb.CreateMnemonic(s.name, crkeys.English, passphrase, crkeys.Secp256k1)
+				if err != nil {
+					return fmt.Errorf("generating %s: %s", s.name, err)
+				}
+				pubKeys[s.seed] = info.GetPubKey()
+
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s\nmnemonic: %s\n\n", s.name, info.GetAddress(), mnemonic)
+			}
+			fmt.Fprintln(cmd.ErrOrStderr(), "Write the mnemonics down in a safe place. They recover the service keys.")
+
+			return setGenesisServiceKeys(ctx, cdc, pubKeys)
+		},
+	}
+
+	cmd.Flags().String(flagClientHome, defaultClientHome, "client's home directory")
+	return cmd
+}
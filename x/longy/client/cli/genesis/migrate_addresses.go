@@ -0,0 +1,88 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/x/genaccounts"
+	"github.com/eco/longy/x/longy"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagMapping = "mapping"
+)
+
+// MigrateAttendeeAddressesCmd moves the attendees of the genesis file from their seed derived addresses
+// to the addresses derived with the event namespace. The accounts of the attendees are moved along and
+// the mapping from the old to the new addresses is written to --mapping, or stdout if not set
+func MigrateAttendeeAddressesCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-attendee-addresses [namespace]",
+		Short: "derive the attendee addresses of genesis.json from the event namespace",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mappingPath, err := cmd.Flags().GetString(flagMapping)
+			if err != nil {
+				return err
+			}
+
+			migrations, err := migrateAttendeeAddresses(ctx, cdc, args[0])
+			if err != nil {
+				return err
+			}
+
+			bz, err := json.MarshalIndent(migrations, "", "  ")
+			if err != nil {
+				return err
+			}
+			if len(mappingPath) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+				return nil
+			}
+			return ioutil.WriteFile(mappingPath, bz, 0600)
+		},
+	}
+
+	cmd.Flags().String(flagMapping, "", "json file to write the old to new address mapping to")
+	return cmd
+}
+
+func migrateAttendeeAddresses(ctx *server.Context, cdc *codec.Codec,
+	namespace string) ([]longy.AddressMigration, error) {
+	appState, genDoc, genFile, err := getGenesisState(ctx, cdc)
+	if err != nil {
+		return nil, err
+	}
+
+	var genState longy.GenesisState
+	if err = cdc.UnmarshalJSON(appState[longy.ModuleName], &genState); err != nil {
+		return nil, err
+	}
+
+	genState, migrations, sdkErr := longy.MigrateAttendeeAddresses(genState, namespace)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	// move the attendee accounts, exported genesis files contain their balances
+	addresses := make(map[string]longy.AddressMigration, len(migrations))
+	for _, m := range migrations {
+		addresses[m.OldAddress.String()] = m
+	}
+	accounts := genaccounts.GetGenesisStateFromAppState(cdc, appState)
+	for i := range accounts {
+		if m, ok := addresses[accounts[i].Address.String()]; ok {
+			accounts[i].Address = m.NewAddress
+		}
+	}
+	appState = genaccounts.SetGenesisStateInAppState(cdc, appState, accounts)
+
+	if err = updateGenesisState(cdc, genState, appState, genDoc, genFile); err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
@@ -5,16 +5,15 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/server"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/genutil"
 	"github.com/eco/longy/x/longy"
 	"github.com/eco/longy/x/longy/internal/types"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/crypto"
 	tmcrypto "github.com/tendermint/tendermint/crypto/secp256k1"
-	"github.com/tendermint/tendermint/libs/cli"
 )
 
-// AddSetGenesisKeyServiceCmd will set the testing master public/address keys where "master" is the seed
+// AddSetGenesisKeyServiceCmd will set the testing master public/address keys where "master" is the seed.
+// The seeds are public, use InitServiceKeysCmd for a deployed chain
 func AddSetGenesisKeyServiceCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "set-genesis-key-service",
@@ -51,13 +50,14 @@ func AddSetGenesisClaimServiceCmd(ctx *server.Context, cdc *codec.Codec) *cobra.
 }
 
 func setGenesisService(ctx *server.Context, cdc *codec.Codec, seed string) error {
-	config := ctx.Config
-	config.SetRoot(viper.GetString(cli.HomeFlag))
+	pubKey := tmcrypto.GenPrivKeySecp256k1([]byte(seed)).PubKey()
+	return setGenesisServiceKeys(ctx, cdc, map[string]crypto.PubKey{seed: pubKey})
+}
 
-	// retrieve genesis
-	// retrieve the app state
-	genFile := config.GenesisFile()
-	appState, genDoc, err := genutil.GenesisStateFromGenFile(cdc, genFile)
+// setGenesisServiceKeys sets the service accounts in the genesis file. The services are
+// identified by their seed constant
+func setGenesisServiceKeys(ctx *server.Context, cdc *codec.Codec, pubKeys map[string]crypto.PubKey) error {
+	appState, genDoc, genFile, err := getGenesisState(ctx, cdc)
 	if err != nil {
 		return err
 	}
@@ -67,34 +67,24 @@ func setGenesisService(ctx *server.Context, cdc *codec.Codec, seed string) error
 	if err != nil {
 		return err
 	}
-	pubKey := tmcrypto.GenPrivKeySecp256k1([]byte(seed)).PubKey()
-	sdkAddr := sdk.AccAddress(pubKey.Address())
-
-	switch seed {
-	case types.ServiceSeed:
-		genState.KeyService.Address = sdkAddr
-		genState.KeyService.PubKey = pubKey
-	case types.BonusServiceSeed:
-		genState.BonusService.Address = sdkAddr
-		genState.BonusService.PubKey = pubKey
-	case types.ClaimServiceSeed:
-		genState.ClaimService.Address = sdkAddr
-		genState.ClaimService.PubKey = pubKey
-	default:
-		return fmt.Errorf("seed %s is not allowed for a service account", seed)
-	}
 
-	bz, err := cdc.MarshalJSON(genState)
-	if err != nil {
-		return err
-	}
-	appState[longy.ModuleName] = bz
+	for seed, pubKey := range pubKeys {
+		sdkAddr := sdk.AccAddress(pubKey.Address())
 
-	appStateJSON, err := cdc.MarshalJSON(appState)
-	if err != nil {
-		return err
+		switch seed {
+		case types.ServiceSeed:
+			genState.KeyService.Address = sdkAddr
+			genState.KeyService.PubKey = pubKey
+		case types.BonusServiceSeed:
+			genState.BonusService.Address = sdkAddr
+			genState.BonusService.PubKey = pubKey
+		case types.ClaimServiceSeed:
+			genState.ClaimService.Address = sdkAddr
+			genState.ClaimService.PubKey = pubKey
+		default:
+			return fmt.Errorf("seed %s is not allowed for a service account", seed)
+		}
 	}
 
-	genDoc.AppState = appStateJSON
-	return genutil.ExportGenesisFile(genDoc, genFile)
+	return updateGenesisState(cdc, genState, appState, genDoc, genFile)
 }
@@ -1,31 +1,46 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/input"
 	"github.com/cosmos/cosmos-sdk/codec"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	longyCfg "github.com/eco/longy/key-service/config"
-	longyClnt "github.com/eco/longy/key-service/longyclient"
 	"github.com/eco/longy/util"
 	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/client/longyclient"
+	"github.com/eco/longy/x/longy/client/txsender"
 	"github.com/eco/longy/x/longy/internal/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/libs/cli"
 )
 
 //nolint
 func init() {
-	// default value uses "bonus" as the seed
-	longyTxCmd.PersistentFlags().String("private-key", "5f220b4a45832c0710e50d99fb0202a8554dcfe4e8593939d2820a689cbff212",
+	longyTxCmd.PersistentFlags().String("private-key", "",
 		"hex-encoded secp256k1 private key of the bonus service account")
+	longyTxCmd.PersistentFlags().String("key-name", "",
+		"name of the bonus service key in the keyring, i.e bonus-service from `lyd init-service-keys`")
+	longyTxCmd.PersistentFlags().Bool("insecure-dev", false,
+		"allow the well-known development bonus key. used when no key is set")
 	longyTxCmd.PersistentFlags().String("longy-rest-url", "http://localhost:1317", "scheme://host:port of the longy rest service")
+	longyTxCmd.PersistentFlags().String("broadcast-mode", txsender.BroadcastBlock,
+		"transaction broadcasting mode (sync|async|block)")
+	longyTxCmd.PersistentFlags().Duration("confirm-timeout", time.Minute,
+		"how long to wait for a sync or async transaction to be committed. 0 does not wait")
 	viper.BindPFlag("private-key", longyTxCmd.PersistentFlags().Lookup("private-key"))
+	viper.BindPFlag("key-name", longyTxCmd.PersistentFlags().Lookup("key-name"))
+	viper.BindPFlag("insecure-dev", longyTxCmd.PersistentFlags().Lookup("insecure-dev"))
 	viper.BindPFlag("longy-rest-url", longyTxCmd.PersistentFlags().Lookup("longy-rest-url"))
+	viper.BindPFlag("broadcast-mode", longyTxCmd.PersistentFlags().Lookup("broadcast-mode"))
+	viper.BindPFlag("confirm-timeout", longyTxCmd.PersistentFlags().Lookup("confirm-timeout"))
 }
 
 var longyTxCmd = &cobra.Command{
@@ -39,6 +54,8 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 	longyTxCmd.AddCommand(
 		createBonusCmd(),
 		clearBonusCmd(),
+		createRaffleCmd(),
+		revealRaffleCmd(),
 	)
 
 	return longyTxCmd
@@ -54,7 +71,7 @@ func createBonusCmd() *cobra.Command {
 			chainID := viper.GetString(client.FlagChainID)
 			restURL := viper.GetString("longy-rest-url")
 			fmt.Printf("Longy Rest Service URL: %s\n", restURL)
-			longyCfg.SetLongyRestURL(restURL)
+			chain := longyclient.New(restURL)
 
 			bonusAmt := args[0]
 			bonusNum, err := strconv.ParseFloat(args[0], 64)
@@ -62,28 +79,21 @@ func createBonusCmd() *cobra.Command {
 				return fmt.Errorf("multiplier must be a positive number > 0 in decimal format")
 			}
 
-			/** read in the private key and retrieve the bonus account */
-			bonusAccount, privKey, err := readBonusAccountFromViper()
+			/** read in the private key of the bonus account */
+			privKey, err := readBonusKeyFromViper()
 			if err != nil {
-				return fmt.Errorf("master account: %s", err)
+				return fmt.Errorf("bonus account: %s", err)
 			}
-
-			/** construct the message **/
-			bonusMsg := longy.NewMsgBonus(bonusAmt, bonusAccount.GetAddress())
+			sender := newSender(privKey, chainID, chain)
 
 			/** send the message **/
-			tx, err := createAuthTx(bonusMsg, privKey, chainID,
-				bonusAccount.GetAccountNumber(), bonusAccount.GetSequence())
-			if err != nil {
-				return fmt.Errorf("tx creation: %s", err)
-			}
-
-			res, err := longyClnt.BroadcastAuthTx(tx, "block")
+			bonusMsg := longy.NewMsgBonus(bonusAmt, sender.Address())
+			res, err := sender.Send(bonusMsg)
 			if err != nil {
 				return fmt.Errorf("tx submission: %s", err)
 			}
 
-			fmt.Printf("Transaction Response:\n%v\n", res)
+			fmt.Printf("Transaction Response:\n%v\n", res.Response)
 
 			return nil
 		},
@@ -100,69 +110,158 @@ func clearBonusCmd() *cobra.Command {
 			chainID := viper.GetString(client.FlagChainID)
 			restURL := viper.GetString("longy-rest-url")
 			fmt.Printf("Longy Rest Service URL: %s\n", restURL)
-			longyCfg.SetLongyRestURL(restURL)
+			chain := longyclient.New(restURL)
 
-			/** read in the private key and retrieve the master account */
-			bonusAccount, privKey, err := readBonusAccountFromViper()
+			/** read in the private key of the bonus account */
+			privKey, err := readBonusKeyFromViper()
 			if err != nil {
-				return fmt.Errorf("master account: %s", err)
+				return fmt.Errorf("bonus account: %s", err)
 			}
-
-			/** construct the message **/
-			clearBonusMsg := longy.NewMsgClearBonus(bonusAccount.GetAddress())
+			sender := newSender(privKey, chainID, chain)
 
 			/** send the message **/
-			tx, err := createAuthTx(clearBonusMsg, privKey, chainID,
-				bonusAccount.GetAccountNumber(), bonusAccount.GetSequence())
+			res, err := sender.Send(longy.NewMsgClearBonus(sender.Address()))
+			if err != nil {
+				return fmt.Errorf("tx submission: %s", err)
+			}
+
+			fmt.Printf("Transaction Response:\n%v\n", res.Response)
+
+			return nil
+		},
+	}
+}
+
+func createRaffleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-raffle <id> <prize-tier> <winners> <deadline>",
+		Short: "create a raffle drawing the prizes of a tier among the eligible attendees at the RFC3339 deadline",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			viper.BindPFlags(cmd.Flags()) //nolint
+			chainID := viper.GetString(client.FlagChainID)
+			restURL := viper.GetString("longy-rest-url")
+			fmt.Printf("Longy Rest Service URL: %s\n", restURL)
+			chain := longyclient.New(restURL)
+
+			prizeTier, err := strconv.ParseUint(args[1], 10, 32)
+			if err != nil {
+				return fmt.Errorf("prize tier must be a positive integer")
+			}
+			winners, err := strconv.ParseUint(args[2], 10, 32)
+			if err != nil {
+				return fmt.Errorf("winners must be a positive integer")
+			}
+			deadline, err := time.Parse(time.RFC3339, args[3])
+			if err != nil {
+				return fmt.Errorf("deadline must be in RFC3339 format, i.e 2019-10-27T18:00:00-07:00")
+			}
+			revealDeadline := deadline.Add(viper.GetDuration("reveal-window"))
+
+			/** read in the private key of the bonus account */
+			privKey, err := readBonusKeyFromViper()
 			if err != nil {
-				return fmt.Errorf("tx creation: %s", err)
+				return fmt.Errorf("bonus account: %s", err)
 			}
+			sender := newSender(privKey, chainID, chain)
 
-			res, err := longyClnt.BroadcastAuthTx(tx, "block")
+			/** commit to the secret that seeds the draw **/
+			secret, commitment := util.CreateCommitment()
+			msg := longy.NewMsgCreateRaffle(sender.Address(), args[0], uint(prizeTier), uint(winners),
+				viper.GetUint("min-tier"), viper.GetUint("min-rep"), deadline, revealDeadline, commitment)
+			if err := msg.ValidateBasic(); err != nil {
+				return fmt.Errorf("raffle: %s", err.Data())
+			}
+
+			/** send the message **/
+			res, err := sender.Send(msg)
 			if err != nil {
 				return fmt.Errorf("tx submission: %s", err)
 			}
 
-			fmt.Printf("Transaction Response:\n%v\n", res)
+			fmt.Printf("Transaction Response:\n%v\n", res.Response)
+			fmt.Printf("Raffle secret, keep it to reveal the raffle between its deadline and %s: %s\n",
+				revealDeadline.Format(time.RFC3339), secret)
 
 			return nil
 		},
 	}
+	cmd.Flags().Uint("min-tier", 0, "minimum tier of the attendees entering the raffle")
+	cmd.Flags().Uint("min-rep", 0, "minimum rep of the attendees entering the raffle")
+	cmd.Flags().Duration("reveal-window", longy.DefaultRaffleRevealWindow,
+		"time after the deadline to reveal the raffle, the prizes go back to their tier if it is not revealed")
+	return cmd
 }
 
-func readBonusAccountFromViper() (auth.Account, tmcrypto.PrivKey, error) {
-	privKey, err := util.Secp256k1FromHex(viper.GetString("private-key"))
-	if err != nil {
-		return nil, nil, fmt.Errorf("private key: %s", err)
-	}
+func revealRaffleCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reveal-raffle <id> <secret>",
+		Short: "reveal the secret of a raffle and draw its winners",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			viper.BindPFlags(cmd.Flags()) //nolint
+			chainID := viper.GetString(client.FlagChainID)
+			restURL := viper.GetString("longy-rest-url")
+			fmt.Printf("Longy Rest Service URL: %s\n", restURL)
+			chain := longyclient.New(restURL)
 
-	// retrieve the bonus account
-	addr := sdk.AccAddress(privKey.PubKey().Address())
-	bonusAccount, err := longyClnt.GetAccount(addr)
-	if err != nil {
-		return nil, nil, fmt.Errorf("retrieving bonus account: %s", err)
-	}
+			/** read in the private key of the bonus account */
+			privKey, err := readBonusKeyFromViper()
+			if err != nil {
+				return fmt.Errorf("bonus account: %s", err)
+			}
+			sender := newSender(privKey, chainID, chain)
+
+			/** send the message **/
+			res, err := sender.Send(longy.NewMsgRevealRaffle(sender.Address(), args[0], args[1]))
+			if err != nil {
+				return fmt.Errorf("tx submission: %s", err)
+			}
+
+			fmt.Printf("Transaction Response:\n%v\n", res.Response)
 
-	return bonusAccount, privKey, nil
+			return nil
+		},
+	}
 }
 
-func createAuthTx(
-	msg sdk.Msg,
-	privKey tmcrypto.PrivKey,
-	chainID string,
-	accNum, seqNum uint64) (*auth.StdTx, error) {
+// newSender sends the transactions of the bonus service in the configured broadcast mode
+func newSender(privKey tmcrypto.PrivKey, chainID string, chain *longyclient.Client) *txsender.Sender {
+	return txsender.New(privKey, chainID, chain,
+		txsender.WithMode(viper.GetString("broadcast-mode")),
+		txsender.WithConfirmation(viper.GetDuration("confirm-timeout"), txsender.DefaultPollInterval))
+}
 
-	msgs := []sdk.Msg{msg}
-	nilFee := auth.NewStdFee(50000, sdk.NewCoins(sdk.NewInt64Coin("longy", 0)))
-	signBytes := auth.StdSignBytes(chainID, accNum, seqNum, nilFee, msgs, "")
+// readBonusKeyFromViper loads the bonus key from the keyring or the hex flag. Keys derived from the
+// public service seeds are refused unless --insecure-dev is set
+func readBonusKeyFromViper() (tmcrypto.PrivKey, error) {
+	insecureDev := viper.GetBool("insecure-dev")
 
-	// sign the message with the master private key
-	sig, err := privKey.Sign(signBytes)
+	var (
+		privKey tmcrypto.PrivKey
+		err     error
+	)
+	switch {
+	case len(viper.GetString("key-name")) > 0:
+		buf := bufio.NewReader(os.Stdin)
+		passphrase, perr := input.GetPassword("Enter the passphrase of the bonus service key:", buf)
+		if perr != nil {
+			return nil, perr
+		}
+		privKey, err = util.Secp256k1FromKeyring(viper.GetString(cli.HomeFlag), viper.GetString("key-name"), passphrase)
+	case len(viper.GetString("private-key")) > 0:
+		privKey, err = util.Secp256k1FromHex(viper.GetString("private-key"))
+	case insecureDev:
+		privKey = secp256k1.GenPrivKeySecp256k1([]byte(types.BonusServiceSeed))
+	default:
+		return nil, fmt.Errorf("--key-name or --private-key must be set")
+	}
 	if err != nil {
 		return nil, err
 	}
-	stdSig := auth.StdSignature{PubKey: privKey.PubKey(), Signature: sig}
-	tx := auth.NewStdTx(msgs, nilFee, []auth.StdSignature{stdSig}, "")
 
-	return &tx, nil
+	if types.IsWellKnownServiceKey(privKey.PubKey()) && !insecureDev {
+		return nil, fmt.Errorf("refusing the well-known development key without --insecure-dev")
+	}
+	return privKey, nil
 }
@@ -0,0 +1,127 @@
+package longyclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+// Broadcast modes of the transactions posted to the rest server
+const (
+	BroadcastSync  = "sync"
+	BroadcastAsync = "async"
+	BroadcastBlock = "block"
+)
+
+// Account returns the account at `addr`. The rest server answers with an empty account for
+// addresses that do not exist on chain
+func (c *Client) Account(addr sdk.AccAddress) (auth.Account, error) {
+	bz, err := c.get(fmt.Sprintf("/auth/accounts/%s", addr), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err = json.Unmarshal(bz, &body); err != nil {
+		return nil, fmt.Errorf("unexpected account response, %s", err)
+	} else if len(body.Result) == 0 {
+		return nil, fmt.Errorf("result not present in the body")
+	}
+
+	var acc auth.BaseAccount
+	if err = auth.ModuleCdc.UnmarshalJSON(body.Result, &acc); err != nil {
+		return nil, fmt.Errorf("unexpected account response, %s", err)
+	}
+	return &acc, nil
+}
+
+// IsSyncing indicates if the full node behind the rest server is catching up with the chain
+func (c *Client) IsSyncing() (bool, error) {
+	bz, err := c.get("/syncing", nil)
+	if err != nil {
+		return false, err
+	}
+
+	var body struct {
+		Syncing bool `json:"syncing"`
+	}
+	if err = json.Unmarshal(bz, &body); err != nil {
+		return false, fmt.Errorf("unexpected syncing response, %s", err)
+	}
+	return body.Syncing, nil
+}
+
+// LatestBlock returns the height and time of the latest block of the full node
+func (c *Client) LatestBlock() (int64, time.Time, error) {
+	bz, err := c.get("/blocks/latest", nil)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var body struct {
+		BlockMeta struct {
+			Header struct {
+				Height string    `json:"height"`
+				Time   time.Time `json:"time"`
+			} `json:"header"`
+		} `json:"block_meta"`
+	}
+	if err = json.Unmarshal(bz, &body); err != nil {
+		return 0, time.Time{}, fmt.Errorf("unexpected latest block response, %s", err)
+	}
+	height, err := strconv.ParseInt(body.BlockMeta.Header.Height, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("unexpected latest block height, %s", err)
+	}
+
+	return height, body.BlockMeta.Header.Time, nil
+}
+
+// BroadcastTx posts `tx` to the open /longy/txs endpoint. `mode` is one of sync, async or block.
+// Transactions are not retried as the sequence of the signer is unknown after a failed broadcast
+func (c *Client) BroadcastTx(tx *auth.StdTx, mode string) (*sdk.TxResponse, error) {
+	if mode != BroadcastSync && mode != BroadcastAsync && mode != BroadcastBlock {
+		return nil, fmt.Errorf("incorrect broadcast mode")
+	}
+
+	body := struct {
+		Tx   *auth.StdTx `json:"tx"`
+		Mode string      `json:"mode"`
+	}{Tx: tx, Mode: mode}
+	bz, err := c.cdc.MarshalJSON(body)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err = c.post(modulePath("txs"), bz)
+	if err != nil {
+		return nil, err
+	}
+
+	var res sdk.TxResponse
+	if err = c.decode(bz, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Tx returns the committed transaction with the hex encoded `hash`. A transaction that is not
+// committed yet is reported as not found
+func (c *Client) Tx(hash string) (*sdk.TxResponse, error) {
+	bz, err := c.get(fmt.Sprintf("/txs/%s", hash), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res sdk.TxResponse
+	if err = c.decode(bz, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
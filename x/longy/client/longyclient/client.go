@@ -0,0 +1,188 @@
+package longyclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+const (
+	// DefaultTimeout is how long a single request may take, retries included
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultRetries is the number of times a failed query is retried
+	DefaultRetries = 3
+
+	// DefaultBackoff is the wait before the first retry. It doubles with every retry
+	DefaultBackoff = 250 * time.Millisecond
+)
+
+// Client queries the longy rest server and broadcasts transactions through it. Queries are
+// retried on network errors and server failures, transactions and claims are sent once
+type Client struct {
+	baseURL string
+	http    *http.Client
+	cdc     *codec.Codec
+	retries int
+	backoff time.Duration
+}
+
+// Option configures a `Client`
+type Option func(*Client)
+
+// WithTimeout bounds every request to `timeout`
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.http.Timeout = timeout
+	}
+}
+
+// WithRetries retries failed queries `retries` times, waiting `backoff` before the first retry
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries, c.backoff = retries, backoff
+	}
+}
+
+// WithHTTPClient sends the requests through `client`
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.http = client
+	}
+}
+
+// WithCodec decodes the responses and encodes the transactions with `cdc`. The codec must
+// register every message broadcast through the client
+func WithCodec(cdc *codec.Codec) Option {
+	return func(c *Client) {
+		c.cdc = cdc
+	}
+}
+
+// New is the constructor for `Client`. `baseURL` is the url of the rest server, i.e http://localhost:1317
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: DefaultTimeout},
+		cdc:     makeCodec(),
+		retries: DefaultRetries,
+		backoff: DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// BaseURL is the url of the rest server
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+func makeCodec() *codec.Codec {
+	cdc := codec.New()
+	sdk.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+	auth.RegisterCodec(cdc)
+	types.RegisterCodec(cdc)
+	return cdc
+}
+
+// get retrieves `path` and returns the body of an ok response
+func (c *Client) get(path string, query url.Values) ([]byte, error) {
+	reqURL := c.baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		bz, err := c.do(http.MethodGet, reqURL, nil)
+		if err == nil || attempt >= c.retries || !retryable(err) {
+			return bz, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post sends `body` to `path` once and returns the body of an ok response
+func (c *Client) post(path string, body []byte) ([]byte, error) {
+	return c.do(http.MethodPost, c.baseURL+path, body)
+}
+
+func (c *Client) do(method, reqURL string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, reqURL, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, decodeError(resp.StatusCode, bz)
+	}
+
+	return bz, nil
+}
+
+// retryable indicates if a query that failed with `err` may succeed when sent again
+func retryable(err error) bool {
+	e, ok := err.(*Error)
+	if !ok {
+		// the request did not complete
+		return true
+	}
+
+	return e.StatusCode == http.StatusTooManyRequests ||
+		(e.StatusCode >= http.StatusInternalServerError && e.Code == 0)
+}
+
+// decodeResult decodes the `result` of a response wrapped with its height
+func (c *Client) decodeResult(bz []byte, v interface{}) error {
+	var body struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(bz, &body); err != nil {
+		return fmt.Errorf("unexpected response, %s", err)
+	} else if len(body.Result) == 0 {
+		return fmt.Errorf("result not present in the body")
+	}
+
+	return c.decode(body.Result, v)
+}
+
+func (c *Client) decode(bz []byte, v interface{}) error {
+	if err := c.cdc.UnmarshalJSON(bz, v); err != nil {
+		return fmt.Errorf("unexpected response, %s", err)
+	}
+	return nil
+}
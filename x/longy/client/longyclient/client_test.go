@@ -0,0 +1,172 @@
+package longyclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/eco/longy/x/longy/client/rest/query"
+	"github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server *httptest.Server
+		client *Client
+
+		mtx      sync.Mutex
+		handlers map[string]http.HandlerFunc
+		calls    map[string]int
+	)
+
+	handle := func(path string, h http.HandlerFunc) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		handlers[path] = h
+	}
+	callsTo := func(path string) int {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return calls[path]
+	}
+
+	BeforeEach(func() {
+		handlers = make(map[string]http.HandlerFunc)
+		calls = make(map[string]int)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mtx.Lock()
+			calls[r.URL.Path]++
+			h, ok := handlers[r.URL.Path]
+			mtx.Unlock()
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			h(w, r)
+		}))
+		client = New(server.URL+"/", WithRetries(2, time.Millisecond))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("decodes the results wrapped with their height", func() {
+		handle("/longy/attendees/1234", func(w http.ResponseWriter, r *http.Request) {
+			result := client.cdc.MustMarshalJSON(types.NewAttendee("1234", "sponsor"))
+			_, _ = w.Write(client.cdc.MustMarshalJSON(rest.NewResponseWithHeight(10, result)))
+		})
+
+		attendee, err := client.Attendee("1234")
+		Expect(err).To(BeNil())
+		Expect(attendee.ID).To(Equal("1234"))
+		Expect(attendee.Role).To(Equal("sponsor"))
+	})
+
+	It("decodes the raw booleans", func() {
+		handle("/longy/attendees/1234/keyed", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("true"))
+		})
+
+		keyed, err := client.IsAttendeeKeyed("1234")
+		Expect(err).To(BeNil())
+		Expect(keyed).To(BeTrue())
+	})
+
+	It("decodes the longy error codes of failed queries", func() {
+		handle("/longy/attendees/1234/claimed", func(w http.ResponseWriter, r *http.Request) {
+			rest.WriteErrorResponse(w, http.StatusNotFound, types.ErrAttendeeNotFound("non-existent attendee id").ABCILog())
+		})
+
+		_, err := client.IsAttendeeClaimed("1234")
+		Expect(IsNotFound(err)).To(BeTrue())
+		Expect(IsCode(err, types.AttendeeNotFound)).To(BeTrue())
+		Expect(err.(*Error).Message).To(Equal("non-existent attendee id"))
+	})
+
+	It("decodes the longy error codes of failed claims", func() {
+		handle("/longy/claim", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprintf(w, `{"error": %q}`, types.ErrInvalidChallenge("unknown nonce").Error())
+		})
+
+		err := client.Claim(query.Claim{DeskID: "desk", Nonce: "nonce"})
+		Expect(IsCode(err, types.InvalidChallenge)).To(BeTrue())
+		Expect(err.(*Error).StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(err.(*Error).Message).To(Equal("unknown nonce"))
+	})
+
+	It("keeps the message of plain errors", func() {
+		handle("/longy/leader", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad request", http.StatusBadRequest)
+		})
+
+		_, err := client.LeaderBoard()
+		Expect(err).To(Equal(&Error{StatusCode: http.StatusBadRequest, Message: "bad request"}))
+	})
+
+	It("retries queries on server failures", func() {
+		handle("/longy/bonus", func(w http.ResponseWriter, r *http.Request) {
+			if callsTo("/longy/bonus") < 3 {
+				rest.WriteErrorResponse(w, http.StatusInternalServerError, "connection refused")
+				return
+			}
+			_, _ = w.Write(client.cdc.MustMarshalJSON(types.NewBonus("2")))
+		})
+
+		bonus, err := client.Bonus()
+		Expect(err).To(BeNil())
+		Expect(bonus.Multiplier).To(Equal("2"))
+		Expect(callsTo("/longy/bonus")).To(Equal(3))
+	})
+
+	It("gives up on queries once the retries are exhausted", func() {
+		handle("/syncing", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		_, err := client.IsSyncing()
+		Expect(err.(*Error).StatusCode).To(Equal(http.StatusBadGateway))
+		Expect(callsTo("/syncing")).To(Equal(3))
+	})
+
+	It("does not retry queries failing with an sdk error", func() {
+		handle("/longy/winnings", func(w http.ResponseWriter, r *http.Request) {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, types.ErrDefault("failed").ABCILog())
+		})
+
+		_, err := client.Winnings(nil)
+		Expect(IsCode(err, types.DefaultError)).To(BeTrue())
+		Expect(callsTo("/longy/winnings")).To(Equal(1))
+	})
+
+	It("reports the absence of a bonus period", func() {
+		handle("/longy/bonus", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		bonus, err := client.Bonus()
+		Expect(err).To(BeNil())
+		Expect(bonus).To(BeNil())
+	})
+
+	It("broadcasts transactions once", func() {
+		handle("/longy/txs", func(w http.ResponseWriter, r *http.Request) {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, "connection refused")
+		})
+
+		_, err := client.BroadcastTx(&auth.StdTx{}, BroadcastBlock)
+		Expect(err).ToNot(BeNil())
+		Expect(callsTo("/longy/txs")).To(Equal(1))
+
+		_, err = client.BroadcastTx(&auth.StdTx{}, "commit")
+		Expect(err).ToNot(BeNil())
+		Expect(callsTo("/longy/txs")).To(Equal(1))
+	})
+})
@@ -0,0 +1,80 @@
+package longyclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+// Error is a non-ok response of the rest server. `Codespace` and `Code` are set when the
+// server failed with an sdk error, i.e a longy error code from the module types
+type Error struct {
+	StatusCode int
+	Codespace  sdk.CodespaceType
+	Code       sdk.CodeType
+	Message    string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%d %s: %s code %d: %s", e.StatusCode, http.StatusText(e.StatusCode),
+			e.Codespace, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// IsCode indicates if `err` is a response that failed with the longy error `code`
+func IsCode(err error, code sdk.CodeType) bool {
+	e, ok := err.(*Error)
+	return ok && e.Codespace == types.LongyCodeSpace && e.Code == code
+}
+
+// IsNotFound indicates if `err` is a response for a missing item
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// sdkErrorText matches the string form of an sdk error
+var sdkErrorText = regexp.MustCompile(`(?s)Codespace: (\S*)\nCode: (\d+)\nMessage: (.*)`)
+
+// decodeError decodes the body of a non-ok response. Handlers write either rest error responses
+// or plain text, and the message of the former is the json abci log or the string form of an sdk error
+func decodeError(status int, body []byte) *Error {
+	e := &Error{StatusCode: status, Message: strings.TrimSpace(string(body))}
+
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && len(resp.Error) > 0 {
+		e.Message = resp.Error
+	}
+
+	var log struct {
+		Codespace sdk.CodespaceType `json:"codespace"`
+		Code      sdk.CodeType      `json:"code"`
+		Message   string            `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(e.Message), &log); err == nil && log.Code != 0 {
+		e.Codespace, e.Code, e.Message = log.Codespace, log.Code, log.Message
+		return e
+	}
+
+	if m := sdkErrorText.FindStringSubmatch(e.Message); m != nil {
+		code, _ := strconv.ParseUint(m[2], 10, 32)
+		message := strings.TrimSpace(m[3])
+		if unquoted, err := strconv.Unquote(message); err == nil {
+			message = unquoted
+		}
+		e.Codespace, e.Code, e.Message = sdk.CodespaceType(m[1]), sdk.CodeType(code), message
+	}
+
+	return e
+}
@@ -0,0 +1,277 @@
+package longyclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/client/rest/query"
+	"github.com/eco/longy/x/longy/internal/querier"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+// modulePath is the path of a route registered by the longy module
+func modulePath(elems ...string) string {
+	return "/" + types.StoreKey + "/" + strings.Join(elems, "/")
+}
+
+// Attendees returns every attendee of the event
+func (c *Client) Attendees() ([]types.Attendee, error) {
+	var attendees []types.Attendee
+	if err := c.getResult(modulePath(querier.QueryAttendees), nil, &attendees); err != nil {
+		return nil, err
+	}
+	return attendees, nil
+}
+
+// Attendee returns the attendee with the badge `id`
+func (c *Client) Attendee(id string) (*types.Attendee, error) {
+	var attendee types.Attendee
+	if err := c.getResult(modulePath(querier.QueryAttendees, url.PathEscape(id)), nil, &attendee); err != nil {
+		return nil, err
+	}
+	return &attendee, nil
+}
+
+// AttendeeByAddress returns the attendee with the account `addr`
+func (c *Client) AttendeeByAddress(addr sdk.AccAddress) (*types.Attendee, error) {
+	var attendee types.Attendee
+	path := modulePath(querier.QueryAttendees, querier.AddressKey, addr.String())
+	if err := c.getResult(path, nil, &attendee); err != nil {
+		return nil, err
+	}
+	return &attendee, nil
+}
+
+// IsAttendeeClaimed indicates if the attendee with the badge `id` has claimed their account
+func (c *Client) IsAttendeeClaimed(id string) (bool, error) {
+	return c.getBool(modulePath(querier.QueryAttendees, url.PathEscape(id), "claimed"))
+}
+
+// IsAttendeeKeyed indicates if the attendee with the badge `id` was keyed by the key service
+func (c *Client) IsAttendeeKeyed(id string) (bool, error) {
+	return c.getBool(modulePath(querier.QueryAttendees, url.PathEscape(id), "keyed"))
+}
+
+// Scan returns the scan with the hex encoded `id`
+func (c *Client) Scan(id string) (*types.Scan, error) {
+	var scan types.Scan
+	if err := c.getResult(modulePath(querier.QueryScans, url.PathEscape(id)), nil, &scan); err != nil {
+		return nil, err
+	}
+	return &scan, nil
+}
+
+// Scans returns every scan of the event
+func (c *Client) Scans() ([]types.Scan, error) {
+	var scans []types.Scan
+	if err := c.getResult(modulePath(querier.QueryScans), nil, &scans); err != nil {
+		return nil, err
+	}
+	return scans, nil
+}
+
+// Prizes returns the prizes of the event and the number left by tier
+func (c *Client) Prizes() ([]types.Prize, error) {
+	var prizes []types.Prize
+	if err := c.getResult(modulePath(querier.PrizesKey), nil, &prizes); err != nil {
+		return nil, err
+	}
+	return prizes, nil
+}
+
+// Roles returns the point rules of the attendee roles
+func (c *Client) Roles() ([]types.RoleRule, error) {
+	var roles []types.RoleRule
+	if err := c.getResult(modulePath(querier.RolesKey), nil, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// Bonus returns the live bonus period, nil outside of them
+func (c *Client) Bonus() (*types.Bonus, error) {
+	bz, err := c.get(modulePath(querier.QueryBonus), nil)
+	if IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var bonus types.Bonus
+	if err = c.decode(bz, &bonus); err != nil {
+		return nil, err
+	}
+	return &bonus, nil
+}
+
+// LeaderBoard returns the top attendees by rep. The rest server caches it for a minute
+func (c *Client) LeaderBoard() (*types.LeaderBoard, error) {
+	var board types.LeaderBoard
+	if err := c.getResult(modulePath(querier.LeaderKey), nil, &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// CategoryLeaderBoard returns the top attendees of `category`, such as types.LeaderSponsors or
+// types.LeaderTierCategory(3). The rest server caches it for a minute
+func (c *Client) CategoryLeaderBoard(category string) (*types.CategoryLeaderBoard, error) {
+	var board types.CategoryLeaderBoard
+	if err := c.getResult(modulePath(querier.LeaderKey, category), nil, &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// TeamLeaderBoard returns the top teams of attendees by company. The rest server caches it for a minute
+func (c *Client) TeamLeaderBoard() (*types.TeamLeaderBoard, error) {
+	var board types.TeamLeaderBoard
+	if err := c.getResult(modulePath(querier.TeamsKey), nil, &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// Winnings returns the prizes won by the attendee with the account `addr`
+func (c *Client) Winnings(addr sdk.AccAddress) ([]types.Win, error) {
+	var winnings []types.Win
+	params := url.Values{query.AddressIDKey: {addr.String()}}
+	if err := c.getResult(modulePath(querier.WinningsKey), params, &winnings); err != nil {
+		return nil, err
+	}
+	return winnings, nil
+}
+
+// Raffles returns the prize raffles, without their entrants
+func (c *Client) Raffles() ([]types.Raffle, error) {
+	var raffles []types.Raffle
+	if err := c.getResult(modulePath(querier.RafflesKey), nil, &raffles); err != nil {
+		return nil, err
+	}
+	return raffles, nil
+}
+
+// Raffle returns the raffle with `id`, its entrants and the proof of its draw
+func (c *Client) Raffle(id string) (*types.Raffle, error) {
+	var raffle types.Raffle
+	if err := c.getResult(modulePath(querier.RafflesKey, id), nil, &raffle); err != nil {
+		return nil, err
+	}
+	return &raffle, nil
+}
+
+// BoothChallenges returns the booth challenges of the sponsors
+func (c *Client) BoothChallenges() ([]types.BoothChallenge, error) {
+	var challenges []types.BoothChallenge
+	if err := c.getResult(modulePath(querier.ChallengesKey), nil, &challenges); err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// BoothChallenge returns the booth challenge with `id`
+func (c *Client) BoothChallenge(id string) (*types.BoothChallenge, error) {
+	var challenge types.BoothChallenge
+	if err := c.getResult(modulePath(querier.ChallengesKey, id), nil, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// Checkpoints returns the venue checkpoints
+func (c *Client) Checkpoints() ([]types.Checkpoint, error) {
+	var checkpoints []types.Checkpoint
+	if err := c.getResult(modulePath(querier.CheckpointsKey), nil, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+// Checkpoint returns the venue checkpoint with `id`
+func (c *Client) Checkpoint(id string) (*types.Checkpoint, error) {
+	var checkpoint types.Checkpoint
+	if err := c.getResult(modulePath(querier.CheckpointsKey, id), nil, &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// CheckpointAttendance returns the attendees who scanned the venue checkpoint with `id`
+func (c *Client) CheckpointAttendance(id string) ([]types.CheckpointScan, error) {
+	var scans []types.CheckpointScan
+	if err := c.getResult(modulePath(querier.CheckpointsKey, id, querier.AttendanceKey), nil, &scans); err != nil {
+		return nil, err
+	}
+	return scans, nil
+}
+
+// Achievements returns the achievements with the number of times they were granted
+func (c *Client) Achievements() ([]types.Achievement, error) {
+	var achievements []types.Achievement
+	if err := c.getResult(modulePath(querier.AchievementsKey), nil, &achievements); err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
+
+// AchievementProgress returns the progress of the attendee at `addr` toward every achievement
+func (c *Client) AchievementProgress(addr sdk.AccAddress) ([]types.AchievementProgress, error) {
+	var progress []types.AchievementProgress
+	if err := c.getResult(modulePath(querier.AchievementsKey, addr.String()), nil, &progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// ClaimChallenge issues a single use nonce to the claim desk `deskID`
+func (c *Client) ClaimChallenge(deskID string) (*query.ClaimChallengeResponse, error) {
+	body, err := json.Marshal(query.ClaimChallengeRequest{DeskID: deskID})
+	if err != nil {
+		return nil, err
+	}
+	bz, err := c.post(modulePath("claim", "challenge"), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge query.ClaimChallengeResponse
+	if err = json.Unmarshal(bz, &challenge); err != nil {
+		return nil, fmt.Errorf("unexpected claim challenge response, %s", err)
+	}
+	return &challenge, nil
+}
+
+// Claim redeems the prizes of the attendee who signed the challenge of the claim
+func (c *Client) Claim(claim query.Claim) error {
+	body, err := json.Marshal(claim)
+	if err != nil {
+		return err
+	}
+	_, err = c.post(modulePath("claim"), body)
+	return err
+}
+
+func (c *Client) getResult(path string, params url.Values, v interface{}) error {
+	bz, err := c.get(path, params)
+	if err != nil {
+		return err
+	}
+	return c.decodeResult(bz, v)
+}
+
+func (c *Client) getBool(path string) (bool, error) {
+	bz, err := c.get(path, nil)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(strings.TrimSpace(string(bz)))
+	if err != nil {
+		return false, fmt.Errorf("unexpected response, %s", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,12 @@
+package longyclient
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestLongyClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Longy Client Suite")
+}
@@ -0,0 +1,12 @@
+package broadcast
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestBroadcast(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Broadcast Suite")
+}
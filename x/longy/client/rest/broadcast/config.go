@@ -0,0 +1,124 @@
+package broadcast
+
+import (
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	//FlagAllowedMsgs is the flag for the longy message types the proxy broadcasts
+	FlagAllowedMsgs = "txs-allowed-msgs"
+	//FlagMaxTxBytes is the flag for the size limit of an encoded transaction
+	FlagMaxTxBytes = "txs-max-bytes"
+	//FlagMaxMsgs is the flag for the number of messages a transaction can carry
+	FlagMaxMsgs = "txs-max-msgs"
+	//FlagMaxSigs is the flag for the number of signatures a transaction can carry
+	FlagMaxSigs = "txs-max-sigs"
+	//FlagIPLimit is the flag for the number of transactions a client ip can post per window
+	FlagIPLimit = "txs-ip-limit"
+	//FlagSignerLimit is the flag for the number of messages a signer can post per window
+	FlagSignerLimit = "txs-signer-limit"
+	//FlagLimitWindow is the flag for the window of the rate limits
+	FlagLimitWindow = "txs-limit-window"
+	//FlagExemptSigners is the flag for the signers that are not rate limited, i.e the key service account
+	FlagExemptSigners = "txs-exempt-signers"
+	//FlagTrustProxy is the flag to take the client ip from the X-Forwarded-For header
+	FlagTrustProxy = "txs-trust-proxy"
+)
+
+const (
+	//DefaultMaxTxBytes is the default size limit of an encoded transaction
+	DefaultMaxTxBytes = 32 * 1024
+	//DefaultMaxMsgs is the default number of messages of a transaction. The key service batches
+	//up to 50 key messages per transaction
+	DefaultMaxMsgs = 50
+	//DefaultMaxSigs is the default number of signatures of a transaction
+	DefaultMaxSigs = 1
+	//DefaultIPLimit is the default number of transactions a client ip can post per window
+	DefaultIPLimit = 120
+	//DefaultSignerLimit is the default number of messages a signer can post per window
+	DefaultSignerLimit = 30
+	//DefaultLimitWindow is the default window of the rate limits
+	DefaultLimitWindow = time.Minute
+)
+
+//DefaultAllowedMsgs are the longy message types sent by the attendees, the key service and the bonus
+//and raffle commands of lycli. Prizes are redeemed through the claim handler, which broadcasts on its own
+var DefaultAllowedMsgs = []string{"qr_scan", "scan_attestations", "info", "key", "claim_key", "bonus", "clear_bonus",
+	"create_raffle", "reveal_raffle", "create_challenge", "commit_answer", "answer_challenge",
+	"create_checkpoint", "scan_checkpoint"}
+
+//Config configures the checks of the broadcast proxy. A zero limit disables the matching check
+type Config struct {
+	AllowedMsgs   []string
+	MaxTxBytes    int
+	MaxMsgs       int
+	MaxSigs       int
+	IPLimit       int
+	SignerLimit   int
+	LimitWindow   time.Duration
+	ExemptSigners []sdk.AccAddress
+	TrustProxy    bool
+	ChainID       string
+}
+
+//DefaultConfig returns the config of the broadcast proxy when no flags are set
+func DefaultConfig() Config {
+	return Config{
+		AllowedMsgs: DefaultAllowedMsgs,
+		MaxTxBytes:  DefaultMaxTxBytes,
+		MaxMsgs:     DefaultMaxMsgs,
+		MaxSigs:     DefaultMaxSigs,
+		IPLimit:     DefaultIPLimit,
+		SignerLimit: DefaultSignerLimit,
+		LimitWindow: DefaultLimitWindow,
+	}
+}
+
+var config = DefaultConfig()
+
+//AddFlags adds the flags configuring the broadcast proxy to the rest server command
+func AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(FlagAllowedMsgs, DefaultAllowedMsgs, "longy message types the /longy/txs proxy broadcasts")
+	cmd.Flags().Int(FlagMaxTxBytes, DefaultMaxTxBytes, "size limit in bytes of a transaction posted to /longy/txs")
+	cmd.Flags().Int(FlagMaxMsgs, DefaultMaxMsgs, "number of messages a transaction posted to /longy/txs can carry")
+	cmd.Flags().Int(FlagMaxSigs, DefaultMaxSigs, "number of signatures a transaction posted to /longy/txs can carry")
+	cmd.Flags().Int(FlagIPLimit, DefaultIPLimit,
+		"transactions a client ip can post to /longy/txs per window, 0 to disable")
+	cmd.Flags().Int(FlagSignerLimit, DefaultSignerLimit,
+		"messages a signer can post to /longy/txs per window, 0 to disable")
+	cmd.Flags().Duration(FlagLimitWindow, DefaultLimitWindow, "window of the /longy/txs rate limits")
+	cmd.Flags().StringSlice(FlagExemptSigners, nil,
+		"bech32 addresses that are not rate limited when they sign, i.e the service account of the key service")
+	cmd.Flags().Bool(FlagTrustProxy, false, "take the client ip of /longy/txs from the X-Forwarded-For header")
+}
+
+//LoadConfig loads the config of the broadcast proxy from the flags
+func LoadConfig() error {
+	cfg := Config{
+		AllowedMsgs: viper.GetStringSlice(FlagAllowedMsgs),
+		MaxTxBytes:  viper.GetInt(FlagMaxTxBytes),
+		MaxMsgs:     viper.GetInt(FlagMaxMsgs),
+		MaxSigs:     viper.GetInt(FlagMaxSigs),
+		IPLimit:     viper.GetInt(FlagIPLimit),
+		SignerLimit: viper.GetInt(FlagSignerLimit),
+		LimitWindow: viper.GetDuration(FlagLimitWindow),
+		TrustProxy:  viper.GetBool(FlagTrustProxy),
+		ChainID:     viper.GetString(flags.FlagChainID),
+	}
+
+	for _, bech32 := range viper.GetStringSlice(FlagExemptSigners) {
+		addr, err := sdk.AccAddressFromBech32(bech32)
+		if err != nil {
+			return err
+		}
+		cfg.ExemptSigners = append(cfg.ExemptSigners, addr)
+	}
+
+	config = cfg
+	return nil
+}
@@ -0,0 +1,72 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows `limit` events per key in consecutive windows. The window of a key starts with
+// its first event, and keys whose window ended are forgotten
+type Limiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mtx       sync.Mutex
+	counts    map[string]*count
+	lastPrune time.Time
+}
+
+type count struct {
+	start time.Time
+	n     int
+}
+
+// NewLimiter is the constructor for `Limiter`. A limit of zero allows every event
+func NewLimiter(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+		counts: make(map[string]*count),
+	}
+}
+
+// Allow records `n` events for `key`. If they exceed the limit, none are recorded and the
+// time until the window of `key` ends is returned
+func (l *Limiter) Allow(key string, n int) (bool, time.Duration) {
+	if l.limit <= 0 {
+		return true, 0
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	c, ok := l.counts[key]
+	if !ok || now.Sub(c.start) >= l.window {
+		c = &count{start: now}
+		l.counts[key] = c
+	}
+	if c.n+n > l.limit {
+		return false, c.start.Add(l.window).Sub(now)
+	}
+
+	c.n += n
+	return true, 0
+}
+
+// prune forgets the keys whose window ended, at most once per window
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.window {
+		return
+	}
+	for key, c := range l.counts {
+		if now.Sub(c.start) >= l.window {
+			delete(l.counts, key)
+		}
+	}
+	l.lastPrune = now
+}
@@ -0,0 +1,61 @@
+package broadcast
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter", func() {
+	var limiter *Limiter
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Now()
+		limiter = NewLimiter(3, time.Minute)
+		limiter.now = func() time.Time { return now }
+	})
+
+	It("should allow events up to the limit of every key", func() {
+		ok, _ := limiter.Allow("a", 2)
+		Expect(ok).To(BeTrue())
+		ok, _ = limiter.Allow("a", 1)
+		Expect(ok).To(BeTrue())
+
+		now = now.Add(20 * time.Second)
+		ok, retry := limiter.Allow("a", 1)
+		Expect(ok).To(BeFalse())
+		Expect(retry).To(Equal(40 * time.Second))
+
+		ok, _ = limiter.Allow("b", 3)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should not record refused events", func() {
+		ok, _ := limiter.Allow("a", 2)
+		Expect(ok).To(BeTrue())
+		ok, _ = limiter.Allow("a", 2)
+		Expect(ok).To(BeFalse())
+		ok, _ = limiter.Allow("a", 1)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should start a new window once the previous one ended", func() {
+		ok, _ := limiter.Allow("a", 3)
+		Expect(ok).To(BeTrue())
+
+		now = now.Add(time.Minute)
+		ok, _ = limiter.Allow("a", 3)
+		Expect(ok).To(BeTrue())
+		Expect(limiter.counts).To(HaveLen(1))
+	})
+
+	It("should allow every event without a limit", func() {
+		limiter = NewLimiter(0, time.Minute)
+		for i := 0; i < 10; i++ {
+			ok, _ := limiter.Allow("a", 100)
+			Expect(ok).To(BeTrue())
+		}
+	})
+})
@@ -0,0 +1,364 @@
+package broadcast
+
+import (
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authrest "github.com/cosmos/cosmos-sdk/x/auth/client/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth/exported"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+const (
+	// exemptSequenceWindow is the number of sequences past the last known one an exempt signer can
+	// sign with, as the key service broadcasts several transactions per block
+	exemptSequenceWindow = 16
+
+	// accountRefresh is the minimum delay between the retrievals of the account of an exempt signer
+	accountRefresh = time.Second
+)
+
+// Proxy broadcasts the transactions posted by the attendees and the key service to the full node.
+// Unlike the stock broadcast handler it only accepts longy messages, limits the size and the
+// signatures of a transaction and rate limits client ips and signers. Errors are written as the
+// ABCI log of a longy error so that clients can switch on the code
+type Proxy struct {
+	cliCtx  context.CLIContext
+	cfg     Config
+	allowed map[string]bool
+	exempt  map[string]bool
+	ips     *Limiter
+	signers *Limiter
+
+	mtx      sync.Mutex
+	chainID  string
+	accounts map[string]*exemptAccount
+
+	broadcast  func(txBytes []byte, mode string) (sdk.TxResponse, error)
+	getAccount func(addr sdk.AccAddress) (exported.Account, error)
+	getChainID func() (string, error)
+}
+
+// exemptAccount is the last retrieved account of an exempt signer
+type exemptAccount struct {
+	pubKey    crypto.PubKey
+	number    uint64
+	sequence  uint64
+	fetchedAt time.Time
+}
+
+// verify checks that `sig` signs `tx` with the account key and a sequence of the window
+//nolint:gocritic
+func (a *exemptAccount) verify(chainID string, tx auth.StdTx, sig []byte) bool {
+	if a.pubKey == nil {
+		return false
+	}
+	for seq := a.sequence; seq < a.sequence+exemptSequenceWindow; seq++ {
+		if a.pubKey.VerifyBytes(auth.StdSignBytes(chainID, a.number, seq, tx.Fee, tx.Msgs, tx.Memo), sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler returns the proxy configured by the flags loaded with `LoadConfig`
+//nolint:gocritic
+func Handler(cliCtx context.CLIContext) http.Handler {
+	return NewProxy(cliCtx, config)
+}
+
+// NewProxy is the constructor for `Proxy`
+//nolint:gocritic
+func NewProxy(cliCtx context.CLIContext, cfg Config) *Proxy {
+	p := &Proxy{
+		cliCtx:  cliCtx,
+		cfg:     cfg,
+		allowed: make(map[string]bool),
+		exempt:  make(map[string]bool),
+		ips:     NewLimiter(cfg.IPLimit, cfg.LimitWindow),
+		signers: NewLimiter(cfg.SignerLimit, cfg.LimitWindow),
+
+		chainID:  cfg.ChainID,
+		accounts: make(map[string]*exemptAccount),
+	}
+	for _, msgType := range cfg.AllowedMsgs {
+		p.allowed[msgType] = true
+	}
+	for _, addr := range cfg.ExemptSigners {
+		p.exempt[addr.String()] = true
+	}
+	p.broadcast = func(txBytes []byte, mode string) (sdk.TxResponse, error) {
+		return p.cliCtx.WithBroadcastMode(mode).BroadcastTx(txBytes)
+	}
+	p.getAccount = auth.NewAccountRetriever(p.cliCtx).GetAccount
+	p.getChainID = func() (string, error) {
+		node, err := p.cliCtx.GetNode()
+		if err != nil {
+			return "", err
+		}
+		status, err := node.Status()
+		if err != nil {
+			return "", err
+		}
+		return status.NodeInfo.Network, nil
+	}
+	return p
+}
+
+// ServeHTTP decodes, checks and broadcasts a `BroadcastReq` of the auth module
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	//nolint:errcheck
+	defer r.Body.Close()
+
+	// the json of a transaction is larger than its binary encoding, the latter is checked below
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, p.maxBodyBytes()))
+	if err != nil {
+		writeError(w, types.ErrTxTooLarge("request body is over the limit of %d bytes", p.maxBodyBytes()))
+		return
+	}
+
+	var req authrest.BroadcastReq
+	if err := p.cliCtx.Codec.UnmarshalJSON(body, &req); err != nil {
+		writeError(w, types.ErrInvalidTx("malformed transaction: %s", err))
+		return
+	}
+
+	exempt := p.isExempt(req.Tx)
+	if !exempt {
+		if ok, retry := p.ips.Allow(p.clientIP(r), 1); !ok {
+			writeRateLimited(w, retry, types.ErrRateLimited("too many transactions from this client, retry in %s",
+				retry.Round(time.Second)))
+			return
+		}
+	}
+
+	if err := p.checkTx(req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	txBytes, err := p.cliCtx.Codec.MarshalBinaryLengthPrefixed(req.Tx)
+	if err != nil {
+		writeError(w, types.ErrInvalidTx("malformed transaction: %s", err))
+		return
+	}
+	if p.cfg.MaxTxBytes > 0 && len(txBytes) > p.cfg.MaxTxBytes {
+		writeError(w, types.ErrTxTooLarge("transaction of %d bytes is over the limit of %d bytes",
+			len(txBytes), p.cfg.MaxTxBytes))
+		return
+	}
+
+	if !exempt {
+		if ok, retry, signer := p.allowSigners(req.Tx.GetMsgs()); !ok {
+			writeRateLimited(w, retry, types.ErrRateLimited("too many messages from %s, retry in %s",
+				signer, retry.Round(time.Second)))
+			return
+		}
+	}
+
+	res, err := p.broadcast(txBytes, req.Mode)
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rest.PostProcessResponseBare(w, p.cliCtx, res)
+}
+
+// checkTx checks a transaction without verifying its signatures, which is left to the full node
+//nolint:gocritic
+func (p *Proxy) checkTx(req authrest.BroadcastReq) sdk.Error {
+	switch req.Mode {
+	case flags.BroadcastBlock, flags.BroadcastSync, flags.BroadcastAsync:
+	default:
+		return types.ErrInvalidTx("unsupported broadcast mode %q", req.Mode)
+	}
+
+	msgs := req.Tx.GetMsgs()
+	if len(msgs) == 0 {
+		return types.ErrInvalidTx("transaction has no messages")
+	}
+	if p.cfg.MaxMsgs > 0 && len(msgs) > p.cfg.MaxMsgs {
+		return types.ErrInvalidTx("transaction has %d messages, the limit is %d", len(msgs), p.cfg.MaxMsgs)
+	}
+	for _, msg := range msgs {
+		if msg.Route() != types.RouterKey || !p.allowed[msg.Type()] {
+			return types.ErrMsgNotAllowed("%s/%s messages cannot be broadcast", msg.Route(), msg.Type())
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+
+	sigs, signers := req.Tx.GetSignatures(), req.Tx.GetSigners()
+	if p.cfg.MaxSigs > 0 && len(signers) > p.cfg.MaxSigs {
+		return types.ErrInvalidTx("transaction has %d signers, the limit is %d", len(signers), p.cfg.MaxSigs)
+	}
+	if len(sigs) != len(signers) {
+		return types.ErrInvalidTx("transaction has %d signatures for %d signers", len(sigs), len(signers))
+	}
+	for i, sig := range sigs {
+		if len(sig.Signature) == 0 {
+			return types.ErrInvalidTx("signature %d is empty", i)
+		}
+	}
+
+	return req.Tx.ValidateBasic()
+}
+
+// allowSigners counts the messages of every signer against its rate limit and returns the
+// first signer over the limit
+func (p *Proxy) allowSigners(msgs []sdk.Msg) (bool, time.Duration, sdk.AccAddress) {
+	var signers []sdk.AccAddress
+	counts := make(map[string]int)
+	for _, msg := range msgs {
+		for _, signer := range msg.GetSigners() {
+			if counts[signer.String()] == 0 {
+				signers = append(signers, signer)
+			}
+			counts[signer.String()]++
+		}
+	}
+
+	for _, signer := range signers {
+		if ok, retry := p.signers.Allow(signer.String(), counts[signer.String()]); !ok {
+			return false, retry, signer
+		}
+	}
+	return true, 0, nil
+}
+
+// isExempt indicates if every signer of `tx` is exempt from the rate limits and signed it. Only
+// the exempt signers themselves escape the limits, not anyone naming them in a message
+//nolint:gocritic
+func (p *Proxy) isExempt(tx auth.StdTx) bool {
+	signers, sigs := tx.GetSigners(), tx.GetSignatures()
+	if len(signers) == 0 || len(sigs) != len(signers) {
+		return false
+	}
+	for _, signer := range signers {
+		if !p.exempt[signer.String()] {
+			return false
+		}
+	}
+
+	chainID, err := p.chain()
+	if err != nil {
+		return false
+	}
+	for i, signer := range signers {
+		if !p.verifyExempt(chainID, signer, tx, sigs[i].Signature) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyExempt checks `sig` against the on chain account of the exempt `signer`. The account is
+// retrieved again, at most once per `accountRefresh`, when the signature does not verify
+//nolint:gocritic
+func (p *Proxy) verifyExempt(chainID string, signer sdk.AccAddress, tx auth.StdTx, sig []byte) bool {
+	p.mtx.Lock()
+	acc := p.accounts[signer.String()]
+	stale := acc == nil || time.Since(acc.fetchedAt) >= accountRefresh
+	if stale && acc != nil {
+		// the other requests keep the cached account until this one is retrieved
+		acc.fetchedAt = time.Now()
+	}
+	p.mtx.Unlock()
+
+	if acc != nil && acc.verify(chainID, tx, sig) {
+		return true
+	} else if !stale {
+		return false
+	}
+
+	// an account that cannot be retrieved or has no key yet is also cached, and exempts nothing
+	acc = &exemptAccount{fetchedAt: time.Now()}
+	fetched, err := p.getAccount(signer)
+	if err == nil && fetched != nil && fetched.GetPubKey() != nil {
+		acc.pubKey = fetched.GetPubKey()
+		acc.number = fetched.GetAccountNumber()
+		acc.sequence = fetched.GetSequence()
+	}
+	p.mtx.Lock()
+	p.accounts[signer.String()] = acc
+	p.mtx.Unlock()
+
+	return acc.verify(chainID, tx, sig)
+}
+
+// chain returns the chain id the transactions are signed for, retrieved from the node unless set
+func (p *Proxy) chain() (string, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if len(p.chainID) > 0 {
+		return p.chainID, nil
+	}
+
+	chainID, err := p.getChainID()
+	if err != nil {
+		return "", err
+	}
+	p.chainID = chainID
+	return chainID, nil
+}
+
+func (p *Proxy) clientIP(r *http.Request) string {
+	if p.cfg.TrustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); len(forwarded) > 0 {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (p *Proxy) maxBodyBytes() int64 {
+	if p.cfg.MaxTxBytes <= 0 {
+		return math.MaxInt64
+	}
+	return int64(p.cfg.MaxTxBytes) * 4
+}
+
+func writeRateLimited(w http.ResponseWriter, retry time.Duration, err sdk.Error) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+	writeError(w, err)
+}
+
+func writeError(w http.ResponseWriter, err sdk.Error) {
+	rest.WriteErrorResponse(w, statusCode(err), err.ABCILog())
+}
+
+func statusCode(err sdk.Error) int {
+	if err.Codespace() != types.LongyCodeSpace {
+		return http.StatusBadRequest
+	}
+
+	switch err.Code() {
+	case types.MsgNotAllowed:
+		return http.StatusForbidden
+	case types.TxTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case types.RateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadRequest
+	}
+}
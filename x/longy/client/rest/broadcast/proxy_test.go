@@ -0,0 +1,249 @@
+package broadcast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authrest "github.com/cosmos/cosmos-sdk/x/auth/client/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth/exported"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Proxy", func() {
+	var cdc *codec.Codec
+	var proxy *Proxy
+	var cfg Config
+	var broadcasts int
+	var alice, bob = secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	var aliceAddr, bobAddr = sdk.AccAddress(alice.PubKey().Address()), sdk.AccAddress(bob.PubKey().Address())
+
+	newTx := func(keys []secp256k1.PrivKeySecp256k1, msgs ...sdk.Msg) auth.StdTx {
+		fee := auth.NewStdFee(200000, sdk.NewCoins())
+		var sigs []auth.StdSignature
+		for _, key := range keys {
+			sig, err := key.Sign(auth.StdSignBytes("longy", 0, 0, fee, msgs, ""))
+			Expect(err).To(BeNil())
+			sigs = append(sigs, auth.StdSignature{PubKey: key.PubKey(), Signature: sig})
+		}
+		return auth.NewStdTx(msgs, fee, sigs, "")
+	}
+
+	post := func(tx auth.StdTx, remoteAddr string) *httptest.ResponseRecorder {
+		body, err := cdc.MarshalJSON(authrest.BroadcastReq{Tx: tx, Mode: "block"})
+		Expect(err).To(BeNil())
+
+		r := httptest.NewRequest(http.MethodPost, "/longy/txs", bytes.NewReader(body))
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		proxy = newProxy(cdc, cfg, proxy, &broadcasts)
+		proxy.ServeHTTP(w, r)
+		return w
+	}
+
+	scan := func(key secp256k1.PrivKeySecp256k1) auth.StdTx {
+		addr := sdk.AccAddress(key.PubKey().Address())
+		return newTx([]secp256k1.PrivKeySecp256k1{key}, types.NewMsgQrScan(addr, "2", nil))
+	}
+
+	expectError := func(w *httptest.ResponseRecorder, status int, code sdk.CodeType) {
+		Expect(w.Code).To(Equal(status))
+
+		var resp struct {
+			Error string `json:"error"`
+		}
+		Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+		var log struct {
+			Codespace sdk.CodespaceType `json:"codespace"`
+			Code      sdk.CodeType      `json:"code"`
+		}
+		Expect(json.Unmarshal([]byte(resp.Error), &log)).To(Succeed())
+		Expect(log.Codespace).To(Equal(types.LongyCodeSpace))
+		Expect(log.Code).To(Equal(code))
+	}
+
+	BeforeEach(func() {
+		cdc = codec.New()
+		sdk.RegisterCodec(cdc)
+		codec.RegisterCrypto(cdc)
+		auth.RegisterCodec(cdc)
+		bank.RegisterCodec(cdc)
+		types.RegisterCodec(cdc)
+
+		cfg = DefaultConfig()
+		proxy, broadcasts = nil, 0
+	})
+
+	It("should broadcast longy transactions", func() {
+		w := post(scan(alice), "192.0.2.1:1234")
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(broadcasts).To(Equal(1))
+
+		var res sdk.TxResponse
+		Expect(cdc.UnmarshalJSON(w.Body.Bytes(), &res)).To(Succeed())
+		Expect(res.TxHash).To(Equal("hash"))
+	})
+
+	It("should broadcast the bonus messages of lycli", func() {
+		tx := newTx([]secp256k1.PrivKeySecp256k1{alice}, types.NewMsgBonus("2", aliceAddr))
+		Expect(post(tx, "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+	})
+
+	It("should reject messages that are not whitelisted", func() {
+		send := bank.MsgSend{FromAddress: aliceAddr, ToAddress: bobAddr, Amount: sdk.NewCoins(sdk.NewInt64Coin("longy", 1))}
+		w := post(newTx([]secp256k1.PrivKeySecp256k1{alice}, send), "192.0.2.1:1234")
+		expectError(w, http.StatusForbidden, types.MsgNotAllowed)
+
+		w = post(newTx([]secp256k1.PrivKeySecp256k1{alice}, types.NewMsgRedeem(aliceAddr, bobAddr)), "192.0.2.1:1234")
+		expectError(w, http.StatusForbidden, types.MsgNotAllowed)
+		Expect(broadcasts).To(BeZero())
+	})
+
+	It("should reject malformed and unsigned transactions", func() {
+		r := httptest.NewRequest(http.MethodPost, "/longy/txs", strings.NewReader(`{"tx":`))
+		w := httptest.NewRecorder()
+		NewProxy(context.CLIContext{}.WithCodec(cdc), cfg).ServeHTTP(w, r)
+		expectError(w, http.StatusBadRequest, types.InvalidTx)
+
+		w = post(newTx(nil, types.NewMsgQrScan(aliceAddr, "2", nil)), "192.0.2.1:1234")
+		expectError(w, http.StatusBadRequest, types.InvalidTx)
+		Expect(broadcasts).To(BeZero())
+	})
+
+	It("should reject transactions with too many signers", func() {
+		tx := newTx([]secp256k1.PrivKeySecp256k1{alice, bob},
+			types.NewMsgQrScan(aliceAddr, "2", nil), types.NewMsgQrScan(bobAddr, "1", nil))
+		expectError(post(tx, "192.0.2.1:1234"), http.StatusBadRequest, types.InvalidTx)
+	})
+
+	It("should reject transactions over the size limit", func() {
+		cfg.MaxTxBytes = 512
+		tx := newTx([]secp256k1.PrivKeySecp256k1{alice},
+			types.NewMsgQrScan(aliceAddr, "2", []byte(strings.Repeat("a", 1024))))
+		expectError(post(tx, "192.0.2.1:1234"), http.StatusRequestEntityTooLarge, types.TxTooLarge)
+	})
+
+	Context("with rate limits", func() {
+		BeforeEach(func() {
+			cfg.IPLimit = 2
+			cfg.SignerLimit = 3
+		})
+
+		It("should limit the transactions of a client ip", func() {
+			Expect(post(scan(alice), "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+			Expect(post(scan(bob), "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+
+			w := post(scan(bob), "192.0.2.1:4321")
+			expectError(w, http.StatusTooManyRequests, types.RateLimited)
+			Expect(w.Header().Get("Retry-After")).To(Equal("60"))
+
+			Expect(post(scan(bob), "192.0.2.2:1234").Code).To(Equal(http.StatusOK))
+		})
+
+		It("should take the client ip from X-Forwarded-For behind a trusted proxy", func() {
+			cfg.TrustProxy = true
+			for i := 0; i < 3; i++ {
+				body, err := cdc.MarshalJSON(authrest.BroadcastReq{Tx: scan(alice), Mode: "block"})
+				Expect(err).To(BeNil())
+				r := httptest.NewRequest(http.MethodPost, "/longy/txs", bytes.NewReader(body))
+				r.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d, 10.0.0.1", i))
+				r.RemoteAddr = "192.0.2.1:1234"
+				w := httptest.NewRecorder()
+				proxy = newProxy(cdc, cfg, proxy, &broadcasts)
+				proxy.ServeHTTP(w, r)
+				Expect(w.Code).To(Equal(http.StatusOK))
+			}
+		})
+
+		It("should limit the messages of a signer", func() {
+			cfg.IPLimit = 0
+			msgs := []sdk.Msg{types.NewMsgQrScan(aliceAddr, "2", nil), types.NewMsgQrScan(aliceAddr, "3", nil)}
+			tx := newTx([]secp256k1.PrivKeySecp256k1{alice}, msgs...)
+			Expect(post(tx, "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+			expectError(post(tx, "192.0.2.2:1234"), http.StatusTooManyRequests, types.RateLimited)
+
+			Expect(post(scan(alice), "192.0.2.3:1234").Code).To(Equal(http.StatusOK))
+			Expect(post(scan(bob), "192.0.2.3:1234").Code).To(Equal(http.StatusOK))
+		})
+
+		Context("with an exempt signer", func() {
+			var account auth.BaseAccount
+
+			BeforeEach(func() {
+				cfg.ExemptSigners = []sdk.AccAddress{aliceAddr}
+				cfg.ChainID = "longy"
+				account = auth.NewBaseAccountWithAddress(aliceAddr)
+				Expect(account.SetPubKey(alice.PubKey())).To(Succeed())
+
+				proxy = newProxy(cdc, cfg, nil, &broadcasts)
+				proxy.getAccount = func(addr sdk.AccAddress) (exported.Account, error) {
+					Expect(addr).To(Equal(aliceAddr))
+					acc := account
+					return &acc, nil
+				}
+			})
+
+			It("should not limit exempt signers", func() {
+				for i := 0; i < 5; i++ {
+					Expect(post(scan(alice), "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+				}
+				Expect(broadcasts).To(Equal(5))
+			})
+
+			It("should limit transactions naming an exempt signer without its signature", func() {
+				msg := types.NewMsgQrScan(aliceAddr, "2", nil)
+				forged := newTx([]secp256k1.PrivKeySecp256k1{bob}, msg)
+				forged.Signatures[0].PubKey = alice.PubKey()
+
+				Expect(post(forged, "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+				Expect(post(forged, "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+				expectError(post(forged, "192.0.2.1:1234"), http.StatusTooManyRequests, types.RateLimited)
+			})
+
+			It("should accept the sequences following the account sequence", func() {
+				Expect(account.SetSequence(3)).To(Succeed())
+				signed := func(seq uint64) auth.StdTx {
+					msgs := []sdk.Msg{types.NewMsgQrScan(aliceAddr, "2", nil)}
+					fee := auth.NewStdFee(200000, sdk.NewCoins())
+					sig, err := alice.Sign(auth.StdSignBytes("longy", 0, seq, fee, msgs, ""))
+					Expect(err).To(BeNil())
+					return auth.NewStdTx(msgs, fee, []auth.StdSignature{{PubKey: alice.PubKey(), Signature: sig}}, "")
+				}
+
+				for i := 0; i < 3; i++ {
+					Expect(post(signed(uint64(4+i)), "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+				}
+				// signed for a sequence that was already used
+				Expect(post(signed(2), "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+				Expect(post(signed(2), "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+				expectError(post(signed(2), "192.0.2.1:1234"), http.StatusTooManyRequests, types.RateLimited)
+			})
+		})
+	})
+})
+
+// newProxy returns `proxy`, or a new proxy broadcasting to a stub counting the broadcasts if nil,
+// so that the rate limits hold across the requests of a test
+func newProxy(cdc *codec.Codec, cfg Config, proxy *Proxy, broadcasts *int) *Proxy {
+	if proxy != nil {
+		return proxy
+	}
+
+	proxy = NewProxy(context.CLIContext{}.WithCodec(cdc), cfg)
+	proxy.broadcast = func(txBytes []byte, mode string) (sdk.TxResponse, error) {
+		*broadcasts++
+		return sdk.TxResponse{TxHash: "hash"}, nil
+	}
+	return proxy
+}
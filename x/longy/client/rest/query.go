@@ -42,7 +42,7 @@ func attendeeClaimedHandler(cliCtx context.CLIContext, storeName string) http.Ha
 		if err != nil {
 			if codeType, ok := codeType(err); ok {
 				if codeType == longyTypes.AttendeeNotFound {
-					http.Error(w, "id not found", http.StatusNotFound)
+					rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 					return
 				}
 			}
@@ -65,7 +65,7 @@ func attendeeKeyedHandler(cliCtx context.CLIContext, storeName string) http.Hand
 		if err != nil {
 			if codeType, ok := codeType(err); ok {
 				if codeType == longyTypes.AttendeeNotFound {
-					http.Error(w, "id not found", http.StatusNotFound)
+					rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 					return
 				}
 			}
@@ -93,6 +93,151 @@ func prizesGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerF
 	}
 }
 
+//nolint:gocritic
+func rolesGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s",
+			storeName, querier.RolesKey))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//nolint:gocritic
+func rafflesGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s",
+			storeName, querier.RafflesKey))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//nolint:gocritic
+func raffleGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)[query.RaffleIDKey]
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s/%s",
+			storeName, querier.RafflesKey, id))
+		if err != nil {
+			if codeType, ok := codeType(err); ok && codeType == longyTypes.RaffleNotFound {
+				rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//nolint:gocritic
+func challengesGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s",
+			storeName, querier.ChallengesKey))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//nolint:gocritic
+func challengeGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)[query.ChallengeIDKey]
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s/%s",
+			storeName, querier.ChallengesKey, id))
+		if err != nil {
+			if codeType, ok := codeType(err); ok && codeType == longyTypes.BoothChallengeNotFound {
+				rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//nolint:gocritic
+func checkpointsGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s",
+			storeName, querier.CheckpointsKey))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//checkpointGetHandler returns the checkpoint, or its attendance when `sub` is querier.AttendanceKey
+//nolint:gocritic
+func checkpointGetHandler(cliCtx context.CLIContext, storeName string, sub string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := fmt.Sprintf("custom/%s/%s/%s", storeName, querier.CheckpointsKey, mux.Vars(r)[query.CheckpointIDKey])
+		if len(sub) > 0 {
+			path = fmt.Sprintf("%s/%s", path, sub)
+		}
+		res, _, err := cliCtx.Query(path)
+		if err != nil {
+			if codeType, ok := codeType(err); ok && codeType == longyTypes.CheckpointNotFound {
+				rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//nolint:gocritic
+func achievementsGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s",
+			storeName, querier.AchievementsKey))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+//nolint:gocritic
+func achievementProgressGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addressID := mux.Vars(r)[query.AddressIDKey]
+		res, _, err := cliCtx.Query(fmt.Sprintf("custom/%s/%s/%s",
+			storeName, querier.AchievementsKey, addressID))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
 //nolint:gocritic
 func scanGetHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -2,39 +2,137 @@ package query
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/eco/longy/x/longy/client/txsender"
 	"github.com/eco/longy/x/longy/crypto"
 	"github.com/eco/longy/x/longy/internal/types"
-	tmcrypto "github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/eco/longy/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/libs/cli"
 	"net/http"
+	"time"
 )
 
-//Claim is the json the claim handler receives
+//ClaimChallengeTTL is how long a claim desk has to collect the attendee's signature over a nonce
+const ClaimChallengeTTL = 2 * time.Minute
+
+//Claim is the json the claim handler receives. Sig is the attendee's signature over the
+//crypto.ClaimSignDoc built from the challenge issued to the desk
 type Claim struct {
 	Address string `json:"address"`
 	Sig     string `json:"sig"`
+	DeskID  string `json:"desk_id"`
+	Nonce   string `json:"nonce"`
+}
+
+//ClaimChallengeRequest is the json the claim challenge handler receives
+type ClaimChallengeRequest struct {
+	DeskID string `json:"desk_id"`
+}
+
+//ClaimChallengeResponse is the challenge a desk has the attendee sign
+type ClaimChallengeResponse struct {
+	crypto.Challenge
+	ChainID string `json:"chain_id"`
+}
+
+var (
+	claimKey   tmcrypto.PrivKey
+	challenges = crypto.NewChallenges(ClaimChallengeTTL)
+)
+
+//AddClaimFlags adds the flags configuring the claim service key to the rest server command
+func AddClaimFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagClaimKeyName, "",
+		"name of the claim service key in the keyring, i.e claim-service from `lyd init-service-keys`")
+	cmd.Flags().String(FlagClaimKeyPassphrase, "", "passphrase of the claim service key")
+	cmd.Flags().Bool(FlagInsecureDev, false, "sign claims with the well-known development claim key")
+}
+
+//LoadClaimSigner loads the claim service key configured by the claim flags. Claims are refused
+//until a key is loaded. Keys derived from the public service seeds need --insecure-dev
+func LoadClaimSigner() error {
+	insecureDev := viper.GetBool(FlagInsecureDev)
+
+	var (
+		key tmcrypto.PrivKey
+		err error
+	)
+	switch {
+	case len(viper.GetString(FlagClaimKeyName)) > 0:
+		key, err = util.Secp256k1FromKeyring(viper.GetString(cli.HomeFlag), viper.GetString(FlagClaimKeyName),
+			viper.GetString(FlagClaimKeyPassphrase))
+	case insecureDev:
+		key = secp256k1.GenPrivKeySecp256k1([]byte(types.ClaimServiceSeed))
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if types.IsWellKnownServiceKey(key.PubKey()) && !insecureDev {
+		return fmt.Errorf("refusing the well-known development claim key without --%s", FlagInsecureDev)
+	}
+
+	claimKey = key
+	return nil
 }
 
-var signer *crypto.Signer
+//ClaimChallengeHandler handles the REST POST issuing a single use nonce to a claim desk
+//nolint:gocritic
+func ClaimChallengeHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ClaimChallengeRequest
+		decoder := json.NewDecoder(r.Body)
+		//nolint:errcheck
+		defer r.Body.Close()
+		if err := decoder.Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid Payload, missing desk_id")
+			return
+		}
+
+		challenge, err := challenges.Issue(req.DeskID)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 
-func init() {
-	key := tmcrypto.GenPrivKeySecp256k1([]byte(types.ClaimServiceSeed))
-	addr := sdk.AccAddress(key.PubKey().Address())
-	signer = crypto.NewSigner(addr, key)
+		respondWithJSON(w, http.StatusCreated, ClaimChallengeResponse{
+			Challenge: challenge,
+			ChainID:   viper.GetString(client.FlagChainID),
+		})
+	}
 }
 
-//ClaimHandler handles the REST POST to claim the attendee's prizes
+//ClaimHandler handles the REST POST to claim the attendee's prizes. The nonce is consumed
+//once the signature is verified, before the redeem transaction is sent by the claim service
 //nolint:gocritic
 func ClaimHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	var sender *txsender.Sender
+	if claimKey != nil {
+		sender = txsender.New(claimKey, viper.GetString(client.FlagChainID), txsender.NewCLIChain(cliCtx))
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
+		if sender == nil {
+			respondWithError(w, http.StatusServiceUnavailable, "claim service key not configured")
+			return
+		}
+
 		var claim Claim
 		decoder := json.NewDecoder(r.Body)
 		//nolint:errcheck
 		defer r.Body.Close()
 		if err := decoder.Decode(&claim); err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid Payload, missing address and sig")
+			respondWithError(w, http.StatusBadRequest, "Invalid Payload, missing address, sig, desk_id and nonce")
 			return
 		}
 
@@ -44,6 +142,12 @@ func ClaimHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
+		challenge, sdkErr := challenges.Get(claim.Nonce, claim.DeskID)
+		if sdkErr != nil {
+			respondWithError(w, http.StatusUnauthorized, sdkErr.Error())
+			return
+		}
+
 		accGetter := auth.NewAccountRetriever(cliCtx)
 		if err = accGetter.EnsureExists(addr); err != nil {
 			respondWithError(w, http.StatusBadRequest, err.Error())
@@ -56,18 +160,30 @@ func ClaimHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
-		err = crypto.ValidateSig(acc.GetPubKey(), acc.GetAddress().String(), claim.Sig)
-		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+		doc := crypto.ClaimSignDoc{
+			ChainID: viper.GetString(client.FlagChainID),
+			DeskID:  challenge.DeskID,
+			Nonce:   challenge.Nonce,
+			Address: acc.GetAddress().String(),
+			Expires: challenge.Expires,
+		}
+		if sdkErr = crypto.ValidateClaimSig(acc.GetPubKey(), doc, claim.Sig); sdkErr != nil {
+			respondWithError(w, http.StatusBadRequest, sdkErr.Error())
+			return
+		}
+
+		// a concurrent request with the same signature may have consumed the nonce
+		if sdkErr = challenges.Consume(challenge.Nonce); sdkErr != nil {
+			respondWithError(w, http.StatusUnauthorized, sdkErr.Error())
 			return
 		}
 
 		msg := types.MsgRedeem{
-			Sender:   signer.AccAddress,
+			Sender:   sender.Address(),
 			Attendee: addr,
 		}
 
-		err = signer.SendTx(&cliCtx, cliCtx.Codec, msg)
+		_, err = sender.Send(msg)
 		if err != nil {
 			respondWithError(w, http.StatusInternalServerError, err.Error())
 			return
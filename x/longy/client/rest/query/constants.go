@@ -13,6 +13,27 @@ const (
 	// BadgeIDKey is the attribute key for badge id
 	BadgeIDKey = "badge_id"
 
+	// RaffleIDKey is the attribute key for raffle id
+	RaffleIDKey = "raffle_id"
+
+	// ChallengeIDKey is the attribute key for booth challenge id
+	ChallengeIDKey = "challenge_id"
+
+	// CheckpointIDKey is the attribute key for checkpoint id
+	CheckpointIDKey = "checkpoint_id"
+
+	// CategoryKey is the attribute key for leader board category
+	CategoryKey = "category"
+
 	// SigKey is the attribute key for the sig
 	SigKey = "sig"
+
+	// FlagClaimKeyName is the flag naming the claim service key in the keyring
+	FlagClaimKeyName = "claim-key-name"
+
+	// FlagClaimKeyPassphrase is the flag with the passphrase of the claim service key
+	FlagClaimKeyPassphrase = "claim-key-passphrase"
+
+	// FlagInsecureDev is the flag allowing the well-known development service keys
+	FlagInsecureDev = "insecure-dev"
 )
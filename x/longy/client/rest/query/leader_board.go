@@ -5,6 +5,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/types/rest"
 	"github.com/eco/longy/x/longy/internal/querier"
+	"github.com/gorilla/mux"
 	"github.com/patrickmn/go-cache"
 	"net/http"
 	"sync"
@@ -17,6 +18,8 @@ var mutex *sync.Mutex
 const (
 	//BoardCacheKey is the key for the cached leaderboard
 	BoardCacheKey = "leaderboard"
+	//TeamBoardCacheKey is the key for the cached team board
+	TeamBoardCacheKey = "teamboard"
 )
 
 func init() {
@@ -27,8 +30,31 @@ func init() {
 //LeaderBoardHandler queries the chain for for the current leader board
 //nolint:gocritic
 func LeaderBoardHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return cachedBoardHandler(cliCtx, BoardCacheKey, fmt.Sprintf("custom/%s/%s", storeName, querier.LeaderKey))
+}
+
+//CategoryLeaderBoardHandler queries the chain for the current leader board of a category
+//nolint:gocritic
+func CategoryLeaderBoardHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		category := mux.Vars(r)[CategoryKey]
+		cachedBoardHandler(cliCtx, fmt.Sprintf("%s/%s", BoardCacheKey, category),
+			fmt.Sprintf("custom/%s/%s/%s", storeName, querier.LeaderKey, category))(w, r)
+	}
+}
+
+//TeamLeaderBoardHandler queries the chain for the current team board
+//nolint:gocritic
+func TeamLeaderBoardHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return cachedBoardHandler(cliCtx, TeamBoardCacheKey, fmt.Sprintf("custom/%s/%s", storeName, querier.TeamsKey))
+}
+
+//cachedBoardHandler serves the board queried at `path` from the cache, so that the boards are built
+//at most once a minute
+//nolint:gocritic
+func cachedBoardHandler(cliCtx context.CLIContext, key string, path string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		res, found := boardCache.Get(BoardCacheKey)
+		res, found := boardCache.Get(key)
 		if found {
 			rest.PostProcessResponse(w, cliCtx, res)
 			return
@@ -37,21 +63,20 @@ func LeaderBoardHandler(cliCtx context.CLIContext, storeName string) http.Handle
 		//update cache
 		mutex.Lock()
 		//pass through for any routines that queued on the lock while the cache was updated
-		res, found = boardCache.Get(BoardCacheKey)
+		res, found = boardCache.Get(key)
 		if found {
 			mutex.Unlock()
 			rest.PostProcessResponse(w, cliCtx, res)
 			return
 		}
 
-		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s",
-			storeName, querier.LeaderKey), nil)
+		res, _, err := cliCtx.QueryWithData(path, nil)
 		if err != nil {
 			mutex.Unlock()
 			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 			return
 		}
-		boardCache.Set(BoardCacheKey, res, cache.DefaultExpiration)
+		boardCache.Set(key, res, cache.DefaultExpiration)
 
 		mutex.Unlock()
 		rest.PostProcessResponse(w, cliCtx, res)
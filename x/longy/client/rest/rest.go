@@ -3,7 +3,7 @@ package rest
 import (
 	"fmt"
 	"github.com/cosmos/cosmos-sdk/client/context"
-	"github.com/cosmos/cosmos-sdk/x/auth/client/rest"
+	"github.com/eco/longy/x/longy/client/rest/broadcast"
 	"github.com/eco/longy/x/longy/client/rest/query"
 	"github.com/eco/longy/x/longy/internal/querier"
 	"github.com/gorilla/mux"
@@ -51,6 +51,10 @@ func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string)
 	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.PrizesKey),
 		prizesGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
 
+	// <storeName>/roles
+	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.RolesKey),
+		rolesGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
 	// <storeName>/bonus
 	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.QueryBonus),
 		bonusGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
@@ -59,17 +63,64 @@ func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string)
 	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.LeaderKey),
 		query.LeaderBoardHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
 
+	// <storeName>/leader/{category}
+	r.HandleFunc(fmt.Sprintf("/%s/%s/{%s}", storeName, querier.LeaderKey, query.CategoryKey),
+		query.CategoryLeaderBoardHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/teams
+	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.TeamsKey),
+		query.TeamLeaderBoardHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
 	// <storeName>/winnings?address_id={address_id}
 	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.WinningsKey),
 		query.WinningsHandler(cliCtx, storeName)).
 		Queries(query.AddressIDKey, fmt.Sprintf("{%s}", query.AddressIDKey)).
 		Methods(http.MethodGet, http.MethodOptions)
 
+	// <storeName>/raffles
+	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.RafflesKey),
+		rafflesGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/raffles/{raffle_id}
+	r.HandleFunc(fmt.Sprintf("/%s/%s/{%s}", storeName, querier.RafflesKey, query.RaffleIDKey),
+		raffleGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/challenges
+	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.ChallengesKey),
+		challengesGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/challenges/{challenge_id}
+	r.HandleFunc(fmt.Sprintf("/%s/%s/{%s}", storeName, querier.ChallengesKey, query.ChallengeIDKey),
+		challengeGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/checkpoints
+	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.CheckpointsKey),
+		checkpointsGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/checkpoints/{checkpoint_id}
+	r.HandleFunc(fmt.Sprintf("/%s/%s/{%s}", storeName, querier.CheckpointsKey, query.CheckpointIDKey),
+		checkpointGetHandler(cliCtx, storeName, "")).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/checkpoints/{checkpoint_id}/attendance
+	r.HandleFunc(fmt.Sprintf("/%s/%s/{%s}/%s", storeName, querier.CheckpointsKey, query.CheckpointIDKey,
+		querier.AttendanceKey), checkpointGetHandler(cliCtx, storeName, querier.AttendanceKey)).
+		Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/achievements
+	r.HandleFunc(fmt.Sprintf("/%s/%s", storeName, querier.AchievementsKey),
+		achievementsGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
+	// <storeName>/achievements/{address_id}
+	r.HandleFunc(fmt.Sprintf("/%s/%s/{%s}", storeName, querier.AchievementsKey, query.AddressIDKey),
+		achievementProgressGetHandler(cliCtx, storeName)).Methods(http.MethodGet, http.MethodOptions)
+
 	// open endpoint to post to in order to claim the prizes of an attendee by passing a sig from the attendee
 	r.HandleFunc("/longy/claim", query.ClaimHandler(cliCtx)).Methods(http.MethodPost, http.MethodOptions)
+	r.HandleFunc("/longy/claim/challenge", query.ClaimChallengeHandler(cliCtx)).
+		Methods(http.MethodPost, http.MethodOptions)
 
-	// open endpoint to post transactions directly to full node
-	r.HandleFunc("/longy/txs", rest.BroadcastTxRequest(cliCtx)).Methods(http.MethodPost, http.MethodOptions)
+	// open endpoint to post longy transactions to the full node, see the broadcast package for the checks
+	r.Handle("/longy/txs", broadcast.Handler(cliCtx)).Methods(http.MethodPost, http.MethodOptions)
 
 	//  IMPORTANT: you must specify an OPTIONS method matcher for the middleware to set CORS headers
 	r.Use(mux.CORSMethodMiddleware(r))
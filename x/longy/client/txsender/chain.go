@@ -0,0 +1,45 @@
+package txsender
+
+import (
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+)
+
+// cliChain reaches the chain through the full node of a cli context
+type cliChain struct {
+	cliCtx context.CLIContext
+}
+
+// NewCLIChain returns a `Chain` querying and broadcasting through the full node of `cliCtx`,
+// used by the rest server to send its own transactions
+//nolint:gocritic
+func NewCLIChain(cliCtx context.CLIContext) Chain {
+	return cliChain{cliCtx: cliCtx}
+}
+
+func (c cliChain) Account(addr sdk.AccAddress) (auth.Account, error) {
+	return auth.NewAccountRetriever(c.cliCtx).GetAccount(addr)
+}
+
+func (c cliChain) BroadcastTx(tx *auth.StdTx, mode string) (*sdk.TxResponse, error) {
+	txBytes, err := utils.GetTxEncoder(c.cliCtx.Codec)(*tx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.cliCtx.WithBroadcastMode(mode).BroadcastTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c cliChain) Tx(hash string) (*sdk.TxResponse, error) {
+	res, err := utils.QueryTx(c.cliCtx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,292 @@
+package txsender
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+// Broadcast modes of the transactions
+const (
+	BroadcastSync  = "sync"
+	BroadcastAsync = "async"
+	BroadcastBlock = "block"
+)
+
+const (
+	// DefaultGasPerMsg is the gas allotted for every message in a transaction
+	DefaultGasPerMsg = 50000
+
+	// DefaultPollInterval is the wait between two lookups of a transaction waiting to be committed
+	DefaultPollInterval = time.Second
+)
+
+// ErrNotConfirmed denotes a transaction accepted by the node that was not committed in time
+var ErrNotConfirmed = errors.New("transaction not committed before the confirmation timeout")
+
+// Signer signs transactions on behalf of an account. A tendermint private key is a signer
+type Signer interface {
+	PubKey() tmcrypto.PubKey
+	Sign(signBytes []byte) ([]byte, error)
+}
+
+// Chain retrieves accounts and transactions and broadcasts transactions. `longyclient.Client`
+// does so through the rest server and `NewCLIChain` through a full node
+type Chain interface {
+	Account(addr sdk.AccAddress) (auth.Account, error)
+	BroadcastTx(tx *auth.StdTx, mode string) (*sdk.TxResponse, error)
+	Tx(hash string) (*sdk.TxResponse, error)
+}
+
+// Result is a broadcast transaction. `Committed` is false for transactions broadcast without
+// waiting for a block and for transactions rejected before they were included in one
+type Result struct {
+	TxHash    string
+	Height    int64
+	Committed bool
+	GasWanted int64
+	GasUsed   int64
+	Response  *sdk.TxResponse
+}
+
+// TxError is a transaction rejected by the chain
+type TxError struct {
+	Codespace sdk.CodespaceType
+	Code      sdk.CodeType
+	Log       string
+	// MsgIndex is the index of the message that failed, -1 if the transaction failed as a whole
+	MsgIndex int
+	Result   *Result
+}
+
+// Error implements the error interface
+func (e *TxError) Error() string {
+	return fmt.Sprintf("transaction failed, %s code %d: %s", e.Codespace, e.Code, e.Log)
+}
+
+// SequenceMismatch indicates that the transaction was signed over a stale sequence. The ante
+// handler rejects such signatures as unauthorized
+func (e *TxError) SequenceMismatch() bool {
+	return e.Codespace == sdk.CodespaceRoot && e.Code == sdk.CodeUnauthorized
+}
+
+// SignError is a transaction the signer failed to sign
+type SignError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *SignError) Error() string {
+	return fmt.Sprintf("signing transaction: %s", e.Err)
+}
+
+// Sender signs and broadcasts the transactions of a single account. The account number and
+// sequence are retrieved once and tracked locally, transactions are sent one at a time and
+// the account is retrieved again whenever its sequence on chain is uncertain
+type Sender struct {
+	signer  Signer
+	address sdk.AccAddress
+	chainID string
+	chain   Chain
+
+	mode         string
+	gasPerMsg    uint64
+	fees         sdk.Coins
+	confirm      time.Duration
+	pollInterval time.Duration
+
+	mtx      sync.Mutex
+	synced   bool
+	accNum   uint64
+	sequence uint64
+}
+
+// Option configures a `Sender`
+type Option func(*Sender)
+
+// WithMode broadcasts the transactions in `mode`, one of sync, async or block
+func WithMode(mode string) Option {
+	return func(s *Sender) {
+		s.mode = mode
+	}
+}
+
+// WithGas allots `gasPerMsg` for every message in a transaction
+func WithGas(gasPerMsg uint64) Option {
+	return func(s *Sender) {
+		s.gasPerMsg = gasPerMsg
+	}
+}
+
+// WithFees pays `fees` for every transaction
+func WithFees(fees sdk.Coins) Option {
+	return func(s *Sender) {
+		s.fees = fees
+	}
+}
+
+// WithConfirmation waits up to `timeout` for transactions broadcast in sync or async mode to be
+// committed, looking them up by hash every `interval`
+func WithConfirmation(timeout, interval time.Duration) Option {
+	return func(s *Sender) {
+		s.confirm, s.pollInterval = timeout, interval
+	}
+}
+
+// New is the constructor for `Sender`. Transactions are signed by `signer` for `chainID` and
+// broadcast through `chain` in block mode unless configured otherwise
+func New(signer Signer, chainID string, chain Chain, opts ...Option) *Sender {
+	s := &Sender{
+		signer:  signer,
+		address: sdk.AccAddress(signer.PubKey().Address()),
+		chainID: chainID,
+		chain:   chain,
+
+		mode:         BroadcastBlock,
+		gasPerMsg:    DefaultGasPerMsg,
+		fees:         sdk.NewCoins(sdk.NewInt64Coin("longy", 0)),
+		pollInterval: DefaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Address is the address of the account sending the transactions
+func (s *Sender) Address() sdk.AccAddress {
+	return s.address
+}
+
+// Account returns the account number and the sequence of the next transaction
+func (s *Sender) Account() (uint64, uint64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.accNum, s.sequence
+}
+
+// Sync retrieves the account number and the sequence from the chain
+func (s *Sender) Sync() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.sync()
+}
+
+// Send signs `msgs` into a single transaction and broadcasts it. A `*TxError` is returned
+// if the chain rejects the transaction and a `*SignError` if it cannot be signed
+func (s *Sender) Send(msgs ...sdk.Msg) (*Result, error) {
+	if s.mode != BroadcastSync && s.mode != BroadcastAsync && s.mode != BroadcastBlock {
+		return nil, fmt.Errorf("incorrect broadcast mode %s", s.mode)
+	}
+
+	result, err := s.broadcast(msgs)
+	if err != nil || result.Committed || s.confirm <= 0 {
+		return result, err
+	}
+
+	return s.waitForCommit(result, len(msgs))
+}
+
+func (s *Sender) broadcast(msgs []sdk.Msg) (*Result, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if !s.synced {
+		if err := s.sync(); err != nil {
+			return nil, err
+		}
+	}
+
+	fee := auth.NewStdFee(s.gasPerMsg*uint64(len(msgs)), s.fees)
+	signBytes := auth.StdSignBytes(s.chainID, s.accNum, s.sequence, fee, msgs, "")
+	sig, err := s.signer.Sign(signBytes)
+	if err != nil {
+		return nil, &SignError{Err: err}
+	}
+	stdSig := auth.StdSignature{PubKey: s.signer.PubKey(), Signature: sig}
+	tx := auth.NewStdTx(msgs, fee, []auth.StdSignature{stdSig}, "")
+
+	res, err := s.chain.BroadcastTx(&tx, s.mode)
+	if err != nil {
+		// the transaction may or may not have been accepted
+		s.synced = false
+		return nil, err
+	}
+
+	result := newResult(res, s.mode == BroadcastBlock)
+	if res.Code != uint32(sdk.CodeOK) {
+		// deliver failures consume the sequence while check failures do not
+		s.synced = false
+		return result, txError(res, result, len(msgs))
+	}
+
+	s.sequence++
+	return result, nil
+}
+
+// waitForCommit looks the transaction up until it is committed or the confirmation times out
+func (s *Sender) waitForCommit(result *Result, count int) (*Result, error) {
+	deadline := time.Now().Add(s.confirm)
+	for time.Now().Before(deadline) {
+		time.Sleep(s.pollInterval)
+
+		res, err := s.chain.Tx(result.TxHash)
+		if err != nil || res == nil || res.Height == 0 {
+			continue
+		}
+
+		committed := newResult(res, true)
+		if res.Code != uint32(sdk.CodeOK) {
+			return committed, txError(res, committed, count)
+		}
+		return committed, nil
+	}
+
+	return result, ErrNotConfirmed
+}
+
+func (s *Sender) sync() error {
+	acc, err := s.chain.Account(s.address)
+	if err != nil {
+		return fmt.Errorf("account retrieval: %s", err)
+	}
+
+	s.accNum, s.sequence, s.synced = acc.GetAccountNumber(), acc.GetSequence(), true
+	return nil
+}
+
+func newResult(res *sdk.TxResponse, committed bool) *Result {
+	return &Result{
+		TxHash:    res.TxHash,
+		Height:    res.Height,
+		Committed: committed && (res.Code == uint32(sdk.CodeOK) || res.Height > 0),
+		GasWanted: res.GasWanted,
+		GasUsed:   res.GasUsed,
+		Response:  res,
+	}
+}
+
+// txError describes the failure of `res`. Messages are executed in order and execution stops
+// at the first failure, which is the failed message of the transaction
+func txError(res *sdk.TxResponse, result *Result, count int) *TxError {
+	e := &TxError{
+		Codespace: sdk.CodespaceType(res.Codespace),
+		Code:      sdk.CodeType(res.Code),
+		Log:       res.RawLog,
+		MsgIndex:  -1,
+		Result:    result,
+	}
+	for _, l := range res.Logs {
+		if !l.Success && int(l.MsgIndex) < count {
+			e.MsgIndex = int(l.MsgIndex)
+			break
+		}
+	}
+
+	return e
+}
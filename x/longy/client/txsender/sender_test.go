@@ -0,0 +1,199 @@
+package txsender
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// fakeChain accepts every transaction signed over its sequence unless a response or an error is scripted
+type fakeChain struct {
+	sync.Mutex
+	sequence  uint64
+	responses []*sdk.TxResponse
+	errs      []error
+	committed map[string]*sdk.TxResponse
+	lookups   int
+	txs       []*auth.StdTx
+}
+
+func (c *fakeChain) Account(addr sdk.AccAddress) (auth.Account, error) {
+	c.Lock()
+	defer c.Unlock()
+	acc := auth.NewBaseAccountWithAddress(addr)
+	_ = acc.SetAccountNumber(7)
+	_ = acc.SetSequence(c.sequence)
+	return &acc, nil
+}
+
+func (c *fakeChain) BroadcastTx(tx *auth.StdTx, mode string) (*sdk.TxResponse, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.txs = append(c.txs, tx)
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	if len(c.responses) > 0 {
+		res := c.responses[0]
+		c.responses = c.responses[1:]
+		return res, nil
+	}
+
+	signBytes := auth.StdSignBytes("longychain", 7, c.sequence, tx.Fee, tx.Msgs, tx.Memo)
+	if !tx.Signatures[0].PubKey.VerifyBytes(signBytes, tx.Signatures[0].Signature) {
+		return &sdk.TxResponse{Codespace: string(sdk.CodespaceRoot), Code: uint32(sdk.CodeUnauthorized)}, nil
+	}
+	c.sequence++
+	if mode == BroadcastBlock {
+		return &sdk.TxResponse{TxHash: "hash", Height: 10}, nil
+	}
+	return &sdk.TxResponse{TxHash: "hash"}, nil
+}
+
+func (c *fakeChain) Tx(hash string) (*sdk.TxResponse, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.lookups++
+	if res, ok := c.committed[hash]; ok && c.lookups > 1 {
+		return res, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// failingSigner fails every signature
+type failingSigner struct {
+	tmcrypto.PrivKey
+}
+
+func (failingSigner) Sign([]byte) ([]byte, error) {
+	return nil, errors.New("daemon unreachable")
+}
+
+var _ = Describe("Sender", func() {
+	var (
+		chain *fakeChain
+		key   tmcrypto.PrivKey
+		msg   sdk.Msg
+	)
+
+	BeforeEach(func() {
+		chain = &fakeChain{sequence: 3, committed: make(map[string]*sdk.TxResponse)}
+		key = secp256k1.GenPrivKeySecp256k1([]byte("sender"))
+		msg = types.NewMsgClearBonus(sdk.AccAddress(key.PubKey().Address()))
+	})
+
+	It("tracks the sequence of the account", func() {
+		sender := New(key, "longychain", chain)
+
+		for i := 0; i < 3; i++ {
+			res, err := sender.Send(msg)
+			Expect(err).To(BeNil())
+			Expect(res.Committed).To(BeTrue())
+			Expect(res.Height).To(Equal(int64(10)))
+		}
+
+		accNum, sequence := sender.Account()
+		Expect(accNum).To(Equal(uint64(7)))
+		Expect(sequence).To(Equal(uint64(6)))
+	})
+
+	It("allots the configured gas and fees", func() {
+		fees := sdk.NewCoins(sdk.NewInt64Coin("longy", 5))
+		sender := New(key, "longychain", chain, WithGas(1000), WithFees(fees))
+
+		_, err := sender.Send(msg, msg)
+		Expect(err).To(BeNil())
+		Expect(chain.txs[0].Fee.Gas).To(Equal(uint64(2000)))
+		Expect(chain.txs[0].Fee.Amount).To(Equal(fees))
+	})
+
+	It("resyncs the account once the sequence is uncertain", func() {
+		sender := New(key, "longychain", chain)
+		Expect(sender.Sync()).To(BeNil())
+
+		chain.Lock()
+		chain.sequence = 10
+		chain.Unlock()
+
+		_, err := sender.Send(msg)
+		Expect(err.(*TxError).SequenceMismatch()).To(BeTrue())
+		_, err = sender.Send(msg)
+		Expect(err).To(BeNil())
+
+		chain.Lock()
+		chain.errs = []error{errors.New("connection refused")}
+		chain.sequence = 20
+		chain.Unlock()
+
+		_, err = sender.Send(msg)
+		Expect(err).ToNot(BeNil())
+		_, err = sender.Send(msg)
+		Expect(err).To(BeNil())
+
+		_, sequence := sender.Account()
+		Expect(sequence).To(Equal(uint64(21)))
+	})
+
+	It("reports the message failing the transaction", func() {
+		sender := New(key, "longychain", chain)
+		chain.responses = []*sdk.TxResponse{{
+			Height:    10,
+			Codespace: types.ModuleName,
+			Code:      uint32(types.AttendeeKeyed),
+			Logs:      sdk.ABCIMessageLogs{{MsgIndex: 0, Success: true}, {MsgIndex: 1, Success: false}},
+		}}
+
+		res, err := sender.Send(msg, msg)
+		txErr := err.(*TxError)
+		Expect(txErr.Codespace).To(Equal(types.LongyCodeSpace))
+		Expect(txErr.Code).To(Equal(types.AttendeeKeyed))
+		Expect(txErr.MsgIndex).To(Equal(1))
+		Expect(res.Committed).To(BeTrue())
+	})
+
+	It("reports signing failures", func() {
+		sender := New(failingSigner{key}, "longychain", chain)
+
+		_, err := sender.Send(msg)
+		Expect(err).To(BeAssignableToTypeOf(&SignError{}))
+		Expect(chain.txs).To(BeEmpty())
+	})
+
+	It("waits for transactions broadcast in sync mode to be committed", func() {
+		sender := New(key, "longychain", chain, WithMode(BroadcastSync),
+			WithConfirmation(time.Second, time.Millisecond))
+		chain.committed["hash"] = &sdk.TxResponse{TxHash: "hash", Height: 12, GasUsed: 100}
+
+		res, err := sender.Send(msg)
+		Expect(err).To(BeNil())
+		Expect(res.Committed).To(BeTrue())
+		Expect(res.Height).To(Equal(int64(12)))
+		Expect(res.GasUsed).To(Equal(int64(100)))
+	})
+
+	It("gives up on transactions that are not committed in time", func() {
+		sender := New(key, "longychain", chain, WithMode(BroadcastAsync),
+			WithConfirmation(20*time.Millisecond, time.Millisecond))
+
+		res, err := sender.Send(msg)
+		Expect(err).To(Equal(ErrNotConfirmed))
+		Expect(res.TxHash).To(Equal("hash"))
+		Expect(res.Committed).To(BeFalse())
+	})
+
+	It("refuses unknown broadcast modes", func() {
+		_, err := New(key, "longychain", chain, WithMode("commit")).Send(msg)
+		Expect(err).ToNot(BeNil())
+		Expect(chain.txs).To(BeEmpty())
+	})
+})
@@ -0,0 +1,12 @@
+package txsender
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestTxSender(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tx Sender Suite")
+}
@@ -0,0 +1,127 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+const nonceLength = 16
+
+//ClaimSignDoc is the document an attendee signs to claim their prizes at a desk. The nonce is
+//single use and bound to the desk and expiry it was issued with, so a signature cannot be replayed
+type ClaimSignDoc struct {
+	ChainID string `json:"chain_id"`
+	DeskID  string `json:"desk_id"`
+	Nonce   string `json:"nonce"`
+	Address string `json:"address"`
+	Expires int64  `json:"expires"`
+}
+
+//Bytes returns the sorted json encoding of the sign doc that is signed by the attendee
+func (d ClaimSignDoc) Bytes() []byte {
+	bz, err := json.Marshal(d)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+//Challenge is a nonce issued to a claim desk. Expires is in unix seconds
+type Challenge struct {
+	Nonce   string `json:"nonce"`
+	DeskID  string `json:"desk_id"`
+	Expires int64  `json:"expires"`
+}
+
+//Challenges keeps the nonces issued to claim desks until they are consumed or expire
+type Challenges struct {
+	mtx     sync.Mutex
+	ttl     time.Duration
+	pending map[string]Challenge
+}
+
+//NewChallenges is the constructor for challenges. Nonces are valid for `ttl`
+func NewChallenges(ttl time.Duration) *Challenges {
+	return &Challenges{
+		ttl:     ttl,
+		pending: make(map[string]Challenge),
+	}
+}
+
+//Issue creates a new challenge for `deskID`
+func (c *Challenges) Issue(deskID string) (Challenge, sdk.Error) {
+	deskID = strings.TrimSpace(deskID)
+	if len(deskID) == 0 {
+		return Challenge{}, types.ErrInvalidChallenge("desk id cannot be empty")
+	}
+
+	b := make([]byte, nonceLength)
+	if _, err := rand.Read(b); err != nil {
+		return Challenge{}, types.ErrDefault("could not generate a nonce: %s", err)
+	}
+
+	challenge := Challenge{
+		Nonce:   hex.EncodeToString(b),
+		DeskID:  deskID,
+		Expires: time.Now().Add(c.ttl).Unix(),
+	}
+
+	c.mtx.Lock()
+	c.prune()
+	c.pending[challenge.Nonce] = challenge
+	c.mtx.Unlock()
+
+	return challenge, nil
+}
+
+//Get returns the unexpired challenge with `nonce` issued to `deskID`
+func (c *Challenges) Get(nonce, deskID string) (Challenge, sdk.Error) {
+	c.mtx.Lock()
+	challenge, ok := c.pending[nonce]
+	c.mtx.Unlock()
+
+	switch {
+	case !ok:
+		return Challenge{}, types.ErrInvalidChallenge("unknown or already used nonce")
+	case challenge.Expires <= time.Now().Unix():
+		return Challenge{}, types.ErrInvalidChallenge("nonce expired")
+	case challenge.DeskID != deskID:
+		return Challenge{}, types.ErrInvalidChallenge("nonce was issued to another desk")
+	}
+
+	return challenge, nil
+}
+
+//Consume removes the challenge with `nonce`. Only the first call for a nonce succeeds
+func (c *Challenges) Consume(nonce string) sdk.Error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	challenge, ok := c.pending[nonce]
+	if !ok {
+		return types.ErrInvalidChallenge("unknown or already used nonce")
+	}
+	delete(c.pending, nonce)
+
+	if challenge.Expires <= time.Now().Unix() {
+		return types.ErrInvalidChallenge("nonce expired")
+	}
+	return nil
+}
+
+// prune drops the expired challenges. The lock must be held
+func (c *Challenges) prune() {
+	now := time.Now().Unix()
+	for nonce, challenge := range c.pending {
+		if challenge.Expires <= now {
+			delete(c.pending, nonce)
+		}
+	}
+}
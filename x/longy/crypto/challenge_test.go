@@ -0,0 +1,113 @@
+package crypto_test
+
+import (
+	"encoding/hex"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/crypto"
+	"github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	tmCrypto "github.com/tendermint/tendermint/crypto"
+	secp "github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Claim Challenge Tests", func() {
+	var challenges *crypto.Challenges
+	var key tmCrypto.PrivKey
+	var addr sdk.AccAddress
+	BeforeEach(func() {
+		BeforeTestRun()
+
+		challenges = crypto.NewChallenges(time.Minute)
+		key = secp.GenPrivKeySecp256k1([]byte("attendee"))
+		addr = sdk.AccAddress(key.PubKey().Address())
+	})
+
+	It("should fail to issue a challenge without a desk", func() {
+		_, e := challenges.Issue(" ")
+		Expect(e).To(Not(BeNil()))
+		Expect(e.Code()).To(Equal(types.InvalidChallenge))
+	})
+
+	It("should issue unique nonces bound to the desk", func() {
+		a, e := challenges.Issue("desk-1")
+		Expect(e).To(BeNil())
+		b, e := challenges.Issue("desk-1")
+		Expect(e).To(BeNil())
+		Expect(a.Nonce).To(Not(Equal(b.Nonce)))
+		Expect(a.Expires).To(BeNumerically(">", time.Now().Unix()))
+
+		c, e := challenges.Get(a.Nonce, "desk-1")
+		Expect(e).To(BeNil())
+		Expect(c).To(Equal(a))
+	})
+
+	It("should fail when the nonce was issued to another desk", func() {
+		c, _ := challenges.Issue("desk-1")
+		_, e := challenges.Get(c.Nonce, "desk-2")
+		Expect(e).To(Not(BeNil()))
+		Expect(e.Code()).To(Equal(types.InvalidChallenge))
+	})
+
+	It("should fail when the nonce is unknown", func() {
+		_, e := challenges.Get("deadbeef", "desk-1")
+		Expect(e).To(Not(BeNil()))
+		Expect(e.Code()).To(Equal(types.InvalidChallenge))
+	})
+
+	It("should fail when the nonce expired", func() {
+		challenges = crypto.NewChallenges(-time.Second)
+		c, _ := challenges.Issue("desk-1")
+		_, e := challenges.Get(c.Nonce, "desk-1")
+		Expect(e).To(Not(BeNil()))
+		Expect(e.Code()).To(Equal(types.InvalidChallenge))
+		Expect(challenges.Consume(c.Nonce)).To(Not(BeNil()))
+	})
+
+	It("should only consume a nonce once", func() {
+		c, _ := challenges.Issue("desk-1")
+		Expect(challenges.Consume(c.Nonce)).To(BeNil())
+
+		e := challenges.Consume(c.Nonce)
+		Expect(e).To(Not(BeNil()))
+		Expect(e.Code()).To(Equal(types.InvalidChallenge))
+
+		_, e = challenges.Get(c.Nonce, "desk-1")
+		Expect(e).To(Not(BeNil()))
+	})
+
+	It("should validate a signature over the sign doc", func() {
+		c, _ := challenges.Issue("desk-1")
+		doc := crypto.ClaimSignDoc{
+			ChainID: "longychain",
+			DeskID:  c.DeskID,
+			Nonce:   c.Nonce,
+			Address: addr.String(),
+			Expires: c.Expires,
+		}
+		sig, err := key.Sign(doc.Bytes())
+		Expect(err).To(BeNil())
+		sigEncoded := hex.EncodeToString(sig)
+
+		Expect(crypto.ValidateClaimSig(key.PubKey(), doc, sigEncoded)).To(BeNil())
+
+		// the signature cannot be used at another desk
+		doc.DeskID = "desk-2"
+		e := crypto.ValidateClaimSig(key.PubKey(), doc, sigEncoded)
+		Expect(e).To(Not(BeNil()))
+		Expect(e.Code()).To(Equal(types.InvalidSignature))
+	})
+
+	It("should not accept a signature over the address alone", func() {
+		c, _ := challenges.Issue("desk-1")
+		sig, err := key.Sign([]byte(addr.String()))
+		Expect(err).To(BeNil())
+
+		doc := crypto.ClaimSignDoc{DeskID: c.DeskID, Nonce: c.Nonce, Address: addr.String(), Expires: c.Expires}
+		e := crypto.ValidateClaimSig(key.PubKey(), doc, hex.EncodeToString(sig))
+		Expect(e).To(Not(BeNil()))
+		Expect(e.Code()).To(Equal(types.InvalidSignature))
+	})
+})
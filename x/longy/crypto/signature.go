@@ -18,12 +18,30 @@ func ValidateSig(key crypto.PubKey, address string, sig string) sdk.Error {
 		return sdk.ErrInvalidAddress("could not decode bech32 address")
 	}
 
+	return verifyHexSig(key, []byte(address), sig)
+}
+
+//ValidateClaimSig validates that the hex encoded sig is the signed `doc` by the corresponding private key
+func ValidateClaimSig(key crypto.PubKey, doc ClaimSignDoc, sig string) sdk.Error {
+	if key == nil {
+		return types.ErrInvalidPublicKey("public key cannot be empty")
+	}
+
+	_, e := sdk.AccAddressFromBech32(doc.Address)
+	if e != nil {
+		return sdk.ErrInvalidAddress("could not decode bech32 address")
+	}
+
+	return verifyHexSig(key, doc.Bytes(), sig)
+}
+
+func verifyHexSig(key crypto.PubKey, msg []byte, sig string) sdk.Error {
 	sigBytes, e := hex.DecodeString(sig)
 	if e != nil {
 		return types.ErrSigDecodeError("error on hex decoding signature")
 	}
 
-	if !key.VerifyBytes([]byte(address), sigBytes) {
+	if !key.VerifyBytes(msg, sigBytes) {
 		return types.ErrInvalidSignature("signature does not match account public key")
 	}
 
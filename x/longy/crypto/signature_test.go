@@ -48,9 +48,8 @@ var _ = Describe("Signature Keeper Tests", func() {
 	})
 
 	It("should succeed when signature is valid", func() {
-		signer := crypto.NewSigner(addr, key)
 		addrString := addr.String()
-		sig, err := signer.PrivKey.Sign([]byte(addrString))
+		sig, err := key.Sign([]byte(addrString))
 		Expect(err).To(BeNil())
 
 		sigEncoded := hex.EncodeToString(sig)
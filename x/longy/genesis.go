@@ -5,6 +5,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/eco/longy/util"
 	"github.com/eco/longy/x/longy/internal/keeper"
 	"github.com/eco/longy/x/longy/internal/types"
 	"github.com/tendermint/tendermint/crypto"
@@ -18,23 +19,62 @@ type GenesisState struct {
 	Attendees    GenesisAttendees `json:"attendees"`
 	Scans        GenesisScans     `json:"scans"`
 	Prizes       GenesisPrizes    `json:"prizes"`
+	Roles        GenesisRoles     `json:"roles"`
+
+	//AddressNamespace is the event namespace attendee addresses are derived with. Genesis files
+	//without a namespace use the legacy seed derived addresses
+	AddressNamespace string `json:"address_namespace"`
+
+	//TxQuota limits the transactions of attendee accounts. Genesis files without a quota use the default
+	TxQuota *TxQuota `json:"tx_quota,omitempty"`
+
+	//AttestedScans are the scans already synced with an attestation by their scanner, refused as replays
+	AttestedScans GenesisAttestedScans `json:"attested_scans,omitempty"`
+
+	//Raffles are the prize raffles, exported with their entrants and draws
+	Raffles GenesisRaffles `json:"raffles,omitempty"`
+
+	//BoothChallenges are the booth challenges of the sponsors and BoothAnswers the answers committed to them
+	//that are not revealed yet. Revealed answers are recorded on the attendees
+	BoothChallenges GenesisBoothChallenges `json:"booth_challenges,omitempty"`
+	BoothAnswers    GenesisBoothAnswers    `json:"booth_answers,omitempty"`
+
+	//Checkpoints are the venue checkpoints and CheckpointScans their attendance
+	Checkpoints     GenesisCheckpoints     `json:"checkpoints,omitempty"`
+	CheckpointScans GenesisCheckpointScans `json:"checkpoint_scans,omitempty"`
+
+	//Achievements are the rules granting badges to the attendees. Their badges are recorded on the attendees
+	Achievements GenesisAchievements `json:"achievements,omitempty"`
+
+	//TeamRules score the teams of the team board. Genesis files without them use the default rules
+	TeamRules *TeamRules `json:"team_rules,omitempty"`
 }
 
 // DefaultGenesisState returns the default genesis struct for the longy module
 func DefaultGenesisState() GenesisState {
+	txQuota := types.DefaultTxQuota()
+	teamRules := types.DefaultTeamRules()
 	return GenesisState{KeyService: GenesisService{}, BonusService: GenesisService{},
-		Attendees: GenesisAttendees{}, Scans: GenesisScans{}, Prizes: GenesisPrizes{}}
+		Attendees: GenesisAttendees{}, Scans: GenesisScans{}, Prizes: GenesisPrizes{},
+		Roles: types.DefaultRoleRules(), TxQuota: &txQuota, TeamRules: &teamRules}
 }
 
 //NewGenesisState returns a genesis object of the state given the input params
 func NewGenesisState(service GenesisService, bonusService GenesisService, claimService GenesisService,
-	attendees []types.Attendee, scans []types.Scan, prizes types.GenesisPrizes) GenesisState {
+	attendees []types.Attendee, scans []types.Scan, prizes types.GenesisPrizes,
+	roles types.GenesisRoles, addressNamespace string, txQuota *types.TxQuota, attestedScans types.GenesisAttestedScans,
+	raffles types.GenesisRaffles, challenges types.GenesisBoothChallenges, answers types.GenesisBoothAnswers,
+	checkpoints types.GenesisCheckpoints, checkpointScans types.GenesisCheckpointScans,
+	achievements types.GenesisAchievements, teamRules *types.TeamRules) GenesisState {
 	return GenesisState{KeyService: service, BonusService: bonusService, ClaimService: claimService,
-		Attendees: attendees, Scans: scans, Prizes: prizes}
+		Attendees: attendees, Scans: scans, Prizes: prizes, Roles: roles, AddressNamespace: addressNamespace,
+		TxQuota: txQuota, AttestedScans: attestedScans, Raffles: raffles, BoothChallenges: challenges,
+		BoothAnswers: answers, Checkpoints: checkpoints, CheckpointScans: checkpointScans, Achievements: achievements,
+		TeamRules: teamRules}
 }
 
 // ValidateGenesis validates that the passed genesis state is valid
-//nolint:gocritic
+//nolint:gocritic,gocyclo
 func ValidateGenesis(data GenesisState) error {
 	if data.KeyService.Address.Empty() {
 		return types.ErrGenesisKeyServiceAddressEmpty("key service address must be set")
@@ -56,12 +96,107 @@ func ValidateGenesis(data GenesisState) error {
 		return types.ErrGenesisPrizesEmpty("empty genesis prizes")
 	}
 
+	var seenRoles = make(map[string]bool)
+	for i := range data.Roles {
+		if err := data.Roles[i].ValidateBasic(); err != nil {
+			return err
+		}
+		if seenRoles[data.Roles[i].Role] {
+			return types.ErrInvalidRole("duplicate role: %s", data.Roles[i].Role)
+		}
+		seenRoles[data.Roles[i].Role] = true
+	}
+
+	if data.TxQuota != nil {
+		if err := data.TxQuota.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+
+	if data.TeamRules != nil {
+		if err := data.TeamRules.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+
+	for i := range data.AttestedScans {
+		if err := data.AttestedScans[i].ValidateBasic(); err != nil {
+			return err
+		}
+	}
+
+	var seenRaffles = make(map[string]bool)
+	for i := range data.Raffles {
+		if err := data.Raffles[i].ValidateBasic(); err != nil {
+			return err
+		}
+		if seenRaffles[data.Raffles[i].ID] {
+			return types.ErrInvalidRaffle("duplicate raffle: %s", data.Raffles[i].ID)
+		}
+		seenRaffles[data.Raffles[i].ID] = true
+	}
+
+	var seenChallenges = make(map[string]bool)
+	for i := range data.BoothChallenges {
+		if err := data.BoothChallenges[i].ValidateBasic(); err != nil {
+			return err
+		}
+		if seenChallenges[data.BoothChallenges[i].ID] {
+			return types.ErrInvalidBoothChallenge("duplicate booth challenge: %s", data.BoothChallenges[i].ID)
+		}
+		seenChallenges[data.BoothChallenges[i].ID] = true
+	}
+	for i := range data.BoothAnswers {
+		if err := data.BoothAnswers[i].ValidateBasic(); err != nil {
+			return err
+		}
+		if !seenChallenges[data.BoothAnswers[i].ChallengeID] {
+			return types.ErrBoothChallengeNotFound("answer to unknown booth challenge: %s",
+				data.BoothAnswers[i].ChallengeID)
+		}
+	}
+
+	var seenCheckpoints = make(map[string]bool)
+	for i := range data.Checkpoints {
+		if err := data.Checkpoints[i].ValidateBasic(); err != nil {
+			return err
+		}
+		if seenCheckpoints[data.Checkpoints[i].ID] {
+			return types.ErrInvalidCheckpoint("duplicate checkpoint: %s", data.Checkpoints[i].ID)
+		}
+		seenCheckpoints[data.Checkpoints[i].ID] = true
+	}
+	for _, scan := range data.CheckpointScans {
+		if !seenCheckpoints[scan.CheckpointID] {
+			return types.ErrCheckpointNotFound("attendance of unknown checkpoint: %s", scan.CheckpointID)
+		}
+	}
+
+	var seenAchievements = make(map[string]bool)
+	for i := range data.Achievements {
+		if err := data.Achievements[i].ValidateBasic(); err != nil {
+			return err
+		}
+		if seenAchievements[data.Achievements[i].ID] {
+			return types.ErrInvalidAchievement("duplicate achievement: %s", data.Achievements[i].ID)
+		}
+		seenAchievements[data.Achievements[i].ID] = true
+	}
+
 	var seenIds = make(map[string]bool)
 	for _, a := range data.Attendees {
 		if seenIds[a.ID] {
 			return fmt.Errorf("duplicate id: %s", a.ID)
 		}
 		seenIds[a.ID] = true
+
+		if a.LegacySponsor && len(a.Role) > 0 && a.Role != types.RoleSponsor && a.Role != types.RoleSpeaker {
+			return fmt.Errorf("attendee %s has the legacy sponsor flag but the role %s", a.ID, a.Role)
+		}
+
+		if !a.Address.Empty() && !a.Address.Equals(util.AttendeeAddress(data.AddressNamespace, a.ID)) {
+			return fmt.Errorf("address of attendee %s is not derived from the address namespace", a.ID)
+		}
 	}
 	return nil
 }
@@ -98,6 +233,35 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, state GenesisState) {
 		panic(err)
 	}
 
+	//set the namespace before the attendees derive their addresses from it
+	k.SetAddressNamespace(ctx, state.AddressNamespace)
+
+	if state.TxQuota != nil {
+		k.SetTxQuota(ctx, *state.TxQuota)
+	}
+
+	if state.TeamRules != nil {
+		k.SetTeamRules(ctx, *state.TeamRules)
+	}
+
+	//set role rules before the attendees that reference them
+	for i := range state.Roles {
+		k.SetRoleRule(ctx, &state.Roles[i])
+	}
+
+	for i := range state.Attendees {
+		a := &state.Attendees[i]
+		//genesis files from before roles flag sponsors and speakers alike as sponsors
+		if a.LegacySponsor && len(a.Role) == 0 {
+			a.Role = types.RoleSponsor
+		}
+		a.LegacySponsor = false
+		if a.Address.Empty() {
+			a.Address = util.AttendeeAddress(state.AddressNamespace, a.ID)
+		}
+	}
+	countScans(state.Attendees, state.Scans)
+
 	for i := range state.Attendees {
 		a := &state.Attendees[i]
 		account := accountKeeper.GetAccount(ctx, a.GetAddress())
@@ -119,10 +283,73 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, state GenesisState) {
 		k.SetScan(ctx, &state.Scans[i])
 	}
 
+	for i := range state.AttestedScans {
+		k.SetAttestedScan(ctx, state.AttestedScans[i].Scanner, state.AttestedScans[i].ScanID)
+	}
+
 	//set prizes
 	for i := range state.Prizes {
 		k.SetPrize(ctx, &state.Prizes[i])
 	}
+
+	//set raffles, their prizes are already reserved in the exported quantities
+	for i := range state.Raffles {
+		k.SetRaffle(ctx, &state.Raffles[i])
+	}
+
+	for i := range state.BoothChallenges {
+		k.SetBoothChallenge(ctx, &state.BoothChallenges[i])
+	}
+	for i := range state.BoothAnswers {
+		k.SetBoothAnswer(ctx, &state.BoothAnswers[i])
+	}
+
+	for i := range state.Checkpoints {
+		k.SetCheckpoint(ctx, &state.Checkpoints[i])
+	}
+	for i := range state.CheckpointScans {
+		k.SetCheckpointScan(ctx, &state.CheckpointScans[i])
+	}
+
+	for i := range state.Achievements {
+		k.SetAchievement(ctx, &state.Achievements[i])
+	}
+}
+
+//countScans recounts the connections and shares of the attendees from the scans, so that genesis files from
+//before they were counted on the attendees evaluate the scan achievements
+func countScans(attendees []types.Attendee, scans []types.Scan) {
+	byAddress := make(map[string]*types.Attendee, len(attendees))
+	for i := range attendees {
+		attendees[i].ScanCounts = nil
+		byAddress[attendees[i].Address.String()] = &attendees[i]
+	}
+	role := func(a *types.Attendee) string {
+		if a == nil {
+			return ""
+		}
+		return a.GetRole()
+	}
+
+	for i := range scans {
+		scan := &scans[i]
+		a1, a2 := byAddress[scan.S1.String()], byAddress[scan.S2.String()]
+		if a1 != nil && len(scan.D1) > 0 {
+			a1.CountShare(role(a2))
+		}
+		if a2 != nil && len(scan.D2) > 0 {
+			a2.CountShare(role(a1))
+		}
+		if !scan.Accepted {
+			continue
+		}
+		if a1 != nil {
+			a1.CountConnection(role(a2))
+		}
+		if a2 != nil {
+			a2.CountConnection(role(a1))
+		}
+	}
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper
@@ -134,7 +361,19 @@ func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
 	attendees := k.GetAllAttendees(ctx)
 	scans := k.GetAllScans(ctx)
 	prizes, _ := k.GetPrizes(ctx)
-	return NewGenesisState(service, bonusService, claimService, attendees, scans, prizes)
+	roles := k.GetAllRoleRules(ctx)
+	namespace := k.GetAddressNamespace(ctx)
+	txQuota := k.GetTxQuota(ctx)
+	attestedScans := k.GetAllAttestedScans(ctx)
+	raffles := k.GetAllRaffles(ctx)
+	challenges := k.GetAllBoothChallenges(ctx)
+	answers := k.GetAllBoothAnswers(ctx)
+	checkpoints := k.GetAllCheckpoints(ctx)
+	checkpointScans := k.GetAllCheckpointScans(ctx)
+	achievements := k.GetAllAchievements(ctx)
+	teamRules := k.GetTeamRules(ctx)
+	return NewGenesisState(service, bonusService, claimService, attendees, scans, prizes, roles, namespace,
+		&txQuota, attestedScans, raffles, challenges, answers, checkpoints, checkpointScans, achievements, &teamRules)
 }
 
 //nolint:gocritic
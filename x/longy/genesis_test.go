@@ -87,6 +87,45 @@ var _ = Describe("Genesis Tests", func() {
 			err := longy.ValidateGenesis(state)
 			Expect(err).To(BeNil())
 		})
+
+		It("should fail to validate attendees whose address is not derived from the namespace", func() {
+			a := utils.EventbriteAttendee{ID: "1", TicketClassName: "regular"}
+			state := longy.GenesisState{
+				KeyService: longy.GenesisService{
+					Address: util.IDToAddress("asdf"),
+					PubKey:  secp.GenPrivKeySecp256k1([]byte("service")).PubKey(),
+				},
+				BonusService: longy.GenesisService{
+					Address: util.IDToAddress("foo"),
+					PubKey:  secp.GenPrivKeySecp256k1([]byte("bonus")).PubKey(),
+				},
+				ClaimService: longy.GenesisService{
+					Address: util.IDToAddress("foasdfao"),
+					PubKey:  secp.GenPrivKeySecp256k1([]byte("claim")).PubKey(),
+				},
+				Attendees:        longy.GenesisAttendees{a.ToGenesisAttendee("")},
+				Prizes:           types.GetGenesisPrizes(),
+				AddressNamespace: "sfbw",
+			}
+			Expect(longy.ValidateGenesis(state)).ToNot(BeNil())
+
+			state.Attendees = longy.GenesisAttendees{a.ToGenesisAttendee("sfbw")}
+			Expect(longy.ValidateGenesis(state)).To(BeNil())
+		})
+
+		It("should fail to validate the legacy sponsor flag on another role", func() {
+			state := longy.GenesisState{
+				KeyService:   longy.GenesisService{Address: util.IDToAddress("asdf")},
+				BonusService: longy.GenesisService{Address: util.IDToAddress("foo")},
+				ClaimService: longy.GenesisService{Address: util.IDToAddress("foasdfao")},
+				Attendees:    longy.GenesisAttendees{{ID: "1", LegacySponsor: true, Role: types.RoleStaff}},
+				Prizes:       types.GetGenesisPrizes(),
+			}
+			Expect(longy.ValidateGenesis(state)).ToNot(BeNil())
+
+			state.Attendees[0].Role = ""
+			Expect(longy.ValidateGenesis(state)).To(BeNil())
+		})
 	})
 
 	Context("ExportGenesis", func() {
@@ -245,7 +284,7 @@ var _ = Describe("Genesis Tests", func() {
 				TicketClassName: "regular",
 				Profile:         utils.EventbriteProfile{},
 			}
-			ba := b.ToGenesisAttendee()
+			ba := b.ToGenesisAttendee("")
 			ba.Winnings = []types.Win{{
 				Tier:    1,
 				Claimed: true,
@@ -255,7 +294,7 @@ var _ = Describe("Genesis Tests", func() {
 				BonusService: bonusService,
 				ClaimService: claimService,
 				Attendees: longy.GenesisAttendees{
-					a.ToGenesisAttendee(),
+					a.ToGenesisAttendee(""),
 					ba,
 				},
 				Scans:  nil,
@@ -272,6 +311,88 @@ var _ = Describe("Genesis Tests", func() {
 			Expect(attendees[1].Winnings[0].Claimed).To(BeTrue())
 		})
 
+		It("should map the legacy sponsor flag onto the sponsor role", func() {
+			state := longy.GenesisState{
+				KeyService:   service,
+				BonusService: bonusService,
+				ClaimService: claimService,
+				Attendees: longy.GenesisAttendees{
+					{ID: "1", LegacySponsor: true},
+					{ID: "2", LegacySponsor: true, Role: types.RoleSpeaker},
+					{ID: "3"},
+				},
+			}
+
+			longy.InitGenesis(ctx, keeper, state)
+
+			roles := make(map[string]string)
+			for _, a := range keeper.GetAllAttendees(ctx) {
+				Expect(a.LegacySponsor).To(BeFalse())
+				roles[a.ID] = a.GetRole()
+			}
+			Expect(roles).To(Equal(map[string]string{"1": types.RoleSponsor, "2": types.RoleSpeaker,
+				"3": types.RoleAttendee}))
+		})
+
+		It("should derive the attendee addresses from the namespace", func() {
+			state := longy.GenesisState{
+				KeyService:       service,
+				BonusService:     bonusService,
+				ClaimService:     claimService,
+				Attendees:        longy.GenesisAttendees{{ID: "1"}},
+				AddressNamespace: "sfbw",
+			}
+
+			longy.InitGenesis(ctx, keeper, state)
+
+			Expect(keeper.GetAddressNamespace(ctx)).To(Equal("sfbw"))
+			attendee, ok := keeper.GetAttendeeWithID(ctx, "1")
+			Expect(ok).To(BeTrue())
+			Expect(attendee.Address).To(Equal(util.AttendeeAddress("sfbw", "1")))
+			Expect(keeper.AccountKeeper().GetAccount(ctx, attendee.Address)).ToNot(BeNil())
+
+			Expect(longy.ExportGenesis(ctx, keeper).AddressNamespace).To(Equal("sfbw"))
+		})
+
+		It("should init and export the attested scans", func() {
+			scanner := util.IDToAddress("1")
+			scanID := []byte("scan")
+			state := longy.GenesisState{
+				KeyService:    service,
+				BonusService:  bonusService,
+				ClaimService:  claimService,
+				AttestedScans: longy.GenesisAttestedScans{{Scanner: scanner, ScanID: scanID}},
+			}
+			longy.InitGenesis(ctx, keeper, state)
+
+			Expect(keeper.HasAttestedScan(ctx, scanner, scanID)).To(BeTrue())
+			Expect(longy.ExportGenesis(ctx, keeper).AttestedScans).To(Equal(state.AttestedScans))
+		})
+
+		It("should count the connections and shares of the attendees from the scans", func() {
+			sponsor := types.NewAttendee("2", types.RoleSponsor)
+			scan, err := types.NewScan(util.IDToAddress("1"), sponsor.Address, []byte{1}, nil, 1, 1)
+			Expect(err).To(BeNil())
+			scan.Accepted = true
+			state := longy.GenesisState{
+				KeyService:   service,
+				BonusService: bonusService,
+				ClaimService: claimService,
+				Attendees:    longy.GenesisAttendees{types.NewAttendee("1", types.RoleAttendee), sponsor},
+				Scans:        longy.GenesisScans{*scan},
+			}
+
+			longy.InitGenesis(ctx, keeper, state)
+
+			attendee, _ := keeper.GetAttendeeWithID(ctx, "1")
+			Expect(attendee.GetScanCount("")).To(Equal(types.ScanCount{Connections: 1, Shares: 1}))
+			Expect(attendee.GetScanCount(types.RoleSponsor)).To(Equal(types.ScanCount{Role: types.RoleSponsor,
+				Connections: 1, Shares: 1}))
+			stored, _ := keeper.GetAttendeeWithID(ctx, "2")
+			Expect(stored.GetScanCount(types.RoleAttendee)).To(Equal(types.ScanCount{Role: types.RoleAttendee,
+				Connections: 1}))
+		})
+
 		It("should init the scans", func() {
 			d1 := []byte{1}
 			d2 := []byte{2}
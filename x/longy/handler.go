@@ -15,6 +15,8 @@ func NewHandler(keeper Keeper) sdk.Handler {
 		switch msg := msg.(type) {
 		case types.MsgScanQr:
 			return handler.HandleMsgQrScan(ctx, keeper, msg)
+		case types.MsgScanAttestations:
+			return handler.HandleMsgScanAttestations(ctx, keeper, msg)
 		case types.MsgInfo:
 			return handler.HandleMsgInfo(ctx, keeper, msg)
 		case types.MsgRedeem:
@@ -27,6 +29,20 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleBonus(ctx, keeper, msg)
 		case types.MsgClearBonus:
 			return handleClearBonus(ctx, keeper, msg)
+		case types.MsgCreateRaffle:
+			return handler.HandleMsgCreateRaffle(ctx, keeper, msg)
+		case types.MsgRevealRaffle:
+			return handler.HandleMsgRevealRaffle(ctx, keeper, msg)
+		case types.MsgCreateChallenge:
+			return handler.HandleMsgCreateChallenge(ctx, keeper, msg)
+		case types.MsgCommitAnswer:
+			return handler.HandleMsgCommitAnswer(ctx, keeper, msg)
+		case types.MsgAnswerChallenge:
+			return handler.HandleMsgAnswerChallenge(ctx, keeper, msg)
+		case types.MsgCreateCheckpoint:
+			return handler.HandleMsgCreateCheckpoint(ctx, keeper, msg)
+		case types.MsgScanCheckpoint:
+			return handler.HandleMsgScanCheckpoint(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("unrecognized %s msg type: %T", RouterKey, msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -91,12 +107,6 @@ func handleMsgClaimKey(ctx sdk.Context, k Keeper, msg types.MsgClaimKey) sdk.Res
 		return types.ErrInvalidCommitmentReveal("incorrect commitment").Result()
 	}
 
-	// award rep for the onboarding flow
-	err := k.AddRep(ctx, &attendee, types.ClaimBadgeAwardPoints)
-	if err != nil {
-		return err.Result()
-	}
-
 	// add the rsa public key
 	attendee.Name = msg.Name
 	//Set the time TODO check that this is indeed deterministic time on block header
@@ -106,7 +116,13 @@ func handleMsgClaimKey(ctx sdk.Context, k Keeper, msg types.MsgClaimKey) sdk.Res
 
 	// mark the attendee as claimed
 	attendee.SetClaimed()
-	k.SetAttendee(ctx, &attendee)
+
+	// award rep for the onboarding flow once claimed, so that it counts toward the claim achievements.
+	// stores the attendee
+	err := k.AddRep(ctx, &attendee, types.ClaimBadgeAwardPoints)
+	if err != nil {
+		return err.Result()
+	}
 
 	return sdk.Result{}
 }
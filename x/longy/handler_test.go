@@ -44,7 +44,7 @@ var _ = Describe("Longy Handler Tests", func() {
 				Address: util.IDToAddress("asfd"),
 			},
 			Attendees: []types.Attendee{
-				et.ToGenesisAttendee(),
+				et.ToGenesisAttendee(""),
 			},
 		}
 		r := keeper.AccountKeeper().NewAccountWithAddress(ctx, redeemer)
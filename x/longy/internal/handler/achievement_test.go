@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Achievement Handler Tests", func() {
+	var attendee types.Attendee
+	var data = []byte{1, 2, 3}
+
+	connect := func(sponsorID string) {
+		sponsor := utils.AddAttendeeToKeeper(ctx, &keeper, sponsorID, true, true)
+		Expect(handler(ctx, types.NewMsgQrScan(attendee.Address, sponsorID, data)).IsOK()).To(BeTrue())
+		Expect(handler(ctx, types.NewMsgQrScan(sponsor.Address, attendee.ID, nil)).IsOK()).To(BeTrue())
+	}
+
+	badges := func() []string {
+		stored, ok := keeper.GetAttendee(ctx, attendee.Address)
+		Expect(ok).To(BeTrue())
+		var ids []string
+		for _, b := range stored.Badges {
+			ids = append(ids, b.ID)
+		}
+		return ids
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		prizes := types.GetGenesisPrizes()
+		for i := range prizes {
+			keeper.SetPrize(ctx, &prizes[i])
+		}
+
+		achievements := []types.Achievement{
+			{ID: "sponsors-2", Name: "Sponsor Friend", Metric: types.AchievementConnections, Role: types.RoleSponsor,
+				Threshold: 2},
+			{ID: "share-1", Name: "Open Book", Metric: types.AchievementShares, Threshold: 1, Rep: 5},
+		}
+		for i := range achievements {
+			keeper.SetAchievement(ctx, &achievements[i])
+		}
+
+		attendee = utils.AddAttendeeToKeeper(ctx, &keeper, qr1, true, false)
+	})
+
+	It("should grant the badges of the connections and shares with sponsors", func() {
+		connect("5678")
+		Expect(badges()).To(Equal([]string{"share-1"}))
+
+		connect("9012")
+		Expect(badges()).To(ConsistOf("share-1", "sponsors-2"))
+
+		stored, _ := keeper.GetAttendee(ctx, attendee.Address)
+		Expect(stored.GetScanCount(types.RoleSponsor)).To(Equal(types.ScanCount{Role: types.RoleSponsor,
+			Connections: 2, Shares: 2}))
+	})
+
+	It("should not count the connections that are not accepted", func() {
+		utils.AddAttendeeToKeeper(ctx, &keeper, "5678", true, true)
+		Expect(handler(ctx, types.NewMsgQrScan(attendee.Address, "5678", nil)).IsOK()).To(BeTrue())
+
+		stored, _ := keeper.GetAttendee(ctx, attendee.Address)
+		progress := keeper.GetAchievementProgress(ctx, &stored)
+		Expect(progress[1].ID).To(Equal("sponsors-2"))
+		Expect(progress[1].Progress).To(Equal(uint(0)))
+	})
+})
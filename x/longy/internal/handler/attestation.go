@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// HandleMsgScanAttestations processes MsgScanAttestations message. Every attestation is recorded like
+// a MsgScanQr sent by its scanner, along with the attested time. The message fails as a whole with the
+// index of the first failing attestation, so the phone can drop it and sync the others again
+//nolint:gocritic
+func HandleMsgScanAttestations(ctx sdk.Context, k keeper.Keeper, msg types.MsgScanAttestations) sdk.Result {
+	for i, attestation := range msg.Attestations {
+		err := handleAttestation(ctx, k, attestation)
+		if err != nil {
+			return types.AttestationError(i, err).Result()
+		}
+	}
+
+	return sdk.Result{}
+}
+
+//nolint:gocritic
+func handleAttestation(ctx sdk.Context, k keeper.Keeper, attestation types.ScanAttestation) sdk.Error {
+	account := k.AccountKeeper().GetAccount(ctx, attestation.Scanner)
+	_, ok := k.GetAttendee(ctx, attestation.Scanner)
+	if account == nil || !ok {
+		return types.ErrAttendeeNotFound("cannot find the scanner")
+	}
+
+	//the scanner signs with the key of their account, which is set once their badge is keyed
+	pubKey := account.GetPubKey()
+	if pubKey == nil {
+		return types.ErrInvalidPublicKey("scanner %s has no public key", attestation.Scanner)
+	}
+	if !pubKey.VerifyBytes(attestation.SignBytes(ctx.ChainID()), attestation.Signature) {
+		return types.ErrInvalidSignature("attestation is not signed by the scanner %s", attestation.Scanner)
+	}
+
+	err := attestation.CheckWindow(ctx.BlockTime())
+	if err != nil {
+		return err
+	}
+
+	//a scanner attests a scan once, so that a replayed attestation is rejected without keeping its nonce
+	scan, err := handleScan(ctx, k, attestation.Scanner, attestation.ScannedQR, attestation.Data)
+	if err != nil {
+		return err
+	}
+	if k.HasAttestedScan(ctx, attestation.Scanner, scan.ID) {
+		return types.ErrAttestationReplayed("%s already attested the scan of %s", attestation.Scanner,
+			attestation.ScannedQR)
+	}
+	k.SetAttestedScan(ctx, attestation.Scanner, scan.ID)
+
+	if scan.SetAttestedTime(attestation.UnixTimeSec) {
+		k.SetScan(ctx, scan)
+	}
+	return nil
+}
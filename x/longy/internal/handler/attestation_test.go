@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Scan Attestation Handler Tests", func() {
+	var scanner, scanned types.Attendee
+	var key secp256k1.PrivKeySecp256k1
+	var now time.Time
+
+	sign := func(attestation types.ScanAttestation) types.ScanAttestation {
+		sig, err := key.Sign(attestation.SignBytes(ctx.ChainID()))
+		Expect(err).To(BeNil())
+		attestation.Signature = sig
+		return attestation
+	}
+
+	attest := func(qrCode string, t time.Time, nonce string) types.ScanAttestation {
+		return sign(types.NewScanAttestation(scanner.Address, qrCode, []byte("data"), t, nonce))
+	}
+
+	getScan := func() *types.Scan {
+		id, err := types.GenScanID(scanner.Address, scanned.Address)
+		Expect(err).To(BeNil())
+		scan, err := keeper.GetScanByID(ctx, id)
+		Expect(err).To(BeNil())
+		return scan
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		now = time.Unix(1570000000, 0)
+		ctx = ctx.WithBlockTime(now)
+
+		scanner = utils.AddAttendeeToKeeper(ctx, &keeper, qr1, true, false)
+		scanned = utils.AddAttendeeToKeeper(ctx, &keeper, "5678", true, false)
+
+		key = secp256k1.GenPrivKey()
+		account := keeper.AccountKeeper().GetAccount(ctx, scanner.Address)
+		Expect(account.SetPubKey(key.PubKey())).To(Succeed())
+		keeper.AccountKeeper().SetAccount(ctx, account)
+	})
+
+	It("should record the attested scans with their attested time", func() {
+		attestedAt := now.Add(-time.Hour)
+		msg := types.NewMsgScanAttestations(scanner.Address,
+			[]types.ScanAttestation{attest(scanned.ID, attestedAt, "1")})
+		result := handler(ctx, msg)
+		Expect(result.Code).To(Equal(sdk.CodeOK))
+
+		scan := getScan()
+		Expect(scan.S1.Equals(scanner.Address)).To(BeTrue())
+		Expect(scan.D1).To(Equal([]byte("data")))
+		Expect(scan.UnixTimeSec).To(Equal(now.Unix()))
+		Expect(scan.AttestedTimeSec).To(Equal(attestedAt.Unix()))
+	})
+
+	It("should accept attestations relayed by another account", func() {
+		msg := types.NewMsgScanAttestations(scanned.Address,
+			[]types.ScanAttestation{attest(scanned.ID, now, "1")})
+		Expect(handler(ctx, msg).Code).To(Equal(sdk.CodeOK))
+	})
+
+	It("should fail when the attestation is not signed by the scanner", func() {
+		attestation := attest(scanned.ID, now, "1")
+		attestation.Data = []byte("tampered")
+		msg := types.NewMsgScanAttestations(scanner.Address, []types.ScanAttestation{attestation})
+		Expect(handler(ctx, msg).Code).To(Equal(types.InvalidSignature))
+	})
+
+	It("should fail when the scanner has no public key", func() {
+		attestation := attest(scanner.ID, now, "1")
+		attestation.Scanner = scanned.Address
+		msg := types.NewMsgScanAttestations(scanner.Address, []types.ScanAttestation{attestation})
+		Expect(handler(ctx, msg).Code).To(Equal(types.InvalidPublicKey))
+	})
+
+	It("should fail when the attested time is out of the window", func() {
+		msg := types.NewMsgScanAttestations(scanner.Address, []types.ScanAttestation{
+			attest(scanned.ID, now.Add(-types.MaxAttestationAge-time.Second), "1"),
+		})
+		Expect(handler(ctx, msg).Code).To(Equal(types.AttestationOutOfWindow))
+
+		msg = types.NewMsgScanAttestations(scanner.Address, []types.ScanAttestation{
+			attest(scanned.ID, now.Add(types.MaxAttestationSkew+time.Second), "2"),
+		})
+		Expect(handler(ctx, msg).Code).To(Equal(types.AttestationOutOfWindow))
+	})
+
+	It("should fail when an attestation is replayed", func() {
+		msg := types.NewMsgScanAttestations(scanner.Address,
+			[]types.ScanAttestation{attest(scanned.ID, now, "1")})
+		Expect(handler(ctx, msg).Code).To(Equal(sdk.CodeOK))
+
+		result := handler(ctx, msg)
+		Expect(result.Code).To(Equal(types.AttestationReplayed))
+		Expect(result.Log).To(ContainSubstring("attestation 0: "))
+
+		// the scan was already attested by the scanner, whatever the nonce
+		msg = types.NewMsgScanAttestations(scanner.Address,
+			[]types.ScanAttestation{attest(scanned.ID, now.Add(-time.Hour), "2")})
+		Expect(handler(ctx, msg).Code).To(Equal(types.AttestationReplayed))
+		Expect(getScan().AttestedTimeSec).To(Equal(now.Unix()))
+	})
+
+	It("should keep the earliest time attested by both parties", func() {
+		msg := types.NewMsgScanAttestations(scanner.Address,
+			[]types.ScanAttestation{attest(scanned.ID, now.Add(-time.Minute), "1")})
+		Expect(handler(ctx, msg).Code).To(Equal(sdk.CodeOK))
+
+		scannedKey := secp256k1.GenPrivKey()
+		account := keeper.AccountKeeper().GetAccount(ctx, scanned.Address)
+		Expect(account.SetPubKey(scannedKey.PubKey())).To(Succeed())
+		keeper.AccountKeeper().SetAccount(ctx, account)
+
+		attestation := types.NewScanAttestation(scanned.Address, scanner.ID, nil, now.Add(-time.Hour), "1")
+		attestation.Signature, _ = scannedKey.Sign(attestation.SignBytes(ctx.ChainID()))
+		msg = types.NewMsgScanAttestations(scanned.Address, []types.ScanAttestation{attestation})
+		Expect(handler(ctx, msg).Code).To(Equal(sdk.CodeOK))
+
+		scan := getScan()
+		Expect(scan.Accepted).To(BeTrue())
+		Expect(scan.AttestedTimeSec).To(Equal(now.Add(-time.Hour).Unix()))
+	})
+})
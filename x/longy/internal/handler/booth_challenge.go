@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// HandleMsgCreateChallenge processes MsgCreateChallenge message
+//nolint:gocritic
+func HandleMsgCreateChallenge(ctx sdk.Context, k keeper.Keeper, msg types.MsgCreateChallenge) sdk.Result {
+	err := k.CreateBoothChallenge(ctx, msg.Challenge())
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+// HandleMsgCommitAnswer processes MsgCommitAnswer message
+//nolint:gocritic
+func HandleMsgCommitAnswer(ctx sdk.Context, k keeper.Keeper, msg types.MsgCommitAnswer) sdk.Result {
+	err := k.CommitBoothAnswer(ctx, msg.Sender, msg.ID, msg.Commitment)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+// HandleMsgAnswerChallenge processes MsgAnswerChallenge message
+//nolint:gocritic
+func HandleMsgAnswerChallenge(ctx sdk.Context, k keeper.Keeper, msg types.MsgAnswerChallenge) sdk.Result {
+	err := k.AnswerBoothChallenge(ctx, msg.Sender, msg.ID, msg.Answer)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
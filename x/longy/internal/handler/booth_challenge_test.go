@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Booth Challenge Handler Tests", func() {
+	const id = "eco-1"
+	var sponsor, attendee types.Attendee
+	var now, expiry time.Time
+
+	createChallenge := func(creator types.Attendee, points uint) sdk.Result {
+		return handler(ctx, types.NewMsgCreateChallenge(creator.Address, id, "What does Eco stand for?",
+			types.NewBoothChallengeCommitment(id, "economy"), points, expiry))
+	}
+
+	commit := func(a types.Attendee, answer string) sdk.Result {
+		return handler(ctx, types.NewMsgCommitAnswer(a.Address, id,
+			types.NewBoothAnswerCommitment(id, answer, a.Address)))
+	}
+
+	answer := func(a types.Attendee, answer string) sdk.Result {
+		return handler(ctx, types.NewMsgAnswerChallenge(a.Address, id, answer))
+	}
+
+	expire := func() {
+		ctx = ctx.WithBlockTime(expiry.Add(time.Second))
+	}
+
+	rep := func(a types.Attendee) uint {
+		stored, ok := keeper.GetAttendee(ctx, a.Address)
+		Expect(ok).To(BeTrue())
+		return stored.Rep
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		now = time.Unix(1570000000, 0)
+		expiry = now.Add(time.Hour)
+		ctx = ctx.WithBlockTime(now)
+
+		prizes := types.GetGenesisPrizes()
+		for i := range prizes {
+			keeper.SetPrize(ctx, &prizes[i])
+		}
+
+		sponsor = utils.AddAttendeeToKeeper(ctx, &keeper, qr1, true, true)
+		attendee = utils.AddAttendeeToKeeper(ctx, &keeper, "5678", true, false)
+	})
+
+	It("should only let claimed sponsors create challenges", func() {
+		Expect(createChallenge(attendee, 5).Code).To(Equal(types.InsufficientPrivileges))
+
+		unclaimed := utils.AddAttendeeToKeeper(ctx, &keeper, "9999", false, true)
+		Expect(createChallenge(unclaimed, 5).Code).To(Equal(types.AttendeeClaimed))
+
+		Expect(createChallenge(sponsor, 5).Code).To(Equal(sdk.CodeOK))
+		challenge, ok := keeper.GetBoothChallenge(ctx, id)
+		Expect(ok).To(BeTrue())
+		Expect(challenge.Sponsor).To(Equal(sponsor.Address))
+
+		Expect(createChallenge(sponsor, 5).Code).To(Equal(types.InvalidBoothChallenge))
+	})
+
+	It("should fail to create a challenge that already expired", func() {
+		expiry = now
+		Expect(createChallenge(sponsor, 5).Code).To(Equal(types.InvalidBoothChallenge))
+	})
+
+	It("should award the points of a committed answer once after the expiry", func() {
+		Expect(createChallenge(sponsor, 5).Code).To(Equal(sdk.CodeOK))
+		Expect(commit(attendee, " Economy").Code).To(Equal(sdk.CodeOK))
+		Expect(answer(attendee, "economy").Code).To(Equal(types.BoothChallengeOpen))
+
+		expire()
+		Expect(answer(attendee, "economy").Code).To(Equal(sdk.CodeOK))
+		Expect(rep(attendee)).To(Equal(uint(5)))
+		_, ok := keeper.GetBoothAnswer(ctx, id, attendee.Address)
+		Expect(ok).To(BeFalse())
+
+		Expect(answer(attendee, "economy").Code).To(Equal(types.BoothChallengeAnswered))
+		Expect(rep(attendee)).To(Equal(uint(5)))
+
+		challenge, _ := keeper.GetBoothChallenge(ctx, id)
+		Expect(challenge.AnswerCount).To(Equal(uint(1)))
+	})
+
+	It("should let attendees replace their answer until the expiry", func() {
+		Expect(createChallenge(sponsor, 5).Code).To(Equal(sdk.CodeOK))
+		Expect(commit(attendee, "ecology").Code).To(Equal(sdk.CodeOK))
+		Expect(commit(attendee, "economy").Code).To(Equal(sdk.CodeOK))
+
+		expire()
+		Expect(commit(attendee, "economy").Code).To(Equal(types.BoothChallengeExpired))
+		Expect(answer(attendee, "ecology").Code).To(Equal(types.InvalidCommitmentReveal))
+		Expect(answer(attendee, "economy").Code).To(Equal(sdk.CodeOK))
+	})
+
+	It("should not award wrong or uncommitted answers", func() {
+		Expect(commit(attendee, "economy").Code).To(Equal(types.BoothChallengeNotFound))
+
+		Expect(createChallenge(sponsor, 5).Code).To(Equal(sdk.CodeOK))
+		other := utils.AddAttendeeToKeeper(ctx, &keeper, "9999", true, false)
+		Expect(commit(attendee, "ecology").Code).To(Equal(sdk.CodeOK))
+		Expect(commit(sponsor, "economy").Code).To(Equal(types.InvalidBoothChallenge))
+
+		expire()
+		Expect(answer(attendee, "ecology").Code).To(Equal(types.InvalidCommitmentReveal))
+		Expect(answer(other, "economy").Code).To(Equal(types.BoothAnswerNotCommitted))
+		Expect(rep(attendee)).To(Equal(uint(0)))
+		Expect(rep(other)).To(Equal(uint(0)))
+	})
+
+	It("should refuse unclaimed attendees", func() {
+		Expect(createChallenge(sponsor, 5).Code).To(Equal(sdk.CodeOK))
+		unclaimed := utils.AddAttendeeToKeeper(ctx, &keeper, "9999", false, false)
+		Expect(commit(unclaimed, "economy").Code).To(Equal(types.AttendeeClaimed))
+	})
+})
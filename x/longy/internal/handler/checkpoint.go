@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// HandleMsgCreateCheckpoint processes MsgCreateCheckpoint message
+//nolint:gocritic
+func HandleMsgCreateCheckpoint(ctx sdk.Context, k keeper.Keeper, msg types.MsgCreateCheckpoint) sdk.Result {
+	if !k.IsServiceAccount(ctx, msg.Sender) {
+		return types.ErrInsufficientPrivileges("only the master service account can create checkpoints").Result()
+	}
+
+	err := k.CreateCheckpoint(ctx, msg.Checkpoint())
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+// HandleMsgScanCheckpoint processes MsgScanCheckpoint message
+//nolint:gocritic
+func HandleMsgScanCheckpoint(ctx sdk.Context, k keeper.Keeper, msg types.MsgScanCheckpoint) sdk.Result {
+	err := k.ScanCheckpoint(ctx, msg.Sender, msg.ID, msg.Signature)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
@@ -0,0 +1,137 @@
+package handler_test
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Checkpoint Handler Tests", func() {
+	const id = "keynote"
+	var service sdk.AccAddress
+	var attendee types.Attendee
+	var key secp256k1.PrivKeySecp256k1
+	var now time.Time
+
+	createCheckpoint := func(sender sdk.AccAddress, maxClaims uint) sdk.Result {
+		return handler(ctx, types.NewMsgCreateCheckpoint(sender, id, "Opening Keynote", types.CheckpointSession,
+			key.PubKey(), 10, now, now.Add(time.Hour), maxClaims))
+	}
+
+	sign := func(key secp256k1.PrivKeySecp256k1, a types.Attendee) []byte {
+		sig, err := key.Sign(types.CheckpointSignBytes(ctx.ChainID(), id, a.Address))
+		Expect(err).To(BeNil())
+		return sig
+	}
+
+	scanSigned := func(a types.Attendee, sig []byte) sdk.Result {
+		return handler(ctx, types.NewMsgScanCheckpoint(a.Address, id, sig))
+	}
+
+	scan := func(a types.Attendee) sdk.Result {
+		return scanSigned(a, sign(key, a))
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		now = time.Unix(1570000000, 0)
+		ctx = ctx.WithBlockTime(now)
+		key = secp256k1.GenPrivKey()
+
+		prizes := types.GetGenesisPrizes()
+		for i := range prizes {
+			keeper.SetPrize(ctx, &prizes[i])
+		}
+
+		service = util.IDToAddress("service")
+		utils.SetServiceAccount(ctx, keeper, service)
+		attendee = utils.AddAttendeeToKeeper(ctx, &keeper, qr1, true, false)
+	})
+
+	It("should only let the master service create checkpoints", func() {
+		Expect(createCheckpoint(attendee.Address, 0).Code).To(Equal(types.InsufficientPrivileges))
+
+		Expect(createCheckpoint(service, 0).Code).To(Equal(sdk.CodeOK))
+		_, ok := keeper.GetCheckpoint(ctx, id)
+		Expect(ok).To(BeTrue())
+
+		Expect(createCheckpoint(service, 0).Code).To(Equal(types.InvalidCheckpoint))
+	})
+
+	It("should award the points of a checkpoint once and record the attendance", func() {
+		Expect(createCheckpoint(service, 0).Code).To(Equal(sdk.CodeOK))
+
+		Expect(scan(attendee).Code).To(Equal(sdk.CodeOK))
+		Expect(scan(attendee).Code).To(Equal(types.CheckpointScanned))
+
+		stored, _ := keeper.GetAttendee(ctx, attendee.Address)
+		Expect(stored.Rep).To(Equal(uint(10)))
+
+		scans := keeper.GetCheckpointScans(ctx, id)
+		Expect(scans).To(Equal([]types.CheckpointScan{{CheckpointID: id, Attendee: attendee.Address,
+			UnixTimeSec: now.Unix()}}))
+		checkpoint, _ := keeper.GetCheckpoint(ctx, id)
+		Expect(checkpoint.ClaimCount).To(Equal(uint(1)))
+	})
+
+	It("should not award unsigned scans or scans outside the time window", func() {
+		Expect(scan(attendee).Code).To(Equal(types.CheckpointNotFound))
+
+		Expect(createCheckpoint(service, 0).Code).To(Equal(sdk.CodeOK))
+		Expect(scanSigned(attendee, sign(secp256k1.GenPrivKey(), attendee)).Code).To(Equal(types.InvalidSignature))
+
+		// the signature of another attendee's scan cannot be replayed
+		other := utils.AddAttendeeToKeeper(ctx, &keeper, "5678", true, false)
+		Expect(scanSigned(attendee, sign(key, other)).Code).To(Equal(types.InvalidSignature))
+
+		ctx = ctx.WithBlockTime(now.Add(time.Hour + time.Second))
+		Expect(scan(attendee).Code).To(Equal(types.CheckpointInactive))
+		Expect(keeper.GetCheckpointScans(ctx, id)).To(BeEmpty())
+	})
+
+	It("should record the time the points of a checkpoint reach a tier", func() {
+		Expect(createCheckpoint(service, 0).Code).To(Equal(sdk.CodeOK))
+		attendee.Rep = types.Tier1Rep - 10
+		keeper.SetAttendee(ctx, &attendee)
+
+		Expect(scan(attendee).Code).To(Equal(sdk.CodeOK))
+		stored, _ := keeper.GetAttendee(ctx, attendee.Address)
+		reached, ok := stored.TierReachedAt(types.Tier1)
+		Expect(ok).To(BeTrue())
+		Expect(reached).To(Equal(now.Unix()))
+		_, ok = stored.TierReachedAt(types.Tier2)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should grant a checkpoint achievement on the scan that reaches its threshold", func() {
+		keeper.SetAchievement(ctx, &types.Achievement{ID: "checkpoints-1", Name: "Explorer",
+			Metric: types.AchievementCheckpoints, Threshold: 1, Rep: 5})
+		Expect(createCheckpoint(service, 0).Code).To(Equal(sdk.CodeOK))
+
+		Expect(scan(attendee).Code).To(Equal(sdk.CodeOK))
+		stored, _ := keeper.GetAttendee(ctx, attendee.Address)
+		_, earned := stored.GetBadge("checkpoints-1")
+		Expect(earned).To(BeTrue())
+		Expect(stored.Rep).To(Equal(attendee.Rep + 10 + 5))
+	})
+
+	It("should stop awarding points at the max claims", func() {
+		Expect(createCheckpoint(service, 1).Code).To(Equal(sdk.CodeOK))
+		other := utils.AddAttendeeToKeeper(ctx, &keeper, "5678", true, false)
+
+		Expect(scan(attendee).Code).To(Equal(sdk.CodeOK))
+		Expect(scan(other).Code).To(Equal(types.CheckpointFull))
+	})
+
+	It("should refuse unclaimed attendees", func() {
+		Expect(createCheckpoint(service, 0).Code).To(Equal(sdk.CodeOK))
+		unclaimed := utils.AddAttendeeToKeeper(ctx, &keeper, "5678", false, false)
+		Expect(scan(unclaimed).Code).To(Equal(types.AttendeeClaimed))
+	})
+})
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// HandleMsgCreateRaffle processes MsgCreateRaffle message
+//nolint:gocritic
+func HandleMsgCreateRaffle(ctx sdk.Context, k keeper.Keeper, msg types.MsgCreateRaffle) sdk.Result {
+	if !k.IsBonusServiceAccount(ctx, msg.Sender) {
+		return types.ErrInsufficientPrivileges("only the bonus service account can create raffles").Result()
+	}
+
+	err := k.CreateRaffle(ctx, msg.Raffle())
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+// HandleMsgRevealRaffle processes MsgRevealRaffle message
+//nolint:gocritic
+func HandleMsgRevealRaffle(ctx sdk.Context, k keeper.Keeper, msg types.MsgRevealRaffle) sdk.Result {
+	if !k.IsBonusServiceAccount(ctx, msg.Sender) {
+		return types.ErrInsufficientPrivileges("only the bonus service account can reveal raffles").Result()
+	}
+
+	_, err := k.DrawRaffle(ctx, msg.ID, msg.Secret)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
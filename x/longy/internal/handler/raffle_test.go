@@ -0,0 +1,211 @@
+package handler_test
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Raffle Handler Tests", func() {
+	var bonus sdk.AccAddress
+	var secret string
+	var commitment util.Commitment
+	var now, deadline time.Time
+
+	prize := func(tier uint) types.Prize {
+		p, err := keeper.GetPrize(ctx, types.GetPrizeIDByTier(tier))
+		Expect(err).To(BeNil())
+		return p
+	}
+
+	addEntrant := func(id string, rep uint) types.Attendee {
+		attendee := utils.AddAttendeeToKeeper(ctx, &keeper, id, true, false)
+		attendee.Rep = rep
+		keeper.SetAttendee(ctx, &attendee)
+		return attendee
+	}
+
+	createRaffle := func(winners uint) sdk.Result {
+		return handler(ctx, types.NewMsgCreateRaffle(bonus, "tier-8", types.Tier8, winners, types.Tier3, 0,
+			deadline, deadline.Add(types.DefaultRaffleRevealWindow), commitment))
+	}
+
+	snapshot := func() {
+		ctx = ctx.WithBlockTime(deadline).WithBlockHeight(ctx.BlockHeight() + 1)
+		keeper.UpdateRaffles(ctx)
+	}
+
+	// seed ends the block following the snapshot, whose header carries the hash of the snapshot block
+	seed := func() {
+		header := ctx.BlockHeader()
+		header.Height++
+		header.LastBlockId.Hash = []byte("snapshot block hash")
+		ctx = ctx.WithBlockHeader(header)
+		keeper.UpdateRaffles(ctx)
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		now = time.Unix(1570000000, 0)
+		deadline = now.Add(time.Hour)
+		header := ctx.BlockHeader()
+		header.Time = now
+		header.LastBlockId.Hash = []byte("last block hash")
+		ctx = ctx.WithBlockHeader(header)
+
+		prizes := types.GetGenesisPrizes()
+		for i := range prizes {
+			keeper.SetPrize(ctx, &prizes[i])
+		}
+
+		bonus = util.IDToAddress("bonus")
+		keeper.AccountKeeper().SetAccount(ctx, keeper.AccountKeeper().NewAccountWithAddress(ctx, bonus))
+		Expect(keeper.SetBonusServiceAddress(ctx, bonus)).To(BeNil())
+
+		secret, commitment = util.CreateCommitment()
+	})
+
+	It("should only let the bonus service create and reveal raffles", func() {
+		msg := types.NewMsgCreateRaffle(util.IDToAddress("1"), "tier-8", types.Tier8, 1, 0, 0, deadline,
+			deadline.Add(time.Hour), commitment)
+		Expect(handler(ctx, msg).Code).To(Equal(types.InsufficientPrivileges))
+
+		reveal := types.NewMsgRevealRaffle(util.IDToAddress("1"), "tier-8", secret)
+		Expect(handler(ctx, reveal).Code).To(Equal(types.InsufficientPrivileges))
+	})
+
+	It("should reserve the prizes of the raffle", func() {
+		Expect(createRaffle(1).Code).To(Equal(sdk.CodeOK))
+		Expect(prize(types.Tier8).Quantity).To(Equal(types.Tier8Quantity - 1))
+
+		raffle, ok := keeper.GetRaffle(ctx, "tier-8")
+		Expect(ok).To(BeTrue())
+		Expect(raffle.Status).To(Equal(types.RaffleOpen))
+
+		Expect(createRaffle(1).Code).To(Equal(types.InvalidRaffle))
+	})
+
+	It("should fail when too few prizes are left or the deadline passed", func() {
+		Expect(createRaffle(types.Tier8Quantity + 1).Code).To(Equal(types.InvalidRaffle))
+
+		deadline = now
+		Expect(createRaffle(1).Code).To(Equal(types.InvalidRaffle))
+		_, ok := keeper.GetRaffle(ctx, "tier-8")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should snapshot the eligible attendees at the deadline", func() {
+		eligible := addEntrant("1", types.Tier3Rep)
+		addEntrant("2", types.Tier2Rep)
+		Expect(createRaffle(1).Code).To(Equal(sdk.CodeOK))
+
+		keeper.UpdateRaffles(ctx)
+		raffle, _ := keeper.GetRaffle(ctx, "tier-8")
+		Expect(raffle.Status).To(Equal(types.RaffleOpen))
+
+		snapshot()
+		raffle, _ = keeper.GetRaffle(ctx, "tier-8")
+		Expect(raffle.Status).To(Equal(types.RaffleSnapshotted))
+		Expect(raffle.SnapshotHeight).To(Equal(ctx.BlockHeight()))
+		Expect(raffle.Entrants).To(Equal([]sdk.AccAddress{eligible.Address}))
+		Expect(raffle.EntrantCount).To(Equal(uint(1)))
+		Expect(raffle.SeedBlockHash).To(BeEmpty())
+
+		seed()
+		raffle, _ = keeper.GetRaffle(ctx, "tier-8")
+		Expect(raffle.SeedHeight).To(Equal(raffle.SnapshotHeight))
+		Expect(raffle.SeedBlockHash).To(Equal([]byte("snapshot block hash")))
+
+		// later blocks do not change the seed
+		header := ctx.BlockHeader()
+		header.Height++
+		header.LastBlockId.Hash = []byte("later block hash")
+		keeper.UpdateRaffles(ctx.WithBlockHeader(header))
+		raffle, _ = keeper.GetRaffle(ctx, "tier-8")
+		Expect(raffle.SeedBlockHash).To(Equal([]byte("snapshot block hash")))
+	})
+
+	It("should not draw before the snapshot or with the wrong secret", func() {
+		addEntrant("1", types.Tier3Rep)
+		Expect(createRaffle(1).Code).To(Equal(sdk.CodeOK))
+
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", secret)).Code).
+			To(Equal(types.RaffleNotDrawable))
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "other", secret)).Code).
+			To(Equal(types.RaffleNotFound))
+
+		snapshot()
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", secret)).Code).
+			To(Equal(types.RaffleNotDrawable))
+
+		seed()
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", "wrong")).Code).
+			To(Equal(types.InvalidCommitmentReveal))
+	})
+
+	It("should award the prize to the drawn winners with a verifiable proof", func() {
+		for _, id := range []string{"1", "2", "3", "4", "5"} {
+			addEntrant(id, types.Tier3Rep)
+		}
+		Expect(createRaffle(2).Code).To(Equal(sdk.CodeOK))
+		snapshot()
+		seed()
+
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", secret)).Code).To(Equal(sdk.CodeOK))
+
+		raffle, _ := keeper.GetRaffle(ctx, "tier-8")
+		Expect(raffle.Status).To(Equal(types.RaffleDrawn))
+		Expect(raffle.Winners).To(HaveLen(2))
+		Expect(raffle.Proof.Secret).To(Equal(secret))
+		Expect(raffle.Proof.Height).To(Equal(raffle.SnapshotHeight))
+		Expect(raffle.Proof.BlockHash).To(Equal([]byte("snapshot block hash")))
+		Expect(raffle.Proof.Seed).To(Equal(types.RaffleSeed(secret, raffle.Proof.BlockHash)))
+		Expect(types.DrawWinners(raffle.Proof.Seed, raffle.Entrants, raffle.WinnerCount)).
+			To(Equal(raffle.Winners))
+
+		for _, addr := range raffle.Winners {
+			attendee, ok := keeper.GetAttendee(ctx, addr)
+			Expect(ok).To(BeTrue())
+			Expect(attendee.Winnings).To(HaveLen(1))
+			Expect(attendee.Winnings[0].Tier).To(Equal(types.Tier8))
+		}
+
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", secret)).Code).
+			To(Equal(types.RaffleNotDrawable))
+	})
+
+	It("should return the prizes without winners to their tier", func() {
+		addEntrant("1", types.Tier3Rep)
+		Expect(createRaffle(3).Code).To(Equal(sdk.CodeOK))
+		Expect(prize(types.Tier8).Quantity).To(Equal(types.Tier8Quantity - 3))
+		snapshot()
+		seed()
+
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", secret)).Code).To(Equal(sdk.CodeOK))
+		Expect(prize(types.Tier8).Quantity).To(Equal(types.Tier8Quantity - 1))
+	})
+
+	It("should return the prizes to their tier when the raffle is not revealed in time", func() {
+		addEntrant("1", types.Tier3Rep)
+		Expect(createRaffle(2).Code).To(Equal(sdk.CodeOK))
+		snapshot()
+		seed()
+
+		ctx = ctx.WithBlockTime(deadline.Add(types.DefaultRaffleRevealWindow))
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", secret)).Code).
+			To(Equal(types.RaffleNotDrawable))
+
+		keeper.UpdateRaffles(ctx)
+		raffle, _ := keeper.GetRaffle(ctx, "tier-8")
+		Expect(raffle.Status).To(Equal(types.RaffleExpired))
+		Expect(prize(types.Tier8).Quantity).To(Equal(types.Tier8Quantity))
+
+		Expect(handler(ctx, types.NewMsgRevealRaffle(bonus, "tier-8", secret)).Code).
+			To(Equal(types.RaffleNotDrawable))
+	})
+})
@@ -10,36 +10,48 @@ import (
 // HandleMsgQrScan processes MsgScanQr message
 //nolint:gocritic
 func HandleMsgQrScan(ctx sdk.Context, k keeper.Keeper, msg types.MsgScanQr) sdk.Result {
+	_, err := handleScan(ctx, k, msg.Sender, msg.ScannedQR, msg.Data)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+//handleScan records the scan of the attendee with `qrCode` by `sender` and returns the scan
+//nolint:gocritic
+func handleScan(ctx sdk.Context, k keeper.Keeper, sender sdk.AccAddress, qrCode string,
+	data []byte) (*types.Scan, sdk.Error) {
 	//get the address for the scanned qr code
-	attendee, ok := k.GetAttendeeWithID(ctx, msg.ScannedQR)
+	attendee, ok := k.GetAttendeeWithID(ctx, qrCode)
 	if !ok {
-		return types.ErrAttendeeNotFound("cannot find the attendee").Result()
+		return nil, types.ErrAttendeeNotFound("cannot find the attendee")
 	}
 
 	if !attendee.Claimed {
-		return types.ErrAttendeeClaimed("attendee badge not claimed").Result()
+		return nil, types.ErrAttendeeClaimed("attendee badge not claimed")
 	}
 	//get the id for the scan event
-	id, err := types.GenScanID(msg.Sender, attendee.Address)
+	id, err := types.GenScanID(sender, attendee.Address)
 	if err != nil {
-		return err.Result()
+		return nil, err
 	}
 
 	//get the scan event
 	scan, err := k.GetScanByID(ctx, id)
 	if err != nil { //if new scan, create it
-		scan, err = handleNewScan(ctx, k, msg, attendee)
+		scan, err = handleNewScan(ctx, k, sender, attendee)
 		if err != nil {
-			return err.Result()
+			return nil, err
 		}
 	}
 
-	err = handleShareInfo(ctx, k, scan, msg.Sender, attendee, msg.Data)
+	err = handleShareInfo(ctx, k, scan, sender, attendee, data)
 	if err != nil {
-		return err.Result()
+		return nil, err
 	}
 
-	return sdk.Result{}
+	return scan, nil
 }
 
 //nolint:gocritic
@@ -67,16 +79,22 @@ func handleShareInfo(ctx sdk.Context, k keeper.Keeper, scan *types.Scan, sender
 	}
 	dataShared := len(*oldData) == 0 && len(data) > 0
 	if dataShared {
+		//set new data into scan and count the share before the award, so that it counts toward the share
+		//achievements
+		*oldData = data
+		k.SetScan(ctx, scan)
+		k.Metrics(ctx).Shares.Add(1)
+		err := k.CountShare(ctx, sender, attendee.Address)
+		if err != nil {
+			return err
+		}
+
 		if scan.Accepted {
-			err := k.AwardShareInfoPoints(ctx, scan, sender, attendee.Address)
+			err = k.AwardShareInfoPoints(ctx, scan, sender, attendee.Address)
 			if err != nil {
 				return err
 			}
 		}
-
-		//set new data into scan and save scan
-		*oldData = data
-		k.SetScan(ctx, scan)
 	}
 	return nil
 }
@@ -88,9 +106,14 @@ func handleAcceptance(ctx sdk.Context, k keeper.Keeper, scan *types.Scan, sender
 	if !scan.Accepted && scan.S2.Equals(sender) {
 		scan.Accepted = true
 		k.SetScan(ctx, scan)
+		k.Metrics(ctx).Accepts.Add(1)
+		err := k.CountConnection(ctx, scan)
+		if err != nil {
+			return err
+		}
 
 		if len(scan.D1) > 0 {
-			err := k.AwardShareInfoPoints(ctx, scan, scan.S1, scan.S2)
+			err = k.AwardShareInfoPoints(ctx, scan, scan.S1, scan.S2)
 			if err != nil {
 				return err
 			}
@@ -103,9 +126,9 @@ func handleAcceptance(ctx sdk.Context, k keeper.Keeper, scan *types.Scan, sender
 }
 
 //nolint:gocritic
-func handleNewScan(ctx sdk.Context, k keeper.Keeper, msg types.MsgScanQr,
+func handleNewScan(ctx sdk.Context, k keeper.Keeper, sender sdk.AccAddress,
 	attendee types.Attendee) (scan *types.Scan, err sdk.Error) {
-	scan, err = types.NewScan(msg.Sender, attendee.Address, nil, nil, 0, 0) //dont pass data here
+	scan, err = types.NewScan(sender, attendee.Address, nil, nil, 0, 0) //dont pass data here
 
 	if err != nil {
 		return
@@ -114,5 +137,6 @@ func handleNewScan(ctx sdk.Context, k keeper.Keeper, msg types.MsgScanQr,
 	//Set the time TODO check that this is indeed deterministic time on block header
 	scan.SetTimeUnixSeconds(ctx.BlockTime().Unix())
 	k.SetScan(ctx, scan)
+	k.Metrics(ctx).Scans.Add(1)
 	return
 }
@@ -0,0 +1,152 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//GetAchievement returns the achievement with `id`
+//nolint:gocritic
+func (k *Keeper) GetAchievement(ctx sdk.Context, id string) (achievement types.Achievement, ok bool) {
+	bz, _ := k.Get(ctx, types.AchievementKey(id))
+	if bz == nil {
+		return
+	}
+
+	k.Cdc.MustUnmarshalBinaryBare(bz, &achievement)
+	return achievement, true
+}
+
+//GetAllAchievements returns all the achievements, ordered by id
+//nolint:gocritic
+func (k *Keeper) GetAllAchievements(ctx sdk.Context) (achievements []types.Achievement) {
+	it := sdk.KVStorePrefixIterator(k.KVStore(ctx), types.Prefix(types.AchievementPrefix))
+	defer it.Close()
+	achievements = make([]types.Achievement, 0)
+	for ; it.Valid(); it.Next() {
+		var achievement types.Achievement
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &achievement)
+		achievements = append(achievements, achievement)
+	}
+
+	return achievements
+}
+
+//SetAchievement puts the achievement into the store with its id as key
+//nolint:gocritic
+func (k *Keeper) SetAchievement(ctx sdk.Context, achievement *types.Achievement) {
+	k.Set(ctx, types.AchievementKey(achievement.ID), k.Cdc.MustMarshalBinaryBare(*achievement))
+}
+
+//GrantAchievements grants the badges of the available achievements the attendee reached. Achievements
+//with rep are evaluated again after granting it, so that it can count toward the rep achievements. The
+//attendee is not stored
+//nolint:gocritic
+func (k *Keeper) GrantAchievements(ctx sdk.Context, attendee *types.Attendee) sdk.Error {
+	if !attendee.IsClaimed() {
+		return nil
+	}
+	achievements := k.GetAllAchievements(ctx)
+	if len(achievements) == 0 {
+		return nil
+	}
+
+	stats := newAchievementStats(k, ctx, attendee)
+	for granted := true; granted; {
+		granted = false
+		for i := range achievements {
+			a := &achievements[i]
+			if _, earned := attendee.GetBadge(a.ID); earned || !a.IsAvailable() || stats.progress(a) < a.Threshold {
+				continue
+			}
+
+			attendee.AddBadge(types.Badge{ID: a.ID, Name: a.Name, UnixTimeSec: ctx.BlockTime().Unix()})
+			a.Granted++
+			k.SetAchievement(ctx, a)
+			k.Metrics(ctx).BadgesEarned.With("achievement", a.ID).Add(1)
+
+			if a.Rep > 0 {
+				if err := k.addRep(ctx, attendee, a.Rep); err != nil {
+					return err
+				}
+				granted = true
+			}
+		}
+	}
+	return nil
+}
+
+//GetAchievementProgress returns the progress of the attendee toward every achievement
+//nolint:gocritic
+func (k *Keeper) GetAchievementProgress(ctx sdk.Context, attendee *types.Attendee) []types.AchievementProgress {
+	achievements := k.GetAllAchievements(ctx)
+	stats := newAchievementStats(k, ctx, attendee)
+
+	progress := make([]types.AchievementProgress, 0, len(achievements))
+	for i := range achievements {
+		a := &achievements[i]
+		badge, earned := attendee.GetBadge(a.ID)
+		progress = append(progress, types.AchievementProgress{
+			ID:          a.ID,
+			Name:        a.Name,
+			Description: a.Description,
+			Progress:    stats.progress(a),
+			Threshold:   a.Threshold,
+			Earned:      earned,
+			UnixTimeSec: badge.UnixTimeSec,
+			Available:   a.IsAvailable(),
+		})
+	}
+	return progress
+}
+
+//achievementStats computes the metrics of an attendee. The connections and shares are counted on the
+//attendee as the scans are accepted and shared, and the checkpoints are only read if an achievement needs them
+type achievementStats struct {
+	k        *Keeper
+	ctx      sdk.Context
+	attendee *types.Attendee
+
+	checkpoints *uint
+}
+
+//nolint:gocritic
+func newAchievementStats(k *Keeper, ctx sdk.Context, attendee *types.Attendee) *achievementStats {
+	return &achievementStats{k: k, ctx: ctx, attendee: attendee}
+}
+
+//progress returns the value of the metric of the achievement
+func (s *achievementStats) progress(a *types.Achievement) uint {
+	switch a.Metric {
+	case types.AchievementConnections:
+		return s.attendee.GetScanCount(a.Role).Connections
+	case types.AchievementShares:
+		return s.attendee.GetScanCount(a.Role).Shares
+	case types.AchievementClaim:
+		if s.attendee.IsClaimed() {
+			return 1
+		}
+		return 0
+	case types.AchievementRep:
+		return s.attendee.Rep
+	case types.AchievementCheckpoints:
+		return s.countCheckpoints()
+	case types.AchievementChallenges:
+		return uint(len(s.attendee.ChallengeIDs))
+	}
+	return 0
+}
+
+//countCheckpoints counts the venue checkpoints scanned by the attendee
+func (s *achievementStats) countCheckpoints() uint {
+	if s.checkpoints == nil {
+		count := uint(0)
+		for _, c := range s.k.GetAllCheckpoints(s.ctx) {
+			if s.k.HasCheckpointScan(s.ctx, c.ID, s.attendee.Address) {
+				count++
+			}
+		}
+		s.checkpoints = &count
+	}
+	return *s.checkpoints
+}
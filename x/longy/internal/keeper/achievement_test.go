@@ -0,0 +1,107 @@
+package keeper_test
+
+import (
+	"time"
+
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Achievement Keeper Tests", func() {
+	var now time.Time
+
+	setAchievements := func(achievements ...types.Achievement) {
+		for i := range achievements {
+			keeper.SetAchievement(ctx, &achievements[i])
+		}
+	}
+
+	addRep := func(a *types.Attendee, points uint) types.Attendee {
+		Expect(keeper.AddRep(ctx, a, points)).To(BeNil())
+		stored, ok := keeper.GetAttendee(ctx, a.Address)
+		Expect(ok).To(BeTrue())
+		return stored
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		now = time.Unix(1570000000, 0)
+		ctx = ctx.WithBlockTime(now)
+
+		prizes := types.GetGenesisPrizes()
+		for i := range prizes {
+			keeper.SetPrize(ctx, &prizes[i])
+		}
+	})
+
+	It("should grant the badge once the threshold is reached", func() {
+		setAchievements(types.Achievement{ID: "rep-20", Name: "Rising Star", Metric: types.AchievementRep,
+			Threshold: 20})
+		attendee := utils.AddAttendeeToKeeper(ctx, &keeper, "1", true, false)
+
+		attendee = addRep(&attendee, 10)
+		Expect(attendee.Badges).To(BeEmpty())
+
+		attendee = addRep(&attendee, 10)
+		Expect(attendee.Badges).To(Equal([]types.Badge{{ID: "rep-20", Name: "Rising Star", UnixTimeSec: now.Unix()}}))
+
+		attendee = addRep(&attendee, 10)
+		Expect(attendee.Badges).To(HaveLen(1))
+		achievement, _ := keeper.GetAchievement(ctx, "rep-20")
+		Expect(achievement.Granted).To(Equal(uint(1)))
+	})
+
+	It("should count the rep of an achievement toward the other achievements", func() {
+		setAchievements(
+			types.Achievement{ID: "claim", Name: "Welcome", Metric: types.AchievementClaim, Threshold: 1, Rep: 10},
+			types.Achievement{ID: "rep-10", Name: "Rising Star", Metric: types.AchievementRep, Threshold: 10},
+		)
+		attendee := utils.AddAttendeeToKeeper(ctx, &keeper, "1", true, false)
+
+		attendee = addRep(&attendee, 0)
+		Expect(attendee.Rep).To(Equal(uint(10)))
+		Expect(attendee.Badges).To(HaveLen(2))
+	})
+
+	It("should only grant the badge to the first attendees when it has max grants", func() {
+		setAchievements(types.Achievement{ID: "first-1", Name: "Early Bird", Metric: types.AchievementClaim,
+			Threshold: 1, MaxGrants: 1})
+		first := utils.AddAttendeeToKeeper(ctx, &keeper, "1", true, false)
+		second := utils.AddAttendeeToKeeper(ctx, &keeper, "2", true, false)
+
+		Expect(addRep(&first, 5).Badges).To(HaveLen(1))
+		Expect(addRep(&second, 5).Badges).To(BeEmpty())
+
+		progress := keeper.GetAchievementProgress(ctx, &second)
+		Expect(progress).To(Equal([]types.AchievementProgress{{ID: "first-1", Name: "Early Bird", Progress: 1,
+			Threshold: 1, Earned: false, Available: false}}))
+	})
+
+	It("should not grant badges to unclaimed attendees", func() {
+		setAchievements(types.Achievement{ID: "rep-10", Name: "Rising Star", Metric: types.AchievementRep,
+			Threshold: 10})
+		attendee := utils.AddAttendeeToKeeper(ctx, &keeper, "1", false, false)
+
+		Expect(addRep(&attendee, 10).Badges).To(BeEmpty())
+	})
+
+	It("should report the progress toward every achievement", func() {
+		setAchievements(
+			types.Achievement{ID: "challenges-3", Name: "Quiz Master", Metric: types.AchievementChallenges,
+				Threshold: 3},
+			types.Achievement{ID: "rep-10", Name: "Rising Star", Metric: types.AchievementRep, Threshold: 10},
+		)
+		attendee := utils.AddAttendeeToKeeper(ctx, &keeper, "1", true, false)
+		attendee.ChallengeIDs = []string{"a", "b"}
+		attendee = addRep(&attendee, 12)
+
+		progress := keeper.GetAchievementProgress(ctx, &attendee)
+		Expect(progress).To(Equal([]types.AchievementProgress{
+			{ID: "challenges-3", Name: "Quiz Master", Progress: 2, Threshold: 3, Available: true},
+			{ID: "rep-10", Name: "Rising Star", Progress: 12, Threshold: 10, Earned: true, UnixTimeSec: now.Unix(),
+				Available: true},
+		}))
+	})
+})
@@ -1,20 +1,45 @@
 package keeper
 
 import (
+	"strconv"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/eco/longy/util"
 	"github.com/eco/longy/x/longy/internal/types"
-	"math"
 )
 
-// GetAttendeeWithID will retrieve the attendee by `id`. The Address of an attendee is generated using
-// the secp256k1 key using `id` as the secret. returns false if the attendee does not exist
+// GetAttendeeWithID will retrieve the attendee by `id`. The Address of an attendee is derived from `id`
+// and the address namespace of the chain. returns false if the attendee does not exist
 //nolint:gocritic
 func (k *Keeper) GetAttendeeWithID(ctx sdk.Context, id string) (types.Attendee, bool) {
-	address := util.IDToAddress(id)
+	address := k.AttendeeAddress(ctx, id)
 	return k.GetAttendee(ctx, address)
 }
 
+// AttendeeAddress returns the address of the attendee with `id`. Chains without an address
+// namespace use the legacy seed derivation
+//nolint:gocritic
+func (k *Keeper) AttendeeAddress(ctx sdk.Context, id string) sdk.AccAddress {
+	return util.AttendeeAddress(k.GetAddressNamespace(ctx), id)
+}
+
+// GetAddressNamespace returns the event namespace attendee addresses are derived with
+//nolint:gocritic
+func (k *Keeper) GetAddressNamespace(ctx sdk.Context) string {
+	bz, _ := k.Get(ctx, types.AddressNamespaceKey())
+	return string(bz)
+}
+
+// SetAddressNamespace sets the event namespace attendee addresses are derived with
+//nolint:gocritic
+func (k *Keeper) SetAddressNamespace(ctx sdk.Context, namespace string) {
+	if len(namespace) == 0 {
+		k.Delete(ctx, types.AddressNamespaceKey())
+		return
+	}
+	k.Set(ctx, types.AddressNamespaceKey(), []byte(namespace))
+}
+
 // GetAttendee will retrieve the attendee via `AccAddress`
 //nolint:gocritic
 func (k *Keeper) GetAttendee(ctx sdk.Context, address sdk.AccAddress) (attendee types.Attendee, exists bool) {
@@ -83,20 +108,8 @@ func (k *Keeper) AwardScanPoints(ctx sdk.Context, scan *types.Scan) sdk.Error {
 		return types.ErrScanNotAccepted("scan must be accepted by both parties before awarding points")
 	}
 
-	multiplier := float64(1)
-	bonus := k.GetBonus(ctx)
-	if bonus != nil {
-		multiplier = bonus.GetMultiplier()
-	}
-
-	a1Points := types.ScanAttendeeAwardPoints
-	a2Points := types.ScanAttendeeAwardPoints
-	if a2.Sponsor && !a1.Sponsor {
-		a1Points = uint(math.Floor(float64(types.ScanSponsorAwardPoints) * multiplier))
-	}
-	if a1.Sponsor && !a2.Sponsor {
-		a2Points = uint(math.Floor(float64(types.ScanSponsorAwardPoints) * multiplier))
-	}
+	a1Points := k.connectionPoints(ctx, &a1, &a2, scanPoints)
+	a2Points := k.connectionPoints(ctx, &a2, &a1, scanPoints)
 
 	err = k.AddRep(ctx, &a1, a1Points)
 	if err != nil {
@@ -114,13 +127,34 @@ func (k *Keeper) AwardScanPoints(ctx sdk.Context, scan *types.Scan) sdk.Error {
 }
 
 //AddRep adds reputation to the attendee, and if that pushes them past a tier, then they will be rewarded a prize
-//if there are any left for that tier level
+//if there are any left for that tier level and their role is allowed to win prizes. The achievements of the
+//attendee are then evaluated
 //nolint:gocritic
 func (k *Keeper) AddRep(ctx sdk.Context, attendee *types.Attendee, points uint) sdk.Error {
+	err := k.addRep(ctx, attendee, points)
+	if err != nil {
+		return err
+	}
+	err = k.GrantAchievements(ctx, attendee)
+	if err != nil {
+		return err
+	}
+	k.SetAttendee(ctx, attendee)
+	return nil
+}
+
+//addRep adds reputation and tier prizes to the attendee and records the time of the tiers reached, without
+//evaluating their achievements or storing them
+//nolint:gocritic
+func (k *Keeper) addRep(ctx sdk.Context, attendee *types.Attendee, points uint) sdk.Error {
 	before := attendee.GetTier()
 	attendee.AddRep(points)
+	k.Metrics(ctx).RepAwarded.Add(float64(points))
+	for i := before + 1; i <= attendee.GetTier(); i++ {
+		attendee.SetTierReached(i, ctx.BlockTime().Unix())
+	}
 
-	if attendee.GetTier() > before {
+	if attendee.GetTier() > before && !k.GetRoleRule(ctx, attendee.GetRole()).ExcludeFromPrizes {
 		for i := before + 1; i <= attendee.GetTier(); i++ {
 			prize, err := k.GetPrize(ctx, types.GetPrizeIDByTier(i))
 			if err != nil {
@@ -135,15 +169,15 @@ func (k *Keeper) AddRep(ctx sdk.Context, attendee *types.Attendee, points uint)
 				if added {
 					prize.Quantity--
 					k.SetPrize(ctx, &prize)
+					k.Metrics(ctx).PrizesWon.With("tier", strconv.Itoa(int(prize.Tier))).Add(1)
 				}
 			}
 		}
 	}
-	k.SetAttendee(ctx, attendee)
 	return nil
 }
 
-//AwardShareInfoPoints adds points to the sender of the shared info based on if the receiver is a sponsor or not
+//AwardShareInfoPoints adds points to the sender of the shared info based on the role of the receiver
 //nolint:gocritic
 func (k *Keeper) AwardShareInfoPoints(ctx sdk.Context, scan *types.Scan, senderAddr sdk.AccAddress,
 	receiverAddr sdk.AccAddress) sdk.Error {
@@ -156,17 +190,8 @@ func (k *Keeper) AwardShareInfoPoints(ctx sdk.Context, scan *types.Scan, senderA
 		return types.ErrScanNotAccepted("scan must be accepted by both parties before awarding points")
 	}
 
-	multiplier := float64(1)
-	bonus := k.GetBonus(ctx)
-	if bonus != nil {
-		multiplier = bonus.GetMultiplier()
-	}
-
-	//give sender points for sharing, check if receiver is a sponsor
-	val := types.ShareAttendeeAwardPoints
-	if receiver.Sponsor && !sender.Sponsor {
-		val = uint(math.Floor(float64(types.ShareSponsorAwardPoints) * multiplier))
-	}
+	//give sender points for sharing based on the role of the receiver
+	val := k.connectionPoints(ctx, &sender, &receiver, sharePoints)
 
 	err = k.AddRep(ctx, &sender, val)
 	if err != nil {
@@ -178,6 +203,34 @@ func (k *Keeper) AwardShareInfoPoints(ctx sdk.Context, scan *types.Scan, senderA
 	return nil
 }
 
+//CountConnection counts the accepted scan for both of its participants, by the role of the other one
+//nolint:gocritic
+func (k *Keeper) CountConnection(ctx sdk.Context, scan *types.Scan) sdk.Error {
+	a1, a2, err := k.getAttendeesByScan(ctx, scan)
+	if err != nil {
+		return err
+	}
+
+	a1.CountConnection(a2.GetRole())
+	a2.CountConnection(a1.GetRole())
+	k.SetAttendee(ctx, &a1)
+	k.SetAttendee(ctx, &a2)
+	return nil
+}
+
+//CountShare counts the info shared by the attendee at `senderAddr` with the attendee at `receiverAddr`
+//nolint:gocritic
+func (k *Keeper) CountShare(ctx sdk.Context, senderAddr sdk.AccAddress, receiverAddr sdk.AccAddress) sdk.Error {
+	sender, receiver, err := k.GetAttendees(ctx, senderAddr, receiverAddr)
+	if err != nil {
+		return err
+	}
+
+	sender.CountShare(receiver.GetRole())
+	k.SetAttendee(ctx, &sender)
+	return nil
+}
+
 //AddSharedID adds the scan id to the scan ids array of both the sender and receiver is they don't contain it yet
 //nolint:gocritic
 func (k *Keeper) AddSharedID(ctx sdk.Context, senderAddr sdk.AccAddress, receiverAddr sdk.AccAddress,
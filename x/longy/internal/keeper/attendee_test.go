@@ -247,6 +247,27 @@ var _ = Describe("Attendee Keeper Tests", func() {
 		})
 	})
 
+	Context("address namespace", func() {
+		It("should find attendees by the legacy address without a namespace", func() {
+			utils.AddAttendeeToKeeper(ctx, &keeper, qr1, false, false)
+			attendee, ok := keeper.GetAttendeeWithID(ctx, qr1)
+			Expect(ok).To(BeTrue())
+			Expect(attendee.Address).To(Equal(util.IDToAddress(qr1)))
+		})
+
+		It("should find attendees by the namespaced address", func() {
+			keeper.SetAddressNamespace(ctx, "sfbw")
+			utils.AddAttendeeToKeeper(ctx, &keeper, qr1, false, false)
+
+			attendee, ok := keeper.GetAttendeeWithID(ctx, qr1)
+			Expect(ok).To(BeTrue())
+			Expect(attendee.Address).To(Equal(util.AttendeeAddress("sfbw", qr1)))
+
+			_, ok = keeper.GetAttendee(ctx, util.IDToAddress(qr1))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
 })
 
 //nolint:gocritic
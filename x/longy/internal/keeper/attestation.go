@@ -0,0 +1,35 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//HasAttestedScan indicates if the attendee at `scanner` already attested the scan with `scanID`
+//nolint:gocritic
+func (k *Keeper) HasAttestedScan(ctx sdk.Context, scanner sdk.AccAddress, scanID []byte) bool {
+	return k.Has(ctx, types.AttestedScanKey(scanner, scanID))
+}
+
+//SetAttestedScan records that the attendee at `scanner` attested the scan with `scanID`. There is one
+//record per scanner and scan, so the replay protection grows with the scans rather than the attestations
+//nolint:gocritic
+func (k *Keeper) SetAttestedScan(ctx sdk.Context, scanner sdk.AccAddress, scanID []byte) {
+	attested := types.AttestedScan{Scanner: scanner, ScanID: scanID}
+	k.Set(ctx, types.AttestedScanKey(scanner, scanID), k.Cdc.MustMarshalBinaryBare(attested))
+}
+
+//GetAllAttestedScans returns the scans attested by all the attendees
+//nolint:gocritic
+func (k *Keeper) GetAllAttestedScans(ctx sdk.Context) (attested []types.AttestedScan) {
+	it := sdk.KVStorePrefixIterator(k.KVStore(ctx), types.Prefix(types.AttestedScanPrefix))
+	defer it.Close()
+	attested = make([]types.AttestedScan, 0)
+	for ; it.Valid(); it.Next() {
+		var a types.AttestedScan
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &a)
+		attested = append(attested, a)
+	}
+
+	return attested
+}
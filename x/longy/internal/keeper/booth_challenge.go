@@ -0,0 +1,182 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//GetBoothChallenge returns the booth challenge with `id`
+//nolint:gocritic
+func (k *Keeper) GetBoothChallenge(ctx sdk.Context, id string) (challenge types.BoothChallenge, ok bool) {
+	bz, _ := k.Get(ctx, types.BoothChallengeKey(id))
+	if bz == nil {
+		return
+	}
+
+	k.Cdc.MustUnmarshalBinaryBare(bz, &challenge)
+	return challenge, true
+}
+
+//GetAllBoothChallenges returns all the booth challenges, ordered by id
+//nolint:gocritic
+func (k *Keeper) GetAllBoothChallenges(ctx sdk.Context) (challenges []types.BoothChallenge) {
+	it := sdk.KVStorePrefixIterator(k.KVStore(ctx), types.Prefix(types.BoothChallengePrefix))
+	defer it.Close()
+	challenges = make([]types.BoothChallenge, 0)
+	for ; it.Valid(); it.Next() {
+		var challenge types.BoothChallenge
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &challenge)
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges
+}
+
+//SetBoothChallenge puts the booth challenge into the store with its id as key
+//nolint:gocritic
+func (k *Keeper) SetBoothChallenge(ctx sdk.Context, challenge *types.BoothChallenge) {
+	k.Set(ctx, types.BoothChallengeKey(challenge.ID), k.Cdc.MustMarshalBinaryBare(*challenge))
+}
+
+//GetBoothAnswer returns the answer the attendee at `addr` committed to the booth challenge with `id`
+//nolint:gocritic
+func (k *Keeper) GetBoothAnswer(ctx sdk.Context, id string, addr sdk.AccAddress) (answer types.BoothAnswer,
+	ok bool) {
+	bz, _ := k.Get(ctx, types.BoothAnswerKey(id, addr))
+	if bz == nil {
+		return
+	}
+
+	k.Cdc.MustUnmarshalBinaryBare(bz, &answer)
+	return answer, true
+}
+
+//GetAllBoothAnswers returns the answers committed to the booth challenges that are not revealed yet
+//nolint:gocritic
+func (k *Keeper) GetAllBoothAnswers(ctx sdk.Context) (answers []types.BoothAnswer) {
+	it := sdk.KVStorePrefixIterator(k.KVStore(ctx), types.Prefix(types.BoothAnswerPrefix))
+	defer it.Close()
+	answers = make([]types.BoothAnswer, 0)
+	for ; it.Valid(); it.Next() {
+		var answer types.BoothAnswer
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &answer)
+		answers = append(answers, answer)
+	}
+
+	return answers
+}
+
+//SetBoothAnswer puts the committed answer into the store, keyed by its challenge and attendee
+//nolint:gocritic
+func (k *Keeper) SetBoothAnswer(ctx sdk.Context, answer *types.BoothAnswer) {
+	k.Set(ctx, types.BoothAnswerKey(answer.ChallengeID, answer.Attendee), k.Cdc.MustMarshalBinaryBare(*answer))
+}
+
+//CreateBoothChallenge opens the booth challenge of a sponsor until its expiry
+//nolint:gocritic
+func (k *Keeper) CreateBoothChallenge(ctx sdk.Context, challenge types.BoothChallenge) sdk.Error {
+	if _, ok := k.GetBoothChallenge(ctx, challenge.ID); ok {
+		return types.ErrInvalidBoothChallenge("challenge %s already exists", challenge.ID)
+	}
+	if !challenge.ExpiryTime().After(ctx.BlockTime()) {
+		return types.ErrInvalidBoothChallenge("expiry of challenge %s has passed", challenge.ID)
+	}
+
+	sponsor, ok := k.GetAttendee(ctx, challenge.Sponsor)
+	if !ok {
+		return types.ErrAttendeeNotFound("cannot find the sponsor %s", challenge.Sponsor)
+	}
+	if sponsor.GetRole() != types.RoleSponsor {
+		return types.ErrInsufficientPrivileges("only sponsors can create booth challenges")
+	}
+	if !sponsor.IsClaimed() {
+		return types.ErrAttendeeClaimed("the sponsor must claim their badge before creating challenges")
+	}
+
+	challenge.AnswerCount = 0
+	k.SetBoothChallenge(ctx, &challenge)
+	return nil
+}
+
+//CommitBoothAnswer records the commitment of the attendee at `addr` to their answer to the challenge with
+//`id`. Until the expiry the attendee can replace it, the chain does not tell whether the answer is right
+//nolint:gocritic
+func (k *Keeper) CommitBoothAnswer(ctx sdk.Context, addr sdk.AccAddress, id string,
+	commitment util.Commitment) sdk.Error {
+	challenge, ok := k.GetBoothChallenge(ctx, id)
+	if !ok {
+		return types.ErrBoothChallengeNotFound("cannot find challenge %s", id)
+	}
+	if ctx.BlockTime().After(challenge.ExpiryTime()) {
+		return types.ErrBoothChallengeExpired("challenge %s expired", id)
+	}
+
+	attendee, err := k.getChallengeAttendee(ctx, &challenge, addr)
+	if err != nil {
+		return err
+	}
+
+	k.SetBoothAnswer(ctx, &types.BoothAnswer{ChallengeID: id, Attendee: attendee.Address, Commitment: commitment})
+	return nil
+}
+
+//AnswerBoothChallenge reveals the answer the attendee at `addr` committed to before the expiry of the
+//challenge, checks it against the commitment of the sponsor and awards the points of the challenge
+//nolint:gocritic
+func (k *Keeper) AnswerBoothChallenge(ctx sdk.Context, addr sdk.AccAddress, id string, answer string) sdk.Error {
+	challenge, ok := k.GetBoothChallenge(ctx, id)
+	if !ok {
+		return types.ErrBoothChallengeNotFound("cannot find challenge %s", id)
+	}
+	if !ctx.BlockTime().After(challenge.ExpiryTime()) {
+		return types.ErrBoothChallengeOpen("answers to challenge %s are revealed after its expiry", id)
+	}
+
+	attendee, err := k.getChallengeAttendee(ctx, &challenge, addr)
+	if err != nil {
+		return err
+	}
+
+	committed, ok := k.GetBoothAnswer(ctx, id, addr)
+	if !ok {
+		return types.ErrBoothAnswerNotCommitted("no answer to challenge %s was committed by %s", id, addr)
+	}
+	if !committed.VerifyReveal(answer) {
+		return types.ErrInvalidCommitmentReveal("answer does not match the commitment to challenge %s", id)
+	}
+	if !challenge.VerifyAnswer(answer) {
+		return types.ErrInvalidCommitmentReveal("wrong answer to challenge %s", id)
+	}
+	k.Delete(ctx, types.BoothAnswerKey(id, addr))
+	attendee.AddChallengeID(id)
+
+	err = k.AddRep(ctx, &attendee, challenge.Points)
+	if err != nil {
+		return err
+	}
+
+	challenge.AnswerCount++
+	k.SetBoothChallenge(ctx, &challenge)
+	return nil
+}
+
+//getChallengeAttendee returns the attendee at `addr` if they can answer the challenge
+//nolint:gocritic
+func (k *Keeper) getChallengeAttendee(ctx sdk.Context, challenge *types.BoothChallenge,
+	addr sdk.AccAddress) (types.Attendee, sdk.Error) {
+	attendee, ok := k.GetAttendee(ctx, addr)
+	if !ok {
+		return attendee, types.ErrAttendeeNotFound("cannot find the attendee %s", addr)
+	}
+	if !attendee.IsClaimed() {
+		return attendee, types.ErrAttendeeClaimed("the attendee must claim their badge before answering challenges")
+	}
+	if attendee.Address.Equals(challenge.Sponsor) {
+		return attendee, types.ErrInvalidBoothChallenge("sponsors cannot answer their own challenges")
+	}
+	if attendee.HasChallengeID(challenge.ID) {
+		return attendee, types.ErrBoothChallengeAnswered("challenge %s was already answered", challenge.ID)
+	}
+	return attendee, nil
+}
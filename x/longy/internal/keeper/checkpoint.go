@@ -0,0 +1,137 @@
+package keeper
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//GetCheckpoint returns the venue checkpoint with `id`
+//nolint:gocritic
+func (k *Keeper) GetCheckpoint(ctx sdk.Context, id string) (checkpoint types.Checkpoint, ok bool) {
+	bz, _ := k.Get(ctx, types.CheckpointKey(id))
+	if bz == nil {
+		return
+	}
+
+	k.Cdc.MustUnmarshalBinaryBare(bz, &checkpoint)
+	return checkpoint, true
+}
+
+//GetAllCheckpoints returns all the venue checkpoints, ordered by id
+//nolint:gocritic
+func (k *Keeper) GetAllCheckpoints(ctx sdk.Context) (checkpoints []types.Checkpoint) {
+	it := sdk.KVStorePrefixIterator(k.KVStore(ctx), types.Prefix(types.CheckpointPrefix))
+	defer it.Close()
+	checkpoints = make([]types.Checkpoint, 0)
+	for ; it.Valid(); it.Next() {
+		var checkpoint types.Checkpoint
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &checkpoint)
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints
+}
+
+//SetCheckpoint puts the venue checkpoint into the store with its id as key
+//nolint:gocritic
+func (k *Keeper) SetCheckpoint(ctx sdk.Context, checkpoint *types.Checkpoint) {
+	k.Set(ctx, types.CheckpointKey(checkpoint.ID), k.Cdc.MustMarshalBinaryBare(*checkpoint))
+}
+
+//GetCheckpointScans returns the attendance of the venue checkpoint with `id`, ordered by address
+//nolint:gocritic
+func (k *Keeper) GetCheckpointScans(ctx sdk.Context, id string) []types.CheckpointScan {
+	return k.getCheckpointScans(ctx, types.CheckpointScansPrefix(id))
+}
+
+//GetAllCheckpointScans returns the attendance of all the venue checkpoints
+//nolint:gocritic
+func (k *Keeper) GetAllCheckpointScans(ctx sdk.Context) []types.CheckpointScan {
+	return k.getCheckpointScans(ctx, types.Prefix(types.CheckpointScanPrefix))
+}
+
+//nolint:gocritic
+func (k *Keeper) getCheckpointScans(ctx sdk.Context, prefix []byte) (scans []types.CheckpointScan) {
+	it := sdk.KVStorePrefixIterator(k.KVStore(ctx), prefix)
+	defer it.Close()
+	scans = make([]types.CheckpointScan, 0)
+	for ; it.Valid(); it.Next() {
+		var scan types.CheckpointScan
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &scan)
+		scans = append(scans, scan)
+	}
+
+	return scans
+}
+
+//HasCheckpointScan indicates if the attendee at `addr` scanned the venue checkpoint with `id`
+//nolint:gocritic
+func (k *Keeper) HasCheckpointScan(ctx sdk.Context, id string, addr sdk.AccAddress) bool {
+	return k.Has(ctx, types.CheckpointScanKey(id, addr))
+}
+
+//SetCheckpointScan records the attendance of an attendee at a venue checkpoint
+//nolint:gocritic
+func (k *Keeper) SetCheckpointScan(ctx sdk.Context, scan *types.CheckpointScan) {
+	k.Set(ctx, types.CheckpointScanKey(scan.CheckpointID, scan.Attendee), k.Cdc.MustMarshalBinaryBare(*scan))
+}
+
+//CreateCheckpoint registers the venue checkpoint
+//nolint:gocritic
+func (k *Keeper) CreateCheckpoint(ctx sdk.Context, checkpoint types.Checkpoint) sdk.Error {
+	if _, ok := k.GetCheckpoint(ctx, checkpoint.ID); ok {
+		return types.ErrInvalidCheckpoint("checkpoint %s already exists", checkpoint.ID)
+	}
+	if !checkpoint.EndTime().After(ctx.BlockTime()) {
+		return types.ErrInvalidCheckpoint("end of checkpoint %s has passed", checkpoint.ID)
+	}
+
+	checkpoint.ClaimCount = 0
+	k.SetCheckpoint(ctx, &checkpoint)
+	return nil
+}
+
+//ScanCheckpoint verifies the signature of the scan by the attendee at `addr` against the public key of the
+//venue checkpoint, records their attendance and awards its points
+//nolint:gocritic
+func (k *Keeper) ScanCheckpoint(ctx sdk.Context, addr sdk.AccAddress, id string, sig []byte) sdk.Error {
+	checkpoint, ok := k.GetCheckpoint(ctx, id)
+	if !ok {
+		return types.ErrCheckpointNotFound("cannot find checkpoint %s", id)
+	}
+	if !checkpoint.IsActive(ctx.BlockTime()) {
+		return types.ErrCheckpointInactive("checkpoint %s can only be scanned between %s and %s", id,
+			checkpoint.StartTime().UTC().Format(time.RFC3339), checkpoint.EndTime().UTC().Format(time.RFC3339))
+	}
+
+	attendee, ok := k.GetAttendee(ctx, addr)
+	if !ok {
+		return types.ErrAttendeeNotFound("cannot find the attendee %s", addr)
+	}
+	if !attendee.IsClaimed() {
+		return types.ErrAttendeeClaimed("the attendee must claim their badge before scanning checkpoints")
+	}
+
+	if !checkpoint.VerifyScan(ctx.ChainID(), addr, sig) {
+		return types.ErrInvalidSignature("scan is not signed with the key of checkpoint %s", id)
+	}
+	if k.HasCheckpointScan(ctx, id, addr) {
+		return types.ErrCheckpointScanned("checkpoint %s was already scanned", id)
+	}
+	if checkpoint.IsFull() {
+		return types.ErrCheckpointFull("checkpoint %s reached its %d claims", id, checkpoint.MaxClaims)
+	}
+
+	//the scan is stored before the award so that it counts toward the checkpoint achievements
+	k.SetCheckpointScan(ctx, &types.CheckpointScan{
+		CheckpointID: id,
+		Attendee:     addr,
+		UnixTimeSec:  ctx.BlockTime().Unix(),
+	})
+	checkpoint.ClaimCount++
+	k.SetCheckpoint(ctx, &checkpoint)
+
+	return k.AddRep(ctx, &attendee, checkpoint.Points)
+}
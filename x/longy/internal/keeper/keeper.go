@@ -15,8 +15,12 @@ type Keeper struct {
 	accountKeeper auth.AccountKeeper
 	coinKeeper    bank.Keeper
 	Cdc           *codec.Codec
+
+	metrics *Metrics
 }
 
+var nopMetrics = NopMetrics()
+
 // NewKeeper is a creator for `Keeper`
 //nolint:gocritic
 func NewKeeper(cdc *codec.Codec, longyStoreKey sdk.StoreKey, accKeeper auth.AccountKeeper,
@@ -26,7 +30,23 @@ func NewKeeper(cdc *codec.Codec, longyStoreKey sdk.StoreKey, accKeeper auth.Acco
 		accountKeeper:   accKeeper,
 		coinKeeper:      coinKeeper,
 		Cdc:             cdc,
+		metrics:         nopMetrics,
+	}
+}
+
+// SetMetrics sets the metrics updated by the module. Must be called before the keeper is handed
+// to the module
+func (k *Keeper) SetMetrics(m *Metrics) {
+	k.metrics = m
+}
+
+// Metrics returns the metrics to update for `ctx`. Checked and simulated transactions are not counted
+//nolint:gocritic
+func (k Keeper) Metrics(ctx sdk.Context) *Metrics {
+	if ctx.IsCheckTx() || k.metrics == nil {
+		return nopMetrics
 	}
+	return k.metrics
 }
 
 // AccountKeeper returns the auth module's account keeper composed with this module
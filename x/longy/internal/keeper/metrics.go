@@ -0,0 +1,114 @@
+package keeper
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsSubsystem is the subsystem of every metric exposed by the module
+const MetricsSubsystem = "longy"
+
+// Metrics are the game metrics exposed through the tendermint instrumentation port. They are
+// only updated while delivering transactions so that checked and simulated messages do not count
+type Metrics struct {
+	// Number of scans between two attendees
+	Scans metrics.Counter
+	// Number of scans accepted by the scanned attendee
+	Accepts metrics.Counter
+	// Number of info shares between attendees
+	Shares metrics.Counter
+	// Rep awarded to attendees
+	RepAwarded metrics.Counter
+	// Number of prizes won by tier
+	PrizesWon metrics.Counter
+	// Number of badges earned by achievement
+	BadgesEarned metrics.Counter
+	// Number of prizes left by tier
+	PrizesRemaining metrics.Gauge
+	// 1 if a bonus period is live
+	BonusActive metrics.Gauge
+	// Multiplier of the live bonus period, 1 outside of them
+	BonusMultiplier metrics.Gauge
+}
+
+// PrometheusMetrics returns Metrics registered with the default prometheus registry served by
+// tendermint. Labels can be provided along with their values ("foo", "fooValue")
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Scans: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "scans_total",
+			Help:      "Number of scans between two attendees.",
+		}, labels).With(labelsAndValues...),
+		Accepts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "accepts_total",
+			Help:      "Number of scans accepted by the scanned attendee.",
+		}, labels).With(labelsAndValues...),
+		Shares: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "shares_total",
+			Help:      "Number of info shares between attendees.",
+		}, labels).With(labelsAndValues...),
+		RepAwarded: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rep_awarded_total",
+			Help:      "Rep awarded to attendees.",
+		}, labels).With(labelsAndValues...),
+		PrizesWon: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "prizes_won_total",
+			Help:      "Number of prizes won by tier.",
+		}, append(labels, "tier")).With(labelsAndValues...),
+		BadgesEarned: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "badges_earned_total",
+			Help:      "Number of badges earned by achievement.",
+		}, append(labels, "achievement")).With(labelsAndValues...),
+		PrizesRemaining: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "prizes_remaining",
+			Help:      "Number of prizes left by tier.",
+		}, append(labels, "tier")).With(labelsAndValues...),
+		BonusActive: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bonus_active",
+			Help:      "1 if a bonus period is live.",
+		}, labels).With(labelsAndValues...),
+		BonusMultiplier: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bonus_multiplier",
+			Help:      "Multiplier of the live bonus period, 1 outside of them.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Scans:           discard.NewCounter(),
+		Accepts:         discard.NewCounter(),
+		Shares:          discard.NewCounter(),
+		RepAwarded:      discard.NewCounter(),
+		PrizesWon:       discard.NewCounter(),
+		BadgesEarned:    discard.NewCounter(),
+		PrizesRemaining: discard.NewGauge(),
+		BonusActive:     discard.NewGauge(),
+		BonusMultiplier: discard.NewGauge(),
+	}
+}
@@ -0,0 +1,82 @@
+package keeper_test
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// labelledCounter sums what is added under every label set, as go-kit's generic counter
+// hands out an independent copy from With
+type labelledCounter struct {
+	mtx    *sync.Mutex
+	values map[string]float64
+	lvs    []string
+}
+
+func newLabelledCounter() *labelledCounter {
+	return &labelledCounter{mtx: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (c *labelledCounter) With(labelValues ...string) metrics.Counter {
+	return &labelledCounter{mtx: c.mtx, values: c.values, lvs: append(append([]string{}, c.lvs...), labelValues...)}
+}
+
+func (c *labelledCounter) Add(delta float64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.values[strings.Join(c.lvs, ",")] += delta
+}
+
+func (c *labelledCounter) value(labelValues ...string) float64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.values[strings.Join(labelValues, ",")]
+}
+
+var _ = Describe("Metrics Keeper Tests", func() {
+	var (
+		rep   *generic.Counter
+		prize *labelledCounter
+	)
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		prizes := types.GetGenesisPrizes()
+		for i := range prizes {
+			keeper.SetPrize(ctx, &prizes[i])
+		}
+
+		rep = generic.NewCounter("rep")
+		prize = newLabelledCounter()
+		metrics := longy.NopMetrics()
+		metrics.RepAwarded = rep
+		metrics.PrizesWon = prize
+		keeper.SetMetrics(metrics)
+	})
+
+	It("should count the rep and prizes awarded while delivering transactions", func() {
+		deliverCtx := ctx.WithIsCheckTx(false)
+		attendee := utils.AddAttendeeToKeeper(deliverCtx, &keeper, "1234", true, false)
+
+		Expect(keeper.AddRep(deliverCtx, &attendee, types.Tier1Rep)).To(BeNil())
+		Expect(rep.Value()).To(Equal(float64(types.Tier1Rep)))
+		Expect(prize.value("tier", "1")).To(Equal(float64(1)))
+	})
+
+	It("should not count checked transactions", func() {
+		Expect(ctx.IsCheckTx()).To(BeTrue())
+		attendee := utils.AddAttendeeToKeeper(ctx, &keeper, "1234", true, false)
+
+		Expect(keeper.AddRep(ctx, &attendee, types.Tier1Rep)).To(BeNil())
+		Expect(rep.Value()).To(BeZero())
+		Expect(prize.values).To(BeEmpty())
+	})
+})
@@ -0,0 +1,82 @@
+package keeper
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+// GetTxQuota returns the transaction quota of attendee accounts. Chains that did not set one
+// use the default quota
+//nolint:gocritic
+func (k *Keeper) GetTxQuota(ctx sdk.Context) types.TxQuota {
+	bz, _ := k.Get(ctx, types.TxQuotaKey())
+	if bz == nil {
+		return types.DefaultTxQuota()
+	}
+
+	var quota types.TxQuota
+	err := k.Cdc.UnmarshalBinaryLengthPrefixed(bz, &quota)
+	if err != nil {
+		panic(err)
+	}
+	return quota
+}
+
+// SetTxQuota sets the transaction quota of attendee accounts
+//nolint:gocritic
+func (k *Keeper) SetTxQuota(ctx sdk.Context, quota types.TxQuota) {
+	bz, err := k.Cdc.MarshalBinaryLengthPrefixed(quota)
+	if err != nil {
+		panic(err)
+	}
+	k.Set(ctx, types.TxQuotaKey(), bz)
+}
+
+// GetTxUsage returns the transactions sent by the account at `addr` in its quota window
+//nolint:gocritic
+func (k *Keeper) GetTxUsage(ctx sdk.Context, addr sdk.AccAddress) (usage types.TxUsage) {
+	bz, _ := k.Get(ctx, types.TxUsageKey(addr))
+	if bz == nil {
+		return
+	}
+
+	err := k.Cdc.UnmarshalBinaryLengthPrefixed(bz, &usage)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// ConsumeTxQuota counts `count` transactions against the quota of the account at `addr`. A window
+// starts with the first transaction sent after the previous one ended, so that every account
+// gets its own window. An error is returned if they do not fit in the quota of the current window
+//nolint:gocritic
+func (k *Keeper) ConsumeTxQuota(ctx sdk.Context, addr sdk.AccAddress, count uint) sdk.Error {
+	quota := k.GetTxQuota(ctx)
+	if quota.MaxTxs == 0 {
+		return nil
+	}
+
+	now := ctx.BlockHeader().Time
+	usage := k.GetTxUsage(ctx, addr)
+	if usage.WindowStart.IsZero() || now.Sub(usage.WindowStart) >= quota.Window() {
+		usage = types.TxUsage{WindowStart: now}
+	}
+
+	if usage.Count+count > quota.MaxTxs {
+		return types.ErrTxQuotaExceeded("account %s sent %d of %d transactions in %s, %d more do not fit, "+
+			"retry after %s", addr, usage.Count, quota.MaxTxs, quota.Window(), count,
+			usage.WindowStart.Add(quota.Window()).UTC().Format(time.RFC3339))
+	}
+
+	usage.Count += count
+	bz, err := k.Cdc.MarshalBinaryLengthPrefixed(usage)
+	if err != nil {
+		panic(err)
+	}
+	k.Set(ctx, types.TxUsageKey(addr), bz)
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package keeper
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//GetRaffle returns the raffle with `id`
+//nolint:gocritic
+func (k *Keeper) GetRaffle(ctx sdk.Context, id string) (raffle types.Raffle, ok bool) {
+	bz, _ := k.Get(ctx, types.RaffleKey(id))
+	if bz == nil {
+		return
+	}
+
+	k.Cdc.MustUnmarshalBinaryBare(bz, &raffle)
+	return raffle, true
+}
+
+//GetAllRaffles returns all the raffles, ordered by id
+//nolint:gocritic
+func (k *Keeper) GetAllRaffles(ctx sdk.Context) (raffles []types.Raffle) {
+	it := sdk.KVStorePrefixIterator(k.KVStore(ctx), types.Prefix(types.RafflePrefix))
+	defer it.Close()
+	raffles = make([]types.Raffle, 0)
+	for ; it.Valid(); it.Next() {
+		var raffle types.Raffle
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &raffle)
+		raffles = append(raffles, raffle)
+	}
+
+	return raffles
+}
+
+//SetRaffle puts the raffle into the store with its id as key
+//nolint:gocritic
+func (k *Keeper) SetRaffle(ctx sdk.Context, raffle *types.Raffle) {
+	k.Set(ctx, types.RaffleKey(raffle.ID), k.Cdc.MustMarshalBinaryBare(*raffle))
+}
+
+//CreateRaffle opens the raffle and reserves its prizes, so that they are no longer awarded to the
+//attendees reaching the tier
+//nolint:gocritic
+func (k *Keeper) CreateRaffle(ctx sdk.Context, raffle types.Raffle) sdk.Error {
+	if _, ok := k.GetRaffle(ctx, raffle.ID); ok {
+		return types.ErrInvalidRaffle("raffle %s already exists", raffle.ID)
+	}
+	if !raffle.DeadlineTime().After(ctx.BlockTime()) {
+		return types.ErrInvalidRaffle("deadline of raffle %s has passed", raffle.ID)
+	}
+	if err := raffle.ValidateBasic(); err != nil {
+		return err
+	}
+
+	prize, err := k.GetPrize(ctx, types.GetPrizeIDByTier(raffle.PrizeTier))
+	if err != nil {
+		return err
+	}
+	if prize.Quantity < raffle.WinnerCount {
+		return types.ErrInvalidRaffle("only %d tier %d prizes are left for %d winners", prize.Quantity,
+			prize.Tier, raffle.WinnerCount)
+	}
+	prize.Quantity -= raffle.WinnerCount
+	k.SetPrize(ctx, &prize)
+
+	raffle.Status = types.RaffleOpen
+	k.SetRaffle(ctx, &raffle)
+	return nil
+}
+
+//UpdateRaffles runs at the end of every block. It snapshots the entrants of the open raffles whose deadline
+//passed, records the hash of the snapshot block once it is committed and expires the snapshotted raffles that
+//were not revealed by their reveal deadline, returning their prizes to their tier
+//nolint:gocritic
+func (k *Keeper) UpdateRaffles(ctx sdk.Context) {
+	var due []types.Raffle
+	for _, raffle := range k.GetAllRaffles(ctx) {
+		switch {
+		case raffle.Status == types.RaffleOpen && !ctx.BlockTime().Before(raffle.DeadlineTime()):
+			due = append(due, raffle)
+		case raffle.Status != types.RaffleSnapshotted:
+		case !ctx.BlockTime().Before(raffle.RevealDeadlineTime()):
+			k.expireRaffle(ctx, &raffle)
+		case len(raffle.SeedBlockHash) == 0 && ctx.BlockHeight() > raffle.SnapshotHeight:
+			//EndBlock runs every block, so the previous block is the snapshot block
+			raffle.SeedHeight = ctx.BlockHeight() - 1
+			raffle.SeedBlockHash = ctx.BlockHeader().LastBlockId.Hash
+			k.SetRaffle(ctx, &raffle)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	attendees := k.GetAllAttendees(ctx)
+	for i := range due {
+		raffle := &due[i]
+		for j := range attendees {
+			a := &attendees[j]
+			if raffle.IsEligible(a) && !k.GetRoleRule(ctx, a.GetRole()).ExcludeFromPrizes {
+				raffle.Entrants = append(raffle.Entrants, a.Address)
+			}
+		}
+		raffle.EntrantCount = uint(len(raffle.Entrants))
+		raffle.SnapshotHeight = ctx.BlockHeight()
+		raffle.Status = types.RaffleSnapshotted
+		k.SetRaffle(ctx, raffle)
+	}
+}
+
+//expireRaffle returns the prizes reserved by the raffle to their tier
+//nolint:gocritic
+func (k *Keeper) expireRaffle(ctx sdk.Context, raffle *types.Raffle) {
+	prize, err := k.GetPrize(ctx, types.GetPrizeIDByTier(raffle.PrizeTier))
+	if err == nil {
+		prize.Quantity += raffle.WinnerCount
+		k.SetPrize(ctx, &prize)
+	}
+
+	raffle.Status = types.RaffleExpired
+	k.SetRaffle(ctx, raffle)
+}
+
+//DrawRaffle verifies `secret` against the commitment of the raffle and awards its prize to the winners
+//drawn with the hash of the snapshot block. Prizes that are not won go back to their tier
+//nolint:gocritic
+func (k *Keeper) DrawRaffle(ctx sdk.Context, id string, secret string) (types.Raffle, sdk.Error) {
+	raffle, ok := k.GetRaffle(ctx, id)
+	if !ok {
+		return raffle, types.ErrRaffleNotFound("cannot find raffle %s", id)
+	}
+
+	switch raffle.Status {
+	case types.RaffleOpen:
+		return raffle, types.ErrRaffleNotDrawable("entrants of raffle %s are snapshotted at its deadline", id)
+	case types.RaffleDrawn:
+		return raffle, types.ErrRaffleNotDrawable("raffle %s is already drawn", id)
+	case types.RaffleExpired:
+		return raffle, types.ErrRaffleNotDrawable("raffle %s was not revealed by its reveal deadline", id)
+	}
+	if !ctx.BlockTime().Before(raffle.RevealDeadlineTime()) {
+		return raffle, types.ErrRaffleNotDrawable("raffle %s was not revealed by its reveal deadline", id)
+	}
+	if len(raffle.SeedBlockHash) == 0 {
+		return raffle, types.ErrRaffleNotDrawable("raffle %s waits for the hash of its snapshot block %d", id,
+			raffle.SnapshotHeight)
+	}
+
+	if !raffle.Commitment.VerifyReveal(secret) {
+		return raffle, types.ErrInvalidCommitmentReveal("secret does not match the commitment of raffle %s", id)
+	}
+
+	prize, err := k.GetPrize(ctx, types.GetPrizeIDByTier(raffle.PrizeTier))
+	if err != nil {
+		return raffle, err
+	}
+
+	seed := types.RaffleSeed(secret, raffle.SeedBlockHash)
+	raffle.Proof = &types.RaffleProof{Secret: secret, Height: raffle.SeedHeight, BlockHash: raffle.SeedBlockHash,
+		Seed: seed}
+
+	won := uint(0)
+	for _, addr := range types.DrawWinners(seed, raffle.Entrants, raffle.WinnerCount) {
+		raffle.Winners = append(raffle.Winners, addr)
+
+		attendee, ok := k.GetAttendee(ctx, addr)
+		if !ok {
+			continue
+		}
+		//attendees who won the prize since the snapshot keep their single win of the tier
+		if attendee.AddWinning(&types.Win{Tier: prize.Tier, Name: prize.PrizeText}) {
+			k.SetAttendee(ctx, &attendee)
+			k.Metrics(ctx).PrizesWon.With("tier", strconv.Itoa(int(prize.Tier))).Add(1)
+			won++
+		}
+	}
+
+	if won < raffle.WinnerCount {
+		prize.Quantity += raffle.WinnerCount - won
+		k.SetPrize(ctx, &prize)
+	}
+
+	raffle.Status = types.RaffleDrawn
+	k.SetRaffle(ctx, &raffle)
+	return raffle, nil
+}
@@ -0,0 +1,94 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+	"math"
+)
+
+//GetRoleRule returns the point rule for `role`. Roles without a rule in the store fall back to
+//the default rules, and unknown roles are scored as regular attendees
+//nolint:gocritic
+func (k *Keeper) GetRoleRule(ctx sdk.Context, role string) types.RoleRule {
+	if len(role) == 0 {
+		role = types.RoleAttendee
+	}
+
+	bz, _ := k.Get(ctx, types.RoleKey(role))
+	if bz != nil {
+		var rule types.RoleRule
+		k.Cdc.MustUnmarshalBinaryBare(bz, &rule)
+		return rule
+	}
+
+	defaults := types.DefaultRoleRules()
+	for i := range defaults {
+		if defaults[i].Role == role {
+			return defaults[i]
+		}
+	}
+
+	if role != types.RoleAttendee {
+		return k.GetRoleRule(ctx, types.RoleAttendee)
+	}
+	return defaults[0]
+}
+
+//GetAllRoleRules returns all the role rules from the keeper
+//nolint:gocritic
+func (k *Keeper) GetAllRoleRules(ctx sdk.Context) (rules types.GenesisRoles) {
+	it := k.KVStore(ctx).Iterator(nil, nil)
+	defer it.Close()
+	rules = make(types.GenesisRoles, 0)
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if types.IsRoleKey(key) {
+			var rule types.RoleRule
+			err := k.Cdc.UnmarshalBinaryBare(it.Value(), &rule)
+			if err != nil {
+				continue
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+//SetRoleRule puts the rule into the store with its role as key
+//nolint:gocritic
+func (k *Keeper) SetRoleRule(ctx sdk.Context, rule *types.RoleRule) {
+	k.Set(ctx, rule.GetID(), k.Cdc.MustMarshalBinaryBare(*rule))
+}
+
+//connectionPoints returns the points `earner` is awarded for connecting with `other`. The rule of
+//`other`'s role decides the points, unless both share a role or a peer group in which case they score
+//as regular attendees. Active bonuses only apply to bonus eligible roles
+//nolint:gocritic
+func (k *Keeper) connectionPoints(ctx sdk.Context, earner *types.Attendee, other *types.Attendee,
+	points func(rule *types.RoleRule) uint) uint {
+	rule := k.GetRoleRule(ctx, other.GetRole())
+	earnerRule := k.GetRoleRule(ctx, earner.GetRole())
+	if earnerRule.IsPeer(&rule) {
+		rule = k.GetRoleRule(ctx, types.RoleAttendee)
+	}
+
+	val := points(&rule)
+	if !rule.BonusEligible {
+		return val
+	}
+
+	bonus := k.GetBonus(ctx)
+	if bonus == nil {
+		return val
+	}
+	return uint(math.Floor(float64(val) * bonus.GetMultiplier()))
+}
+
+func scanPoints(rule *types.RoleRule) uint {
+	return rule.ScanPoints
+}
+
+func sharePoints(rule *types.RoleRule) uint {
+	return rule.SharePoints
+}
@@ -0,0 +1,139 @@
+package keeper_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Role Keeper Tests", func() {
+	var s1, s2 sdk.AccAddress
+	var scan *types.Scan
+	const (
+		qr1 = "1234"
+		qr2 = "asdf"
+	)
+	BeforeEach(func() {
+		BeforeTestRun()
+
+		s1 = util.IDToAddress(qr1)
+		s2 = util.IDToAddress(qr2)
+		var err sdk.Error
+		scan, err = types.NewScan(s1, s2, nil, nil, 0, 0)
+		Expect(err).To(BeNil())
+		scan.Accepted = true
+		keeper.SetScan(ctx, scan)
+
+		prizes := types.GetGenesisPrizes()
+		for i := range prizes {
+			keeper.SetPrize(ctx, &prizes[i])
+		}
+	})
+
+	It("should fall back to the default rules when none are set", func() {
+		rule := keeper.GetRoleRule(ctx, types.RoleSponsor)
+		Expect(rule.ScanPoints).To(Equal(types.ScanSponsorAwardPoints))
+		Expect(rule.BonusEligible).To(BeTrue())
+
+		Expect(keeper.GetRoleRule(ctx, "").Role).To(Equal(types.RoleAttendee))
+		Expect(len(keeper.GetAllRoleRules(ctx))).To(Equal(0))
+	})
+
+	It("should score unknown roles as regular attendees", func() {
+		rule := keeper.GetRoleRule(ctx, "unknown")
+		Expect(rule.Role).To(Equal(types.RoleAttendee))
+		Expect(rule.ScanPoints).To(Equal(types.ScanAttendeeAwardPoints))
+	})
+
+	It("should return the rules that have been set", func() {
+		rule := types.RoleRule{Role: types.RoleSpeaker, ScanPoints: 10, SharePoints: 20}
+		keeper.SetRoleRule(ctx, &rule)
+
+		Expect(keeper.GetRoleRule(ctx, types.RoleSpeaker)).To(Equal(rule))
+		rules := keeper.GetAllRoleRules(ctx)
+		Expect(len(rules)).To(Equal(1))
+		Expect(rules[0]).To(Equal(rule))
+	})
+
+	It("should score sponsors and speakers as regular attendees with each other by default", func() {
+		utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr1, true, types.RoleSponsor)
+		utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr2, true, types.RoleSpeaker)
+
+		err := keeper.AwardScanPoints(ctx, scan)
+		Expect(err).To(BeNil())
+		inspectAttendees(ctx, s1, s2, types.ScanAttendeeAwardPoints, types.ScanAttendeeAwardPoints)
+	})
+
+	Context("when the rules are configured", func() {
+		BeforeEach(func() {
+			rules := types.GenesisRoles{
+				{Role: types.RoleAttendee, ScanPoints: 1, SharePoints: 2},
+				{Role: types.RoleVIP, ScanPoints: 7, SharePoints: 9, BonusEligible: true},
+				{Role: types.RoleStaff, ScanPoints: 1, SharePoints: 2, ExcludeFromPrizes: true},
+			}
+			for i := range rules {
+				keeper.SetRoleRule(ctx, &rules[i])
+			}
+		})
+
+		It("should award scan points from the rule of the other party", func() {
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr1, true, types.RoleAttendee)
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr2, true, types.RoleVIP)
+
+			err := keeper.AwardScanPoints(ctx, scan)
+			Expect(err).To(BeNil())
+			inspectScan(ctx, scan.ID, 7, 1)
+			inspectAttendees(ctx, s1, s2, 7, 1)
+		})
+
+		It("should award share points from the rule of the receiver", func() {
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr1, true, types.RoleAttendee)
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr2, true, types.RoleVIP)
+
+			err := keeper.AwardShareInfoPoints(ctx, scan, s1, s2)
+			Expect(err).To(BeNil())
+			inspectAttendees(ctx, s1, s2, 9, 0)
+		})
+
+		It("should score attendees of the same role as regular attendees", func() {
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr1, true, types.RoleVIP)
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr2, true, types.RoleVIP)
+
+			err := keeper.AwardScanPoints(ctx, scan)
+			Expect(err).To(BeNil())
+			inspectAttendees(ctx, s1, s2, 1, 1)
+		})
+
+		It("should apply the bonus multiplier to bonus eligible roles only", func() {
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr1, true, types.RoleAttendee)
+			utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr2, true, types.RoleVIP)
+			keeper.SetBonus(ctx, types.NewBonus("2"))
+
+			err := keeper.AwardScanPoints(ctx, scan)
+			Expect(err).To(BeNil())
+			inspectAttendees(ctx, s1, s2, 14, 1)
+		})
+
+		It("should not award prizes to roles excluded from prizes", func() {
+			staff := utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr1, true, types.RoleStaff)
+			staff.Rep = types.Tier1Rep - 1
+			keeper.SetAttendee(ctx, &staff)
+			attendee := utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, qr2, true, types.RoleAttendee)
+			attendee.Rep = types.Tier1Rep - 1
+			keeper.SetAttendee(ctx, &attendee)
+
+			err := keeper.AwardScanPoints(ctx, scan)
+			Expect(err).To(BeNil())
+			a, b, err := keeper.GetAttendees(ctx, s1, s2)
+			Expect(err).To(BeNil())
+
+			Expect(a.Rep).To(Equal(types.Tier1Rep))
+			Expect(len(a.Winnings)).To(Equal(0))
+			Expect(b.Rep).To(Equal(types.Tier1Rep))
+			Expect(len(b.Winnings)).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+package keeper
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+// GetTeamRules returns the scoring rules of the team board. Chains that did not set them use the
+// default rules
+//nolint:gocritic
+func (k *Keeper) GetTeamRules(ctx sdk.Context) types.TeamRules {
+	bz, _ := k.Get(ctx, types.TeamRulesKey())
+	if bz == nil {
+		return types.DefaultTeamRules()
+	}
+
+	var rules types.TeamRules
+	err := k.Cdc.UnmarshalBinaryLengthPrefixed(bz, &rules)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// SetTeamRules sets the scoring rules of the team board
+//nolint:gocritic
+func (k *Keeper) SetTeamRules(ctx sdk.Context, rules types.TeamRules) {
+	bz, err := k.Cdc.MarshalBinaryLengthPrefixed(rules)
+	if err != nil {
+		panic(err)
+	}
+	k.Set(ctx, types.TeamRulesKey(), bz)
+}
+
+// GetTeams returns the teams of the claimed attendees that have a company, scored with the team rules
+// and ordered by score. Roles excluded from the leader board do not play for their team and teams under
+// the min size are left out
+//nolint:gocritic
+func (k *Keeper) GetTeams(ctx sdk.Context) []types.Team {
+	rules := k.GetTeamRules(ctx)
+	attendees := k.GetAllAttendees(ctx)
+
+	excluded := make(map[string]bool)
+	index := make(map[string]int)
+	teams := make([]types.Team, 0)
+	for i := range attendees {
+		key := types.TeamKey(attendees[i].Company)
+		if len(key) == 0 || !attendees[i].IsClaimed() {
+			continue
+		}
+		role := attendees[i].GetRole()
+		if _, ok := excluded[role]; !ok {
+			excluded[role] = k.GetRoleRule(ctx, role).ExcludeFromLeaderBoard
+		}
+		if excluded[role] {
+			continue
+		}
+
+		j, ok := index[key]
+		if !ok {
+			j = len(teams)
+			index[key] = j
+			teams = append(teams, types.Team{Name: attendees[i].Company})
+		}
+		teams[j].Size++
+		teams[j].Rep += attendees[i].Rep
+	}
+
+	ranked := make([]types.Team, 0, len(teams))
+	for i := range teams {
+		if teams[i].Size < rules.MinSize {
+			continue
+		}
+		teams[i].Score = rules.Score(teams[i].Rep, teams[i].Size)
+		ranked = append(ranked, teams[i])
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Rep > ranked[j].Rep
+	})
+	return ranked
+}
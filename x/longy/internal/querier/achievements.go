@@ -0,0 +1,43 @@
+package querier
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//queryAchievements returns the achievements with the number of times they were granted
+//nolint:gocritic,unparam
+func queryAchievements(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	achievements := keeper.GetAllAchievements(ctx)
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, achievements)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
+
+//queryAchievementProgress returns the progress of the attendee at the address toward every achievement
+//nolint:gocritic
+func queryAchievementProgress(ctx sdk.Context, path []string, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	addr, e := sdk.AccAddressFromBech32(path[0])
+	if e != nil {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("cannot turn param into cosmos AccAddress : %s", path[0]))
+	}
+	attendee, ok := keeper.GetAttendee(ctx, addr)
+	if !ok {
+		return nil, types.ErrAttendeeNotFound("could not find attendee with that AccAddress")
+	}
+
+	res, e = codec.MarshalJSONIndent(keeper.Cdc, keeper.GetAchievementProgress(ctx, &attendee))
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,37 @@
+package querier
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//queryBoothChallenges returns the booth challenges of the sponsors
+//nolint:gocritic,unparam
+func queryBoothChallenges(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	challenges := keeper.GetAllBoothChallenges(ctx)
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, challenges)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
+
+//queryBoothChallenge returns the booth challenge with the id
+//nolint:gocritic
+func queryBoothChallenge(ctx sdk.Context, path []string, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	challenge, ok := keeper.GetBoothChallenge(ctx, path[0])
+	if !ok {
+		return nil, types.ErrBoothChallengeNotFound("cannot find challenge %s", path[0])
+	}
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, challenge)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,46 @@
+package querier
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//queryCheckpoints returns the venue checkpoints
+//nolint:gocritic,unparam
+func queryCheckpoints(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	checkpoints := keeper.GetAllCheckpoints(ctx)
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, checkpoints)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
+
+//queryCheckpoint returns the venue checkpoint with the id, or its attendance when the path ends with
+//AttendanceKey
+//nolint:gocritic
+func queryCheckpoint(ctx sdk.Context, path []string, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	checkpoint, ok := keeper.GetCheckpoint(ctx, path[0])
+	if !ok {
+		return nil, types.ErrCheckpointNotFound("cannot find checkpoint %s", path[0])
+	}
+
+	var result interface{} = checkpoint
+	if len(path) > 1 {
+		if path[1] != AttendanceKey {
+			return nil, sdk.ErrUnknownRequest("unknown query endpoint")
+		}
+		result = keeper.GetCheckpointScans(ctx, checkpoint.ID)
+	}
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, result)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,55 @@
+package querier_test
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	q "github.com/eco/longy/x/longy/internal/querier"
+	"github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Checkpoints Querier Tests", func() {
+	const id = "keynote"
+	var checkpoint types.Checkpoint
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		start := time.Unix(1570000000, 0)
+		checkpoint = types.NewCheckpoint(id, "Opening Keynote", types.CheckpointSession,
+			secp256k1.GenPrivKey().PubKey(), 10, start, start.Add(time.Hour), 0)
+		keeper.SetCheckpoint(ctx, &checkpoint)
+	})
+
+	It("should return the checkpoints", func() {
+		res, err := querier(ctx, []string{q.CheckpointsKey}, abci.RequestQuery{})
+		Expect(err).To(BeNil())
+		var checkpoints []types.Checkpoint
+		keeper.Cdc.MustUnmarshalJSON(res, &checkpoints)
+		Expect(checkpoints).To(Equal([]types.Checkpoint{checkpoint}))
+	})
+
+	It("should return the attendance of a checkpoint", func() {
+		sender = util.IDToAddress(qr1)
+		scan := types.CheckpointScan{CheckpointID: id, Attendee: sender, UnixTimeSec: 1570000100}
+		keeper.SetCheckpointScan(ctx, &scan)
+
+		res, err := querier(ctx, []string{q.CheckpointsKey, id, q.AttendanceKey}, abci.RequestQuery{})
+		Expect(err).To(BeNil())
+		var scans []types.CheckpointScan
+		keeper.Cdc.MustUnmarshalJSON(res, &scans)
+		Expect(scans).To(Equal([]types.CheckpointScan{scan}))
+	})
+
+	It("should fail when the checkpoint does not exist", func() {
+		_, err := querier(ctx, []string{q.CheckpointsKey, "other", q.AttendanceKey}, abci.RequestQuery{})
+		Expect(err.Code()).To(Equal(types.CheckpointNotFound))
+
+		_, err = querier(ctx, []string{q.CheckpointsKey, id, "other"}, abci.RequestQuery{})
+		Expect(err.Code()).To(Equal(sdk.CodeUnknownRequest))
+	})
+})
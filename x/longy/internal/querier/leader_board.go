@@ -12,15 +12,15 @@ import (
 //LeaderBoard returns the leader board after building it from the attendees in the event
 //nolint:gocritic,unparam,nakedret
 func leaderBoard(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Error) { //test this
-	attendees := keeper.GetAllAttendees(ctx)
-	countAll := len(attendees)
+	countAll, attendees := rankedAttendees(ctx, keeper)
+	countRanked := len(attendees)
 
 	sort.Slice(attendees, func(i, j int) bool { return attendees[i].Rep > attendees[j].Rep })
 
 	var lb *types.LeaderBoard
 	min := types.LeaderBoardCount
-	if countAll < types.LeaderBoardCount {
-		min = countAll
+	if countRanked < types.LeaderBoardCount {
+		min = countRanked
 	}
 	top := make([]types.Attendee, min, types.LeaderBoardCount)
 	copy(top, attendees)
@@ -34,6 +34,7 @@ func leaderBoard(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Err
 			ID:      top[i].ID,
 			Address: top[i].Address,
 			Name:    top[i].Name,
+			Company: top[i].Company,
 			Rep:     top[i].Rep,
 		}
 	}
@@ -47,3 +48,23 @@ func leaderBoard(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Err
 	}
 	return
 }
+
+//rankedAttendees returns the count of all the attendees and the attendees ranked on the leader boards
+//nolint:gocritic
+func rankedAttendees(ctx sdk.Context, keeper keeper.Keeper) (int, []types.Attendee) {
+	all := keeper.GetAllAttendees(ctx)
+
+	//roles such as staff play along but are not ranked
+	excluded := make(map[string]bool)
+	attendees := make([]types.Attendee, 0, len(all))
+	for i := range all {
+		role := all[i].GetRole()
+		if _, ok := excluded[role]; !ok {
+			excluded[role] = keeper.GetRoleRule(ctx, role).ExcludeFromLeaderBoard
+		}
+		if !excluded[role] {
+			attendees = append(attendees, all[i])
+		}
+	}
+	return len(all), attendees
+}
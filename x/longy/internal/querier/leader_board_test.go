@@ -89,6 +89,21 @@ var _ = Describe("Leader Board Querier Tests", func() {
 		Expect(board.Tier2.PrizeAmount).To(Equal(types.LeaderBoardTier2Prize))
 	})
 
+	It("should not rank roles that are excluded from the leader board", func() {
+		count := types.LeaderBoardTier1Count
+		AddAttendeesToKeeper(ctx, &keeper, count, true)
+		staff := utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, "staff", true, types.RoleStaff)
+		staff.Rep = 1000
+		keeper.SetAttendee(ctx, &staff)
+
+		board := getLead()
+		Expect(board.TotalCount).To(Equal(count + 1))
+		Expect(len(board.Tier1.Attendees)).To(Equal(count))
+		for _, a := range board.Tier1.Attendees {
+			Expect(a.ID).ToNot(Equal(staff.ID))
+		}
+	})
+
 	It("should return order the tiers and attendees in descending order by Rep", func() {
 		count := types.LeaderBoardCount * 2
 		AddAttendeesToKeeper(ctx, &keeper, count, true)
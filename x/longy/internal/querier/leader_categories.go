@@ -0,0 +1,155 @@
+package querier
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//repEvent is rep awarded to an attendee at a time
+type repEvent struct {
+	unixTimeSec int64
+	points      uint
+}
+
+//categoryStats are the scan counts and the rep timeline of an attendee
+type categoryStats struct {
+	sponsors    int64
+	shares      int64
+	connections int64
+	timeline    []repEvent
+}
+
+//categoryBoard returns the leader board of `category`, built from the scans of the attendees
+//nolint:gocritic,unparam
+func categoryBoard(ctx sdk.Context, category string, keeper keeper.Keeper) ([]byte, sdk.Error) {
+	tier, isTier := types.ParseLeaderTierCategory(category)
+	switch category {
+	case types.LeaderSponsors, types.LeaderShares, types.LeaderConnections:
+	default:
+		if !isTier {
+			return nil, sdk.ErrUnknownRequest("unknown leader board category " + category)
+		}
+	}
+
+	countAll, attendees := rankedAttendees(ctx, keeper)
+	stats := scanStats(ctx, keeper, attendees)
+
+	ranked := make([]types.RankedAttendee, 0, len(attendees))
+	for i := range attendees {
+		a := &attendees[i]
+		s := stats[a.Address.String()]
+
+		var score int64
+		switch category {
+		case types.LeaderSponsors:
+			score = s.sponsors
+		case types.LeaderShares:
+			score = s.shares
+		case types.LeaderConnections:
+			score = s.connections
+		default:
+			reached, ok := tierReachedAt(a, s.timeline, tier)
+			if !ok {
+				continue
+			}
+			score = reached - a.UnixTimeSecClaimed
+		}
+		if !isTier && score == 0 {
+			continue
+		}
+		ranked = append(ranked, types.RankedAttendee{ID: a.ID, Address: a.Address, Name: a.Name, Score: score})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if isTier {
+			return ranked[i].Score < ranked[j].Score
+		}
+		return ranked[i].Score > ranked[j].Score
+	})
+
+	board := types.NewCategoryLeaderBoard(category, countAll, ranked)
+	board.Time = time.Now()
+	res, err := codec.MarshalJSONIndent(keeper.Cdc, board)
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+	return res, nil
+}
+
+//scanStats returns the stats of the ranked `attendees` by address, counting every scan once per party
+//nolint:gocritic
+func scanStats(ctx sdk.Context, keeper keeper.Keeper, attendees []types.Attendee) map[string]*categoryStats {
+	stats := make(map[string]*categoryStats, len(attendees))
+	for i := range attendees {
+		stats[attendees[i].Address.String()] = &categoryStats{
+			timeline: []repEvent{{attendees[i].UnixTimeSecClaimed, types.ClaimBadgeAwardPoints}},
+		}
+	}
+
+	//the roles of all the attendees, including the ones that are not ranked
+	roles := make(map[string]string)
+	all := keeper.GetAllAttendees(ctx)
+	for i := range all {
+		roles[all[i].Address.String()] = all[i].GetRole()
+	}
+
+	scans := keeper.GetAllScans(ctx)
+	for i := range scans {
+		scan := &scans[i]
+		parties := []struct {
+			self, other sdk.AccAddress
+			data        []byte
+			points      uint
+		}{
+			{scan.S1, scan.S2, scan.D1, scan.P1},
+			{scan.S2, scan.S1, scan.D2, scan.P2},
+		}
+		for _, p := range parties {
+			s, ok := stats[p.self.String()]
+			if !ok {
+				continue
+			}
+			if roles[p.other.String()] == types.RoleSponsor {
+				s.sponsors++
+			}
+			if len(p.data) > 0 {
+				s.shares++
+			}
+			if scan.Accepted {
+				s.connections++
+			}
+			if p.points > 0 {
+				s.timeline = append(s.timeline, repEvent{scan.UnixTimeSec, p.points})
+			}
+		}
+	}
+	return stats
+}
+
+//tierReachedAt returns the time the attendee reached `tier`, false if they did not reach it. Attendees
+//without a recorded time, who reached the tier before tier times were recorded, replay the rep of their
+//timeline and are not ranked when it does not reach the tier
+func tierReachedAt(a *types.Attendee, timeline []repEvent, tier uint) (int64, bool) {
+	if !a.IsClaimed() || a.UnixTimeSecClaimed == 0 || a.GetTier() < tier {
+		return 0, false
+	}
+	if t, ok := a.TierReachedAt(tier); ok {
+		return t, true
+	}
+	rep := types.TierRep(tier)
+
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].unixTimeSec < timeline[j].unixTimeSec })
+	var total uint
+	for _, e := range timeline {
+		total += e.points
+		if total >= rep {
+			return e.unixTimeSec, true
+		}
+	}
+	return 0, false
+}
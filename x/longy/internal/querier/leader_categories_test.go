@@ -0,0 +1,125 @@
+package querier_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	querier2 "github.com/eco/longy/x/longy/internal/querier"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+var _ = Describe("Category Leader Board Querier Tests", func() {
+	var alice, bob, sponsor1, sponsor2 types.Attendee
+
+	var getCategory = func(category string) types.CategoryLeaderBoard {
+		res, err := querier(ctx, []string{querier2.LeaderKey, category}, abci.RequestQuery{})
+		Expect(err).To(BeNil())
+		var board types.CategoryLeaderBoard
+		keeper.Cdc.MustUnmarshalJSON(res, &board)
+		Expect(board.Category).To(Equal(category))
+		return board
+	}
+
+	var ids = func(board types.CategoryLeaderBoard) (ids []string) {
+		for _, a := range board.Tier1.Attendees {
+			ids = append(ids, a.ID)
+		}
+		return
+	}
+
+	var addScan = func(s1, s2 sdk.AccAddress, d1, d2 []byte, p1, p2 uint, unixTimeSec int64, accepted bool) {
+		scan, err := types.NewScan(s1, s2, d1, d2, p1, p2)
+		Expect(err).To(BeNil())
+		scan.UnixTimeSec = unixTimeSec
+		scan.Accepted = accepted
+		keeper.SetScan(ctx, scan)
+	}
+
+	var claimAt = func(a *types.Attendee, rep uint, unixTimeSec int64) {
+		a.Rep = rep
+		a.UnixTimeSecClaimed = unixTimeSec
+		keeper.SetAttendee(ctx, a)
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		alice = utils.AddAttendeeToKeeper(ctx, &keeper, "1", true, false)
+		bob = utils.AddAttendeeToKeeper(ctx, &keeper, "2", true, false)
+		sponsor1 = utils.AddAttendeeToKeeper(ctx, &keeper, "3", true, true)
+		sponsor2 = utils.AddAttendeeToKeeper(ctx, &keeper, "4", true, true)
+	})
+
+	It("should fail for an unknown category", func() {
+		_, err := querier(ctx, []string{querier2.LeaderKey, "likes"}, abci.RequestQuery{})
+		Expect(err).ToNot(BeNil())
+		_, err = querier(ctx, []string{querier2.LeaderKey, types.LeaderTierCategory(10)}, abci.RequestQuery{})
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("should rank the attendees by the sponsors they visited", func() {
+		addScan(alice.Address, sponsor1.Address, nil, nil, 2, 0, 100, false)
+		addScan(sponsor2.Address, alice.Address, nil, nil, 0, 2, 100, true)
+		addScan(bob.Address, sponsor1.Address, nil, nil, 2, 0, 100, true)
+		addScan(bob.Address, alice.Address, nil, nil, 1, 0, 100, true)
+
+		board := getCategory(types.LeaderSponsors)
+		Expect(board.TotalCount).To(Equal(4))
+		Expect(board.Tier1.Attendees[0].ID).To(Equal(alice.ID))
+		Expect(board.Tier1.Attendees[0].Score).To(Equal(int64(2)))
+		Expect(board.Tier1.Attendees[1].ID).To(Equal(bob.ID))
+		Expect(board.Tier1.Attendees[1].Score).To(Equal(int64(1)))
+		Expect(board.Tier1.Attendees).To(HaveLen(2))
+	})
+
+	It("should rank the attendees by their shares and accepted connections", func() {
+		addScan(alice.Address, sponsor1.Address, []byte{1}, nil, 8, 0, 100, true)
+		addScan(alice.Address, bob.Address, []byte{1}, []byte{1}, 4, 4, 100, true)
+		addScan(bob.Address, sponsor2.Address, nil, nil, 2, 0, 100, false)
+
+		Expect(ids(getCategory(types.LeaderShares))).To(Equal([]string{alice.ID, bob.ID}))
+
+		board := getCategory(types.LeaderConnections)
+		Expect(ids(board)).To(ConsistOf(alice.ID, sponsor1.ID, bob.ID))
+		Expect(board.Tier1.Attendees[0].ID).To(Equal(alice.ID))
+		Expect(board.Tier1.Attendees[0].Score).To(Equal(int64(2)))
+	})
+
+	It("should rank the fastest attendees to reach a tier from their claim", func() {
+		claimAt(&alice, 40, 1000)
+		claimAt(&bob, 40, 1000)
+		addScan(alice.Address, sponsor1.Address, nil, nil, 10, 0, 1100, true)
+		addScan(alice.Address, sponsor2.Address, nil, nil, 20, 0, 1500, true)
+		addScan(sponsor1.Address, bob.Address, nil, nil, 0, 25, 1200, true)
+
+		board := getCategory(types.LeaderTierCategory(types.Tier1))
+		Expect(ids(board)).To(Equal([]string{bob.ID, alice.ID}))
+		Expect(board.Tier1.Attendees[0].Score).To(Equal(int64(200)))
+		Expect(board.Tier1.Attendees[1].Score).To(Equal(int64(500)))
+
+		Expect(getCategory(types.LeaderTierCategory(types.Tier2)).Tier1.Attendees).To(BeEmpty())
+	})
+
+	It("should rank by the recorded tier times, including rep the scans do not carry", func() {
+		claimAt(&alice, 40, 1000)
+		claimAt(&bob, 40, 1000)
+		addScan(sponsor1.Address, bob.Address, nil, nil, 0, 25, 1200, true)
+
+		// alice reached the tier with a checkpoint, recorded when the rep was awarded
+		alice.SetTierReached(types.Tier1, 1100)
+		keeper.SetAttendee(ctx, &alice)
+
+		board := getCategory(types.LeaderTierCategory(types.Tier1))
+		Expect(ids(board)).To(Equal([]string{alice.ID, bob.ID}))
+		Expect(board.Tier1.Attendees[0].Score).To(Equal(int64(100)))
+		Expect(board.Tier1.Attendees[1].Score).To(Equal(int64(200)))
+	})
+
+	It("should not rank roles that are excluded from the leader board", func() {
+		staff := utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, "staff", true, types.RoleStaff)
+		addScan(staff.Address, sponsor1.Address, nil, nil, 2, 0, 100, true)
+
+		Expect(getCategory(types.LeaderSponsors).Tier1.Attendees).To(BeEmpty())
+	})
+})
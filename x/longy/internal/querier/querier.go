@@ -28,11 +28,32 @@ const (
 	// PrizesKey is the key for the event prizes
 	PrizesKey = "prizes"
 
-	// LeaderKey is the key for the leader board
+	// LeaderKey is the key for the leader board and the leader boards of the categories
 	LeaderKey = "leader"
 
+	// TeamsKey is the key for the team board
+	TeamsKey = "teams"
+
+	// RolesKey is the key for the point rules of the attendee roles
+	RolesKey = "roles"
+
 	// WinningsKey is the key for getting the unclaimed prizes of an attendee
 	WinningsKey = "winnings"
+
+	// RafflesKey is the key for the prize raffles
+	RafflesKey = "raffles"
+
+	// ChallengesKey is the key for the booth challenges of the sponsors
+	ChallengesKey = "challenges"
+
+	// CheckpointsKey is the key for the venue checkpoints
+	CheckpointsKey = "checkpoints"
+
+	// AttendanceKey is the key for the attendance of a venue checkpoint
+	AttendanceKey = "attendance"
+
+	// AchievementsKey is the key for the achievements and the progress of an attendee toward them
+	AchievementsKey = "achievements"
 )
 
 // NewQuerier is the module level router for state queries
@@ -69,14 +90,47 @@ func NewQuerier(keeper keeper.Keeper) sdk.Querier {
 		case PrizesKey:
 			return queryPrizes(ctx, keeper)
 
+		case RolesKey:
+			return queryRoles(ctx, keeper)
+
 		case QueryBonus:
 			return queryBonus(ctx, keeper)
 
 		case LeaderKey:
+			if len(queryArgs) > 0 {
+				return categoryBoard(ctx, queryArgs[0], keeper)
+			}
 			return leaderBoard(ctx, keeper)
 
+		case TeamsKey:
+			return teamLeaderBoard(ctx, keeper)
+
 		case WinningsKey:
 			return queryWinnings(ctx, keeper, queryArgs)
+
+		case RafflesKey:
+			if len(queryArgs) > 0 {
+				return queryRaffle(ctx, queryArgs, keeper)
+			}
+			return queryRaffles(ctx, keeper)
+
+		case ChallengesKey:
+			if len(queryArgs) > 0 {
+				return queryBoothChallenge(ctx, queryArgs, keeper)
+			}
+			return queryBoothChallenges(ctx, keeper)
+
+		case CheckpointsKey:
+			if len(queryArgs) > 0 {
+				return queryCheckpoint(ctx, queryArgs, keeper)
+			}
+			return queryCheckpoints(ctx, keeper)
+
+		case AchievementsKey:
+			if len(queryArgs) > 0 {
+				return queryAchievementProgress(ctx, queryArgs, keeper)
+			}
+			return queryAchievements(ctx, keeper)
 		}
 
 		return nil, sdk.ErrUnknownRequest("unknown query endpoint")
@@ -0,0 +1,40 @@
+package querier
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//queryRaffles returns the raffles without their entrants, which are returned by queryRaffle
+//nolint:gocritic,unparam
+func queryRaffles(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	raffles := keeper.GetAllRaffles(ctx)
+	for i := range raffles {
+		raffles[i].Entrants = nil
+	}
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, raffles)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
+
+//queryRaffle returns the raffle with its entrants, winners and the proof of its draw
+//nolint:gocritic
+func queryRaffle(ctx sdk.Context, path []string, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	raffle, ok := keeper.GetRaffle(ctx, path[0])
+	if !ok {
+		return nil, types.ErrRaffleNotFound("cannot find raffle %s", path[0])
+	}
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, raffle)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,19 @@
+package querier
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/keeper"
+)
+
+//nolint:gocritic,unparam
+func queryRoles(ctx sdk.Context, keeper keeper.Keeper) (res []byte, err sdk.Error) {
+	roles := keeper.GetAllRoleRules(ctx)
+
+	res, e := codec.MarshalJSONIndent(keeper.Cdc, roles)
+	if e != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
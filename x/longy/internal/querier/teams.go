@@ -0,0 +1,29 @@
+package querier
+
+import (
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/x/longy/internal/keeper"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+//teamLeaderBoard returns the team board after building it from the teams of the attendees
+//nolint:gocritic,unparam
+func teamLeaderBoard(ctx sdk.Context, keeper keeper.Keeper) ([]byte, sdk.Error) {
+	teams := keeper.GetTeams(ctx)
+
+	withScore := 0
+	for withScore < len(teams) && teams[withScore].Score > 0 {
+		withScore++
+	}
+
+	board := types.NewTeamLeaderBoard(len(teams), keeper.GetTeamRules(ctx), teams[:withScore])
+	board.Time = time.Now()
+	res, err := codec.MarshalJSONIndent(keeper.Cdc, board)
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+	return res, nil
+}
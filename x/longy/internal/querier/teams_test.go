@@ -0,0 +1,80 @@
+package querier_test
+
+import (
+	querier2 "github.com/eco/longy/x/longy/internal/querier"
+	"github.com/eco/longy/x/longy/internal/types"
+	"github.com/eco/longy/x/longy/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+var _ = Describe("Team Board Querier Tests", func() {
+
+	var getTeams = func() types.TeamLeaderBoard {
+		res, err := querier(ctx, []string{querier2.TeamsKey}, abci.RequestQuery{})
+		Expect(err).To(BeNil())
+		var board types.TeamLeaderBoard
+		keeper.Cdc.MustUnmarshalJSON(res, &board)
+		return board
+	}
+
+	var addMember = func(id string, company string, rep uint, claimed bool, role string) {
+		a := utils.AddAttendeeWithRoleToKeeper(ctx, &keeper, id, claimed, role)
+		a.Company = company
+		a.Rep = rep
+		keeper.SetAttendee(ctx, &a)
+	}
+
+	BeforeEach(func() {
+		BeforeTestRun()
+		keeper.SetTeamRules(ctx, types.TeamRules{Scoring: types.TeamScoringAverage, MinSize: 2})
+	})
+
+	It("should return an empty team board if no teams", func() {
+		board := getTeams()
+		Expect(board.TotalCount).To(Equal(0))
+		Expect(board.Tier1.Teams).To(BeEmpty())
+		Expect(board.Rules).To(Equal(types.TeamRules{Scoring: types.TeamScoringAverage, MinSize: 2}))
+	})
+
+	It("should rank the teams by the average rep of their claimed members", func() {
+		addMember("1", "Eco", 10, true, types.RoleAttendee)
+		addMember("2", " eco ", 30, true, types.RoleAttendee)
+		addMember("3", "Acme", 25, true, types.RoleAttendee)
+		addMember("4", "Acme", 25, true, types.RoleAttendee)
+		addMember("5", "Acme", 0, false, types.RoleAttendee)
+
+		board := getTeams()
+		Expect(board.TotalCount).To(Equal(2))
+		Expect(board.Tier1.Teams).To(Equal([]types.Team{
+			{Name: "Acme", Size: 2, Rep: 50, Score: 25},
+			{Name: "Eco", Size: 2, Rep: 40, Score: 20},
+		}))
+	})
+
+	It("should rank the teams by the total rep of their members", func() {
+		keeper.SetTeamRules(ctx, types.TeamRules{Scoring: types.TeamScoringSum, MinSize: 2})
+		addMember("1", "Eco", 10, true, types.RoleAttendee)
+		addMember("2", "Eco", 30, true, types.RoleAttendee)
+		addMember("3", "Eco", 5, true, types.RoleAttendee)
+		addMember("4", "Acme", 25, true, types.RoleAttendee)
+		addMember("5", "Acme", 15, true, types.RoleAttendee)
+
+		board := getTeams()
+		Expect(board.Tier1.Teams[0].Name).To(Equal("Eco"))
+		Expect(board.Tier1.Teams[0].Score).To(Equal(uint(45)))
+		Expect(board.Tier1.Teams[1].Score).To(Equal(uint(40)))
+	})
+
+	It("should not rank teams under the min size or the members of excluded roles", func() {
+		addMember("1", "Eco", 10, true, types.RoleAttendee)
+		addMember("2", "Eco", 1000, true, types.RoleStaff)
+		addMember("3", "Solo", 50, true, types.RoleAttendee)
+		addMember("4", "", 50, true, types.RoleAttendee)
+
+		board := getTeams()
+		Expect(board.TotalCount).To(Equal(0))
+		Expect(board.Tier1.Teams).To(BeEmpty())
+	})
+})
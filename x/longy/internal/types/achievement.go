@@ -0,0 +1,129 @@
+package types
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	//AchievementConnections counts the accepted scans of an attendee, with attendees of `Role` when it is set
+	AchievementConnections = "connections"
+	//AchievementShares counts the scans an attendee shared their info in, with attendees of `Role` when it is set
+	AchievementShares = "shares"
+	//AchievementClaim is 1 once the attendee claimed their badge, with `MaxGrants` it rewards the first claims
+	AchievementClaim = "claim"
+	//AchievementRep is the rep of an attendee
+	AchievementRep = "rep"
+	//AchievementCheckpoints counts the venue checkpoints scanned by an attendee
+	AchievementCheckpoints = "checkpoints"
+	//AchievementChallenges counts the booth challenges answered by an attendee
+	AchievementChallenges = "challenges"
+
+	//MaxAchievementIDLength is the length limit of the id of an achievement
+	MaxAchievementIDLength = 64
+	//MaxAchievementTextLength is the length limit of the name and description of an achievement
+	MaxAchievementTextLength = 280
+)
+
+//achievementID matches the ids of achievements
+var achievementID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,` + strconv.Itoa(MaxAchievementIDLength) + `}$`)
+
+//Achievement is a rule of the genesis file granting a badge, and optionally rep, to the claimed attendees
+//whose `Metric` reaches `Threshold`. Achievements are evaluated every time an attendee is awarded rep. With
+//`MaxGrants` only the first attendees get the badge
+type Achievement struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Metric      string `json:"metric"`
+	Role        string `json:"role,omitempty"`
+	Threshold   uint   `json:"threshold"`
+	MaxGrants   uint   `json:"max_grants,omitempty"`
+	Rep         uint   `json:"rep,omitempty"`
+	Granted     uint   `json:"granted"`
+}
+
+//GenesisAchievements is the full array of achievements for the genesis file
+type GenesisAchievements []Achievement
+
+//Badge is the non-transferable record of an achievement on the attendee who earned it
+type Badge struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	UnixTimeSec int64  `json:"unixTimeSec"`
+}
+
+//AchievementProgress is the progress of an attendee toward an achievement
+type AchievementProgress struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Progress    uint   `json:"progress"`
+	Threshold   uint   `json:"threshold"`
+	Earned      bool   `json:"earned"`
+	UnixTimeSec int64  `json:"unixTimeSec,omitempty"` //when the badge was earned
+	Available   bool   `json:"available"`             //false once the max grants are reached
+}
+
+//ValidateBasic checks that the achievement is well formed
+//nolint:gocritic
+func (a Achievement) ValidateBasic() sdk.Error {
+	if !achievementID.MatchString(a.ID) {
+		return ErrInvalidAchievement("achievement id must be 1 to %d letters, digits, dashes or underscores",
+			MaxAchievementIDLength)
+	}
+	if len(strings.TrimSpace(a.Name)) == 0 || len(a.Name) > MaxAchievementTextLength ||
+		len(a.Description) > MaxAchievementTextLength {
+		return ErrInvalidAchievement("name of achievement %s must be between 1 and %d characters, "+
+			"its description at most %d", a.ID, MaxAchievementTextLength, MaxAchievementTextLength)
+	}
+	switch a.Metric {
+	case AchievementConnections, AchievementShares:
+	case AchievementClaim, AchievementRep, AchievementCheckpoints, AchievementChallenges:
+		if len(a.Role) > 0 {
+			return ErrInvalidAchievement("only %s and %s achievements can have a role, got %s for %s",
+				AchievementConnections, AchievementShares, a.Metric, a.ID)
+		}
+	default:
+		return ErrInvalidAchievement("unknown metric %s of achievement %s", a.Metric, a.ID)
+	}
+	if a.Threshold == 0 {
+		return ErrInvalidAchievement("threshold of achievement %s must be positive", a.ID)
+	}
+	if a.Metric == AchievementClaim && a.Threshold != 1 {
+		return ErrInvalidAchievement("threshold of %s achievement %s must be 1", AchievementClaim, a.ID)
+	}
+	if a.MaxGrants > 0 && a.Granted > a.MaxGrants {
+		return ErrInvalidAchievement("achievement %s is granted %d times, over its max grants %d", a.ID,
+			a.Granted, a.MaxGrants)
+	}
+	return nil
+}
+
+//IsAvailable indicates if the achievement can still be granted
+//nolint:gocritic
+func (a Achievement) IsAvailable() bool {
+	return a.MaxGrants == 0 || a.Granted < a.MaxGrants
+}
+
+//GetBadge returns the badge of the achievement with `id`, false if the attendee did not earn it
+func (a *Attendee) GetBadge(id string) (Badge, bool) {
+	for _, b := range a.Badges {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Badge{}, false
+}
+
+//AddBadge adds the badge if the attendee did not earn it yet
+func (a *Attendee) AddBadge(badge Badge) (added bool) {
+	if _, ok := a.GetBadge(badge.ID); ok {
+		return false
+	}
+	a.Badges = append(a.Badges, badge)
+	return true
+}
@@ -0,0 +1,62 @@
+package types_test
+
+import (
+	. "github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Achievement Tests", func() {
+	var achievement Achievement
+
+	BeforeEach(func() {
+		achievement = Achievement{ID: "sponsor-10", Name: "Sponsor Friend", Metric: AchievementConnections,
+			Role: RoleSponsor, Threshold: 10, Rep: 5}
+	})
+
+	It("should validate a well formed achievement", func() {
+		Expect(achievement.ValidateBasic()).To(BeNil())
+	})
+
+	It("should fail when the achievement is malformed", func() {
+		invalid := achievement
+		invalid.ID = "sponsor 10"
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidAchievement))
+
+		invalid = achievement
+		invalid.Name = ""
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidAchievement))
+
+		invalid = achievement
+		invalid.Metric = "likes"
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidAchievement))
+
+		invalid = achievement
+		invalid.Metric = AchievementRep
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidAchievement))
+
+		invalid = achievement
+		invalid.Threshold = 0
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidAchievement))
+
+		invalid = Achievement{ID: "first-100", Name: "Early Bird", Metric: AchievementClaim, Threshold: 2}
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidAchievement))
+	})
+
+	It("should not be available once its max grants are reached", func() {
+		Expect(achievement.IsAvailable()).To(BeTrue())
+		achievement.MaxGrants = 1
+		achievement.Granted = 1
+		Expect(achievement.IsAvailable()).To(BeFalse())
+	})
+
+	It("should only add a badge once", func() {
+		attendee := NewAttendee("1", RoleAttendee)
+		Expect(attendee.AddBadge(Badge{ID: "sponsor-10", UnixTimeSec: 1})).To(BeTrue())
+		Expect(attendee.AddBadge(Badge{ID: "sponsor-10", UnixTimeSec: 2})).To(BeFalse())
+
+		badge, ok := attendee.GetBadge("sponsor-10")
+		Expect(ok).To(BeTrue())
+		Expect(badge.UnixTimeSec).To(Equal(int64(1)))
+	})
+})
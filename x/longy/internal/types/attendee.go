@@ -14,20 +14,35 @@ type Attendee struct {
 	Address            sdk.AccAddress  `json:"address"`
 	PubKey             crypto.PubKey   `json:"pubKey,omitempty"`
 	Name               string          `json:"name,omitempty"`
+	Company            string          `json:"company,omitempty"`
 	UnixTimeSecClaimed int64           `json:"unixTimeSecClaimed,omitempty"` //time when this attendee account was claimed
 	Commitment         util.Commitment `json:"commitment,omitempty"`
 	Claimed            bool            `json:"claimed,omitempty"`
-	Sponsor            bool            `json:"sponsor,omitempty"`
+	Role               string          `json:"role,omitempty"`
+	LegacySponsor      bool            `json:"sponsor,omitempty"` //pre-role genesis files, mapped to Role
 	RsaPublicKey       string          `json:"rsaPublicKey,omitempty"`
 	EncryptedInfo      []byte          `json:"encryptedInfo,omitempty"`
 	ScanIDs            []string        `json:"scanIds,omitempty"`
 	Winnings           []Win           `json:"winnings,omitempty"`
+	ChallengeIDs       []string        `json:"challengeIds,omitempty"` //booth challenges answered
+	Badges             []Badge         `json:"badges,omitempty"`       //badges of the achievements earned
 	Rep                uint            `json:"rep,omitempty"`
+	TierTimes          []int64         `json:"tierTimes,omitempty"`  //unix times in seconds tier 1 and up were reached
+	ScanCounts         []ScanCount     `json:"scanCounts,omitempty"` //connections and shares by role of the other party
+}
+
+//ScanCount counts the accepted scans of an attendee with attendees of `Role` and the scans they shared their
+//info in. The count of the empty role is across all the roles
+type ScanCount struct {
+	Role        string `json:"role,omitempty"`
+	Connections uint   `json:"connections,omitempty"`
+	Shares      uint   `json:"shares,omitempty"`
 }
 
 // NewAttendee is the constructor for `Attendee`. New attendees default to 0 rep
-// and is unclaimed
-func NewAttendee(id string, sponsor bool) Attendee {
+// and is unclaimed. The address is the legacy seed derived address, chains with an
+// address namespace overwrite it
+func NewAttendee(id string, role string) Attendee {
 	addr := util.IDToAddress(id)
 
 	return Attendee{
@@ -40,7 +55,7 @@ func NewAttendee(id string, sponsor bool) Attendee {
 		Claimed:       false,
 		EncryptedInfo: []byte{},
 		ScanIDs:       []string{},
-		Sponsor:       sponsor,
+		Role:          role,
 		Rep:           0,
 	}
 }
@@ -55,6 +70,78 @@ func (a *Attendee) AddScanID(id []byte) (added bool) {
 	return false
 }
 
+//AddChallengeID adds the id of an answered booth challenge, returns false if it was already answered
+func (a *Attendee) AddChallengeID(id string) (added bool) {
+	if contains(a.ChallengeIDs, id) {
+		return false
+	}
+	a.ChallengeIDs = append(a.ChallengeIDs, id)
+	return true
+}
+
+//HasChallengeID indicates if the booth challenge with `id` was answered
+func (a *Attendee) HasChallengeID(id string) bool {
+	return contains(a.ChallengeIDs, id)
+}
+
+//SetTierReached records `unixTimeSec` as the time `tier` was reached. Lower tiers without a time are left
+//at 0, unknown
+func (a *Attendee) SetTierReached(tier uint, unixTimeSec int64) {
+	if tier == Tier0 {
+		return
+	}
+	for uint(len(a.TierTimes)) < tier {
+		a.TierTimes = append(a.TierTimes, 0)
+	}
+	a.TierTimes[tier-1] = unixTimeSec
+}
+
+//TierReachedAt returns the time `tier` was reached, false if it was not reached or its time is unknown
+func (a *Attendee) TierReachedAt(tier uint) (int64, bool) {
+	if tier == Tier0 || uint(len(a.TierTimes)) < tier || a.TierTimes[tier-1] == 0 {
+		return 0, false
+	}
+	return a.TierTimes[tier-1], true
+}
+
+//CountConnection counts an accepted scan with an attendee of `role`, only across all the roles when it is empty
+func (a *Attendee) CountConnection(role string) {
+	a.scanCount("").Connections++
+	if len(role) > 0 {
+		a.scanCount(role).Connections++
+	}
+}
+
+//CountShare counts a scan the attendee shared their info in with an attendee of `role`, only across all the
+//roles when it is empty
+func (a *Attendee) CountShare(role string) {
+	a.scanCount("").Shares++
+	if len(role) > 0 {
+		a.scanCount(role).Shares++
+	}
+}
+
+//GetScanCount returns the connections and shares of the attendee with attendees of `role`, or of all the roles
+//when it is empty
+func (a *Attendee) GetScanCount(role string) ScanCount {
+	for _, c := range a.ScanCounts {
+		if c.Role == role {
+			return c
+		}
+	}
+	return ScanCount{Role: role}
+}
+
+func (a *Attendee) scanCount(role string) *ScanCount {
+	for i := range a.ScanCounts {
+		if a.ScanCounts[i].Role == role {
+			return &a.ScanCounts[i]
+		}
+	}
+	a.ScanCounts = append(a.ScanCounts, ScanCount{Role: role})
+	return &a.ScanCounts[len(a.ScanCounts)-1]
+}
+
 //AddWinning adds the winning to the array
 func (a *Attendee) AddWinning(winning *Win) (added bool) {
 	if winning == nil || winning.Claimed || a.containsWinning(winning) {
@@ -127,6 +214,15 @@ func (a *Attendee) GetAddress() sdk.AccAddress {
 	return a.Address
 }
 
+// GetRole returns the attendee's role. Attendees without a role are regular attendees
+//nolint:gocritic
+func (a *Attendee) GetRole() string {
+	if len(a.Role) == 0 {
+		return RoleAttendee
+	}
+	return a.Role
+}
+
 // GetRep returns the attendee's current rep
 //nolint:gocritic
 func (a *Attendee) GetRep() uint {
@@ -14,21 +14,21 @@ var _ = Describe("Attendee Tests", func() {
 	})
 
 	It("should fail if the adding id is nil or empty", func() {
-		attendee := types.NewAttendee("asdf", false)
+		attendee := types.NewAttendee("asdf", types.RoleAttendee)
 		added := attendee.AddScanID(nil)
 		Expect(added).To(BeFalse())
 	})
 
 	It("should succeed if the id is not in the array", func() {
 		b := []byte{1, 2, 3}
-		attendee := types.NewAttendee("asdf", false)
+		attendee := types.NewAttendee("asdf", types.RoleAttendee)
 		added := attendee.AddScanID(b)
 		Expect(added).To(BeTrue())
 	})
 
 	It("should fail when id already in scan ids", func() {
 		b := []byte{1, 2, 3}
-		attendee := types.NewAttendee("asdf", false)
+		attendee := types.NewAttendee("asdf", types.RoleAttendee)
 		added := attendee.AddScanID(b)
 		Expect(added).To(BeTrue())
 
@@ -41,7 +41,7 @@ var _ = Describe("Attendee Tests", func() {
 		s2 := util.IDToAddress("1234")
 		id, err := types.GenScanID(s1, s2)
 		Expect(err).To(BeNil())
-		attendee := types.NewAttendee("asdf", false)
+		attendee := types.NewAttendee("asdf", types.RoleAttendee)
 		added := attendee.AddScanID(id)
 		Expect(added).To(BeTrue())
 
@@ -61,7 +61,7 @@ var _ = Describe("Attendee Tests", func() {
 	})
 
 	It("should return the correct tier for the attendee", func() {
-		attendee := types.NewAttendee("asdf", false)
+		attendee := types.NewAttendee("asdf", types.RoleAttendee)
 		Expect(attendee.GetTier()).To(Equal(types.Tier0))
 
 		attendee.Rep = types.Tier1Rep
@@ -95,8 +95,25 @@ var _ = Describe("Attendee Tests", func() {
 		Expect(attendee.GetTier()).To(Equal(types.Tier9))
 	})
 
+	It("should record the times the tiers were reached", func() {
+		attendee := types.NewAttendee("1234", types.RoleAttendee)
+		attendee.SetTierReached(types.Tier0, 100)
+		Expect(attendee.TierTimes).To(BeEmpty())
+
+		attendee.SetTierReached(types.Tier2, 200)
+		Expect(attendee.TierTimes).To(Equal([]int64{0, 200}))
+
+		_, ok := attendee.TierReachedAt(types.Tier1)
+		Expect(ok).To(BeFalse())
+		reached, ok := attendee.TierReachedAt(types.Tier2)
+		Expect(ok).To(BeTrue())
+		Expect(reached).To(Equal(int64(200)))
+		_, ok = attendee.TierReachedAt(types.Tier3)
+		Expect(ok).To(BeFalse())
+	})
+
 	It("should refuse to add invalid win to winnings", func() {
-		attendee := types.NewAttendee("asdf", false)
+		attendee := types.NewAttendee("asdf", types.RoleAttendee)
 		Expect(len(attendee.Winnings)).To(Equal(0))
 
 		w := types.Win{
@@ -109,7 +126,7 @@ var _ = Describe("Attendee Tests", func() {
 	})
 
 	It("should add valid win to winnings", func() {
-		attendee := types.NewAttendee("asdf", false)
+		attendee := types.NewAttendee("asdf", types.RoleAttendee)
 		Expect(len(attendee.Winnings)).To(Equal(0))
 
 		w := types.Win{
@@ -126,7 +143,7 @@ var _ = Describe("Attendee Tests", func() {
 		var win types.Win
 		var attendee types.Attendee
 		BeforeEach(func() {
-			attendee = types.NewAttendee("asdf", false)
+			attendee = types.NewAttendee("asdf", types.RoleAttendee)
 			win = types.Win{
 				Tier:    types.Tier1,
 				Name:    "Name and stuff",
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	//MaxAttestationAge is how long after a scan its attestation can be synced
+	MaxAttestationAge = 24 * time.Hour
+	//MaxAttestationSkew is how far ahead of the block time the clock of a scanning phone can be
+	MaxAttestationSkew = 5 * time.Minute
+	//MaxAttestationNonceLength is the length limit of the nonce of a scan attestation
+	MaxAttestationNonceLength = 64
+)
+
+//ScanAttestation is a scan signed by the scanning attendee when their phone is offline. The phone
+//signs the scanned qr code, the data to share, its local time and a random nonce at scan time, and
+//the attestation is synced with a MsgScanAttestations once the phone is back online
+type ScanAttestation struct {
+	Scanner     sdk.AccAddress `json:"scanner"`
+	ScannedQR   string         `json:"scannedQR"`
+	Data        []byte         `json:"data,omitempty"`
+	UnixTimeSec int64          `json:"unixTimeSec"`
+	Nonce       string         `json:"nonce"`
+	Signature   []byte         `json:"signature"`
+}
+
+//AttestedScan records that `Scanner` attested the scan with `ScanID`, so that the attestation is not replayed
+type AttestedScan struct {
+	Scanner sdk.AccAddress `json:"scanner"`
+	ScanID  []byte         `json:"scan_id"`
+}
+
+//GenesisAttestedScans is the full array of attested scans for the genesis file
+type GenesisAttestedScans []AttestedScan
+
+//AttestationSignDoc is the document the scanning attendee signs
+type AttestationSignDoc struct {
+	ChainID     string `json:"chain_id"`
+	Scanner     string `json:"scanner"`
+	ScannedQR   string `json:"scanned_qr"`
+	Data        []byte `json:"data,omitempty"`
+	UnixTimeSec int64  `json:"unix_time_sec"`
+	Nonce       string `json:"nonce"`
+}
+
+//NewScanAttestation is the constructor for an unsigned `ScanAttestation`
+func NewScanAttestation(scanner sdk.AccAddress, qrCode string, data []byte, t time.Time,
+	nonce string) ScanAttestation {
+	return ScanAttestation{
+		Scanner:     scanner,
+		ScannedQR:   qrCode,
+		Data:        data,
+		UnixTimeSec: t.Unix(),
+		Nonce:       nonce,
+	}
+}
+
+//SignBytes returns the sorted json encoding of the sign doc of the attestation on the chain `chainID`
+//nolint:gocritic
+func (a ScanAttestation) SignBytes(chainID string) []byte {
+	bz, err := json.Marshal(AttestationSignDoc{
+		ChainID:     chainID,
+		Scanner:     a.Scanner.String(),
+		ScannedQR:   a.ScannedQR,
+		Data:        a.Data,
+		UnixTimeSec: a.UnixTimeSec,
+		Nonce:       a.Nonce,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+//Time returns the attested time of the scan
+//nolint:gocritic
+func (a ScanAttestation) Time() time.Time {
+	return time.Unix(a.UnixTimeSec, 0)
+}
+
+//ValidateBasic performs the stateless checks of the attestation. The signature is verified by the
+//handler against the public key of the scanner
+//nolint:gocritic
+func (a ScanAttestation) ValidateBasic() sdk.Error {
+	if a.Scanner.Empty() {
+		return sdk.ErrInvalidAddress(a.Scanner.String())
+	}
+	if !ValidQrCode(a.ScannedQR) {
+		return ErrQRCodeInvalid("attested QR code is invalid, should be a string of a positive integer")
+	}
+	if len(a.Data) > MaxDataSize {
+		return ErrDataSizeOverLimit("attested data size is over the limit of %d bytes", MaxDataSize)
+	}
+	if a.UnixTimeSec <= 0 {
+		return ErrInvalidAttestation("attested time must be positive")
+	}
+	if len(a.Nonce) == 0 || len(a.Nonce) > MaxAttestationNonceLength {
+		return ErrInvalidAttestation("nonce must be between 1 and %d characters", MaxAttestationNonceLength)
+	}
+	if len(a.Signature) == 0 {
+		return ErrInvalidAttestation("attestation is not signed")
+	}
+	return nil
+}
+
+//CheckWindow checks that the attested time is within `MaxAttestationAge` before and
+//`MaxAttestationSkew` after `blockTime`
+//nolint:gocritic
+func (a ScanAttestation) CheckWindow(blockTime time.Time) sdk.Error {
+	t := a.Time()
+	if t.Before(blockTime.Add(-MaxAttestationAge)) {
+		return ErrAttestationOutOfWindow("scan attested at %s is older than %s", t.UTC().Format(time.RFC3339),
+			MaxAttestationAge)
+	}
+	if t.After(blockTime.Add(MaxAttestationSkew)) {
+		return ErrAttestationOutOfWindow("scan attested at %s is more than %s ahead of the block time",
+			t.UTC().Format(time.RFC3339), MaxAttestationSkew)
+	}
+	return nil
+}
+
+//ValidateBasic checks that the attested scan is well formed
+//nolint:gocritic
+func (a AttestedScan) ValidateBasic() sdk.Error {
+	if a.Scanner.Empty() {
+		return sdk.ErrInvalidAddress(a.Scanner.String())
+	}
+	if len(a.ScanID) == 0 {
+		return ErrInvalidAttestation("attested scan id cannot be empty")
+	}
+	return nil
+}
+
+//AttestationError prefixes the message of `err` with the index of the failing attestation, keeping its code
+func AttestationError(index int, err sdk.Error) sdk.Error {
+	return sdk.NewError(err.Codespace(), err.Code(), "attestation %d: %s", index, err.Data())
+}
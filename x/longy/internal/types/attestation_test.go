@@ -0,0 +1,95 @@
+package types_test
+
+import (
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	. "github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scan Attestation Tests", func() {
+	var attestation ScanAttestation
+	var now = time.Unix(1570000000, 0)
+
+	BeforeEach(func() {
+		attestation = NewScanAttestation(util.IDToAddress("1234"), "5678", []byte("data"), now, "nonce")
+		attestation.Signature = []byte("sig")
+	})
+
+	It("should validate a signed attestation", func() {
+		Expect(attestation.ValidateBasic()).To(BeNil())
+		Expect(NewMsgScanAttestations(util.IDToAddress("1234"), []ScanAttestation{attestation}).ValidateBasic()).
+			To(BeNil())
+	})
+
+	It("should fail when the attestation is malformed", func() {
+		attestation.ScannedQR = "abc"
+		Expect(attestation.ValidateBasic().Code()).To(Equal(QRCodeInvalid))
+
+		attestation.ScannedQR = "5678"
+		attestation.Nonce = strings.Repeat("a", MaxAttestationNonceLength+1)
+		Expect(attestation.ValidateBasic().Code()).To(Equal(InvalidAttestation))
+
+		attestation.Nonce = "nonce"
+		attestation.Signature = nil
+		Expect(attestation.ValidateBasic().Code()).To(Equal(InvalidAttestation))
+	})
+
+	It("should fail when the message has no or too many attestations", func() {
+		msg := NewMsgScanAttestations(util.IDToAddress("1234"), nil)
+		Expect(msg.ValidateBasic().Code()).To(Equal(InvalidAttestation))
+
+		attestations := make([]ScanAttestation, MaxAttestations+1)
+		for i := range attestations {
+			attestations[i] = attestation
+		}
+		msg = NewMsgScanAttestations(util.IDToAddress("1234"), attestations)
+		Expect(msg.ValidateBasic().Code()).To(Equal(InvalidAttestation))
+	})
+
+	It("should report the index of the failing attestation", func() {
+		invalid := attestation
+		invalid.Data = make([]byte, MaxDataSize+1)
+		msg := NewMsgScanAttestations(util.IDToAddress("1234"), []ScanAttestation{attestation, invalid})
+		err := msg.ValidateBasic()
+		Expect(err.Code()).To(Equal(DataSizeOverLimit))
+		Expect(err.ABCILog()).To(ContainSubstring("attestation 1: "))
+	})
+
+	It("should bind the sign bytes to the chain and the attested scan", func() {
+		Expect(attestation.SignBytes("longy")).To(Equal(attestation.SignBytes("longy")))
+		Expect(attestation.SignBytes("longy")).ToNot(Equal(attestation.SignBytes("other")))
+
+		later := attestation
+		later.UnixTimeSec++
+		Expect(later.SignBytes("longy")).ToNot(Equal(attestation.SignBytes("longy")))
+	})
+
+	It("should only accept attested times within the window", func() {
+		Expect(attestation.CheckWindow(now)).To(BeNil())
+		Expect(attestation.CheckWindow(now.Add(MaxAttestationAge))).To(BeNil())
+		Expect(attestation.CheckWindow(now.Add(-MaxAttestationSkew))).To(BeNil())
+
+		Expect(attestation.CheckWindow(now.Add(MaxAttestationAge + time.Second)).Code()).
+			To(Equal(AttestationOutOfWindow))
+		Expect(attestation.CheckWindow(now.Add(-MaxAttestationSkew - time.Second)).Code()).
+			To(Equal(AttestationOutOfWindow))
+	})
+
+	It("should keep the earliest attested time of a scan", func() {
+		scan := Scan{}
+		Expect(scan.SetAttestedTime(100)).To(BeTrue())
+		Expect(scan.SetAttestedTime(200)).To(BeFalse())
+		Expect(scan.SetAttestedTime(50)).To(BeTrue())
+		Expect(scan.AttestedTimeSec).To(Equal(int64(50)))
+	})
+
+	It("should be signed by the sender", func() {
+		Expect(NewMsgScanAttestations(util.IDToAddress("1"), nil).GetSigners()).
+			To(Equal([]sdk.AccAddress{util.IDToAddress("1")}))
+	})
+})
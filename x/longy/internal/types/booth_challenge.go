@@ -0,0 +1,161 @@
+package types
+
+import (
+	"crypto/sha256"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+)
+
+const (
+	//MaxBoothChallengeIDLength is the length limit of the id of a booth challenge
+	MaxBoothChallengeIDLength = 64
+	//MaxBoothChallengeQuestionLength is the length limit of the question of a booth challenge
+	MaxBoothChallengeQuestionLength = 280
+	//MaxBoothChallengeAnswerLength is the length limit of an answer to a booth challenge
+	MaxBoothChallengeAnswerLength = 128
+	//MaxBoothChallengePoints is the most rep a booth challenge can award, a few sponsor shares
+	MaxBoothChallengePoints uint = 20
+)
+
+//boothChallengeID matches the ids of booth challenges, which are part of the paths of the challenge queries
+var boothChallengeID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,` + strconv.Itoa(MaxBoothChallengeIDLength) + `}$`)
+
+//BoothChallenge is a question asked by a sponsor at their booth. The sponsor commits to the answer with
+//NewBoothChallengeCommitment. Until the expiry the attendees commit to their own answer with
+//NewBoothAnswerCommitment, and after it every attendee revealing the right answer earns the points once.
+//Answers only become public once nobody can commit anymore
+type BoothChallenge struct {
+	ID          string          `json:"id"`
+	Sponsor     sdk.AccAddress  `json:"sponsor"`
+	Question    string          `json:"question"`
+	Commitment  util.Commitment `json:"commitment"`
+	Points      uint            `json:"points"`
+	Expiry      int64           `json:"expiry"` //unix time in seconds after which answers are refused
+	AnswerCount uint            `json:"answer_count"`
+}
+
+//GenesisBoothChallenges is the full array of booth challenges for the genesis file
+type GenesisBoothChallenges []BoothChallenge
+
+//BoothAnswer is the commitment of an attendee to their answer to a booth challenge, waiting for its reveal.
+//CommittedBy is the address the commitment is salted with when the attendee moved since
+type BoothAnswer struct {
+	ChallengeID string          `json:"challenge_id"`
+	Attendee    sdk.AccAddress  `json:"attendee"`
+	Commitment  util.Commitment `json:"commitment"`
+	CommittedBy sdk.AccAddress  `json:"committed_by,omitempty"`
+}
+
+//GenesisBoothAnswers is the full array of unrevealed booth answers for the genesis file
+type GenesisBoothAnswers []BoothAnswer
+
+//NewBoothChallenge is the constructor for `BoothChallenge`
+func NewBoothChallenge(id string, sponsor sdk.AccAddress, question string, commitment util.Commitment, points uint,
+	expiry time.Time) BoothChallenge {
+	return BoothChallenge{
+		ID:         id,
+		Sponsor:    sponsor,
+		Question:   question,
+		Commitment: commitment,
+		Points:     points,
+		Expiry:     expiry.Unix(),
+	}
+}
+
+//NormalizeAnswer lower cases the answer and collapses its white space, so that attendees are not
+//refused for typing "Proof of Stake " instead of "proof of stake"
+func NormalizeAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
+
+//NewBoothChallengeCommitment returns the commitment to the answer of the challenge `id`. The id salts the
+//answer so that challenges with the same answer have different commitments
+func NewBoothChallengeCommitment(id string, answer string) util.Commitment {
+	return util.NewCommitment([]byte(boothChallengeSecret(id, answer)))
+}
+
+//NewBoothAnswerCommitment returns the commitment of the attendee at `addr` to their answer to the challenge
+//`id`. The address salts the answer so that attendees cannot copy the commitments of others
+func NewBoothAnswerCommitment(id string, answer string, addr sdk.AccAddress) util.Commitment {
+	return util.NewCommitment(boothAnswerSecret(id, answer, addr))
+}
+
+func boothChallengeSecret(id string, answer string) string {
+	return id + ":" + NormalizeAnswer(answer)
+}
+
+func boothAnswerSecret(id string, answer string, addr sdk.AccAddress) []byte {
+	return append([]byte(boothChallengeSecret(id, answer)), addr...)
+}
+
+//ValidateBasic checks that the challenge is well formed
+//
+//nolint:gocritic
+func (c BoothChallenge) ValidateBasic() sdk.Error {
+	if !boothChallengeID.MatchString(c.ID) {
+		return ErrInvalidBoothChallenge("challenge id must be 1 to %d letters, digits, dashes or underscores",
+			MaxBoothChallengeIDLength)
+	}
+	if c.Sponsor.Empty() {
+		return sdk.ErrInvalidAddress(c.Sponsor.String())
+	}
+	if len(strings.TrimSpace(c.Question)) == 0 || len(c.Question) > MaxBoothChallengeQuestionLength {
+		return ErrInvalidBoothChallenge("question must be between 1 and %d characters", MaxBoothChallengeQuestionLength)
+	}
+	if c.Points == 0 || c.Points > MaxBoothChallengePoints {
+		return ErrInvalidBoothChallenge("points must be between 1 and %d, got %d", MaxBoothChallengePoints, c.Points)
+	}
+	if c.Expiry <= 0 {
+		return ErrInvalidBoothChallenge("expiry must be positive")
+	}
+	if c.Commitment.Len() != sha256.Size {
+		return ErrInvalidBoothChallenge("commitment must be the %d bytes sha256 of the answer", sha256.Size)
+	}
+	return nil
+}
+
+//VerifyAnswer indicates if `answer` is the answer the sponsor committed to
+//
+//nolint:gocritic
+func (c BoothChallenge) VerifyAnswer(answer string) bool {
+	return c.Commitment.VerifyReveal(boothChallengeSecret(c.ID, answer))
+}
+
+//ExpiryTime returns the time after which answers are revealed instead of committed
+//
+//nolint:gocritic
+func (c BoothChallenge) ExpiryTime() time.Time {
+	return time.Unix(c.Expiry, 0)
+}
+
+//ValidateBasic checks that the answer is well formed
+//
+//nolint:gocritic
+func (a BoothAnswer) ValidateBasic() sdk.Error {
+	if len(a.ChallengeID) == 0 {
+		return ErrInvalidBoothChallenge("challenge id cannot be empty")
+	}
+	if a.Attendee.Empty() {
+		return sdk.ErrInvalidAddress(a.Attendee.String())
+	}
+	if a.Commitment.Len() != sha256.Size {
+		return ErrInvalidBoothChallenge("answer commitment must be the %d bytes sha256 of the answer", sha256.Size)
+	}
+	return nil
+}
+
+//VerifyReveal indicates if `answer` is the answer the attendee committed to
+//
+//nolint:gocritic
+func (a BoothAnswer) VerifyReveal(answer string) bool {
+	addr := a.Attendee
+	if !a.CommittedBy.Empty() {
+		addr = a.CommittedBy
+	}
+	return a.Commitment.VerifyRevealBytes(boothAnswerSecret(a.ChallengeID, answer, addr))
+}
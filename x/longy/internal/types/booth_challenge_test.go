@@ -0,0 +1,89 @@
+package types_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eco/longy/util"
+	. "github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Booth Challenge Tests", func() {
+	var challenge BoothChallenge
+
+	BeforeEach(func() {
+		challenge = NewBoothChallenge("eco-1", util.IDToAddress("1234"), "What does Eco stand for?",
+			NewBoothChallengeCommitment("eco-1", "Economy"), 5, time.Unix(1570000000, 0))
+	})
+
+	It("should validate a well formed challenge", func() {
+		Expect(challenge.ValidateBasic()).To(BeNil())
+		msg := NewMsgCreateChallenge(challenge.Sponsor, challenge.ID, challenge.Question, challenge.Commitment,
+			challenge.Points, challenge.ExpiryTime())
+		Expect(msg.ValidateBasic()).To(BeNil())
+		Expect(msg.Challenge()).To(Equal(challenge))
+	})
+
+	It("should fail when the challenge is malformed", func() {
+		invalid := challenge
+		invalid.ID = "eco 1"
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+
+		invalid = challenge
+		invalid.Question = strings.Repeat("?", MaxBoothChallengeQuestionLength+1)
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+
+		invalid = challenge
+		invalid.Points = MaxBoothChallengePoints + 1
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+
+		invalid = challenge
+		invalid.Points = 0
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+
+		invalid = challenge
+		invalid.Commitment = util.Commitment("economy")
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+	})
+
+	It("should verify the normalized answer", func() {
+		Expect(challenge.VerifyAnswer("Economy")).To(BeTrue())
+		Expect(challenge.VerifyAnswer("  economy ")).To(BeTrue())
+		Expect(challenge.VerifyAnswer("ECONOMY")).To(BeTrue())
+		Expect(challenge.VerifyAnswer("ecology")).To(BeFalse())
+	})
+
+	It("should salt the commitment with the challenge id", func() {
+		Expect(NewBoothChallengeCommitment("eco-2", "Economy")).ToNot(Equal(challenge.Commitment))
+	})
+
+	It("should salt the answer commitment of an attendee with their address", func() {
+		a1, a2 := util.IDToAddress("1"), util.IDToAddress("2")
+		answer := BoothAnswer{ChallengeID: "eco-1", Attendee: a1,
+			Commitment: NewBoothAnswerCommitment("eco-1", " Economy", a1)}
+		Expect(answer.ValidateBasic()).To(BeNil())
+		Expect(answer.VerifyReveal("economy")).To(BeTrue())
+		Expect(answer.VerifyReveal("ecology")).To(BeFalse())
+		Expect(NewBoothAnswerCommitment("eco-1", "economy", a2)).ToNot(Equal(answer.Commitment))
+
+		msg := NewMsgCommitAnswer(a1, "eco-1", util.Commitment("economy"))
+		Expect(msg.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+	})
+
+	It("should fail when the answer is empty or too long", func() {
+		msg := NewMsgAnswerChallenge(util.IDToAddress("1"), "eco-1", "")
+		Expect(msg.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+
+		msg = NewMsgAnswerChallenge(util.IDToAddress("1"), "eco-1", strings.Repeat("a", MaxBoothChallengeAnswerLength+1))
+		Expect(msg.ValidateBasic().Code()).To(Equal(InvalidBoothChallenge))
+	})
+
+	It("should only record an answered challenge once", func() {
+		attendee := NewAttendee("1", RoleAttendee)
+		Expect(attendee.AddChallengeID("eco-1")).To(BeTrue())
+		Expect(attendee.AddChallengeID("eco-1")).To(BeFalse())
+		Expect(attendee.ChallengeIDs).To(Equal([]string{"eco-1"}))
+	})
+})
@@ -0,0 +1,158 @@
+package types
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+const (
+	//CheckpointSession is the kind of the checkpoints of talk sessions
+	CheckpointSession = "session"
+	//CheckpointBooth is the kind of the checkpoints of sponsor booths
+	CheckpointBooth = "booth"
+	//CheckpointTreasure is the kind of the hidden treasure hunt codes
+	CheckpointTreasure = "treasure"
+
+	//MaxCheckpointIDLength is the length limit of the id of a checkpoint
+	MaxCheckpointIDLength = 64
+	//MaxCheckpointNameLength is the length limit of the name of a checkpoint
+	MaxCheckpointNameLength = 140
+	//MaxCheckpointPoints is the most rep a checkpoint can award
+	MaxCheckpointPoints uint = 50
+)
+
+//checkpointID matches the ids of checkpoints, which are part of the paths of the checkpoint queries
+var checkpointID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,` + strconv.Itoa(MaxCheckpointIDLength) + `}$`)
+
+//Checkpoint is a scannable venue object, a talk session, a booth or a hidden treasure code. Its qr code
+//encodes its id and a private key whose public key is registered with the checkpoint. Scanning it signs
+//the CheckpointSignBytes of the attendee, so that scans sent on chain do not give the key away. Scanning
+//it between its start and end awards its points once per attendee, to the first `MaxClaims` attendees
+//when it is set
+type Checkpoint struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Kind       string        `json:"kind"`
+	PubKey     crypto.PubKey `json:"pub_key"`
+	Points     uint          `json:"points"`
+	Start      int64         `json:"start"` //unix time in seconds
+	End        int64         `json:"end"`   //unix time in seconds
+	MaxClaims  uint          `json:"max_claims,omitempty"`
+	ClaimCount uint          `json:"claim_count"`
+}
+
+//GenesisCheckpoints is the full array of venue checkpoints for the genesis file
+type GenesisCheckpoints []Checkpoint
+
+//CheckpointScan records the attendance of an attendee at a checkpoint
+type CheckpointScan struct {
+	CheckpointID string         `json:"checkpoint_id"`
+	Attendee     sdk.AccAddress `json:"attendee"`
+	UnixTimeSec  int64          `json:"unixTimeSec"`
+}
+
+//GenesisCheckpointScans is the full array of checkpoint attendance for the genesis file
+type GenesisCheckpointScans []CheckpointScan
+
+//CheckpointSignDoc is the document signed with the key of a checkpoint when an attendee scans it
+type CheckpointSignDoc struct {
+	ChainID      string `json:"chain_id"`
+	CheckpointID string `json:"checkpoint_id"`
+	Sender       string `json:"sender"`
+}
+
+//NewCheckpoint is the constructor for `Checkpoint`
+func NewCheckpoint(id string, name string, kind string, pubKey crypto.PubKey, points uint, start time.Time,
+	end time.Time, maxClaims uint) Checkpoint {
+	return Checkpoint{
+		ID:        id,
+		Name:      name,
+		Kind:      kind,
+		PubKey:    pubKey,
+		Points:    points,
+		Start:     start.Unix(),
+		End:       end.Unix(),
+		MaxClaims: maxClaims,
+	}
+}
+
+//ValidateBasic checks that the checkpoint is well formed
+//nolint:gocritic
+func (c Checkpoint) ValidateBasic() sdk.Error {
+	if !checkpointID.MatchString(c.ID) {
+		return ErrInvalidCheckpoint("checkpoint id must be 1 to %d letters, digits, dashes or underscores",
+			MaxCheckpointIDLength)
+	}
+	if len(strings.TrimSpace(c.Name)) == 0 || len(c.Name) > MaxCheckpointNameLength {
+		return ErrInvalidCheckpoint("name must be between 1 and %d characters", MaxCheckpointNameLength)
+	}
+	switch c.Kind {
+	case CheckpointSession, CheckpointBooth, CheckpointTreasure:
+	default:
+		return ErrInvalidCheckpoint("kind must be %s, %s or %s, got %s", CheckpointSession, CheckpointBooth,
+			CheckpointTreasure, c.Kind)
+	}
+	if c.Points == 0 || c.Points > MaxCheckpointPoints {
+		return ErrInvalidCheckpoint("points must be between 1 and %d, got %d", MaxCheckpointPoints, c.Points)
+	}
+	if c.Start <= 0 || c.End <= c.Start {
+		return ErrInvalidCheckpoint("end must be after a positive start")
+	}
+	if c.MaxClaims > 0 && c.ClaimCount > c.MaxClaims {
+		return ErrInvalidCheckpoint("claim count %d is over the max claims %d", c.ClaimCount, c.MaxClaims)
+	}
+	if c.PubKey == nil {
+		return ErrInvalidCheckpoint("public key cannot be empty")
+	}
+	return nil
+}
+
+//CheckpointSignBytes returns the sorted json encoding of the document signed when the attendee at `sender`
+//scans the checkpoint `id` on the chain `chainID`
+func CheckpointSignBytes(chainID string, id string, sender sdk.AccAddress) []byte {
+	bz, err := json.Marshal(CheckpointSignDoc{
+		ChainID:      chainID,
+		CheckpointID: id,
+		Sender:       sender.String(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+//VerifyScan indicates if `sig` is the signature of the scan of the checkpoint by the attendee at `sender`
+//nolint:gocritic
+func (c Checkpoint) VerifyScan(chainID string, sender sdk.AccAddress, sig []byte) bool {
+	return c.PubKey != nil && c.PubKey.VerifyBytes(CheckpointSignBytes(chainID, c.ID, sender), sig)
+}
+
+//IsActive indicates if the checkpoint can be scanned at `t`
+//nolint:gocritic
+func (c Checkpoint) IsActive(t time.Time) bool {
+	return !t.Before(c.StartTime()) && !t.After(c.EndTime())
+}
+
+//IsFull indicates if the checkpoint reached its max claims
+//nolint:gocritic
+func (c Checkpoint) IsFull() bool {
+	return c.MaxClaims > 0 && c.ClaimCount >= c.MaxClaims
+}
+
+//StartTime returns the time the checkpoint can be scanned from
+//nolint:gocritic
+func (c Checkpoint) StartTime() time.Time {
+	return time.Unix(c.Start, 0)
+}
+
+//EndTime returns the time the checkpoint can be scanned until
+//nolint:gocritic
+func (c Checkpoint) EndTime() time.Time {
+	return time.Unix(c.End, 0)
+}
@@ -0,0 +1,87 @@
+package types_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eco/longy/util"
+	. "github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var _ = Describe("Checkpoint Tests", func() {
+	var checkpoint Checkpoint
+	var key secp256k1.PrivKeySecp256k1
+	var start = time.Unix(1570000000, 0)
+	var end = start.Add(time.Hour)
+
+	BeforeEach(func() {
+		key = secp256k1.GenPrivKey()
+		checkpoint = NewCheckpoint("keynote", "Opening Keynote", CheckpointSession, key.PubKey(), 10, start, end, 2)
+	})
+
+	It("should validate a well formed checkpoint", func() {
+		Expect(checkpoint.ValidateBasic()).To(BeNil())
+		msg := NewMsgCreateCheckpoint(util.IDToAddress("1"), checkpoint.ID, checkpoint.Name, checkpoint.Kind,
+			checkpoint.PubKey, checkpoint.Points, start, end, checkpoint.MaxClaims)
+		Expect(msg.ValidateBasic()).To(BeNil())
+		Expect(msg.Checkpoint()).To(Equal(checkpoint))
+	})
+
+	It("should fail when the checkpoint is malformed", func() {
+		invalid := checkpoint
+		invalid.ID = "key/note"
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidCheckpoint))
+
+		invalid = checkpoint
+		invalid.Name = strings.Repeat("a", MaxCheckpointNameLength+1)
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidCheckpoint))
+
+		invalid = checkpoint
+		invalid.Kind = "party"
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidCheckpoint))
+
+		invalid = checkpoint
+		invalid.Points = MaxCheckpointPoints + 1
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidCheckpoint))
+
+		invalid = checkpoint
+		invalid.End = invalid.Start
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidCheckpoint))
+
+		invalid = checkpoint
+		invalid.PubKey = nil
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidCheckpoint))
+	})
+
+	It("should only be active within its time window", func() {
+		Expect(checkpoint.IsActive(start)).To(BeTrue())
+		Expect(checkpoint.IsActive(end)).To(BeTrue())
+		Expect(checkpoint.IsActive(start.Add(-time.Second))).To(BeFalse())
+		Expect(checkpoint.IsActive(end.Add(time.Second))).To(BeFalse())
+	})
+
+	It("should be full at its max claims unless unlimited", func() {
+		checkpoint.ClaimCount = 2
+		Expect(checkpoint.IsFull()).To(BeTrue())
+
+		checkpoint.MaxClaims = 0
+		Expect(checkpoint.IsFull()).To(BeFalse())
+	})
+
+	It("should verify scans signed with the key of the checkpoint for the sender and chain", func() {
+		sender := util.IDToAddress("1")
+		sig, err := key.Sign(CheckpointSignBytes("longy", checkpoint.ID, sender))
+		Expect(err).To(BeNil())
+		Expect(checkpoint.VerifyScan("longy", sender, sig)).To(BeTrue())
+		Expect(checkpoint.VerifyScan("other", sender, sig)).To(BeFalse())
+		Expect(checkpoint.VerifyScan("longy", util.IDToAddress("2"), sig)).To(BeFalse())
+	})
+
+	It("should fail when the scan is not signed", func() {
+		msg := NewMsgScanCheckpoint(util.IDToAddress("1"), "keynote", nil)
+		Expect(msg.ValidateBasic().Code()).To(Equal(InvalidCheckpoint))
+	})
+})
@@ -23,6 +23,14 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgClaimKey{}, RouterKey+"/MsgClaimKey", nil)
 	cdc.RegisterConcrete(MsgBonus{}, RouterKey+"/MsgBonus", nil)
 	cdc.RegisterConcrete(MsgClearBonus{}, RouterKey+"/MsgClearBonus", nil)
+	cdc.RegisterConcrete(MsgScanAttestations{}, RouterKey+"/MsgScanAttestations", nil)
+	cdc.RegisterConcrete(MsgCreateRaffle{}, RouterKey+"/MsgCreateRaffle", nil)
+	cdc.RegisterConcrete(MsgRevealRaffle{}, RouterKey+"/MsgRevealRaffle", nil)
+	cdc.RegisterConcrete(MsgCreateChallenge{}, RouterKey+"/MsgCreateChallenge", nil)
+	cdc.RegisterConcrete(MsgCommitAnswer{}, RouterKey+"/MsgCommitAnswer", nil)
+	cdc.RegisterConcrete(MsgAnswerChallenge{}, RouterKey+"/MsgAnswerChallenge", nil)
+	cdc.RegisterConcrete(MsgCreateCheckpoint{}, RouterKey+"/MsgCreateCheckpoint", nil)
+	cdc.RegisterConcrete(MsgScanCheckpoint{}, RouterKey+"/MsgScanCheckpoint", nil)
 
 	// register types
 	cdc.RegisterConcrete(Attendee{}, RouterKey+"/Attendee", nil)
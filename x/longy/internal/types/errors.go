@@ -102,6 +102,60 @@ const (
 	InvalidPublicKey
 	//ServiceAccountNotSet is the code for when the service account has not been set
 	ServiceAccountNotSet
+	//InvalidRole is the code for when a role rule is malformed
+	InvalidRole
+	//InvalidChallenge is the code for when a claim challenge is unknown, expired, consumed or issued to another desk
+	InvalidChallenge
+	//InvalidTxQuota is the code for when the transaction quota of the genesis file is malformed
+	InvalidTxQuota
+	//TxQuotaExceeded is the code for when an attendee account sent too many transactions in its quota window
+	TxQuotaExceeded
+	//MsgNotAllowed is the code for when a message is sent from an account that is not allowed to send it
+	MsgNotAllowed
+	//InvalidTx is the code for when a transaction posted to the broadcast proxy is malformed or not signed as expected
+	InvalidTx
+	//TxTooLarge is the code for when a transaction posted to the broadcast proxy is over the size limit
+	TxTooLarge
+	//RateLimited is the code for when a client or a signer posted too many transactions to the broadcast proxy
+	RateLimited
+	//InvalidAttestation is the code for when a scan attestation is malformed or not signed by its scanner
+	InvalidAttestation
+	//AttestationOutOfWindow is the code for when the time of a scan attestation is too far from the block time
+	AttestationOutOfWindow
+	//AttestationReplayed is the code for when the scanner of a scan attestation already attested the scan
+	AttestationReplayed
+	//InvalidRaffle is the code for when a raffle is malformed or cannot be created
+	InvalidRaffle
+	//RaffleNotFound is the code for when a raffle cannot be found
+	RaffleNotFound
+	//RaffleNotDrawable is the code for when a raffle is drawn before its entrants are snapshotted or drawn twice
+	RaffleNotDrawable
+	//InvalidBoothChallenge is the code for when a booth challenge is malformed or cannot be created
+	InvalidBoothChallenge
+	//BoothChallengeNotFound is the code for when a booth challenge does not exist
+	BoothChallengeNotFound
+	//BoothChallengeExpired is the code for when a booth challenge is answered after its expiry
+	BoothChallengeExpired
+	//BoothChallengeAnswered is the code for when an attendee answers a booth challenge twice
+	BoothChallengeAnswered
+	//InvalidCheckpoint is the code for when a venue checkpoint is malformed or cannot be created
+	InvalidCheckpoint
+	//CheckpointNotFound is the code for when a venue checkpoint does not exist
+	CheckpointNotFound
+	//CheckpointInactive is the code for when a venue checkpoint is scanned outside of its time window
+	CheckpointInactive
+	//CheckpointFull is the code for when a venue checkpoint reached its max claims
+	CheckpointFull
+	//CheckpointScanned is the code for when an attendee scans a venue checkpoint twice
+	CheckpointScanned
+	//InvalidAchievement is the code for when an achievement of the genesis file is malformed
+	InvalidAchievement
+	//InvalidTeamRules is the code for when the team rules of the genesis file are malformed
+	InvalidTeamRules
+	//BoothChallengeOpen is the code for when an answer to a booth challenge is revealed before its expiry
+	BoothChallengeOpen
+	//BoothAnswerNotCommitted is the code for when an answer to a booth challenge is revealed without a commitment
+	BoothAnswerNotCommitted
 
 	// DefaultError is the code for when a random error occurs that we do not provide a unique code to
 	DefaultError
@@ -288,6 +342,141 @@ func ErrServiceAccountNotSet(format string, args ...interface{}) sdk.Error {
 	return sdk.NewError(LongyCodeSpace, ServiceAccountNotSet, format, args...)
 }
 
+//ErrInvalidRole occurs when a role rule is malformed
+func ErrInvalidRole(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidRole, format, args...)
+}
+
+//ErrInvalidChallenge occurs when a claim challenge is unknown, expired, consumed or issued to another desk
+func ErrInvalidChallenge(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidChallenge, format, args...)
+}
+
+//ErrInvalidTxQuota occurs when the transaction quota of the genesis file is malformed
+func ErrInvalidTxQuota(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidTxQuota, format, args...)
+}
+
+//ErrTxQuotaExceeded occurs when an attendee account sent too many transactions in its quota window
+func ErrTxQuotaExceeded(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, TxQuotaExceeded, format, args...)
+}
+
+//ErrMsgNotAllowed occurs when a message is sent from an account that is not allowed to send it
+func ErrMsgNotAllowed(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, MsgNotAllowed, format, args...)
+}
+
+//ErrInvalidTx occurs when a transaction posted to the broadcast proxy is malformed or not signed as expected
+func ErrInvalidTx(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidTx, format, args...)
+}
+
+//ErrTxTooLarge occurs when a transaction posted to the broadcast proxy is over the size limit
+func ErrTxTooLarge(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, TxTooLarge, format, args...)
+}
+
+//ErrRateLimited occurs when a client or a signer posted too many transactions to the broadcast proxy
+func ErrRateLimited(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, RateLimited, format, args...)
+}
+
+//ErrInvalidAttestation occurs when a scan attestation is malformed or not signed by its scanner
+func ErrInvalidAttestation(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidAttestation, format, args...)
+}
+
+//ErrAttestationOutOfWindow occurs when the time of a scan attestation is too far from the block time
+func ErrAttestationOutOfWindow(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, AttestationOutOfWindow, format, args...)
+}
+
+//ErrAttestationReplayed occurs when the scanner of a scan attestation already attested the scan
+func ErrAttestationReplayed(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, AttestationReplayed, format, args...)
+}
+
+//ErrInvalidRaffle occurs when a raffle is malformed or cannot be created
+func ErrInvalidRaffle(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidRaffle, format, args...)
+}
+
+//ErrRaffleNotFound occurs when a raffle cannot be found
+func ErrRaffleNotFound(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, RaffleNotFound, format, args...)
+}
+
+//ErrRaffleNotDrawable occurs when a raffle is drawn before its entrants are snapshotted or drawn twice
+func ErrRaffleNotDrawable(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, RaffleNotDrawable, format, args...)
+}
+
+//ErrInvalidBoothChallenge occurs when a booth challenge is malformed or cannot be created
+func ErrInvalidBoothChallenge(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidBoothChallenge, format, args...)
+}
+
+//ErrBoothChallengeNotFound occurs when a booth challenge does not exist
+func ErrBoothChallengeNotFound(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, BoothChallengeNotFound, format, args...)
+}
+
+//ErrBoothChallengeExpired occurs when a booth challenge is answered after its expiry
+func ErrBoothChallengeExpired(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, BoothChallengeExpired, format, args...)
+}
+
+//ErrBoothChallengeAnswered occurs when an attendee answers a booth challenge twice
+func ErrBoothChallengeAnswered(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, BoothChallengeAnswered, format, args...)
+}
+
+//ErrInvalidCheckpoint occurs when a venue checkpoint is malformed or cannot be created
+func ErrInvalidCheckpoint(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidCheckpoint, format, args...)
+}
+
+//ErrCheckpointNotFound occurs when a venue checkpoint does not exist
+func ErrCheckpointNotFound(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, CheckpointNotFound, format, args...)
+}
+
+//ErrCheckpointInactive occurs when a venue checkpoint is scanned outside of its time window
+func ErrCheckpointInactive(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, CheckpointInactive, format, args...)
+}
+
+//ErrCheckpointFull occurs when a venue checkpoint reached its max claims
+func ErrCheckpointFull(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, CheckpointFull, format, args...)
+}
+
+//ErrCheckpointScanned occurs when an attendee scans a venue checkpoint twice
+func ErrCheckpointScanned(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, CheckpointScanned, format, args...)
+}
+
+//ErrInvalidAchievement occurs when an achievement of the genesis file is malformed
+func ErrInvalidAchievement(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidAchievement, format, args...)
+}
+
+//ErrInvalidTeamRules occurs when the team rules of the genesis file are malformed
+func ErrInvalidTeamRules(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, InvalidTeamRules, format, args...)
+}
+
+//ErrBoothChallengeOpen occurs when an answer to a booth challenge is revealed before its expiry
+func ErrBoothChallengeOpen(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, BoothChallengeOpen, format, args...)
+}
+
+//ErrBoothAnswerNotCommitted occurs when an answer to a booth challenge is revealed without a commitment
+func ErrBoothAnswerNotCommitted(format string, args ...interface{}) sdk.Error {
+	return sdk.NewError(LongyCodeSpace, BoothAnswerNotCommitted, format, args...)
+}
+
 //ErrDefault occurs when a random error occurs that we do not provide a unique code to
 func ErrDefault(format string, args ...interface{}) sdk.Error {
 	return sdk.NewError(LongyCodeSpace, DefaultError, format, args...)
@@ -3,6 +3,8 @@ package types
 import (
 	"bytes"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
 const (
@@ -23,8 +25,26 @@ const (
 	BonusServiceSeed = "bonus"
 	//ClaimServiceSeed is the seed for the claim service account
 	ClaimServiceSeed = "claim"
+
+	//KeyServiceKeyName is the keyring name of the generated key service account
+	KeyServiceKeyName = "key-service"
+	//BonusServiceKeyName is the keyring name of the generated bonus service account
+	BonusServiceKeyName = "bonus-service"
+	//ClaimServiceKeyName is the keyring name of the generated claim service account
+	ClaimServiceKeyName = "claim-service"
 )
 
+//IsWellKnownServiceKey indicates if `pubKey` is derived from one of the public service seeds.
+//These keys are only safe to use for local development
+func IsWellKnownServiceKey(pubKey crypto.PubKey) bool {
+	for _, seed := range []string{ServiceSeed, BonusServiceSeed, ClaimServiceSeed} {
+		if secp256k1.GenPrivKeySecp256k1([]byte(seed)).PubKey().Equals(pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	//AttendeePrefix is the prefix for the attendee type
 	AttendeePrefix = []byte{0x0}
@@ -40,6 +60,30 @@ var (
 	BonusServicePrefix = []byte{0x5}
 	//ClaimServicePrefix is the prefix for the account that sends claims
 	ClaimServicePrefix = []byte{0x6}
+	//RolePrefix is the prefix for the role rules
+	RolePrefix = []byte{0x7}
+	//AddressNamespacePrefix is the prefix for the event namespace attendee addresses are derived with
+	AddressNamespacePrefix = []byte{0x8}
+	//TxQuotaPrefix is the prefix for the transaction quota of attendee accounts
+	TxQuotaPrefix = []byte{0x9}
+	//TxUsagePrefix is the prefix for the transactions sent by an attendee account in its quota window
+	TxUsagePrefix = []byte{0xa}
+	//AttestedScanPrefix is the prefix for the scans attested by an attendee
+	AttestedScanPrefix = []byte{0xb}
+	//RafflePrefix is the prefix for the prize raffles
+	RafflePrefix = []byte{0xc}
+	//BoothChallengePrefix is the prefix for the booth challenges of the sponsors
+	BoothChallengePrefix = []byte{0xd}
+	//CheckpointPrefix is the prefix for the venue checkpoints
+	CheckpointPrefix = []byte{0xe}
+	//CheckpointScanPrefix is the prefix for the attendance of the venue checkpoints
+	CheckpointScanPrefix = []byte{0xf}
+	//AchievementPrefix is the prefix for the achievements
+	AchievementPrefix = []byte{0x10}
+	//TeamRulesPrefix is the prefix for the scoring rules of the team board
+	TeamRulesPrefix = []byte{0x11}
+	//BoothAnswerPrefix is the prefix for the answers committed to the booth challenges
+	BoothAnswerPrefix = []byte{0x12}
 	//KeySeparator is the separator between the prefix and the type key
 	KeySeparator = []byte("::")
 )
@@ -59,6 +103,11 @@ func PrizeKey(id []byte) []byte {
 	return PrefixKey(PrizePrefix, id)
 }
 
+//RoleKey returns the prefixed key for managing the rule of `role` in the store
+func RoleKey(role string) []byte {
+	return PrefixKey(RolePrefix, []byte(role))
+}
+
 // ServiceKey will return the store key for the service
 func ServiceKey() []byte {
 	return ServicePrefix
@@ -74,6 +123,77 @@ func ClaimServiceKey() []byte {
 	return ClaimServicePrefix
 }
 
+//AddressNamespaceKey returns the store key for the attendee address namespace
+func AddressNamespaceKey() []byte {
+	return AddressNamespacePrefix
+}
+
+//TxQuotaKey returns the store key for the transaction quota
+func TxQuotaKey() []byte {
+	return TxQuotaPrefix
+}
+
+//TeamRulesKey returns the store key for the scoring rules of the team board
+func TeamRulesKey() []byte {
+	return TeamRulesPrefix
+}
+
+//TxUsageKey returns the store key for the quota usage of the account at `addr`
+func TxUsageKey(addr sdk.AccAddress) []byte {
+	return PrefixKey(TxUsagePrefix, addr[:])
+}
+
+//AttestedScanKey returns the store key for the attestation of the scan with `scanID` by the attendee at `scanner`
+func AttestedScanKey(scanner sdk.AccAddress, scanID []byte) []byte {
+	key := make([]byte, 0, len(scanner)+len(KeySeparator)+len(scanID))
+	key = append(key, scanner...)
+	key = append(key, KeySeparator...)
+	key = append(key, scanID...)
+	return PrefixKey(AttestedScanPrefix, key)
+}
+
+//RaffleKey returns the store key for the raffle with `id`
+func RaffleKey(id string) []byte {
+	return PrefixKey(RafflePrefix, []byte(id))
+}
+
+//BoothChallengeKey returns the store key for the booth challenge with `id`
+func BoothChallengeKey(id string) []byte {
+	return PrefixKey(BoothChallengePrefix, []byte(id))
+}
+
+//BoothAnswerKey returns the store key for the answer of the attendee at `addr` to the booth challenge `id`
+func BoothAnswerKey(id string, addr sdk.AccAddress) []byte {
+	key := make([]byte, 0, len(id)+len(KeySeparator)+len(addr))
+	key = append(key, id...)
+	key = append(key, KeySeparator...)
+	key = append(key, addr...)
+	return PrefixKey(BoothAnswerPrefix, key)
+}
+
+//CheckpointKey returns the store key for the venue checkpoint with `id`
+func CheckpointKey(id string) []byte {
+	return PrefixKey(CheckpointPrefix, []byte(id))
+}
+
+//CheckpointScansPrefix returns the store prefix of the attendance of the venue checkpoint with `id`
+func CheckpointScansPrefix(id string) []byte {
+	key := make([]byte, 0, len(id)+len(KeySeparator))
+	key = append(key, id...)
+	key = append(key, KeySeparator...)
+	return PrefixKey(CheckpointScanPrefix, key)
+}
+
+//CheckpointScanKey returns the store key for the scan of the venue checkpoint `id` by the attendee at `addr`
+func CheckpointScanKey(id string, addr sdk.AccAddress) []byte {
+	return append(CheckpointScansPrefix(id), addr...)
+}
+
+//AchievementKey returns the store key for the achievement with `id`
+func AchievementKey(id string) []byte {
+	return PrefixKey(AchievementPrefix, []byte(id))
+}
+
 // BonusKey -
 func BonusKey() []byte {
 	return BonusKeyPrefix
@@ -89,6 +209,11 @@ func IsScanKey(key []byte) bool {
 	return isKeyOf(key, ScanPrefix)
 }
 
+//IsRoleKey checks the key to see if its for a role rule by checking it starts with the RolePrefix
+func IsRoleKey(key []byte) bool {
+	return isKeyOf(key, RolePrefix)
+}
+
 func isKeyOf(key []byte, prefix []byte) bool {
 	l := len(prefix)
 	if len(key) < l {
@@ -6,6 +6,7 @@ import (
 	"github.com/eco/longy/x/longy/internal/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
 var _ = Describe("Key Tests", func() {
@@ -66,4 +67,17 @@ var _ = Describe("Key Tests", func() {
 			Expect(types.IsScanKey(key)).To(BeTrue())
 		})
 	})
+
+	Context("service keys", func() {
+		It("should flag the keys derived from the public seeds", func() {
+			for _, seed := range []string{types.ServiceSeed, types.BonusServiceSeed, types.ClaimServiceSeed} {
+				key := secp256k1.GenPrivKeySecp256k1([]byte(seed))
+				Expect(types.IsWellKnownServiceKey(key.PubKey())).To(BeTrue())
+			}
+		})
+
+		It("should not flag generated keys", func() {
+			Expect(types.IsWellKnownServiceKey(secp256k1.GenPrivKey().PubKey())).To(BeFalse())
+		})
+	})
 })
@@ -1,6 +1,11 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
 
 const (
 	//LeaderBoardCount is the total number of attendees on the board
@@ -11,6 +16,16 @@ const (
 	LeaderBoardTier2Prize = 2000
 	//LeaderBoardTier1Count is the number of people in the first tier
 	LeaderBoardTier1Count = 10
+
+	//LeaderSponsors ranks the attendees by the distinct sponsors they scanned or were scanned by
+	LeaderSponsors = "sponsors"
+	//LeaderShares ranks the attendees by the scans they shared their info in
+	LeaderShares = "shares"
+	//LeaderConnections ranks the attendees by their accepted scans
+	LeaderConnections = "connections"
+	//LeaderTierPrefix prefixes the tier of the categories ranking the fastest attendees to reach a tier,
+	//`tier1` to `tier9`
+	LeaderTierPrefix = "tier"
 )
 
 //Tier is a prize tier in the leader  board
@@ -58,3 +73,63 @@ func NewLeaderBoard(count int, top []Attendee) *LeaderBoard { // test this
 		},
 	}
 }
+
+//RankedAttendee is an attendee on a category board with their score in the category
+type RankedAttendee struct {
+	ID      string         `json:"id"`
+	Address sdk.AccAddress `json:"address"`
+	Name    string         `json:"name,omitempty"`
+	Score   int64          `json:"score"` //a count, or the seconds since the claim for the tier categories
+}
+
+//CategoryTier is a tier in a category board
+type CategoryTier struct {
+	Attendees []RankedAttendee `json:"attendees"`
+}
+
+//CategoryLeaderBoard is the leader board of a category, with the same tiers as the `LeaderBoard` but no
+//prize pools
+type CategoryLeaderBoard struct {
+	Category   string       `json:"category"`
+	TotalCount int          `json:"totalCount"`
+	Tier1      CategoryTier `json:"tier1"`
+	Tier2      CategoryTier `json:"tier2"`
+	Time       time.Time    `json:"time"`
+}
+
+//NewCategoryLeaderBoard returns the board of the `top` attendees of the category, cut to the board size
+func NewCategoryLeaderBoard(category string, count int, top []RankedAttendee) *CategoryLeaderBoard {
+	if len(top) > LeaderBoardCount {
+		top = top[:LeaderBoardCount]
+	}
+
+	first, second := top, []RankedAttendee(nil)
+	if len(top) > LeaderBoardTier1Count {
+		first = make([]RankedAttendee, LeaderBoardTier1Count)
+		copy(first, top)
+		second = make([]RankedAttendee, len(top)-LeaderBoardTier1Count)
+		copy(second, top[LeaderBoardTier1Count:])
+	}
+
+	return &CategoryLeaderBoard{
+		Category:   category,
+		TotalCount: count,
+		Tier1:      CategoryTier{Attendees: first},
+		Tier2:      CategoryTier{Attendees: second},
+	}
+}
+
+//LeaderTierCategory returns the category ranking the fastest attendees to reach `tier`
+func LeaderTierCategory(tier uint) string {
+	return fmt.Sprintf("%s%d", LeaderTierPrefix, tier)
+}
+
+//ParseLeaderTierCategory returns the tier of a tier category, false if `category` is not one
+func ParseLeaderTierCategory(category string) (uint, bool) {
+	for tier := Tier1; tier <= Tier9; tier++ {
+		if category == LeaderTierCategory(tier) {
+			return tier, true
+		}
+	}
+	return 0, false
+}
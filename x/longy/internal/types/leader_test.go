@@ -56,4 +56,28 @@ var _ = Describe("Leader Tests", func() {
 		Expect(len(board.Tier1.Attendees)).To(Equal(types.LeaderBoardTier1Count))
 		Expect(len(board.Tier2.Attendees)).To(Equal(types.LeaderBoardCount - types.LeaderBoardTier1Count))
 	})
+
+	It("should split the category board in the tiers of the leader board", func() {
+		board := types.NewCategoryLeaderBoard(types.LeaderShares, 100, make([]types.RankedAttendee, 5))
+		Expect(board.Category).To(Equal(types.LeaderShares))
+		Expect(len(board.Tier1.Attendees)).To(Equal(5))
+		Expect(len(board.Tier2.Attendees)).To(Equal(0))
+
+		board = types.NewCategoryLeaderBoard(types.LeaderShares, 100,
+			make([]types.RankedAttendee, types.LeaderBoardCount*2))
+		Expect(len(board.Tier1.Attendees)).To(Equal(types.LeaderBoardTier1Count))
+		Expect(len(board.Tier2.Attendees)).To(Equal(types.LeaderBoardCount - types.LeaderBoardTier1Count))
+	})
+
+	It("should parse the tier categories", func() {
+		tier, ok := types.ParseLeaderTierCategory(types.LeaderTierCategory(types.Tier3))
+		Expect(ok).To(BeTrue())
+		Expect(tier).To(Equal(types.Tier3))
+		Expect(types.TierRep(tier)).To(Equal(types.Tier3Rep))
+
+		_, ok = types.ParseLeaderTierCategory("tier0")
+		Expect(ok).To(BeFalse())
+		_, ok = types.ParseLeaderTierCategory(types.LeaderShares)
+		Expect(ok).To(BeFalse())
+	})
 })
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+)
+
+var _ sdk.Msg = MsgCreateChallenge{}
+var _ sdk.Msg = MsgCommitAnswer{}
+var _ sdk.Msg = MsgAnswerChallenge{}
+
+// MsgCreateChallenge is used by a sponsor to ask a question at their booth
+type MsgCreateChallenge struct {
+	Sender     sdk.AccAddress  `json:"sender"` //Standard for all messages
+	ID         string          `json:"id"`
+	Question   string          `json:"question"`
+	Commitment util.Commitment `json:"commitment"`
+	Points     uint            `json:"points"`
+	Expiry     int64           `json:"expiry"`
+}
+
+// NewMsgCreateChallenge is the constructor function for MsgCreateChallenge. The commitment is created
+// with NewBoothChallengeCommitment
+func NewMsgCreateChallenge(sender sdk.AccAddress, id string, question string, commitment util.Commitment,
+	points uint, expiry time.Time) MsgCreateChallenge {
+	return MsgCreateChallenge{
+		Sender:     sender,
+		ID:         id,
+		Question:   question,
+		Commitment: commitment,
+		Points:     points,
+		Expiry:     expiry.Unix(),
+	}
+}
+
+// Challenge returns the booth challenge created by the message
+//nolint:gocritic
+func (msg MsgCreateChallenge) Challenge() BoothChallenge {
+	return NewBoothChallenge(msg.ID, msg.Sender, msg.Question, msg.Commitment, msg.Points,
+		time.Unix(msg.Expiry, 0))
+}
+
+// Route defines the route for this message
+//nolint:gocritic
+func (msg MsgCreateChallenge) Route() string {
+	return RouterKey
+}
+
+// Type is the message type
+//nolint:gocritic
+func (msg MsgCreateChallenge) Type() string {
+	return "create_challenge"
+}
+
+// ValidateBasic performs sanity checks on the message
+//nolint:gocritic
+func (msg MsgCreateChallenge) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+
+	return msg.Challenge().ValidateBasic()
+}
+
+// GetSignBytes returns the byte array that is signed over
+//nolint:gocritic
+func (msg MsgCreateChallenge) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgCreateChallenge) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgCommitAnswer is used by an attendee to commit to their answer to a booth challenge before its expiry
+type MsgCommitAnswer struct {
+	Sender     sdk.AccAddress  `json:"sender"` //Standard for all messages
+	ID         string          `json:"id"`
+	Commitment util.Commitment `json:"commitment"`
+}
+
+// NewMsgCommitAnswer is the constructor function for MsgCommitAnswer. The commitment is created with
+// NewBoothAnswerCommitment
+func NewMsgCommitAnswer(sender sdk.AccAddress, id string, commitment util.Commitment) MsgCommitAnswer {
+	return MsgCommitAnswer{
+		Sender:     sender,
+		ID:         id,
+		Commitment: commitment,
+	}
+}
+
+// Route defines the route for this message
+//nolint:gocritic
+func (msg MsgCommitAnswer) Route() string {
+	return RouterKey
+}
+
+// Type is the message type
+//nolint:gocritic
+func (msg MsgCommitAnswer) Type() string {
+	return "commit_answer"
+}
+
+// ValidateBasic performs sanity checks on the message
+//nolint:gocritic
+func (msg MsgCommitAnswer) ValidateBasic() sdk.Error {
+	return BoothAnswer{ChallengeID: msg.ID, Attendee: msg.Sender, Commitment: msg.Commitment}.ValidateBasic()
+}
+
+// GetSignBytes returns the byte array that is signed over
+//nolint:gocritic
+func (msg MsgCommitAnswer) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgCommitAnswer) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgAnswerChallenge is used by an attendee to reveal their committed answer to a booth challenge after its
+// expiry
+type MsgAnswerChallenge struct {
+	Sender sdk.AccAddress `json:"sender"` //Standard for all messages
+	ID     string         `json:"id"`
+	Answer string         `json:"answer"`
+}
+
+// NewMsgAnswerChallenge is the constructor function for MsgAnswerChallenge
+func NewMsgAnswerChallenge(sender sdk.AccAddress, id string, answer string) MsgAnswerChallenge {
+	return MsgAnswerChallenge{
+		Sender: sender,
+		ID:     id,
+		Answer: answer,
+	}
+}
+
+// Route defines the route for this message
+//nolint:gocritic
+func (msg MsgAnswerChallenge) Route() string {
+	return RouterKey
+}
+
+// Type is the message type
+//nolint:gocritic
+func (msg MsgAnswerChallenge) Type() string {
+	return "answer_challenge"
+}
+
+// ValidateBasic performs sanity checks on the message
+//nolint:gocritic
+func (msg MsgAnswerChallenge) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+
+	if len(msg.ID) == 0 {
+		return ErrInvalidBoothChallenge("challenge id cannot be empty")
+	}
+
+	if len(msg.Answer) == 0 || len(msg.Answer) > MaxBoothChallengeAnswerLength {
+		return ErrInvalidBoothChallenge("answer must be between 1 and %d characters", MaxBoothChallengeAnswerLength)
+	}
+
+	return nil
+}
+
+// GetSignBytes returns the byte array that is signed over
+//nolint:gocritic
+func (msg MsgAnswerChallenge) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgAnswerChallenge) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+var _ sdk.Msg = MsgCreateCheckpoint{}
+var _ sdk.Msg = MsgScanCheckpoint{}
+
+// MsgCreateCheckpoint is used by the master service to register a venue checkpoint
+type MsgCreateCheckpoint struct {
+	Sender    sdk.AccAddress `json:"sender"` //Standard for all messages
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Kind      string         `json:"kind"`
+	PubKey    crypto.PubKey  `json:"pub_key"`
+	Points    uint           `json:"points"`
+	Start     int64          `json:"start"`
+	End       int64          `json:"end"`
+	MaxClaims uint           `json:"max_claims,omitempty"`
+}
+
+// NewMsgCreateCheckpoint is the constructor function for MsgCreateCheckpoint. The private key of `pubKey`
+// goes in the qr code of the checkpoint
+func NewMsgCreateCheckpoint(sender sdk.AccAddress, id string, name string, kind string,
+	pubKey crypto.PubKey, points uint, start time.Time, end time.Time, maxClaims uint) MsgCreateCheckpoint {
+	return MsgCreateCheckpoint{
+		Sender:    sender,
+		ID:        id,
+		Name:      name,
+		Kind:      kind,
+		PubKey:    pubKey,
+		Points:    points,
+		Start:     start.Unix(),
+		End:       end.Unix(),
+		MaxClaims: maxClaims,
+	}
+}
+
+// Checkpoint returns the venue checkpoint registered by the message
+//nolint:gocritic
+func (msg MsgCreateCheckpoint) Checkpoint() Checkpoint {
+	return NewCheckpoint(msg.ID, msg.Name, msg.Kind, msg.PubKey, msg.Points, time.Unix(msg.Start, 0),
+		time.Unix(msg.End, 0), msg.MaxClaims)
+}
+
+// Route defines the route for this message
+//nolint:gocritic
+func (msg MsgCreateCheckpoint) Route() string {
+	return RouterKey
+}
+
+// Type is the message type
+//nolint:gocritic
+func (msg MsgCreateCheckpoint) Type() string {
+	return "create_checkpoint"
+}
+
+// ValidateBasic performs sanity checks on the message
+//nolint:gocritic
+func (msg MsgCreateCheckpoint) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+
+	return msg.Checkpoint().ValidateBasic()
+}
+
+// GetSignBytes returns the byte array that is signed over
+//nolint:gocritic
+func (msg MsgCreateCheckpoint) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgCreateCheckpoint) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgScanCheckpoint is used by an attendee to scan the qr code of a venue checkpoint. The signature is made
+// with the private key of the qr code over the CheckpointSignBytes of the sender
+type MsgScanCheckpoint struct {
+	Sender    sdk.AccAddress `json:"sender"` //Standard for all messages
+	ID        string         `json:"id"`
+	Signature []byte         `json:"signature"`
+}
+
+// NewMsgScanCheckpoint is the constructor function for MsgScanCheckpoint
+func NewMsgScanCheckpoint(sender sdk.AccAddress, id string, signature []byte) MsgScanCheckpoint {
+	return MsgScanCheckpoint{
+		Sender:    sender,
+		ID:        id,
+		Signature: signature,
+	}
+}
+
+// Route defines the route for this message
+//nolint:gocritic
+func (msg MsgScanCheckpoint) Route() string {
+	return RouterKey
+}
+
+// Type is the message type
+//nolint:gocritic
+func (msg MsgScanCheckpoint) Type() string {
+	return "scan_checkpoint"
+}
+
+// ValidateBasic performs sanity checks on the message
+//nolint:gocritic
+func (msg MsgScanCheckpoint) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+
+	if len(msg.ID) == 0 {
+		return ErrInvalidCheckpoint("checkpoint id cannot be empty")
+	}
+
+	if len(msg.Signature) == 0 {
+		return ErrInvalidCheckpoint("scan is not signed with the key of the checkpoint")
+	}
+
+	return nil
+}
+
+// GetSignBytes returns the byte array that is signed over
+//nolint:gocritic
+func (msg MsgScanCheckpoint) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgScanCheckpoint) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+)
+
+var _ sdk.Msg = MsgCreateRaffle{}
+var _ sdk.Msg = MsgRevealRaffle{}
+
+// MsgCreateRaffle is used by the bonus service to open a raffle for the prizes of a tier
+type MsgCreateRaffle struct {
+	Sender         sdk.AccAddress  `json:"sender"` //Standard for all messages
+	ID             string          `json:"id"`
+	PrizeTier      uint            `json:"prize_tier"`
+	WinnerCount    uint            `json:"winner_count"`
+	MinTier        uint            `json:"min_tier"`
+	MinRep         uint            `json:"min_rep"`
+	Deadline       int64           `json:"deadline"`
+	RevealDeadline int64           `json:"reveal_deadline"`
+	Commitment     util.Commitment `json:"commitment"`
+}
+
+// NewMsgCreateRaffle is the constructor function for MsgCreateRaffle
+func NewMsgCreateRaffle(sender sdk.AccAddress, id string, prizeTier uint, winnerCount uint, minTier uint,
+	minRep uint, deadline time.Time, revealDeadline time.Time, commitment util.Commitment) MsgCreateRaffle {
+	return MsgCreateRaffle{
+		Sender:         sender,
+		ID:             id,
+		PrizeTier:      prizeTier,
+		WinnerCount:    winnerCount,
+		MinTier:        minTier,
+		MinRep:         minRep,
+		Deadline:       deadline.Unix(),
+		RevealDeadline: revealDeadline.Unix(),
+		Commitment:     commitment,
+	}
+}
+
+// Raffle returns the open raffle created by the message
+//nolint:gocritic
+func (msg MsgCreateRaffle) Raffle() Raffle {
+	return NewRaffle(msg.ID, msg.PrizeTier, msg.WinnerCount, msg.MinTier, msg.MinRep,
+		time.Unix(msg.Deadline, 0), time.Unix(msg.RevealDeadline, 0), msg.Commitment)
+}
+
+// Route defines the route for this message
+//nolint:gocritic
+func (msg MsgCreateRaffle) Route() string {
+	return RouterKey
+}
+
+// Type is the message type
+//nolint:gocritic
+func (msg MsgCreateRaffle) Type() string {
+	return "create_raffle"
+}
+
+// ValidateBasic performs sanity checks on the message
+//nolint:gocritic
+func (msg MsgCreateRaffle) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+
+	return msg.Raffle().ValidateBasic()
+}
+
+// GetSignBytes returns the byte array that is signed over
+//nolint:gocritic
+func (msg MsgCreateRaffle) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgCreateRaffle) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgRevealRaffle is used by the bonus service to reveal the secret of a raffle and draw its winners
+type MsgRevealRaffle struct {
+	Sender sdk.AccAddress `json:"sender"` //Standard for all messages
+	ID     string         `json:"id"`
+	Secret string         `json:"secret"`
+}
+
+// NewMsgRevealRaffle is the constructor function for MsgRevealRaffle
+func NewMsgRevealRaffle(sender sdk.AccAddress, id string, secret string) MsgRevealRaffle {
+	return MsgRevealRaffle{
+		Sender: sender,
+		ID:     id,
+		Secret: secret,
+	}
+}
+
+// Route defines the route for this message
+//nolint:gocritic
+func (msg MsgRevealRaffle) Route() string {
+	return RouterKey
+}
+
+// Type is the message type
+//nolint:gocritic
+func (msg MsgRevealRaffle) Type() string {
+	return "reveal_raffle"
+}
+
+// ValidateBasic performs sanity checks on the message
+//nolint:gocritic
+func (msg MsgRevealRaffle) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+
+	if len(msg.ID) == 0 {
+		return ErrInvalidRaffle("raffle id cannot be empty")
+	}
+
+	if len(msg.Secret) == 0 {
+		return ErrInvalidCommitmentReveal("secret cannot be empty")
+	}
+
+	return nil
+}
+
+// GetSignBytes returns the byte array that is signed over
+//nolint:gocritic
+func (msg MsgRevealRaffle) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgRevealRaffle) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	//MaxAttestations is the number of attestations a MsgScanAttestations can carry
+	MaxAttestations = 50
+)
+
+// MsgScanAttestations syncs the scans attested while the phone of the sender was offline. Every
+// attestation is signed by its scanner, so the sender can also relay the attestations of others
+type MsgScanAttestations struct {
+	Sender       sdk.AccAddress    `json:"sender"` //Standard for all messages
+	Attestations []ScanAttestation `json:"attestations"`
+}
+
+// NewMsgScanAttestations is the constructor function for MsgScanAttestations
+func NewMsgScanAttestations(sender sdk.AccAddress, attestations []ScanAttestation) MsgScanAttestations {
+	return MsgScanAttestations{
+		Sender:       sender,
+		Attestations: attestations,
+	}
+}
+
+// Route string for this message
+//nolint:gocritic
+func (msg MsgScanAttestations) Route() string { return RouterKey }
+
+// Type returns the message type, used to tagging transactions
+//nolint:gocritic
+func (msg MsgScanAttestations) Type() string { return "scan_attestations" }
+
+// ValidateBasic performs basic checks of the message
+//nolint:gocritic
+func (msg MsgScanAttestations) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+
+	if len(msg.Attestations) == 0 {
+		return ErrInvalidAttestation("no attestations to sync")
+	}
+
+	if len(msg.Attestations) > MaxAttestations {
+		return ErrInvalidAttestation("%d attestations are over the limit of %d", len(msg.Attestations),
+			MaxAttestations)
+	}
+
+	for i, attestation := range msg.Attestations {
+		if err := attestation.ValidateBasic(); err != nil {
+			return AttestationError(i, err)
+		}
+	}
+	return nil
+}
+
+// GetSignBytes returns byte representation of the message
+//nolint:gocritic
+func (msg MsgScanAttestations) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the signers of this message for the authentication module
+//nolint:gocritic
+func (msg MsgScanAttestations) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
@@ -50,6 +50,15 @@ const (
 	Tier9
 )
 
+//TierRep returns the rep needed to be in `tier`
+func TierRep(tier uint) uint {
+	reps := []uint{0, Tier1Rep, Tier2Rep, Tier3Rep, Tier4Rep, Tier5Rep, Tier6Rep, Tier7Rep, Tier8Rep, Tier9Rep}
+	if tier >= uint(len(reps)) {
+		return reps[len(reps)-1]
+	}
+	return reps[tier]
+}
+
 //Prize is the genesis type for the prizes
 type Prize struct {
 	Tier             uint   `json:"tier"`
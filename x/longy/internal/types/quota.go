@@ -0,0 +1,54 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	//DefaultMaxTxs is the default number of transactions an attendee account can send per window
+	DefaultMaxTxs = 30
+	//DefaultWindowSeconds is the default length of the quota window
+	DefaultWindowSeconds = 60
+)
+
+//TxQuota limits the transactions attendee accounts can send. Transactions are free, so every
+//attendee account can send `MaxTxs` of them within `WindowSeconds` of block time. A zero `MaxTxs`
+//disables the quota and a zero `MaxDataBytes` the size limit of scan and info data
+type TxQuota struct {
+	MaxTxs        uint  `json:"max_txs"`
+	WindowSeconds int64 `json:"window_seconds"`
+	MaxDataBytes  int   `json:"max_data_bytes"`
+}
+
+//DefaultTxQuota returns the quota of chains that do not set one in their genesis file
+func DefaultTxQuota() TxQuota {
+	return TxQuota{
+		MaxTxs:        DefaultMaxTxs,
+		WindowSeconds: DefaultWindowSeconds,
+		MaxDataBytes:  MaxDataSize,
+	}
+}
+
+//Window is the length of the quota window
+func (q TxQuota) Window() time.Duration {
+	return time.Duration(q.WindowSeconds) * time.Second
+}
+
+//ValidateBasic checks that the quota is well formed
+func (q TxQuota) ValidateBasic() sdk.Error {
+	if q.MaxTxs > 0 && q.WindowSeconds <= 0 {
+		return ErrInvalidTxQuota("the quota window must be positive, got %d seconds", q.WindowSeconds)
+	}
+	if q.MaxDataBytes < 0 {
+		return ErrInvalidTxQuota("the data size limit cannot be negative, got %d bytes", q.MaxDataBytes)
+	}
+	return nil
+}
+
+//TxUsage is the number of transactions an account sent since the start of its quota window
+type TxUsage struct {
+	WindowStart time.Time `json:"window_start"`
+	Count       uint      `json:"count"`
+}
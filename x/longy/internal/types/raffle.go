@@ -0,0 +1,162 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"regexp"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+const (
+	//RaffleOpen is the status of a raffle waiting for its deadline
+	RaffleOpen = "open"
+	//RaffleSnapshotted is the status of a raffle whose entrants are snapshotted, waiting for the reveal
+	RaffleSnapshotted = "snapshotted"
+	//RaffleDrawn is the status of a raffle whose winners are drawn
+	RaffleDrawn = "drawn"
+	//RaffleExpired is the status of a raffle that was not revealed by its reveal deadline
+	RaffleExpired = "expired"
+
+	//MaxRaffleIDLength is the length limit of the id of a raffle
+	MaxRaffleIDLength = 64
+
+	//DefaultRaffleRevealWindow is the time the bonus service has after the deadline of a raffle to reveal it
+	DefaultRaffleRevealWindow = 24 * time.Hour
+)
+
+//raffleID matches the ids of raffles, which are part of the paths of the raffle queries
+var raffleID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,` + strconv.Itoa(MaxRaffleIDLength) + `}$`)
+
+//Raffle draws the prizes of a tier among the attendees eligible at its deadline, instead of awarding them
+//to the first attendees reaching the tier. The admin commits to a secret when creating the raffle and the
+//eligible attendees are snapshotted at the deadline. The hash of the snapshot block is recorded once it is
+//committed, and the reveal of the secret combined with that hash seeds the draw. The admin cannot choose
+//the hash and the proposer of the snapshot block does not know the secret, but the admin learns the outcome
+//before revealing and can withhold the reveal: the reserved prizes then go back to their tier at the reveal
+//deadline instead of being drawn
+type Raffle struct {
+	ID             string          `json:"id"`
+	PrizeTier      uint            `json:"prize_tier"`
+	WinnerCount    uint            `json:"winner_count"`
+	MinTier        uint            `json:"min_tier"`
+	MinRep         uint            `json:"min_rep"`
+	Deadline       int64           `json:"deadline"`        //unix time in seconds the entrants are snapshotted at
+	RevealDeadline int64           `json:"reveal_deadline"` //unix time in seconds the raffle expires at
+	Commitment     util.Commitment `json:"commitment"`
+	Status         string          `json:"status"`
+
+	SnapshotHeight int64            `json:"snapshot_height,omitempty"`
+	SeedHeight     int64            `json:"seed_height,omitempty"` //the snapshot height, or the first block after
+	SeedBlockHash  []byte           `json:"seed_block_hash,omitempty"`
+	EntrantCount   uint             `json:"entrant_count"`
+	Entrants       []sdk.AccAddress `json:"entrants,omitempty"`
+	Proof          *RaffleProof     `json:"proof,omitempty"`
+	Winners        []sdk.AccAddress `json:"winners,omitempty"`
+}
+
+//GenesisRaffles is the full array of raffles for the genesis file
+type GenesisRaffles []Raffle
+
+//RaffleProof is what anyone needs to redraw the winners of a raffle with DrawWinners: the seed is the
+//sha256 of the secret followed by the hash of the block at `Height`, the seed height of the raffle
+type RaffleProof struct {
+	Secret    string `json:"secret"`
+	Height    int64  `json:"height"`
+	BlockHash []byte `json:"block_hash"`
+	Seed      []byte `json:"seed"`
+}
+
+//NewRaffle is the constructor for an open `Raffle`
+func NewRaffle(id string, prizeTier uint, winnerCount uint, minTier uint, minRep uint, deadline time.Time,
+	revealDeadline time.Time, commitment util.Commitment) Raffle {
+	return Raffle{
+		ID:             id,
+		PrizeTier:      prizeTier,
+		WinnerCount:    winnerCount,
+		MinTier:        minTier,
+		MinRep:         minRep,
+		Deadline:       deadline.Unix(),
+		RevealDeadline: revealDeadline.Unix(),
+		Commitment:     commitment,
+		Status:         RaffleOpen,
+	}
+}
+
+//ValidateBasic checks that the raffle is well formed
+//nolint:gocritic
+func (r Raffle) ValidateBasic() sdk.Error {
+	if !raffleID.MatchString(r.ID) {
+		return ErrInvalidRaffle("raffle id must be 1 to %d letters, digits, dashes or underscores",
+			MaxRaffleIDLength)
+	}
+	if r.PrizeTier < Tier1 || r.PrizeTier > Tier9 {
+		return ErrInvalidRaffle("prize tier must be between %d and %d, got %d", Tier1, Tier9, r.PrizeTier)
+	}
+	if r.MinTier > Tier9 {
+		return ErrInvalidRaffle("minimum tier must be at most %d, got %d", Tier9, r.MinTier)
+	}
+	if r.WinnerCount == 0 {
+		return ErrInvalidRaffle("a raffle must have at least one winner")
+	}
+	if r.Deadline <= 0 {
+		return ErrInvalidRaffle("deadline must be positive")
+	}
+	if r.RevealDeadline <= r.Deadline {
+		return ErrInvalidRaffle("reveal deadline must be after the deadline")
+	}
+	if r.Commitment.Len() != sha256.Size {
+		return ErrInvalidRaffle("commitment must be the %d bytes sha256 of the secret", sha256.Size)
+	}
+	return nil
+}
+
+//IsEligible indicates if the attendee enters the raffle: they claimed their badge, reached the
+//minimum tier and rep and did not win the prize of the raffle yet
+//nolint:gocritic
+func (r Raffle) IsEligible(a *Attendee) bool {
+	return a.IsClaimed() && a.GetTier() >= r.MinTier && a.Rep >= r.MinRep &&
+		!a.containsWinning(&Win{Tier: r.PrizeTier})
+}
+
+//DeadlineTime returns the deadline the entrants are snapshotted at
+//nolint:gocritic
+func (r Raffle) DeadlineTime() time.Time {
+	return time.Unix(r.Deadline, 0)
+}
+
+//RevealDeadlineTime returns the time the raffle expires at if it is not revealed
+//nolint:gocritic
+func (r Raffle) RevealDeadlineTime() time.Time {
+	return time.Unix(r.RevealDeadline, 0)
+}
+
+//RaffleSeed returns the seed of a draw, the sha256 of the revealed secret followed by the block hash
+func RaffleSeed(secret string, blockHash []byte) []byte {
+	return tmcrypto.Sha256(append([]byte(secret), blockHash...))
+}
+
+//DrawWinners deterministically draws `n` distinct winners among the entrants. Winner i is picked among
+//the remaining entrants with the first 8 bytes of sha256(seed || i) in big endian. All entrants win if
+//there are `n` or fewer
+func DrawWinners(seed []byte, entrants []sdk.AccAddress, n uint) []sdk.AccAddress {
+	pool := make([]sdk.AccAddress, len(entrants))
+	copy(pool, entrants)
+
+	var winners []sdk.AccAddress
+	for i := uint64(0); i < uint64(n) && len(pool) > 0; i++ {
+		buf := make([]byte, len(seed)+8)
+		copy(buf, seed)
+		binary.BigEndian.PutUint64(buf[len(seed):], i)
+		h := tmcrypto.Sha256(buf)
+
+		idx := binary.BigEndian.Uint64(h[:8]) % uint64(len(pool))
+		winners = append(winners, pool[idx])
+		pool = append(pool[:idx], pool[idx+1:]...)
+	}
+	return winners
+}
@@ -0,0 +1,103 @@
+package types_test
+
+import (
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	. "github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Raffle Tests", func() {
+	var raffle Raffle
+	var entrants []sdk.AccAddress
+
+	BeforeEach(func() {
+		_, commitment := util.CreateCommitment()
+		deadline := time.Unix(1570000000, 0)
+		raffle = NewRaffle("tier-9", Tier9, 1, Tier3, 0, deadline, deadline.Add(DefaultRaffleRevealWindow), commitment)
+
+		entrants = nil
+		for _, id := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+			entrants = append(entrants, util.IDToAddress(id))
+		}
+	})
+
+	It("should validate a well formed raffle", func() {
+		Expect(raffle.ValidateBasic()).To(BeNil())
+		Expect(raffle.Status).To(Equal(RaffleOpen))
+	})
+
+	It("should fail when the raffle is malformed", func() {
+		invalid := raffle
+		invalid.ID = "tier 9"
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidRaffle))
+
+		invalid.ID = strings.Repeat("a", MaxRaffleIDLength+1)
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidRaffle))
+
+		invalid = raffle
+		invalid.PrizeTier = Tier0
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidRaffle))
+
+		invalid = raffle
+		invalid.WinnerCount = 0
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidRaffle))
+
+		invalid = raffle
+		invalid.RevealDeadline = invalid.Deadline
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidRaffle))
+
+		invalid = raffle
+		invalid.Commitment = util.Commitment("short")
+		Expect(invalid.ValidateBasic().Code()).To(Equal(InvalidRaffle))
+	})
+
+	It("should only enter claimed attendees above the minimums without the prize", func() {
+		attendee := NewAttendee("1", RoleAttendee)
+		attendee.Claimed = true
+		attendee.Rep = Tier3Rep
+		Expect(raffle.IsEligible(&attendee)).To(BeTrue())
+
+		attendee.Rep = Tier2Rep
+		Expect(raffle.IsEligible(&attendee)).To(BeFalse())
+
+		attendee.Rep = Tier3Rep
+		attendee.Claimed = false
+		Expect(raffle.IsEligible(&attendee)).To(BeFalse())
+
+		attendee.Claimed = true
+		attendee.AddWinning(&Win{Tier: Tier9, Name: "prize"})
+		Expect(raffle.IsEligible(&attendee)).To(BeFalse())
+	})
+
+	It("should draw the same distinct winners for the same seed", func() {
+		seed := RaffleSeed("secret", []byte("hash"))
+		winners := DrawWinners(seed, entrants, 3)
+		Expect(winners).To(HaveLen(3))
+		Expect(DrawWinners(seed, entrants, 3)).To(Equal(winners))
+
+		seen := make(map[string]bool)
+		for _, w := range winners {
+			Expect(seen[w.String()]).To(BeFalse())
+			seen[w.String()] = true
+		}
+
+		Expect(RaffleSeed("secret", []byte("other"))).ToNot(Equal(seed))
+	})
+
+	It("should not reorder the entrants", func() {
+		snapshot := append([]sdk.AccAddress{}, entrants...)
+		DrawWinners(RaffleSeed("secret", nil), entrants, 5)
+		Expect(entrants).To(Equal(snapshot))
+	})
+
+	It("should draw all the entrants when there are fewer than the winners", func() {
+		winners := DrawWinners(RaffleSeed("secret", nil), entrants[:2], 5)
+		Expect(winners).To(ConsistOf(entrants[0], entrants[1]))
+		Expect(DrawWinners(RaffleSeed("secret", nil), nil, 5)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/ticketing"
+)
+
+const (
+	//RoleAttendee is the role of a regular ticket holder
+	RoleAttendee = ticketing.RoleAttendee
+	//RoleSponsor is the role of event sponsors
+	RoleSponsor = ticketing.RoleSponsor
+	//RoleSpeaker is the role of event speakers
+	RoleSpeaker = ticketing.RoleSpeaker
+	//RoleStaff is the role of event staff and volunteers
+	RoleStaff = ticketing.RoleStaff
+	//RoleVIP is the role of invited guests
+	RoleVIP = ticketing.RoleVIP
+
+	//PeerGroupSponsors is the peer group of the sponsors and speakers, who shared the sponsor flag before
+	//roles and scored as regular attendees with each other
+	PeerGroupSponsors = "sponsors"
+)
+
+// RoleRule is the point rule of an attendee role. ScanPoints and SharePoints are what an attendee
+// earns for connecting with, or sharing their info with, an attendee of this role. Roles of the same
+// PeerGroup score as regular attendees with each other, like attendees of the same role
+type RoleRule struct {
+	Role                   string `json:"role"`
+	PeerGroup              string `json:"peer_group,omitempty"`
+	ScanPoints             uint   `json:"scan_points"`
+	SharePoints            uint   `json:"share_points"`
+	BonusEligible          bool   `json:"bonus_eligible,omitempty"`
+	ExcludeFromLeaderBoard bool   `json:"exclude_from_leader_board,omitempty"`
+	ExcludeFromPrizes      bool   `json:"exclude_from_prizes,omitempty"`
+}
+
+// GenesisRoles is the full array of role rules for the event
+type GenesisRoles []RoleRule
+
+// IsPeer indicates if attendees of `r` and `other` score as regular attendees with each other
+func (r *RoleRule) IsPeer(other *RoleRule) bool {
+	return r.Role == other.Role || (len(r.PeerGroup) > 0 && r.PeerGroup == other.PeerGroup)
+}
+
+// ValidateBasic runs stateless checks on the rule
+func (r *RoleRule) ValidateBasic() sdk.Error {
+	if len(r.Role) == 0 {
+		return ErrInvalidRole("role name cannot be empty")
+	}
+	return nil
+}
+
+//String returns a human readable version of the rule
+func (r *RoleRule) String() string {
+	return fmt.Sprintf("role: %s, scan: %d, share: %d", r.Role, r.ScanPoints, r.SharePoints)
+}
+
+//GetID returns the store key of the rule
+func (r *RoleRule) GetID() []byte {
+	return RoleKey(r.Role)
+}
+
+// DefaultRoleRules returns the point rules of SF Blockchain Week. Sponsors and speakers are worth
+// more points and are affected by bonuses but score as regular attendees with each other, staff can
+// play but cannot rank or win prizes
+func DefaultRoleRules() GenesisRoles {
+	return GenesisRoles{
+		{
+			Role:        RoleAttendee,
+			ScanPoints:  ScanAttendeeAwardPoints,
+			SharePoints: ShareAttendeeAwardPoints,
+		},
+		{
+			Role:          RoleSponsor,
+			PeerGroup:     PeerGroupSponsors,
+			ScanPoints:    ScanSponsorAwardPoints,
+			SharePoints:   ShareSponsorAwardPoints,
+			BonusEligible: true,
+		},
+		{
+			Role:          RoleSpeaker,
+			PeerGroup:     PeerGroupSponsors,
+			ScanPoints:    ScanSponsorAwardPoints,
+			SharePoints:   ShareSponsorAwardPoints,
+			BonusEligible: true,
+		},
+		{
+			Role:        RoleVIP,
+			ScanPoints:  ScanSponsorAwardPoints,
+			SharePoints: ShareAttendeeAwardPoints,
+		},
+		{
+			Role:                   RoleStaff,
+			ScanPoints:             ScanAttendeeAwardPoints,
+			SharePoints:            ShareAttendeeAwardPoints,
+			ExcludeFromLeaderBoard: true,
+			ExcludeFromPrizes:      true,
+		},
+	}
+}
@@ -26,6 +26,9 @@ type Scan struct {
 	//Accepted is true when S2, the scanned participant, posts on-chain that they accept the connection
 	//this is equivalent of posting their own MsgScanQR or MsgInfo
 	Accepted bool `json:"accepted"`
+	//AttestedTimeSec is the unix time in seconds the scanner's phone attested for a scan synced after
+	//being made offline, zero for scans broadcast live
+	AttestedTimeSec int64 `json:"attestedTimeSec,omitempty"`
 }
 
 //NewScan creates a new scan and sets its id
@@ -65,6 +68,16 @@ func (s *Scan) SetTimeUnixSeconds(unix int64) {
 	s.UnixTimeSec = unix
 }
 
+//SetAttestedTime records the attested unix time in seconds of the scan, keeping the earliest of the
+//times attested by both parties. Returns true if the scan changed
+func (s *Scan) SetAttestedTime(unix int64) bool {
+	if s.AttestedTimeSec != 0 && s.AttestedTimeSec <= unix {
+		return false
+	}
+	s.AttestedTimeSec = unix
+	return true
+}
+
 //GenScanID creates the unique id between a scan pair, regardless of the order of the account addresses passed into it
 func GenScanID(s1, s2 sdk.AccAddress) (id []byte, err sdk.Error) {
 	err = CheckSameness(s1, s2)
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	//TeamScoringSum scores a team with the total rep of its members
+	TeamScoringSum = "sum"
+	//TeamScoringAverage scores a team with the average rep of its members, rounded down
+	TeamScoringAverage = "average"
+	//DefaultTeamMinSize is the default number of members a team needs to be ranked
+	DefaultTeamMinSize = 3
+
+	//TeamLeaderBoardCount is the total number of teams on the board
+	TeamLeaderBoardCount = 10
+	//TeamLeaderBoardTier1Count is the number of teams in the first tier
+	TeamLeaderBoardTier1Count = 3
+	//TeamLeaderBoardTier1Prize is the tier 1 team prize pool in USD
+	TeamLeaderBoardTier1Prize = 3000
+	//TeamLeaderBoardTier2Prize is the tier 2 team prize pool in USD
+	TeamLeaderBoardTier2Prize = 1000
+)
+
+//TeamRules are the scoring rules of the team board, where the attendees are teamed by company. A team is
+//scored with the sum or the average of the rep of its claimed members, and only ranked once it has
+//`MinSize` of them
+type TeamRules struct {
+	Scoring string `json:"scoring"`
+	MinSize uint   `json:"min_size"`
+}
+
+//DefaultTeamRules returns the team rules of chains that do not set them in their genesis file
+func DefaultTeamRules() TeamRules {
+	return TeamRules{
+		Scoring: TeamScoringAverage,
+		MinSize: DefaultTeamMinSize,
+	}
+}
+
+//ValidateBasic checks that the team rules are well formed
+func (r TeamRules) ValidateBasic() sdk.Error {
+	switch r.Scoring {
+	case TeamScoringSum, TeamScoringAverage:
+	default:
+		return ErrInvalidTeamRules("scoring must be %s or %s, got %s", TeamScoringSum, TeamScoringAverage,
+			r.Scoring)
+	}
+	if r.MinSize == 0 {
+		return ErrInvalidTeamRules("the min team size must be positive")
+	}
+	return nil
+}
+
+//Score returns the score of a team of `size` members with `rep` in total
+func (r TeamRules) Score(rep uint, size uint) uint {
+	if r.Scoring == TeamScoringAverage && size > 0 {
+		return rep / size
+	}
+	return rep
+}
+
+//TeamKey returns the key attendees are teamed by, so that the case and spacing of their company does not
+//split a team
+func TeamKey(company string) string {
+	return strings.ToLower(strings.Join(strings.Fields(company), " "))
+}
+
+//Team is a company on the team board
+type Team struct {
+	Name  string `json:"name"`
+	Size  uint   `json:"size"`
+	Rep   uint   `json:"rep"`
+	Score uint   `json:"score"`
+}
+
+//TeamTier is a prize tier in the team board
+type TeamTier struct {
+	PrizeAmount int    `json:"prizeAmount"`
+	Teams       []Team `json:"teams"`
+}
+
+//TeamLeaderBoard is the leader board of the teams, alongside the individual `LeaderBoard`
+type TeamLeaderBoard struct {
+	TotalCount int       `json:"totalCount"` //teams with at least the min size
+	Rules      TeamRules `json:"rules"`
+	Tier1      TeamTier  `json:"tier1"`
+	Tier2      TeamTier  `json:"tier2"`
+	Time       time.Time `json:"time"`
+}
+
+//NewTeamLeaderBoard returns the team board of the `top` teams, cut to the board size
+func NewTeamLeaderBoard(count int, rules TeamRules, top []Team) *TeamLeaderBoard {
+	if len(top) > TeamLeaderBoardCount {
+		top = top[:TeamLeaderBoardCount]
+	}
+
+	first, second := top, []Team(nil)
+	if len(top) > TeamLeaderBoardTier1Count {
+		first = make([]Team, TeamLeaderBoardTier1Count)
+		copy(first, top)
+		second = make([]Team, len(top)-TeamLeaderBoardTier1Count)
+		copy(second, top[TeamLeaderBoardTier1Count:])
+	}
+
+	return &TeamLeaderBoard{
+		TotalCount: count,
+		Rules:      rules,
+		Tier1: TeamTier{
+			PrizeAmount: TeamLeaderBoardTier1Prize,
+			Teams:       first,
+		},
+		Tier2: TeamTier{
+			PrizeAmount: TeamLeaderBoardTier2Prize,
+			Teams:       second,
+		},
+	}
+}
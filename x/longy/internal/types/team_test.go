@@ -0,0 +1,39 @@
+package types_test
+
+import (
+	"github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Team Tests", func() {
+
+	It("should validate the team rules", func() {
+		Expect(types.DefaultTeamRules().ValidateBasic()).To(BeNil())
+		Expect(types.TeamRules{Scoring: "max", MinSize: 1}.ValidateBasic().Code()).To(Equal(types.InvalidTeamRules))
+		Expect(types.TeamRules{Scoring: types.TeamScoringSum}.ValidateBasic().Code()).To(Equal(types.InvalidTeamRules))
+	})
+
+	It("should score the team with the sum or the average of the rep", func() {
+		Expect(types.TeamRules{Scoring: types.TeamScoringSum, MinSize: 1}.Score(100, 3)).To(Equal(uint(100)))
+		Expect(types.TeamRules{Scoring: types.TeamScoringAverage, MinSize: 1}.Score(100, 3)).To(Equal(uint(33)))
+	})
+
+	It("should team companies regardless of their case and spacing", func() {
+		Expect(types.TeamKey(" Eco  Inc ")).To(Equal(types.TeamKey("eco inc")))
+		Expect(types.TeamKey("   ")).To(BeEmpty())
+	})
+
+	It("should split the teams in tiers", func() {
+		board := types.NewTeamLeaderBoard(5, types.DefaultTeamRules(), make([]types.Team, 2))
+		Expect(board.Tier1.Teams).To(HaveLen(2))
+		Expect(board.Tier2.Teams).To(BeEmpty())
+		Expect(board.Tier1.PrizeAmount).To(Equal(types.TeamLeaderBoardTier1Prize))
+		Expect(board.Tier2.PrizeAmount).To(Equal(types.TeamLeaderBoardTier2Prize))
+
+		board = types.NewTeamLeaderBoard(50, types.DefaultTeamRules(), make([]types.Team, 50))
+		Expect(board.Tier1.Teams).To(HaveLen(types.TeamLeaderBoardTier1Count))
+		Expect(board.Tier2.Teams).To(HaveLen(types.TeamLeaderBoardCount - types.TeamLeaderBoardTier1Count))
+		Expect(board.TotalCount).To(Equal(50))
+	})
+})
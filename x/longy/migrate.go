@@ -0,0 +1,108 @@
+package longy
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy/internal/types"
+)
+
+// AddressMigration maps the address of an attendee before and after a namespace migration
+type AddressMigration struct {
+	ID         string         `json:"id"`
+	OldAddress sdk.AccAddress `json:"old_address"`
+	NewAddress sdk.AccAddress `json:"new_address"`
+}
+
+// MigrateAttendeeAddresses moves every attendee of `state` to the address derived with `namespace`.
+// The scans between attendees and the records of their attestations are re-keyed to the new addresses. The
+// raffle entrants and winners, the booth challenge sponsors, the attendees of unrevealed booth answers and of
+// checkpoint scans move in place, so raffle proofs still redraw the same winners. Booth answers keep the
+// address their commitment is salted with. The returned mapping is used to move the accounts of the attendees
+// held by other modules
+//nolint:gocritic
+func MigrateAttendeeAddresses(state GenesisState, namespace string) (GenesisState, []AddressMigration, sdk.Error) {
+	if len(namespace) == 0 {
+		return state, nil, types.ErrDefault("address namespace cannot be empty")
+	} else if namespace == state.AddressNamespace {
+		return state, nil, types.ErrDefault("attendee addresses already derived with namespace %s", namespace)
+	}
+
+	migrations := make([]AddressMigration, len(state.Attendees))
+	addresses := make(map[string]sdk.AccAddress, len(state.Attendees))
+	for i := range state.Attendees {
+		a := &state.Attendees[i]
+		newAddr := util.AttendeeAddress(namespace, a.ID)
+		migrations[i] = AddressMigration{ID: a.ID, OldAddress: a.Address, NewAddress: newAddr}
+		addresses[a.Address.String()] = newAddr
+		a.Address = newAddr
+	}
+
+	migrate := func(addr sdk.AccAddress) sdk.AccAddress {
+		if newAddr, ok := addresses[addr.String()]; ok {
+			return newAddr
+		}
+		return addr
+	}
+
+	//scan ids are derived from the addresses of both participants
+	scanIDs := make(map[string]string, len(state.Scans))
+	for i := range state.Scans {
+		s := &state.Scans[i]
+		s.S1 = migrate(s.S1)
+		s.S2 = migrate(s.S2)
+
+		id, err := types.GenScanID(s.S1, s.S2)
+		if err != nil {
+			return state, nil, err
+		}
+		scanIDs[types.Encode(s.ID)] = types.Encode(id)
+		s.ID = id
+	}
+
+	for i := range state.AttestedScans {
+		a := &state.AttestedScans[i]
+		a.Scanner = migrate(a.Scanner)
+		if newID, ok := scanIDs[types.Encode(a.ScanID)]; ok {
+			a.ScanID = types.Decode(newID)
+		}
+	}
+
+	for i := range state.Attendees {
+		a := &state.Attendees[i]
+		for j, id := range a.ScanIDs {
+			if newID, ok := scanIDs[id]; ok {
+				a.ScanIDs[j] = newID
+			}
+		}
+	}
+
+	for i := range state.Raffles {
+		r := &state.Raffles[i]
+		for j := range r.Entrants {
+			r.Entrants[j] = migrate(r.Entrants[j])
+		}
+		for j := range r.Winners {
+			r.Winners[j] = migrate(r.Winners[j])
+		}
+	}
+
+	for i := range state.BoothChallenges {
+		c := &state.BoothChallenges[i]
+		c.Sponsor = migrate(c.Sponsor)
+	}
+	for i := range state.BoothAnswers {
+		a := &state.BoothAnswers[i]
+		if a.CommittedBy.Empty() {
+			a.CommittedBy = a.Attendee
+		}
+		a.Attendee = migrate(a.Attendee)
+	}
+
+	for i := range state.CheckpointScans {
+		s := &state.CheckpointScans[i]
+		s.Attendee = migrate(s.Attendee)
+	}
+
+	state.AddressNamespace = namespace
+	return state, migrations, nil
+}
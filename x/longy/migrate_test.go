@@ -0,0 +1,140 @@
+package longy_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/util"
+	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/internal/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Attendee Address Migration Tests", func() {
+	const (
+		id1       = "1234"
+		id2       = "5678"
+		namespace = "sfbw"
+	)
+	var state longy.GenesisState
+
+	BeforeEach(func() {
+		a1 := types.NewAttendee(id1, types.RoleAttendee)
+		a2 := types.NewAttendee(id2, types.RoleSponsor)
+		scan, err := types.NewScan(a1.Address, a2.Address, nil, nil, 1, 2)
+		Expect(err).To(BeNil())
+		a1.AddScanID(scan.ID)
+		a2.AddScanID(scan.ID)
+
+		state = longy.GenesisState{
+			Attendees: longy.GenesisAttendees{a1, a2},
+			Scans:     longy.GenesisScans{*scan},
+		}
+	})
+
+	It("should fail without a namespace", func() {
+		_, _, err := longy.MigrateAttendeeAddresses(state, "")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("should fail when the attendees already use the namespace", func() {
+		migrated, _, err := longy.MigrateAttendeeAddresses(state, namespace)
+		Expect(err).To(BeNil())
+
+		_, _, err = longy.MigrateAttendeeAddresses(migrated, namespace)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("should move the attendees to the namespaced addresses", func() {
+		migrated, migrations, err := longy.MigrateAttendeeAddresses(state, namespace)
+		Expect(err).To(BeNil())
+		Expect(migrated.AddressNamespace).To(Equal(namespace))
+
+		Expect(migrations).To(HaveLen(2))
+		Expect(migrations[0].ID).To(Equal(id1))
+		Expect(migrations[0].OldAddress).To(Equal(util.IDToAddress(id1)))
+		Expect(migrations[0].NewAddress).To(Equal(util.AttendeeAddress(namespace, id1)))
+
+		Expect(migrated.Attendees[0].Address).To(Equal(util.AttendeeAddress(namespace, id1)))
+		Expect(migrated.Attendees[1].Address).To(Equal(util.AttendeeAddress(namespace, id2)))
+	})
+
+	It("should re-key the scans between attendees", func() {
+		migrated, _, err := longy.MigrateAttendeeAddresses(state, namespace)
+		Expect(err).To(BeNil())
+
+		s1 := util.AttendeeAddress(namespace, id1)
+		s2 := util.AttendeeAddress(namespace, id2)
+		id, err := types.GenScanID(s1, s2)
+		Expect(err).To(BeNil())
+
+		scan := migrated.Scans[0]
+		Expect(scan.S1).To(Equal(s1))
+		Expect(scan.S2).To(Equal(s2))
+		Expect(scan.ID).To(Equal(id))
+		Expect(scan.P1).To(Equal(uint(1)))
+		Expect(migrated.Attendees[0].ScanIDs).To(Equal([]string{types.Encode(id)}))
+		Expect(migrated.Attendees[1].ScanIDs).To(Equal([]string{types.Encode(id)}))
+	})
+
+	It("should re-key the attested scans", func() {
+		state.AttestedScans = longy.GenesisAttestedScans{{Scanner: state.Attendees[0].Address,
+			ScanID: state.Scans[0].ID}}
+
+		migrated, _, err := longy.MigrateAttendeeAddresses(state, namespace)
+		Expect(err).To(BeNil())
+
+		attested := migrated.AttestedScans[0]
+		Expect(attested.Scanner).To(Equal(util.AttendeeAddress(namespace, id1)))
+		Expect(attested.ScanID).To(Equal(migrated.Scans[0].ID))
+	})
+
+	It("should move the entrants and winners of the raffles", func() {
+		a1 := state.Attendees[0].Address
+		a2 := state.Attendees[1].Address
+		seed := types.RaffleSeed("secret", []byte("snapshot block hash"))
+		state.Raffles = longy.GenesisRaffles{{
+			ID:       "tier-8",
+			Status:   types.RaffleDrawn,
+			Entrants: []sdk.AccAddress{a1, a2},
+			Proof:    &types.RaffleProof{Seed: seed},
+			Winners:  types.DrawWinners(seed, []sdk.AccAddress{a1, a2}, 1),
+		}}
+
+		migrated, _, err := longy.MigrateAttendeeAddresses(state, namespace)
+		Expect(err).To(BeNil())
+
+		s1 := util.AttendeeAddress(namespace, id1)
+		s2 := util.AttendeeAddress(namespace, id2)
+		raffle := migrated.Raffles[0]
+		Expect(raffle.Entrants).To(Equal([]sdk.AccAddress{s1, s2}))
+		Expect(raffle.Winners).To(HaveLen(1))
+		Expect(raffle.Winners).To(Equal(types.DrawWinners(seed, raffle.Entrants, 1)))
+	})
+
+	It("should move the sponsors of the booth challenges and the unrevealed answers", func() {
+		a1 := state.Attendees[0].Address
+		a2 := state.Attendees[1].Address
+		state.BoothChallenges = longy.GenesisBoothChallenges{{ID: "eco-1", Sponsor: a2}}
+		state.BoothAnswers = longy.GenesisBoothAnswers{{ChallengeID: "eco-1", Attendee: a1,
+			Commitment: longy.NewBoothAnswerCommitment("eco-1", "economy", a1)}}
+
+		migrated, _, err := longy.MigrateAttendeeAddresses(state, namespace)
+		Expect(err).To(BeNil())
+		Expect(migrated.BoothChallenges[0].Sponsor).To(Equal(util.AttendeeAddress(namespace, id2)))
+
+		answer := migrated.BoothAnswers[0]
+		Expect(answer.Attendee).To(Equal(util.AttendeeAddress(namespace, id1)))
+		Expect(answer.CommittedBy).To(Equal(a1))
+		Expect(answer.VerifyReveal("economy")).To(BeTrue())
+	})
+
+	It("should move the attendees of the checkpoint scans", func() {
+		state.CheckpointScans = longy.GenesisCheckpointScans{{CheckpointID: "keynote",
+			Attendee: state.Attendees[0].Address, UnixTimeSec: 1570000000}}
+
+		migrated, _, err := longy.MigrateAttendeeAddresses(state, namespace)
+		Expect(err).To(BeNil())
+		Expect(migrated.CheckpointScans[0].Attendee).To(Equal(util.AttendeeAddress(namespace, id1)))
+		Expect(migrated.CheckpointScans[0].UnixTimeSec).To(Equal(int64(1570000000)))
+	})
+})
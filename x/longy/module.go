@@ -107,7 +107,8 @@ func (AppModule) BeginBlock(sdk.Context, abci.RequestBeginBlock) {
 
 // EndBlock runs at the end of each block
 //nolint:gocritic
-func (AppModule) EndBlock(sdk.Context, abci.RequestEndBlock) []abci.ValidatorUpdate {
+func (am AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+	EndBlocker(ctx, am.keeper)
 	return []abci.ValidatorUpdate{}
 }
 
@@ -236,12 +236,16 @@ func NewLongyApp(
 	// The initChainer handles translating the genesis.json file into initial state for the network
 	app.SetInitChainer(app.InitChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
-	// The AnteHandler handles signature verification and transaction pre-processing
+	// The AnteHandler handles signature verification and transaction pre-processing. The longy
+	// ante handler restricts what attendee accounts can send and how often
 	app.SetAnteHandler(
-		auth.NewAnteHandler(
-			app.AccountKeeper,
-			app.supplyKeeper,
-			auth.DefaultSigVerificationGasConsumer,
+		longy.NewAnteHandler(
+			app.LongyKeeper,
+			auth.NewAnteHandler(
+				app.AccountKeeper,
+				app.supplyKeeper,
+				auth.DefaultSigVerificationGasConsumer,
+			),
 		),
 	)
 	app.SetEndBlocker(app.EndBlocker)
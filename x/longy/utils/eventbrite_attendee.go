@@ -1,18 +1,20 @@
 package utils
 
 import (
+	"strings"
+
+	"github.com/eco/longy/ticketing"
 	"github.com/eco/longy/util"
 	"github.com/eco/longy/x/longy"
-	"strings"
 )
 
 const (
 	//TicketSponsorNameLowerCase is the sponsors ticket type
-	TicketSponsorNameLowerCase = "sponsors"
+	TicketSponsorNameLowerCase = ticketing.TicketSponsorNameLowerCase
 	//TicketSpeakerCescNameLowerCase is the cesc speakers ticket type
-	TicketSpeakerCescNameLowerCase = "cesc speakers"
+	TicketSpeakerCescNameLowerCase = ticketing.TicketSpeakerCescNameLowerCase
 	//TicketSpeakerEpicenterNameLowerCase is the epicenter speakers ticket type
-	TicketSpeakerEpicenterNameLowerCase = "epicenter speakers"
+	TicketSpeakerEpicenterNameLowerCase = ticketing.TicketSpeakerEpicenterNameLowerCase
 )
 
 //EventbriteAttendees is the array of attendees that the api returns for processing
@@ -25,28 +27,22 @@ type EventbriteAttendee struct {
 	Profile         EventbriteProfile `json:"profile"` //gets the full info of the account
 }
 
-//ToGenesisAttendee turns the eventbrite type to our local type
-func (e *EventbriteAttendee) ToGenesisAttendee() longy.Attendee {
+//ToGenesisAttendee turns the eventbrite type to our local type. The address is derived with the
+//event `namespace`
+func (e *EventbriteAttendee) ToGenesisAttendee(namespace string) longy.Attendee {
 	return longy.Attendee{
 		ID:      e.ID,
-		Address: util.IDToAddress(e.ID),
-		Sponsor: e.IsSponsorTicket(),
+		Address: util.AttendeeAddress(namespace, e.ID),
+		Role:    e.Role(ticketing.DefaultRoleMap()),
 		Name:    e.Profile.Name,
-		// can also add Profile to the Attendee's struc to show all
+		Company: strings.TrimSpace(e.Profile.Company),
+		// job titles and emails stay off chain
 	}
 }
 
-//IsSponsorTicket checks to see if the ticket type is of a speaker or sponsor that gets special point bonuses
-func (e *EventbriteAttendee) IsSponsorTicket() bool {
-	switch strings.ToLower(e.TicketClassName) {
-	case TicketSponsorNameLowerCase:
-		fallthrough
-	case TicketSpeakerCescNameLowerCase:
-		fallthrough
-	case TicketSpeakerEpicenterNameLowerCase:
-		return true
-	}
-	return false
+//Role returns the role `roles` maps the ticket type to
+func (e *EventbriteAttendee) Role(roles ticketing.RoleMap) string {
+	return roles.Role(e.TicketClassName)
 }
 
 // EventbriteProfile is the profile of the attendee from eventbrite
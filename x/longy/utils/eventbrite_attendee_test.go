@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"github.com/eco/longy/ticketing"
 	"github.com/eco/longy/util"
 	"github.com/eco/longy/x/longy/utils"
 	. "github.com/onsi/ginkgo"
@@ -15,19 +16,40 @@ var _ = Describe("Eventbrite Attendee Genesis Tests", func() {
 			TicketClassName: "Standard",
 			Profile:         utils.EventbriteProfile{},
 		}
-		a := ga.ToGenesisAttendee()
-		Expect(a.Sponsor).To(BeFalse())
+		a := ga.ToGenesisAttendee("")
+		Expect(a.Role).To(Equal(ticketing.RoleAttendee))
 		Expect(a.Address).To(Equal(util.IDToAddress(ga.ID)))
 	})
 
-	It("should not be sponsor when standard ticket type", func() {
+	It("should derive the address from the event namespace", func() {
 		ga := utils.EventbriteAttendee{
 			ID:              "123",
 			TicketClassName: "Standard",
 			Profile:         utils.EventbriteProfile{},
 		}
-		a := ga.ToGenesisAttendee()
-		Expect(a.Sponsor).To(BeFalse())
+		a := ga.ToGenesisAttendee("sfbw")
+		Expect(a.Address).To(Equal(util.AttendeeAddress("sfbw", ga.ID)))
+		Expect(a.Address).ToNot(Equal(util.IDToAddress(ga.ID)))
+	})
+
+	It("should carry the company of the profile", func() {
+		ga := utils.EventbriteAttendee{
+			ID:              "123",
+			TicketClassName: "Standard",
+			Profile:         utils.EventbriteProfile{Company: " Eco ", JobTitle: "Engineer"},
+		}
+		a := ga.ToGenesisAttendee("")
+		Expect(a.Company).To(Equal("Eco"))
+	})
+
+	It("should be a regular attendee when standard ticket type", func() {
+		ga := utils.EventbriteAttendee{
+			ID:              "123",
+			TicketClassName: "Standard",
+			Profile:         utils.EventbriteProfile{},
+		}
+		a := ga.ToGenesisAttendee("")
+		Expect(a.Role).To(Equal(ticketing.RoleAttendee))
 	})
 
 	It("should be sponsor when sponsor ticket type", func() {
@@ -36,27 +58,37 @@ var _ = Describe("Eventbrite Attendee Genesis Tests", func() {
 			TicketClassName: "Sponsors",
 			Profile:         utils.EventbriteProfile{},
 		}
-		a := ga.ToGenesisAttendee()
-		Expect(a.Sponsor).To(BeTrue())
+		a := ga.ToGenesisAttendee("")
+		Expect(a.Role).To(Equal(ticketing.RoleSponsor))
 	})
 
-	It("should be sponsor when cesc speaker ticket type", func() {
+	It("should be speaker when cesc speaker ticket type", func() {
 		ga := utils.EventbriteAttendee{
 			ID:              "123",
 			TicketClassName: "CESC Speakers",
 			Profile:         utils.EventbriteProfile{},
 		}
-		a := ga.ToGenesisAttendee()
-		Expect(a.Sponsor).To(BeTrue())
+		a := ga.ToGenesisAttendee("")
+		Expect(a.Role).To(Equal(ticketing.RoleSpeaker))
 	})
 
-	It("should be sponsor when epicenter speaker ticket type", func() {
+	It("should be speaker when epicenter speaker ticket type", func() {
 		ga := utils.EventbriteAttendee{
 			ID:              "123",
 			TicketClassName: "Epicenter Speakers",
 			Profile:         utils.EventbriteProfile{},
 		}
-		a := ga.ToGenesisAttendee()
-		Expect(a.Sponsor).To(BeTrue())
+		a := ga.ToGenesisAttendee("")
+		Expect(a.Role).To(Equal(ticketing.RoleSpeaker))
+	})
+
+	It("should use the role of a custom ticket class mapping", func() {
+		ga := utils.EventbriteAttendee{
+			ID:              "123",
+			TicketClassName: "Volunteers",
+			Profile:         utils.EventbriteProfile{},
+		}
+		Expect(ga.Role(ticketing.RoleMap{"volunteers": ticketing.RoleStaff})).To(Equal(ticketing.RoleStaff))
+		Expect(ga.Role(ticketing.DefaultRoleMap())).To(Equal(ticketing.RoleAttendee))
 	})
 })
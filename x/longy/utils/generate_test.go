@@ -2,6 +2,7 @@ package utils_test
 
 import (
 	"fmt"
+	"github.com/eco/longy/ticketing"
 	"github.com/eco/longy/x/longy/internal/types"
 	"github.com/eco/longy/x/longy/utils"
 	"os"
@@ -37,7 +38,7 @@ var _ = PDescribe("Generate Attendee Genesis Tests", func() {
 
 	It("should fail when environment variable for auth not set", func() {
 		_ = os.Unsetenv(utils.EventbriteAuthEnvKey)
-		_, err := utils.GetAttendees()
+		_, err := utils.GetAttendees(ticketing.DefaultRoleMap(), "")
 		Expect(err).To(Not(BeNil()))
 	})
 
@@ -48,7 +49,7 @@ var _ = PDescribe("Generate Attendee Genesis Tests", func() {
 
 		errOS := os.Setenv(utils.EventbriteAuthEnvKey, "afakekeyandstuff")
 		Expect(errOS).To(BeNil())
-		_, err := utils.GetAttendees()
+		_, err := utils.GetAttendees(ticketing.DefaultRoleMap(), "")
 		Expect(err).To(Not(BeNil()))
 		Expect(err.Code()).To(Equal(types.NetworkResponseError))
 	})
@@ -60,7 +61,7 @@ var _ = PDescribe("Generate Attendee Genesis Tests", func() {
 		sysErr := os.Setenv(utils.EventbriteAuthEnvKey, key)
 		Expect(sysErr).To(BeNil())
 
-		_, err := utils.GetAttendees()
+		_, err := utils.GetAttendees(ticketing.DefaultRoleMap(), "")
 		Expect(err).To(BeNil())
 	})
 
@@ -71,7 +72,7 @@ var _ = PDescribe("Generate Attendee Genesis Tests", func() {
 		sysErr := os.Setenv(utils.EventbriteAuthEnvKey, key)
 		Expect(sysErr).To(BeNil())
 
-		ga, err := utils.GetAttendees()
+		ga, err := utils.GetAttendees(ticketing.DefaultRoleMap(), "")
 		Expect(err).To(BeNil())
 
 		dup := make(map[string]bool, len(ga))
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"encoding/json"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/eco/longy/ticketing"
+	"github.com/eco/longy/x/longy"
+	"github.com/eco/longy/x/longy/internal/types"
+	"io/ioutil"
+	"strings"
+)
+
+// RoleConfig is the role configuration of an event. TicketClasses maps ticket class names to a role
+// and Rules holds the point rules of each role. Ticket classes that are not mapped are regular attendees
+type RoleConfig struct {
+	TicketClasses ticketing.RoleMap  `json:"ticket_classes"`
+	Rules         longy.GenesisRoles `json:"rules"`
+}
+
+// DefaultRoleConfig returns the role configuration of SF Blockchain Week
+func DefaultRoleConfig() RoleConfig {
+	return RoleConfig{
+		TicketClasses: ticketing.DefaultRoleMap(),
+		Rules:         types.DefaultRoleRules(),
+	}
+}
+
+// LoadRoleConfig reads the role configuration json file at `path`. Ticket class names are matched
+// case insensitively and every mapped role must have a rule
+func LoadRoleConfig(path string) (config RoleConfig, err sdk.Error) {
+	bz, e := ioutil.ReadFile(path)
+	if e != nil {
+		err = types.ErrDefault("reading role config: %s", e)
+		return
+	}
+
+	if e = json.Unmarshal(bz, &config); e != nil {
+		err = types.ErrInvalidRole("decoding role config: %s", e)
+		return
+	}
+
+	rules := make(map[string]bool, len(config.Rules))
+	for i := range config.Rules {
+		if err = config.Rules[i].ValidateBasic(); err != nil {
+			return
+		}
+		rules[config.Rules[i].Role] = true
+	}
+	if !rules[types.RoleAttendee] {
+		err = types.ErrInvalidRole("role config must have a rule for %s", types.RoleAttendee)
+		return
+	}
+
+	classes := make(ticketing.RoleMap, len(config.TicketClasses))
+	for class, role := range config.TicketClasses {
+		if !rules[role] {
+			err = types.ErrInvalidRole("ticket class %s maps to role %s which has no rule", class, role)
+			return
+		}
+		classes[strings.ToLower(strings.TrimSpace(class))] = role
+	}
+	config.TicketClasses = classes
+
+	return
+}
@@ -3,7 +3,6 @@ package utils
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth/exported"
-	"github.com/eco/longy/util"
 	"github.com/eco/longy/x/longy"
 	"github.com/eco/longy/x/longy/internal/types"
 	"github.com/onsi/gomega"
@@ -14,9 +13,21 @@ import (
 //nolint:gocritic
 func AddAttendeeToKeeper(ctx sdk.Context, keeper *longy.Keeper, badgeID string, claimed bool,
 	sponsor bool) (attendee types.Attendee) {
-	addr := util.IDToAddress(badgeID)
+	role := types.RoleAttendee
+	if sponsor {
+		role = types.RoleSponsor
+	}
+	return AddAttendeeWithRoleToKeeper(ctx, keeper, badgeID, claimed, role)
+}
+
+//AddAttendeeWithRoleToKeeper is a helper for adding an attendee with `role` and its associate account to a test keeper
+//nolint:gocritic
+func AddAttendeeWithRoleToKeeper(ctx sdk.Context, keeper *longy.Keeper, badgeID string, claimed bool,
+	role string) (attendee types.Attendee) {
+	addr := keeper.AttendeeAddress(ctx, badgeID)
 	acc := keeper.AccountKeeper().NewAccountWithAddress(ctx, addr)
-	attendee = types.NewAttendee(badgeID, sponsor)
+	attendee = types.NewAttendee(badgeID, role)
+	attendee.Address = addr
 	attendee.Claimed = claimed
 	keeper.AccountKeeper().SetAccount(ctx, acc)
 	keeper.SetAttendee(ctx, &attendee)